@@ -0,0 +1,74 @@
+// Package main runs a fake Polar API for offline development.
+//
+// Point the API at it with POLAR_BASE_URL, POLAR_ACCESS_TOKEN and
+// WEBHOOK_SECRET as printed on start. Checkouts created by the API are paid
+// by opening their URL, which sends subscription.created to -webhook-url.
+// State is scripted through the /_polartest routes, e.g.
+//
+//	go run ./cmd/fakepolar -products plans.json
+//	curl -X POST localhost:8090/_polartest/webhooks \
+//	  -d '{"type":"subscription.updated","external_customer_id":"organization-test-123"}'
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/moasq/go-b2b-starter/internal/platform/polar/polartest"
+)
+
+func main() {
+	var (
+		addr       = flag.String("addr", "localhost:8090", "address to listen on")
+		webhookURL = flag.String("webhook-url", "http://localhost:8080/api/webhooks/polar", "where webhooks are delivered")
+		products   = flag.String("products", "", "JSON file with the products to list (array of polartest.Product)")
+	)
+	flag.Parse()
+
+	srv, err := polartest.NewServerAt(*addr)
+	if err != nil {
+		exitf("failed to start fake Polar API: %v", err)
+	}
+	defer srv.Close()
+	srv.WebhookURL = *webhookURL
+
+	if *products != "" {
+		catalog, err := readProducts(*products)
+		if err != nil {
+			srv.Close()
+			exitf("invalid -products: %v", err)
+		}
+		srv.SetProducts(catalog...)
+	}
+
+	fmt.Printf("Fake Polar API listening on %s\n\n", srv.URL)
+	fmt.Printf("POLAR_BASE_URL=%s\n", srv.URL)
+	fmt.Printf("POLAR_ACCESS_TOKEN=%s\n", polartest.AccessToken)
+	fmt.Printf("WEBHOOK_SECRET=%s\n\n", polartest.WebhookSecret)
+	fmt.Printf("Webhooks are delivered to %s\n", srv.WebhookURL)
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	<-stop
+}
+
+func readProducts(path string) ([]polartest.Product, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var products []polartest.Product
+	if err := json.Unmarshal(data, &products); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+func exitf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
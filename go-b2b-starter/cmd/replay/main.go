@@ -0,0 +1,122 @@
+// Package main provides a command that replays stored events through the
+// current event subscribers, or recorded billing webhooks through the
+// billing service.
+//
+// Examples:
+//
+//	go run ./cmd/replay -event document.uploaded
+//	go run ./cmd/replay -from 2025-01-01 -to 2025-02-01 -dry-run
+//	go run ./cmd/replay -billing-webhook 42,43
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/bootstrap"
+	"github.com/moasq/go-b2b-starter/internal/platform/eventbus"
+)
+
+func main() {
+	var (
+		from      = flag.String("from", "", "replay events created at or after this time (RFC3339 or YYYY-MM-DD)")
+		to        = flag.String("to", "", "replay events created before this time (RFC3339 or YYYY-MM-DD)")
+		events    = flag.String("event", "", "comma-separated event names to replay (default: all)")
+		batchSize = flag.Int("batch-size", 500, "number of stored events read per query")
+		dryRun    = flag.Bool("dry-run", false, "count matching events without invoking handlers")
+		webhooks  = flag.String("billing-webhook", "", "comma-separated IDs of recorded billing webhooks to process again")
+	)
+	flag.Parse()
+
+	if *webhooks != "" {
+		replayBillingWebhooks(*webhooks)
+		return
+	}
+
+	opts := eventbus.ReplayOptions{
+		BatchSize: *batchSize,
+		DryRun:    *dryRun,
+	}
+
+	var err error
+	if opts.From, err = parseTime(*from); err != nil {
+		exitf("invalid -from: %v", err)
+	}
+	if opts.To, err = parseTime(*to); err != nil {
+		exitf("invalid -to: %v", err)
+	}
+	for _, name := range strings.Split(*events, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			opts.EventNames = append(opts.EventNames, name)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	result, err := bootstrap.ExecuteReplay(ctx, opts)
+	if err != nil {
+		exitf("replay failed: %v", err)
+	}
+
+	out, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(out))
+
+	if result.Failed > 0 {
+		os.Exit(2)
+	}
+}
+
+func replayBillingWebhooks(spec string) {
+	var ids []int64
+	for _, value := range strings.Split(spec, ",") {
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			exitf("invalid -billing-webhook ID %q", value)
+		}
+		ids = append(ids, id)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	results, err := bootstrap.ExecuteBillingWebhookReplay(ctx, ids)
+	if err != nil {
+		exitf("webhook replay failed: %v", err)
+	}
+
+	out, _ := json.MarshalIndent(results, "", "  ")
+	fmt.Println(string(out))
+
+	for _, result := range results {
+		if result.Error != "" {
+			os.Exit(2)
+		}
+	}
+}
+
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
+
+func exitf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
@@ -36,6 +36,7 @@ require (
 	github.com/twpayne/go-geom v1.6.1
 	go.uber.org/dig v1.19.0
 	go.uber.org/zap v1.27.0
+	golang.org/x/sync v0.12.0
 	golang.org/x/time v0.8.0
 	gopkg.in/natefinch/lumberjack.v2 v2.2.1
 )
@@ -101,7 +102,6 @@ require (
 	golang.org/x/crypto v0.36.0 // indirect
 	golang.org/x/exp v0.0.0-20240325151524-a685a6edb6d8 // indirect
 	golang.org/x/net v0.37.0 // indirect
-	golang.org/x/sync v0.12.0 // indirect
 	golang.org/x/sys v0.31.0 // indirect
 	golang.org/x/text v0.23.0 // indirect
 	golang.org/x/tools v0.31.0 // indirect
@@ -7,7 +7,9 @@ import (
 	"github.com/moasq/go-b2b-starter/internal/modules/billing"
 	"github.com/moasq/go-b2b-starter/internal/modules/cognitive"
 	"github.com/moasq/go-b2b-starter/internal/modules/documents"
+	"github.com/moasq/go-b2b-starter/internal/modules/eventadmin"
 	"github.com/moasq/go-b2b-starter/internal/modules/organizations"
+	"github.com/moasq/go-b2b-starter/internal/modules/webhooks"
 	server "github.com/moasq/go-b2b-starter/internal/platform/server/domain"
 )
 
@@ -17,12 +19,16 @@ import (
 // 3. BillingHandler - Handles billing status and subscription routes (uses billing module)
 // 4. DocumentsRoutes - Handles PDF document upload and management routes
 // 5. CognitiveRoutes - Handles AI/RAG chat and document search routes
+// 6. EventAdminRoutes - Handles event bus operator routes (dead letters)
+// 7. WebhooksRoutes - Handles customer webhook endpoint and delivery log routes
 type moduleRoutes struct {
 	OrganizationRoutes  *organizations.Routes
 	RbacRoutes          *auth.Routes
 	SubscriptionHandler *billing.Handler
 	DocumentsRoutes     *documents.Routes
 	CognitiveRoutes     *cognitive.Routes
+	EventAdminRoutes    *eventadmin.Routes
+	WebhooksRoutes      *webhooks.Routes
 }
 
 // Init sets up all module dependencies and registers API routes
@@ -45,6 +51,8 @@ func registerAPI(container *dig.Container) error {
 		subscriptionHandler *billing.Handler,
 		documentsRoutes *documents.Routes,
 		cognitiveRoutes *cognitive.Routes,
+		eventAdminRoutes *eventadmin.Routes,
+		webhooksRoutes *webhooks.Routes,
 	) *moduleRoutes {
 		return &moduleRoutes{
 			OrganizationRoutes:  organizationRoutes,
@@ -52,6 +60,8 @@ func registerAPI(container *dig.Container) error {
 			SubscriptionHandler: subscriptionHandler,
 			DocumentsRoutes:     documentsRoutes,
 			CognitiveRoutes:     cognitiveRoutes,
+			EventAdminRoutes:    eventAdminRoutes,
+			WebhooksRoutes:      webhooksRoutes,
 		}
 	}); err != nil {
 		return err
@@ -67,6 +77,8 @@ func registerAPI(container *dig.Container) error {
 		srv.RegisterRoutes(modules.SubscriptionHandler.Routes, server.ApiPrefix)
 		srv.RegisterRoutes(modules.DocumentsRoutes.Routes, server.ApiPrefix)
 		srv.RegisterRoutes(modules.CognitiveRoutes.Routes, server.ApiPrefix)
+		srv.RegisterRoutes(modules.EventAdminRoutes.Routes, server.ApiPrefix)
+		srv.RegisterRoutes(modules.WebhooksRoutes.Routes, server.ApiPrefix)
 	})
 }
 
@@ -96,5 +108,15 @@ func setupDependencies(container *dig.Container) error {
 		return err
 	}
 
+	// Initialize event bus admin API (dead letter inspection and replay)
+	if err := eventadmin.NewProvider(container).RegisterDependencies(); err != nil {
+		return err
+	}
+
+	// Initialize webhooks API (endpoint registration and delivery log)
+	if err := webhooks.NewProvider(container).RegisterDependencies(); err != nil {
+		return err
+	}
+
 	return nil
 }
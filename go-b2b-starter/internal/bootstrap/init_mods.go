@@ -24,7 +24,9 @@ import (
 	polar "github.com/moasq/go-b2b-starter/internal/platform/polar/cmd"
 	redisCmd "github.com/moasq/go-b2b-starter/internal/platform/redis/cmd"
 	server "github.com/moasq/go-b2b-starter/internal/platform/server/cmd"
+	stripe "github.com/moasq/go-b2b-starter/internal/platform/stripe/cmd"
 	stytchCmd "github.com/moasq/go-b2b-starter/internal/platform/stytch/cmd"
+	webhooks "github.com/moasq/go-b2b-starter/internal/modules/webhooks/cmd"
 )
 
 // orgLookupAdapter adapts orgDomain.OrganizationRepository to auth.OrganizationLookup
@@ -59,10 +61,14 @@ func InitMods(container *dig.Container) {
 		panic(err)
 	}
 
-	// Polar package must be initialized before payment module (payment depends on Polar client)
+	// Billing provider packages must be initialized before the billing module;
+	// only the provider selected by BILLING_PROVIDER is ever constructed
 	if err := polar.Init(container); err != nil {
 		panic(err)
 	}
+	if err := stripe.Init(container); err != nil {
+		panic(err)
+	}
 
 	// Redis must be initialized before auth (Stytch repositories rely on Redis-backed clients upstream)
 	if err := redisCmd.Init(container); err != nil {
@@ -141,6 +147,12 @@ func InitMods(container *dig.Container) {
 		panic(err)
 	}
 
+	// Webhooks module (customer-facing outbound webhooks)
+	// Initialized after the modules whose events it forwards
+	if err := webhooks.Init(container); err != nil {
+		panic(err)
+	}
+
 	// api
 	api.Init(container)
 }
@@ -0,0 +1,81 @@
+package bootstrap
+
+import (
+	"context"
+	"log"
+
+	"github.com/joho/godotenv"
+	"go.uber.org/dig"
+
+	billingServices "github.com/moasq/go-b2b-starter/internal/modules/billing/app/services"
+	"github.com/moasq/go-b2b-starter/internal/platform/eventbus"
+	eventbusCmd "github.com/moasq/go-b2b-starter/internal/platform/eventbus/cmd"
+)
+
+// ExecuteReplay wires all modules without starting the server or the event
+// bus transport, then replays stored events through the registered subscribers
+func ExecuteReplay(ctx context.Context, opts eventbus.ReplayOptions) (eventbus.ReplayResult, error) {
+	if err := godotenv.Load("app.env"); err != nil {
+		log.Printf("Warning: Error loading app.env file: %v", err)
+	}
+
+	container := dig.New()
+
+	InitMods(container)
+
+	var result eventbus.ReplayResult
+	err := container.Invoke(func(replayer *eventbus.Replayer) error {
+		var err error
+		result, err = replayer.Replay(ctx, opts)
+		return err
+	})
+
+	// Drain anything handlers published asynchronously during the replay
+	if closeErr := eventbusCmd.Close(container); closeErr != nil {
+		log.Printf("Warning: %v", closeErr)
+	}
+
+	return result, err
+}
+
+// WebhookReplayResult is the outcome of replaying one recorded billing webhook
+type WebhookReplayResult struct {
+	ID        int64  `json:"id"`
+	WebhookID string `json:"webhook_id,omitempty"`
+	EventType string `json:"event_type,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ExecuteBillingWebhookReplay wires all modules and processes recorded
+// billing webhooks again from their stored payloads
+func ExecuteBillingWebhookReplay(ctx context.Context, ids []int64) ([]WebhookReplayResult, error) {
+	if err := godotenv.Load("app.env"); err != nil {
+		log.Printf("Warning: Error loading app.env file: %v", err)
+	}
+
+	container := dig.New()
+
+	InitMods(container)
+
+	results := make([]WebhookReplayResult, 0, len(ids))
+	err := container.Invoke(func(webhooks billingServices.WebhookService) {
+		for _, id := range ids {
+			result := WebhookReplayResult{ID: id}
+			replayed, err := webhooks.ReplayWebhook(ctx, id)
+			if err != nil {
+				result.Error = err.Error()
+			} else {
+				result.WebhookID = replayed.WebhookID
+				result.EventType = replayed.EventType
+			}
+			results = append(results, result)
+		}
+	})
+
+	// Drain anything the billing service published asynchronously
+	if closeErr := eventbusCmd.Close(container); closeErr != nil {
+		log.Printf("Warning: %v", closeErr)
+	}
+
+	return results, err
+}
@@ -6,6 +6,9 @@ import (
 	"github.com/joho/godotenv"
 	"go.uber.org/dig"
 
+	billing "github.com/moasq/go-b2b-starter/internal/modules/billing/cmd"
+	webhooks "github.com/moasq/go-b2b-starter/internal/modules/webhooks/cmd"
+	eventbus "github.com/moasq/go-b2b-starter/internal/platform/eventbus/cmd"
 	server "github.com/moasq/go-b2b-starter/internal/platform/server/domain"
 )
 
@@ -18,6 +21,22 @@ func Execute() {
 
 	InitMods(container)
 
+	// Start event bus delivery (outbox relay, stream consumer) once all
+	// subscribers are registered
+	if err := eventbus.Start(container); err != nil {
+		panic(err)
+	}
+
+	// Start sending queued customer webhooks
+	if err := webhooks.Start(container); err != nil {
+		panic(err)
+	}
+
+	// Start the billing jobs: reconciliation and quota period rollover
+	if err := billing.Start(container); err != nil {
+		panic(err)
+	}
+
 	var srv server.Server
 
 	if err := container.Invoke(func(s server.Server) {
@@ -28,4 +47,18 @@ func Execute() {
 
 	srv.Start()
 
+	// Stop the billing jobs first, they publish events
+	if err := billing.Close(container); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+
+	// Drain in-flight events once the server stopped accepting requests
+	if err := eventbus.Close(container); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+
+	// Stop the webhook dispatcher after the bus, so drained events still get queued
+	if err := webhooks.Close(container); err != nil {
+		log.Printf("Warning: %v", err)
+	}
 }
@@ -14,14 +14,34 @@ type SubscriptionStore interface {
 	UpsertSubscription(ctx context.Context, arg db.UpsertSubscriptionParams) (db.SubscriptionBillingSubscription, error)
 	DeleteSubscription(ctx context.Context, organizationID int32) error
 	ListActiveSubscriptions(ctx context.Context) ([]db.SubscriptionBillingSubscription, error)
+	ListSubscriptionsAfterID(ctx context.Context, arg db.ListSubscriptionsAfterIDParams) ([]db.SubscriptionBillingSubscription, error)
+	ListPastDueSubscriptionsAfterID(ctx context.Context, arg db.ListPastDueSubscriptionsAfterIDParams) ([]db.SubscriptionBillingSubscription, error)
+	SetSubscriptionDunningPhase(ctx context.Context, arg db.SetSubscriptionDunningPhaseParams) (db.SubscriptionBillingSubscription, error)
 
 	// Quota operations
 	GetQuotaByOrgID(ctx context.Context, organizationID int32) (db.SubscriptionBillingQuotaTracking, error)
 	UpsertQuota(ctx context.Context, arg db.UpsertQuotaParams) (db.SubscriptionBillingQuotaTracking, error)
-	DecrementInvoiceCount(ctx context.Context, organizationID int32) (db.SubscriptionBillingQuotaTracking, error)
 	ResetQuotaForPeriod(ctx context.Context, arg db.ResetQuotaForPeriodParams) (db.SubscriptionBillingQuotaTracking, error)
+	ListQuotasDueForRollover(ctx context.Context, arg db.ListQuotasDueForRolloverParams) ([]db.SubscriptionBillingQuotaTracking, error)
+	SetQuotaSeatGrace(ctx context.Context, arg db.SetQuotaSeatGraceParams) (db.SubscriptionBillingQuotaTracking, error)
 
 	// Combined operations
 	GetQuotaStatus(ctx context.Context, organizationID int32) (db.GetQuotaStatusRow, error)
-	ListQuotasNearLimit(ctx context.Context, threshold int32) ([]db.ListQuotasNearLimitRow, error)
+
+	// Meter quota operations
+	GetMeterQuota(ctx context.Context, arg db.GetMeterQuotaParams) (db.SubscriptionBillingMeterQuota, error)
+	ListMeterQuotasByOrgID(ctx context.Context, organizationID int32) ([]db.SubscriptionBillingMeterQuota, error)
+	ResetMeterQuotasForPeriod(ctx context.Context, arg db.ResetMeterQuotasForPeriodParams) ([]db.SubscriptionBillingMeterQuota, error)
+	ConsumeMeterQuota(ctx context.Context, arg db.ConsumeMeterQuotaParams) (db.ConsumeMeterQuotaRow, error)
+	ListMeterQuotasNearLimit(ctx context.Context, arg db.ListMeterQuotasNearLimitParams) ([]db.ListMeterQuotasNearLimitRow, error)
+
+	// Quota reservation operations
+	ReserveMeterQuota(ctx context.Context, arg db.ReserveMeterQuotaParams) (db.ReserveMeterQuotaRow, error)
+	CommitQuotaReservation(ctx context.Context, arg db.CommitQuotaReservationParams) (db.CommitQuotaReservationRow, error)
+	ReleaseQuotaReservation(ctx context.Context, id int64) (int64, error)
+	ReleaseExpiredQuotaReservations(ctx context.Context, arg db.ReleaseExpiredQuotaReservationsParams) (int64, error)
+
+	// Usage ledger operations
+	SummarizeUsageLedgerByDay(ctx context.Context, arg db.SummarizeUsageLedgerByDayParams) ([]db.SummarizeUsageLedgerByDayRow, error)
+	ListUsageLedgerEntriesAfterID(ctx context.Context, arg db.ListUsageLedgerEntriesAfterIDParams) ([]db.ListUsageLedgerEntriesAfterIDRow, error)
 }
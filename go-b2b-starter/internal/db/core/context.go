@@ -0,0 +1,66 @@
+package core
+
+import (
+	"context"
+	"sync"
+)
+
+// txContextKey is the context key for the ambient transaction.
+type txContextKey struct{}
+
+// ContextWithTransaction returns a copy of ctx that carries tx.
+//
+// Code that receives this context (repositories, the outbox event bus) can
+// join the caller's transaction instead of opening its own.
+func ContextWithTransaction(ctx context.Context, tx Transaction) context.Context {
+	return context.WithValue(ctx, txContextKey{}, tx)
+}
+
+// TransactionFromContext returns the ambient transaction stored in ctx, if any.
+func TransactionFromContext(ctx context.Context) (Transaction, bool) {
+	tx, ok := ctx.Value(txContextKey{}).(Transaction)
+	return tx, ok && tx != nil
+}
+
+// afterCommitContextKey is the context key for the hooks of the ambient
+// transaction.
+type afterCommitContextKey struct{}
+
+// afterCommitHooks collects the functions to run once a transaction commits.
+type afterCommitHooks struct {
+	mu    sync.Mutex
+	hooks []func()
+}
+
+func (h *afterCommitHooks) add(fn func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.hooks = append(h.hooks, fn)
+}
+
+func (h *afterCommitHooks) run() {
+	h.mu.Lock()
+	hooks := h.hooks
+	h.hooks = nil
+	h.mu.Unlock()
+
+	for _, fn := range hooks {
+		fn()
+	}
+}
+
+// AfterCommit runs fn once the ambient transaction of ctx commits, or right
+// away when ctx carries no transaction started by WithTransaction. fn is
+// dropped if the transaction rolls back.
+//
+// Use it for side effects that must not be seen before the data they refer
+// to, such as publishing events to subscribers outside the transaction.
+func AfterCommit(ctx context.Context, fn func()) {
+	if _, ok := TransactionFromContext(ctx); ok {
+		if hooks, ok := ctx.Value(afterCommitContextKey{}).(*afterCommitHooks); ok {
+			hooks.add(fn)
+			return
+		}
+	}
+	fn()
+}
@@ -18,7 +18,16 @@ type TxFunc func(ctx context.Context, tx Transaction) error
 
 // WithTransaction executes a function within a transaction
 // It automatically handles commit/rollback based on the function's return value
+//
+// The context passed to fn carries the transaction (see TransactionFromContext),
+// so nested calls to WithTransaction join the outer transaction instead of
+// opening a new one. Only the outermost call commits or rolls back, and runs
+// the functions registered with AfterCommit once the commit succeeds.
 func WithTransaction(ctx context.Context, pool Pool, fn TxFunc) error {
+	if tx, ok := TransactionFromContext(ctx); ok {
+		return fn(ctx, tx)
+	}
+
 	tx, err := pool.BeginTx(ctx)
 	if err != nil {
 		return err
@@ -31,7 +40,9 @@ func WithTransaction(ctx context.Context, pool Pool, fn TxFunc) error {
 		}
 	}()
 	
-	if err := fn(ctx, tx); err != nil {
+	hooks := &afterCommitHooks{}
+	txCtx := context.WithValue(ContextWithTransaction(ctx, tx), afterCommitContextKey{}, hooks)
+	if err := fn(txCtx, tx); err != nil {
 		if rbErr := tx.Rollback(ctx); rbErr != nil {
 			return ErrTxRollbackFailed{
 				OriginalErr: err,
@@ -44,6 +55,7 @@ func WithTransaction(ctx context.Context, pool Pool, fn TxFunc) error {
 	if err := tx.Commit(ctx); err != nil {
 		return ErrTxCommitFailed{Err: err}
 	}
+	hooks.run()
 	
 	return nil
 }
\ No newline at end of file
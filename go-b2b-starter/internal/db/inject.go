@@ -15,6 +15,7 @@ import (
 	documentDomain "github.com/moasq/go-b2b-starter/internal/modules/documents/domain"
 	fileDomain "github.com/moasq/go-b2b-starter/internal/modules/files/domain"
 	orgDomain "github.com/moasq/go-b2b-starter/internal/modules/organizations/domain"
+	webhookDomain "github.com/moasq/go-b2b-starter/internal/modules/webhooks/domain"
 
 	// Repository implementations from module infra layers
 	billingRepos "github.com/moasq/go-b2b-starter/internal/modules/billing/infra/repositories"
@@ -22,9 +23,11 @@ import (
 	documentRepos "github.com/moasq/go-b2b-starter/internal/modules/documents/infra/repositories"
 	fileInfra "github.com/moasq/go-b2b-starter/internal/modules/files/infra"
 	orgRepos "github.com/moasq/go-b2b-starter/internal/modules/organizations/infra/repositories"
+	webhookRepos "github.com/moasq/go-b2b-starter/internal/modules/webhooks/infra/repositories"
 
 	// Legacy adapters - kept temporarily for backward compatibility
 	"github.com/moasq/go-b2b-starter/internal/db/adapters"
+	"github.com/moasq/go-b2b-starter/internal/db/core"
 	"github.com/moasq/go-b2b-starter/internal/db/postgres"
 	adapterImpl "github.com/moasq/go-b2b-starter/internal/db/postgres/adapter_impl"
 	sqlc "github.com/moasq/go-b2b-starter/internal/db/postgres/sqlc/gen"
@@ -47,6 +50,11 @@ func Inject(container *dig.Container) error {
 		return fmt.Errorf("failed to provide SQLC store: %w", err)
 	}
 
+	// Register core.Pool for packages that run raw SQL/transactions without sqlc
+	if err := container.Provide(provideCorePool); err != nil {
+		return fmt.Errorf("failed to provide core pool: %w", err)
+	}
+
 	// Register *sql.DB for modules that need standard database/sql interface
 	if err := container.Provide(provideSQLDB); err != nil {
 		return fmt.Errorf("failed to provide SQL DB: %w", err)
@@ -75,6 +83,11 @@ func provideSQLCStore(pool *pgxpool.Pool) sqlc.Store {
 	return sqlc.NewStore(pool)
 }
 
+// provideCorePool wraps the pgx pool in the driver-agnostic core.Pool interface
+func provideCorePool(pool *pgxpool.Pool) core.Pool {
+	return postgres.NewCorePool(pool)
+}
+
 // provideSQLDB creates a *sql.DB from the pgxpool for compatibility
 func provideSQLDB(pool *pgxpool.Pool) *sql.DB {
 	// Use pgx stdlib to create a sql.DB from the pool connection string
@@ -123,6 +136,13 @@ func registerDomainStores(container *dig.Container) error {
 		return fmt.Errorf("failed to provide subscription repository: %w", err)
 	}
 
+	// Register WebhookRepository - implements billing/domain.WebhookRepository
+	if err := container.Provide(func(sqlcStore sqlc.Store) billingDomain.WebhookRepository {
+		return billingRepos.NewWebhookRepository(sqlcStore)
+	}); err != nil {
+		return fmt.Errorf("failed to provide billing webhook repository: %w", err)
+	}
+
 	// Register EmbeddingRepository - implements cognitive/domain.EmbeddingRepository
 	if err := container.Provide(func(sqlcStore sqlc.Store) cognitiveDomain.EmbeddingRepository {
 		return cognitiveRepos.NewEmbeddingRepository(sqlcStore)
@@ -144,6 +164,20 @@ func registerDomainStores(container *dig.Container) error {
 		return fmt.Errorf("failed to provide file metadata repository: %w", err)
 	}
 
+	// Register EndpointRepository - implements webhooks/domain.EndpointRepository
+	if err := container.Provide(func(sqlcStore sqlc.Store) webhookDomain.EndpointRepository {
+		return webhookRepos.NewEndpointRepository(sqlcStore)
+	}); err != nil {
+		return fmt.Errorf("failed to provide webhook endpoint repository: %w", err)
+	}
+
+	// Register DeliveryRepository - implements webhooks/domain.DeliveryRepository
+	if err := container.Provide(func(sqlcStore sqlc.Store) webhookDomain.DeliveryRepository {
+		return webhookRepos.NewDeliveryRepository(sqlcStore)
+	}); err != nil {
+		return fmt.Errorf("failed to provide webhook delivery repository: %w", err)
+	}
+
 	// ============================================
 	// LEGACY: Adapter stores (kept for backward compatibility)
 	// TODO: Migrate callers to use domain interfaces, then remove these
@@ -38,6 +38,18 @@ func (s *subscriptionStore) ListActiveSubscriptions(ctx context.Context) ([]sqlc
 	return s.store.ListActiveSubscriptions(ctx)
 }
 
+func (s *subscriptionStore) ListSubscriptionsAfterID(ctx context.Context, arg sqlc.ListSubscriptionsAfterIDParams) ([]sqlc.SubscriptionBillingSubscription, error) {
+	return s.store.ListSubscriptionsAfterID(ctx, arg)
+}
+
+func (s *subscriptionStore) ListPastDueSubscriptionsAfterID(ctx context.Context, arg sqlc.ListPastDueSubscriptionsAfterIDParams) ([]sqlc.SubscriptionBillingSubscription, error) {
+	return s.store.ListPastDueSubscriptionsAfterID(ctx, arg)
+}
+
+func (s *subscriptionStore) SetSubscriptionDunningPhase(ctx context.Context, arg sqlc.SetSubscriptionDunningPhaseParams) (sqlc.SubscriptionBillingSubscription, error) {
+	return s.store.SetSubscriptionDunningPhase(ctx, arg)
+}
+
 // Quota operations
 
 func (s *subscriptionStore) GetQuotaByOrgID(ctx context.Context, organizationID int32) (sqlc.SubscriptionBillingQuotaTracking, error) {
@@ -48,20 +60,70 @@ func (s *subscriptionStore) UpsertQuota(ctx context.Context, arg sqlc.UpsertQuot
 	return s.store.UpsertQuota(ctx, arg)
 }
 
-func (s *subscriptionStore) DecrementInvoiceCount(ctx context.Context, organizationID int32) (sqlc.SubscriptionBillingQuotaTracking, error) {
-	return s.store.DecrementInvoiceCount(ctx, organizationID)
-}
-
 func (s *subscriptionStore) ResetQuotaForPeriod(ctx context.Context, arg sqlc.ResetQuotaForPeriodParams) (sqlc.SubscriptionBillingQuotaTracking, error) {
 	return s.store.ResetQuotaForPeriod(ctx, arg)
 }
 
+func (s *subscriptionStore) ListQuotasDueForRollover(ctx context.Context, arg sqlc.ListQuotasDueForRolloverParams) ([]sqlc.SubscriptionBillingQuotaTracking, error) {
+	return s.store.ListQuotasDueForRollover(ctx, arg)
+}
+
+func (s *subscriptionStore) SetQuotaSeatGrace(ctx context.Context, arg sqlc.SetQuotaSeatGraceParams) (sqlc.SubscriptionBillingQuotaTracking, error) {
+	return s.store.SetQuotaSeatGrace(ctx, arg)
+}
+
 // Combined operations
 
 func (s *subscriptionStore) GetQuotaStatus(ctx context.Context, organizationID int32) (sqlc.GetQuotaStatusRow, error) {
 	return s.store.GetQuotaStatus(ctx, organizationID)
 }
 
-func (s *subscriptionStore) ListQuotasNearLimit(ctx context.Context, threshold int32) ([]sqlc.ListQuotasNearLimitRow, error) {
-	return s.store.ListQuotasNearLimit(ctx, threshold)
+// Meter quota operations
+
+func (s *subscriptionStore) GetMeterQuota(ctx context.Context, arg sqlc.GetMeterQuotaParams) (sqlc.SubscriptionBillingMeterQuota, error) {
+	return s.store.GetMeterQuota(ctx, arg)
+}
+
+func (s *subscriptionStore) ListMeterQuotasByOrgID(ctx context.Context, organizationID int32) ([]sqlc.SubscriptionBillingMeterQuota, error) {
+	return s.store.ListMeterQuotasByOrgID(ctx, organizationID)
+}
+
+func (s *subscriptionStore) ResetMeterQuotasForPeriod(ctx context.Context, arg sqlc.ResetMeterQuotasForPeriodParams) ([]sqlc.SubscriptionBillingMeterQuota, error) {
+	return s.store.ResetMeterQuotasForPeriod(ctx, arg)
+}
+
+func (s *subscriptionStore) ConsumeMeterQuota(ctx context.Context, arg sqlc.ConsumeMeterQuotaParams) (sqlc.ConsumeMeterQuotaRow, error) {
+	return s.store.ConsumeMeterQuota(ctx, arg)
+}
+
+func (s *subscriptionStore) ListMeterQuotasNearLimit(ctx context.Context, arg sqlc.ListMeterQuotasNearLimitParams) ([]sqlc.ListMeterQuotasNearLimitRow, error) {
+	return s.store.ListMeterQuotasNearLimit(ctx, arg)
+}
+
+// Quota reservation operations
+
+func (s *subscriptionStore) ReserveMeterQuota(ctx context.Context, arg sqlc.ReserveMeterQuotaParams) (sqlc.ReserveMeterQuotaRow, error) {
+	return s.store.ReserveMeterQuota(ctx, arg)
+}
+
+func (s *subscriptionStore) CommitQuotaReservation(ctx context.Context, arg sqlc.CommitQuotaReservationParams) (sqlc.CommitQuotaReservationRow, error) {
+	return s.store.CommitQuotaReservation(ctx, arg)
+}
+
+func (s *subscriptionStore) ReleaseQuotaReservation(ctx context.Context, id int64) (int64, error) {
+	return s.store.ReleaseQuotaReservation(ctx, id)
+}
+
+func (s *subscriptionStore) ReleaseExpiredQuotaReservations(ctx context.Context, arg sqlc.ReleaseExpiredQuotaReservationsParams) (int64, error) {
+	return s.store.ReleaseExpiredQuotaReservations(ctx, arg)
+}
+
+// Usage ledger operations
+
+func (s *subscriptionStore) SummarizeUsageLedgerByDay(ctx context.Context, arg sqlc.SummarizeUsageLedgerByDayParams) ([]sqlc.SummarizeUsageLedgerByDayRow, error) {
+	return s.store.SummarizeUsageLedgerByDay(ctx, arg)
+}
+
+func (s *subscriptionStore) ListUsageLedgerEntriesAfterID(ctx context.Context, arg sqlc.ListUsageLedgerEntriesAfterIDParams) ([]sqlc.ListUsageLedgerEntriesAfterIDRow, error) {
+	return s.store.ListUsageLedgerEntriesAfterID(ctx, arg)
 }
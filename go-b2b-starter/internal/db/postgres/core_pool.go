@@ -0,0 +1,181 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/moasq/go-b2b-starter/internal/db/core"
+)
+
+// Ensure the pgx adapters implement the core interfaces at compile time
+var (
+	_ core.Pool        = (*corePool)(nil)
+	_ core.Transaction = (*coreTx)(nil)
+)
+
+// corePool adapts a pgxpool.Pool to the driver-agnostic core.Pool interface.
+// It lets packages that only know about core (e.g. the outbox event bus)
+// run raw SQL and transactions without importing pgx.
+type corePool struct {
+	pool *pgxpool.Pool
+}
+
+// NewCorePool wraps an existing pgx pool. The pool is shared, not owned:
+// closing the returned core.Pool is a no-op so the DI-managed pool stays usable.
+func NewCorePool(pool *pgxpool.Pool) core.Pool {
+	return &corePool{pool: pool}
+}
+
+func (p *corePool) Execute(ctx context.Context, query string, args ...any) error {
+	_, err := p.pool.Exec(ctx, query, args...)
+	return err
+}
+
+func (p *corePool) Query(ctx context.Context, query string, args ...any) (core.Rows, error) {
+	rows, err := p.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &coreRows{rows: rows}, nil
+}
+
+func (p *corePool) QueryRow(ctx context.Context, query string, args ...any) core.Row {
+	return &coreRow{row: p.pool.QueryRow(ctx, query, args...)}
+}
+
+func (p *corePool) BeginTx(ctx context.Context) (core.Transaction, error) {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &coreTx{tx: tx}, nil
+}
+
+func (p *corePool) Ping(ctx context.Context) error {
+	return p.pool.Ping(ctx)
+}
+
+func (p *corePool) Close() error {
+	return nil
+}
+
+func (p *corePool) Stats() core.PoolStats {
+	stat := p.pool.Stat()
+	return core.PoolStats{
+		TotalConnections:    int(stat.TotalConns()),
+		IdleConnections:     int(stat.IdleConns()),
+		AcquiredConnections: int(stat.AcquiredConns()),
+		MaxConnections:      int(stat.MaxConns()),
+	}
+}
+
+// pgxpool limits are fixed when the pool is created (see connPool), so the
+// setters below are intentionally no-ops. Configure limits through Config.
+func (p *corePool) SetMaxConnections(n int)                  {}
+func (p *corePool) SetMaxConnectionLifetime(d time.Duration) {}
+func (p *corePool) SetMaxConnectionIdleTime(d time.Duration) {}
+
+// coreTx adapts a pgx.Tx to core.Transaction.
+type coreTx struct {
+	tx pgx.Tx
+}
+
+// PgxTx returns the pgx transaction behind a core.Transaction started by a
+// core pool, so sqlc queries can join it (see Store.WithTx).
+func PgxTx(tx core.Transaction) (pgx.Tx, bool) {
+	t, ok := tx.(*coreTx)
+	if !ok {
+		return nil, false
+	}
+	return t.tx, true
+}
+
+func (t *coreTx) Execute(ctx context.Context, query string, args ...any) error {
+	_, err := t.tx.Exec(ctx, query, args...)
+	return mapTxError(err)
+}
+
+func (t *coreTx) Query(ctx context.Context, query string, args ...any) (core.Rows, error) {
+	rows, err := t.tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, mapTxError(err)
+	}
+	return &coreRows{rows: rows}, nil
+}
+
+func (t *coreTx) QueryRow(ctx context.Context, query string, args ...any) core.Row {
+	return &coreRow{row: t.tx.QueryRow(ctx, query, args...)}
+}
+
+// BeginTx starts a savepoint-backed nested transaction.
+func (t *coreTx) BeginTx(ctx context.Context) (core.Transaction, error) {
+	tx, err := t.tx.Begin(ctx)
+	if err != nil {
+		return nil, mapTxError(err)
+	}
+	return &coreTx{tx: tx}, nil
+}
+
+func (t *coreTx) Ping(ctx context.Context) error {
+	return t.tx.Conn().Ping(ctx)
+}
+
+func (t *coreTx) Close() error {
+	return nil
+}
+
+func (t *coreTx) Commit(ctx context.Context) error {
+	return mapTxError(t.tx.Commit(ctx))
+}
+
+func (t *coreTx) Rollback(ctx context.Context) error {
+	return mapTxError(t.tx.Rollback(ctx))
+}
+
+// coreRows adapts pgx.Rows to core.Rows.
+type coreRows struct {
+	rows pgx.Rows
+}
+
+func (r *coreRows) Next() bool {
+	return r.rows.Next()
+}
+
+func (r *coreRows) Scan(dest ...any) error {
+	return r.rows.Scan(dest...)
+}
+
+func (r *coreRows) Close() error {
+	r.rows.Close()
+	return r.rows.Err()
+}
+
+func (r *coreRows) Err() error {
+	return r.rows.Err()
+}
+
+// coreRow adapts pgx.Row to core.Row, translating pgx.ErrNoRows to core.ErrNoRows.
+type coreRow struct {
+	row pgx.Row
+}
+
+func (r *coreRow) Scan(dest ...any) error {
+	if err := r.row.Scan(dest...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return core.ErrNoRows
+		}
+		return err
+	}
+	return nil
+}
+
+func mapTxError(err error) error {
+	if errors.Is(err, pgx.ErrTxClosed) {
+		return core.ErrTxClosed
+	}
+	return err
+}
@@ -82,6 +82,54 @@ type DuplicateCandidate struct {
 	UpdatedAt        pgtype.Timestamp `json:"updated_at"`
 }
 
+// Events that exhausted a handler retry policy, kept for inspection, replay or discard
+type EventbusDeadLetter struct {
+	ID        int64  `json:"id"`
+	EventID   string `json:"event_id"`
+	EventName string `json:"event_name"`
+	// Name the handler was subscribed under; replay targets only this handler
+	HandlerName string           `json:"handler_name"`
+	Payload     []byte           `json:"payload"`
+	Metadata    []byte           `json:"metadata"`
+	Attempts    int32            `json:"attempts"`
+	LastError   string           `json:"last_error"`
+	FailedAt    pgtype.Timestamp `json:"failed_at"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+}
+
+// Append-only store of every published event, used for audit and replay
+type EventbusEvent struct {
+	ID           int64       `json:"id"`
+	EventID      string      `json:"event_id"`
+	EventName    string      `json:"event_name"`
+	PartitionKey pgtype.Text `json:"partition_key"`
+	Payload      []byte      `json:"payload"`
+	Metadata     []byte      `json:"metadata"`
+	// Event creation time (BaseEvent.CreatedAt); replay ranges filter on this column
+	OccurredAt pgtype.Timestamp `json:"occurred_at"`
+	RecordedAt pgtype.Timestamp `json:"recorded_at"`
+}
+
+// Transactional outbox of domain events awaiting dispatch to subscribers
+type EventbusOutbox struct {
+	ID            int64            `json:"id"`
+	EventID       string           `json:"event_id"`
+	EventName     string           `json:"event_name"`
+	Payload       []byte           `json:"payload"`
+	Metadata      []byte           `json:"metadata"`
+	OccurredAt    pgtype.Timestamp `json:"occurred_at"`
+	Attempts      int32            `json:"attempts"`
+	LastError     pgtype.Text      `json:"last_error"`
+	NextAttemptAt pgtype.Timestamp `json:"next_attempt_at"`
+	// Relay lease expiry; rows with an expired lease are reclaimed by another worker
+	LockedUntil  pgtype.Timestamp `json:"locked_until"`
+	DispatchedAt pgtype.Timestamp `json:"dispatched_at"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	// Events with the same key are dispatched one at a time in id order
+	PartitionKey pgtype.Text `json:"partition_key"`
+}
+
 // Example module demonstrating Clean Architecture patterns with file uploads, OCR/LLM processing, RBAC, approval workflows, and multi-tenancy
 type ExampleResource struct {
 	ID             int32       `json:"id"`
@@ -192,6 +240,36 @@ type ResourceEmbedding struct {
 	UpdatedAt      pgtype.Timestamp `json:"updated_at"`
 }
 
+// Usage allowance and consumption per organization and meter for the current period
+type SubscriptionBillingMeterQuota struct {
+	ID             int32  `json:"id"`
+	OrganizationID int32  `json:"organization_id"`
+	MeterSlug      string `json:"meter_slug"`
+	// Units allowed in the period, from product metadata or provider meter grants
+	Allowance int64 `json:"allowance"`
+	// Units consumed in the period; reset when a new period starts
+	Consumed     int64            `json:"consumed"`
+	PeriodStart  pgtype.Timestamp `json:"period_start"`
+	PeriodEnd    pgtype.Timestamp `json:"period_end"`
+	LastSyncedAt pgtype.Timestamp `json:"last_synced_at"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+	// Units held by open quota reservations, not yet consumed
+	Reserved int64 `json:"reserved"`
+}
+
+// Open quota reservations; deleted when committed or released
+type SubscriptionBillingQuotaReservation struct {
+	ID             int64            `json:"id"`
+	OrganizationID int32            `json:"organization_id"`
+	MeterSlug      string           `json:"meter_slug"`
+	Amount         int64            `json:"amount"`
+	AccountID      pgtype.Int4      `json:"account_id"`
+	Reference      pgtype.Text      `json:"reference"`
+	ExpiresAt      pgtype.Timestamp `json:"expires_at"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+}
+
 // Tracks usage quotas per organization for fast quota checks
 type SubscriptionBillingQuotaTracking struct {
 	ID             int32            `json:"id"`
@@ -202,8 +280,8 @@ type SubscriptionBillingQuotaTracking struct {
 	LastSyncedAt   pgtype.Timestamp `json:"last_synced_at"`
 	CreatedAt      pgtype.Timestamp `json:"created_at"`
 	UpdatedAt      pgtype.Timestamp `json:"updated_at"`
-	// Remaining invoices in current billing period (decremented on use)
-	InvoiceCount int32 `json:"invoice_count"`
+	// End of the grace period for an organization over its seat limit, NULL when within the limit
+	SeatGraceEndsAt pgtype.Timestamp `json:"seat_grace_ends_at"`
 }
 
 // Stores subscription details from Polar, synced via webhooks
@@ -224,4 +302,86 @@ type SubscriptionBillingSubscription struct {
 	CreatedAt          pgtype.Timestamp `json:"created_at"`
 	UpdatedAt          pgtype.Timestamp `json:"updated_at"`
 	Metadata           []byte           `json:"metadata"`
+	// When the subscription became past_due, NULL in any other status
+	PastDueSince pgtype.Timestamp `json:"past_due_since"`
+	// Last dunning phase announced by a reminder event, NULL when not past_due
+	DunningPhase pgtype.Text `json:"dunning_phase"`
+}
+
+// Append-only record of every quota consumption
+type SubscriptionBillingUsageLedger struct {
+	ID             int64       `json:"id"`
+	OrganizationID int32       `json:"organization_id"`
+	MeterSlug      string      `json:"meter_slug"`
+	Amount         int64       `json:"amount"`
+	AccountID      pgtype.Int4 `json:"account_id"`
+	// Optional idempotency key, unique per organization and meter
+	Reference   pgtype.Text      `json:"reference"`
+	PeriodStart pgtype.Timestamp `json:"period_start"`
+	PeriodEnd   pgtype.Timestamp `json:"period_end"`
+	Metadata    []byte           `json:"metadata"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+}
+
+// Inbound billing provider webhooks, one row per webhook ID
+type SubscriptionBillingWebhookEvent struct {
+	ID        int64  `json:"id"`
+	Provider  string `json:"provider"`
+	WebhookID string `json:"webhook_id"`
+	EventType string `json:"event_type"`
+	// Raw request body, kept for auditing and replay
+	Payload []byte `json:"payload"`
+	// Processing status: processing, processed, failed
+	Status      string           `json:"status"`
+	Attempts    int32            `json:"attempts"`
+	LastError   pgtype.Text      `json:"last_error"`
+	ReceivedAt  pgtype.Timestamp `json:"received_at"`
+	ProcessedAt pgtype.Timestamp `json:"processed_at"`
+	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+}
+
+// Outbound webhook messages and their delivery state
+type WebhooksDelivery struct {
+	ID             int64  `json:"id"`
+	EndpointID     int32  `json:"endpoint_id"`
+	OrganizationID int32  `json:"organization_id"`
+	EventID        string `json:"event_id"`
+	EventType      string `json:"event_type"`
+	Payload        []byte `json:"payload"`
+	// Delivery status: pending, succeeded, failed
+	Status         string           `json:"status"`
+	Attempts       int32            `json:"attempts"`
+	NextAttemptAt  pgtype.Timestamp `json:"next_attempt_at"`
+	LockedUntil    pgtype.Timestamp `json:"locked_until"`
+	LastStatusCode pgtype.Int4      `json:"last_status_code"`
+	LastError      pgtype.Text      `json:"last_error"`
+	DeliveredAt    pgtype.Timestamp `json:"delivered_at"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+	UpdatedAt      pgtype.Timestamp `json:"updated_at"`
+}
+
+// Log of HTTP attempts made for each webhook delivery
+type WebhooksDeliveryAttempt struct {
+	ID         int64            `json:"id"`
+	DeliveryID int64            `json:"delivery_id"`
+	Attempt    int32            `json:"attempt"`
+	StatusCode pgtype.Int4      `json:"status_code"`
+	Error      pgtype.Text      `json:"error"`
+	DurationMs int32            `json:"duration_ms"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+}
+
+// Customer webhook endpoints, per organization
+type WebhooksEndpoint struct {
+	ID             int32  `json:"id"`
+	OrganizationID int32  `json:"organization_id"`
+	Url            string `json:"url"`
+	Description    string `json:"description"`
+	// Event types delivered to the endpoint; empty means all
+	EventTypes []string `json:"event_types"`
+	// Signing secret: whsec_ followed by the base64-encoded HMAC-SHA256 key of the Standard Webhooks signature
+	Secret    string           `json:"secret"`
+	Enabled   bool             `json:"enabled"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
 }
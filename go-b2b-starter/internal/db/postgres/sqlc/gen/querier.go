@@ -16,6 +16,24 @@ type Querier interface {
 	// Attach a file to a resource
 	AttachFileToResource(ctx context.Context, arg AttachFileToResourceParams) error
 	CheckAccountPermission(ctx context.Context, arg CheckAccountPermissionParams) (CheckAccountPermissionRow, error)
+	// Record a webhook for processing. Returns no row when the webhook was
+	// already processed or another delivery of it is still being processed;
+	// failed or abandoned attempts are claimed again.
+	ClaimBillingWebhookEvent(ctx context.Context, arg ClaimBillingWebhookEventParams) (SubscriptionBillingWebhookEvent, error)
+	// Lease due deliveries to this worker; expired leases are reclaimed
+	ClaimDueWebhookDeliveries(ctx context.Context, arg ClaimDueWebhookDeliveriesParams) ([]WebhooksDelivery, error)
+	// Zero the allowance of meters no longer granted by the organization's product
+	ClearMeterQuotaAllowancesExcept(ctx context.Context, arg ClearMeterQuotaAllowancesExceptParams) error
+	// Consume the units of a reservation and append them to the usage ledger,
+	// under the given reference or else the one given when reserving. Returns no
+	// row when the reservation is gone (released or expired); a repeated
+	// reference fails with a unique violation and commits nothing.
+	CommitQuotaReservation(ctx context.Context, arg CommitQuotaReservationParams) (CommitQuotaReservationRow, error)
+	// Atomically consume units of a meter and append the usage to the ledger.
+	// Units held by open reservations are not available. Returns no row when the
+	// meter is missing or the allowance would be exceeded; a repeated reference
+	// fails with a unique violation and consumes nothing.
+	ConsumeMeterQuota(ctx context.Context, arg ConsumeMeterQuotaParams) (ConsumeMeterQuotaRow, error)
 	CountChatMessagesBySession(ctx context.Context, sessionID int32) (int64, error)
 	CountDocumentEmbeddingsByOrganization(ctx context.Context, organizationID int32) (int64, error)
 	CountDocumentsByOrganization(ctx context.Context, organizationID int32) (int64, error)
@@ -42,8 +60,10 @@ type Querier interface {
 	// file attachments, OCR/LLM processing, and approval workflows
 	// CREATE operations
 	CreateResource(ctx context.Context, arg CreateResourceParams) (ExampleResource, error)
-	// Decrement invoice count by 1 (called after successful invoice processing)
-	DecrementInvoiceCount(ctx context.Context, organizationID int32) (SubscriptionBillingQuotaTracking, error)
+	// Queue a delivery; an event is delivered to an endpoint only once
+	CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) error
+	CreateWebhookDeliveryAttempt(ctx context.Context, arg CreateWebhookDeliveryAttemptParams) error
+	CreateWebhookEndpoint(ctx context.Context, arg CreateWebhookEndpointParams) (WebhooksEndpoint, error)
 	DeleteAccount(ctx context.Context, arg DeleteAccountParams) error
 	DeleteChatMessage(ctx context.Context, id int32) error
 	DeleteChatSession(ctx context.Context, arg DeleteChatSessionParams) error
@@ -56,10 +76,12 @@ type Querier interface {
 	DeleteResource(ctx context.Context, arg DeleteResourceParams) error
 	// Delete subscription (when subscription is permanently deleted)
 	DeleteSubscription(ctx context.Context, organizationID int32) error
+	DeleteWebhookEndpoint(ctx context.Context, arg DeleteWebhookEndpointParams) error
 	GetAccountByEmail(ctx context.Context, arg GetAccountByEmailParams) (OrganizationsAccount, error)
 	GetAccountByID(ctx context.Context, arg GetAccountByIDParams) (OrganizationsAccount, error)
 	GetAccountOrganization(ctx context.Context, id int32) (OrganizationsOrganization, error)
 	GetAccountStats(ctx context.Context, id int32) (GetAccountStatsRow, error)
+	GetBillingWebhookEventByID(ctx context.Context, id int64) (SubscriptionBillingWebhookEvent, error)
 	GetChatMessagesBySession(ctx context.Context, sessionID int32) ([]CognitiveChatMessage, error)
 	GetChatSessionByID(ctx context.Context, arg GetChatSessionByIDParams) (CognitiveChatSession, error)
 	GetDocumentByFileAssetID(ctx context.Context, arg GetDocumentByFileAssetIDParams) (DocumentsDocument, error)
@@ -74,6 +96,8 @@ type Querier interface {
 	GetFileAssetsByEntityAndPurpose(ctx context.Context, arg GetFileAssetsByEntityAndPurposeParams) ([]FileManagerFileAsset, error)
 	GetFileCategories(ctx context.Context) ([]FileManagerFileCategory, error)
 	GetFileContexts(ctx context.Context) ([]FileManagerFileContext, error)
+	// Get the quota of one meter for an organization
+	GetMeterQuota(ctx context.Context, arg GetMeterQuotaParams) (SubscriptionBillingMeterQuota, error)
 	GetOrganizationByID(ctx context.Context, id int32) (OrganizationsOrganization, error)
 	GetOrganizationBySlug(ctx context.Context, slug string) (OrganizationsOrganization, error)
 	GetOrganizationByStytchID(ctx context.Context, stytchOrgID pgtype.Text) (OrganizationsOrganization, error)
@@ -100,6 +124,8 @@ type Querier interface {
 	GetSubscriptionByOrgID(ctx context.Context, organizationID int32) (SubscriptionBillingSubscription, error)
 	// Get subscription by Polar subscription ID
 	GetSubscriptionBySubscriptionID(ctx context.Context, subscriptionID string) (SubscriptionBillingSubscription, error)
+	GetWebhookDeliveryByID(ctx context.Context, arg GetWebhookDeliveryByIDParams) (WebhooksDelivery, error)
+	GetWebhookEndpointByID(ctx context.Context, arg GetWebhookEndpointByIDParams) (WebhooksEndpoint, error)
 	// Hard delete a resource (use with caution)
 	HardDeleteResource(ctx context.Context, arg HardDeleteResourceParams) error
 	ListAccountsByOrganization(ctx context.Context, organizationID int32) ([]OrganizationsAccount, error)
@@ -109,17 +135,63 @@ type Querier interface {
 	ListDocumentsByOrganization(ctx context.Context, arg ListDocumentsByOrganizationParams) ([]DocumentsDocument, error)
 	ListDocumentsByStatus(ctx context.Context, arg ListDocumentsByStatusParams) ([]DocumentsDocument, error)
 	ListFileAssets(ctx context.Context, arg ListFileAssetsParams) ([]ListFileAssetsRow, error)
+	// List all meter quotas of an organization
+	ListMeterQuotasByOrgID(ctx context.Context, organizationID int32) ([]SubscriptionBillingMeterQuota, error)
+	// List active organizations with few units left on a meter (for alerting)
+	ListMeterQuotasNearLimit(ctx context.Context, arg ListMeterQuotasNearLimitParams) ([]ListMeterQuotasNearLimitRow, error)
 	ListOrganizations(ctx context.Context, arg ListOrganizationsParams) ([]OrganizationsOrganization, error)
-	// List organizations approaching their quota limit (for alerting)
-	ListQuotasNearLimit(ctx context.Context, invoiceCount int32) ([]ListQuotasNearLimitRow, error)
+	// Page through past_due subscriptions in id order for dunning reminders
+	ListPastDueSubscriptionsAfterID(ctx context.Context, arg ListPastDueSubscriptionsAfterIDParams) ([]SubscriptionBillingSubscription, error)
+	// Page through renewing organizations whose quota period has ended
+	ListQuotasDueForRollover(ctx context.Context, arg ListQuotasDueForRolloverParams) ([]SubscriptionBillingQuotaTracking, error)
 	// List resources with filtering and pagination
 	ListResources(ctx context.Context, arg ListResourcesParams) ([]ListResourcesRow, error)
-	// Reset quota counters for a new billing period
+	// Page through all subscriptions in id order for background reconciliation
+	ListSubscriptionsAfterID(ctx context.Context, arg ListSubscriptionsAfterIDParams) ([]SubscriptionBillingSubscription, error)
+	// Page through an organization's usage ledger entries in [from_time, to_time)
+	// in id order, optionally for one meter only (for CSV export)
+	ListUsageLedgerEntriesAfterID(ctx context.Context, arg ListUsageLedgerEntriesAfterIDParams) ([]ListUsageLedgerEntriesAfterIDRow, error)
+	ListWebhookDeliveriesByEndpoint(ctx context.Context, arg ListWebhookDeliveriesByEndpointParams) ([]WebhooksDelivery, error)
+	ListWebhookDeliveryAttempts(ctx context.Context, deliveryID int64) ([]WebhooksDeliveryAttempt, error)
+	ListWebhookEndpointsByOrganization(ctx context.Context, organizationID int32) ([]WebhooksEndpoint, error)
+	// Enabled endpoints of an organization that accept the event type
+	ListWebhookEndpointsForEvent(ctx context.Context, arg ListWebhookEndpointsForEventParams) ([]WebhooksEndpoint, error)
+	MarkBillingWebhookEventFailed(ctx context.Context, arg MarkBillingWebhookEventFailedParams) error
+	MarkBillingWebhookEventProcessed(ctx context.Context, id int64) error
+	// Record a failed attempt: status stays 'pending' with a retry delay, or becomes 'failed'
+	MarkWebhookDeliveryFailed(ctx context.Context, arg MarkWebhookDeliveryFailedParams) error
+	MarkWebhookDeliverySucceeded(ctx context.Context, arg MarkWebhookDeliverySucceededParams) error
+	// Release an organization's reservations of a meter that expired before now,
+	// e.g. left behind by a crashed process; returns how many were released
+	ReleaseExpiredQuotaReservations(ctx context.Context, arg ReleaseExpiredQuotaReservationsParams) (int64, error)
+	// Return the units of a reservation to its meter without consuming them;
+	// affects no row when the reservation is already gone
+	ReleaseQuotaReservation(ctx context.Context, id int64) (int64, error)
+	// Queue a delivery again for an immediate attempt
+	RequeueWebhookDelivery(ctx context.Context, arg RequeueWebhookDeliveryParams) (WebhooksDelivery, error)
+	// Atomically hold units of a meter for work in progress, until the
+	// reservation is committed or released. Returns no row when the meter is
+	// missing or the units not consumed or held elsewhere are insufficient.
+	ReserveMeterQuota(ctx context.Context, arg ReserveMeterQuotaParams) (ReserveMeterQuotaRow, error)
+	// Start a new billing period for every meter of an organization; meters
+	// already in that period keep their consumption, so a retry is harmless
+	ResetMeterQuotasForPeriod(ctx context.Context, arg ResetMeterQuotasForPeriodParams) ([]SubscriptionBillingMeterQuota, error)
+	// Move quota tracking to a new billing period
 	ResetQuotaForPeriod(ctx context.Context, arg ResetQuotaForPeriodParams) (SubscriptionBillingQuotaTracking, error)
 	// SEARCH operations
 	// Full-text search on title and description
 	SearchResourcesByText(ctx context.Context, arg SearchResourcesByTextParams) ([]SearchResourcesByTextRow, error)
 	SearchSimilarDocuments(ctx context.Context, arg SearchSimilarDocumentsParams) ([]SearchSimilarDocumentsRow, error)
+	// Set the remaining units of a meter (provider credit balance) keeping consumption
+	SetMeterQuotaRemaining(ctx context.Context, arg SetMeterQuotaRemainingParams) (SubscriptionBillingMeterQuota, error)
+	// Start or end the seat grace period of an organization over its seat limit
+	SetQuotaSeatGrace(ctx context.Context, arg SetQuotaSeatGraceParams) (SubscriptionBillingQuotaTracking, error)
+	// Record the dunning phase announced for a past_due subscription; returns no
+	// row when the phase was already recorded, so each phase is announced once
+	SetSubscriptionDunningPhase(ctx context.Context, arg SetSubscriptionDunningPhaseParams) (SubscriptionBillingSubscription, error)
+	// Sum an organization's usage in [from_time, to_time) per day, meter and
+	// account, optionally for one meter only
+	SummarizeUsageLedgerByDay(ctx context.Context, arg SummarizeUsageLedgerByDayParams) ([]SummarizeUsageLedgerByDayRow, error)
 	UpdateAccount(ctx context.Context, arg UpdateAccountParams) (OrganizationsAccount, error)
 	UpdateAccountLastLogin(ctx context.Context, arg UpdateAccountLastLoginParams) (OrganizationsAccount, error)
 	UpdateAccountStytchInfo(ctx context.Context, arg UpdateAccountStytchInfoParams) (OrganizationsAccount, error)
@@ -137,6 +209,10 @@ type Querier interface {
 	// Update OCR/LLM processing results
 	UpdateResourceProcessingData(ctx context.Context, arg UpdateResourceProcessingDataParams) error
 	UpdateResourceStatus(ctx context.Context, arg UpdateResourceStatusParams) error
+	UpdateWebhookEndpoint(ctx context.Context, arg UpdateWebhookEndpointParams) (WebhooksEndpoint, error)
+	UpdateWebhookEndpointSecret(ctx context.Context, arg UpdateWebhookEndpointSecretParams) (WebhooksEndpoint, error)
+	// Set the allowance of a meter; consumption restarts when the period changes
+	UpsertMeterQuotaAllowance(ctx context.Context, arg UpsertMeterQuotaAllowanceParams) (SubscriptionBillingMeterQuota, error)
 	// Create or update quota tracking
 	UpsertQuota(ctx context.Context, arg UpsertQuotaParams) (SubscriptionBillingQuotaTracking, error)
 	// Create or update subscription from Polar webhook
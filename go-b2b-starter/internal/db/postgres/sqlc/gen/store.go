@@ -12,6 +12,7 @@ import (
 
 type Store interface {
 	Querier
+	WithTx(db DBTX) Store
 }
 
 type SQLStore struct {
@@ -11,33 +11,6 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const decrementInvoiceCount = `-- name: DecrementInvoiceCount :one
-UPDATE subscription_billing.quota_tracking
-SET
-    invoice_count = invoice_count - 1,
-    updated_at = CURRENT_TIMESTAMP
-WHERE organization_id = $1
-RETURNING id, organization_id, max_seats, period_start, period_end, last_synced_at, created_at, updated_at, invoice_count
-`
-
-// Decrement invoice count by 1 (called after successful invoice processing)
-func (q *Queries) DecrementInvoiceCount(ctx context.Context, organizationID int32) (SubscriptionBillingQuotaTracking, error) {
-	row := q.db.QueryRow(ctx, decrementInvoiceCount, organizationID)
-	var i SubscriptionBillingQuotaTracking
-	err := row.Scan(
-		&i.ID,
-		&i.OrganizationID,
-		&i.MaxSeats,
-		&i.PeriodStart,
-		&i.PeriodEnd,
-		&i.LastSyncedAt,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.InvoiceCount,
-	)
-	return i, err
-}
-
 const deleteSubscription = `-- name: DeleteSubscription :exec
 DELETE FROM subscription_billing.subscriptions
 WHERE organization_id = $1
@@ -50,7 +23,7 @@ func (q *Queries) DeleteSubscription(ctx context.Context, organizationID int32)
 }
 
 const getQuotaByOrgID = `-- name: GetQuotaByOrgID :one
-SELECT id, organization_id, max_seats, period_start, period_end, last_synced_at, created_at, updated_at, invoice_count FROM subscription_billing.quota_tracking
+SELECT id, organization_id, max_seats, period_start, period_end, last_synced_at, created_at, updated_at, seat_grace_ends_at FROM subscription_billing.quota_tracking
 WHERE organization_id = $1
 LIMIT 1
 `
@@ -68,7 +41,7 @@ func (q *Queries) GetQuotaByOrgID(ctx context.Context, organizationID int32) (Su
 		&i.LastSyncedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.InvoiceCount,
+		&i.SeatGraceEndsAt,
 	)
 	return i, err
 }
@@ -76,16 +49,12 @@ func (q *Queries) GetQuotaByOrgID(ctx context.Context, organizationID int32) (Su
 const getQuotaStatus = `-- name: GetQuotaStatus :one
 SELECT
     s.subscription_status,
+    s.product_id,
     s.current_period_start,
     s.current_period_end,
     s.cancel_at_period_end,
-    q.invoice_count,
-    q.max_seats,
-    CASE
-        WHEN s.subscription_status = 'active' AND q.invoice_count > 0
-        THEN TRUE
-        ELSE FALSE
-    END AS can_process_invoice
+    s.past_due_since,
+    q.max_seats
 FROM subscription_billing.subscriptions s
 INNER JOIN subscription_billing.quota_tracking q ON s.organization_id = q.organization_id
 WHERE s.organization_id = $1
@@ -94,12 +63,12 @@ LIMIT 1
 
 type GetQuotaStatusRow struct {
 	SubscriptionStatus string           `json:"subscription_status"`
+	ProductID          string           `json:"product_id"`
 	CurrentPeriodStart pgtype.Timestamp `json:"current_period_start"`
 	CurrentPeriodEnd   pgtype.Timestamp `json:"current_period_end"`
 	CancelAtPeriodEnd  pgtype.Bool      `json:"cancel_at_period_end"`
-	InvoiceCount       int32            `json:"invoice_count"`
+	PastDueSince       pgtype.Timestamp `json:"past_due_since"`
 	MaxSeats           pgtype.Int4      `json:"max_seats"`
-	CanProcessInvoice  bool             `json:"can_process_invoice"`
 }
 
 // Get combined subscription and quota status for fast quota checks
@@ -108,18 +77,18 @@ func (q *Queries) GetQuotaStatus(ctx context.Context, organizationID int32) (Get
 	var i GetQuotaStatusRow
 	err := row.Scan(
 		&i.SubscriptionStatus,
+		&i.ProductID,
 		&i.CurrentPeriodStart,
 		&i.CurrentPeriodEnd,
 		&i.CancelAtPeriodEnd,
-		&i.InvoiceCount,
+		&i.PastDueSince,
 		&i.MaxSeats,
-		&i.CanProcessInvoice,
 	)
 	return i, err
 }
 
 const getSubscriptionByOrgID = `-- name: GetSubscriptionByOrgID :one
-SELECT id, organization_id, external_customer_id, subscription_id, subscription_status, product_id, product_name, plan_name, current_period_start, current_period_end, cancel_at_period_end, canceled_at, created_at, updated_at, metadata FROM subscription_billing.subscriptions
+SELECT id, organization_id, external_customer_id, subscription_id, subscription_status, product_id, product_name, plan_name, current_period_start, current_period_end, cancel_at_period_end, canceled_at, created_at, updated_at, metadata, past_due_since, dunning_phase FROM subscription_billing.subscriptions
 WHERE organization_id = $1
 LIMIT 1
 `
@@ -144,12 +113,14 @@ func (q *Queries) GetSubscriptionByOrgID(ctx context.Context, organizationID int
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Metadata,
+		&i.PastDueSince,
+		&i.DunningPhase,
 	)
 	return i, err
 }
 
 const getSubscriptionBySubscriptionID = `-- name: GetSubscriptionBySubscriptionID :one
-SELECT id, organization_id, external_customer_id, subscription_id, subscription_status, product_id, product_name, plan_name, current_period_start, current_period_end, cancel_at_period_end, canceled_at, created_at, updated_at, metadata FROM subscription_billing.subscriptions
+SELECT id, organization_id, external_customer_id, subscription_id, subscription_status, product_id, product_name, plan_name, current_period_start, current_period_end, cancel_at_period_end, canceled_at, created_at, updated_at, metadata, past_due_since, dunning_phase FROM subscription_billing.subscriptions
 WHERE subscription_id = $1
 LIMIT 1
 `
@@ -174,12 +145,14 @@ func (q *Queries) GetSubscriptionBySubscriptionID(ctx context.Context, subscript
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Metadata,
+		&i.PastDueSince,
+		&i.DunningPhase,
 	)
 	return i, err
 }
 
 const listActiveSubscriptions = `-- name: ListActiveSubscriptions :many
-SELECT id, organization_id, external_customer_id, subscription_id, subscription_status, product_id, product_name, plan_name, current_period_start, current_period_end, cancel_at_period_end, canceled_at, created_at, updated_at, metadata FROM subscription_billing.subscriptions
+SELECT id, organization_id, external_customer_id, subscription_id, subscription_status, product_id, product_name, plan_name, current_period_start, current_period_end, cancel_at_period_end, canceled_at, created_at, updated_at, metadata, past_due_since, dunning_phase FROM subscription_billing.subscriptions
 WHERE subscription_status = 'active'
 ORDER BY created_at DESC
 `
@@ -210,6 +183,8 @@ func (q *Queries) ListActiveSubscriptions(ctx context.Context) ([]SubscriptionBi
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Metadata,
+			&i.PastDueSince,
+			&i.DunningPhase,
 		); err != nil {
 			return nil, err
 		}
@@ -221,43 +196,86 @@ func (q *Queries) ListActiveSubscriptions(ctx context.Context) ([]SubscriptionBi
 	return items, nil
 }
 
-const listQuotasNearLimit = `-- name: ListQuotasNearLimit :many
-SELECT
-    q.id, q.organization_id, q.max_seats, q.period_start, q.period_end, q.last_synced_at, q.created_at, q.updated_at, q.invoice_count,
-    s.subscription_status,
-    s.product_name
-FROM subscription_billing.quota_tracking q
-INNER JOIN subscription_billing.subscriptions s ON q.organization_id = s.organization_id
-WHERE
-    s.subscription_status = 'active'
-    AND q.invoice_count <= $1
-ORDER BY q.invoice_count ASC
+const listPastDueSubscriptionsAfterID = `-- name: ListPastDueSubscriptionsAfterID :many
+SELECT id, organization_id, external_customer_id, subscription_id, subscription_status, product_id, product_name, plan_name, current_period_start, current_period_end, cancel_at_period_end, canceled_at, created_at, updated_at, metadata, past_due_since, dunning_phase FROM subscription_billing.subscriptions
+WHERE subscription_status = 'past_due'
+  AND id > $1
+ORDER BY id
+LIMIT $2
 `
 
-type ListQuotasNearLimitRow struct {
-	ID                 int32            `json:"id"`
-	OrganizationID     int32            `json:"organization_id"`
-	MaxSeats           pgtype.Int4      `json:"max_seats"`
-	PeriodStart        pgtype.Timestamp `json:"period_start"`
-	PeriodEnd          pgtype.Timestamp `json:"period_end"`
-	LastSyncedAt       pgtype.Timestamp `json:"last_synced_at"`
-	CreatedAt          pgtype.Timestamp `json:"created_at"`
-	UpdatedAt          pgtype.Timestamp `json:"updated_at"`
-	InvoiceCount       int32            `json:"invoice_count"`
-	SubscriptionStatus string           `json:"subscription_status"`
-	ProductName        pgtype.Text      `json:"product_name"`
+type ListPastDueSubscriptionsAfterIDParams struct {
+	AfterID   int32 `json:"after_id"`
+	BatchSize int32 `json:"batch_size"`
 }
 
-// List organizations approaching their quota limit (for alerting)
-func (q *Queries) ListQuotasNearLimit(ctx context.Context, invoiceCount int32) ([]ListQuotasNearLimitRow, error) {
-	rows, err := q.db.Query(ctx, listQuotasNearLimit, invoiceCount)
+// Page through past_due subscriptions in id order for dunning reminders
+func (q *Queries) ListPastDueSubscriptionsAfterID(ctx context.Context, arg ListPastDueSubscriptionsAfterIDParams) ([]SubscriptionBillingSubscription, error) {
+	rows, err := q.db.Query(ctx, listPastDueSubscriptionsAfterID, arg.AfterID, arg.BatchSize)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []ListQuotasNearLimitRow{}
+	items := []SubscriptionBillingSubscription{}
 	for rows.Next() {
-		var i ListQuotasNearLimitRow
+		var i SubscriptionBillingSubscription
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrganizationID,
+			&i.ExternalCustomerID,
+			&i.SubscriptionID,
+			&i.SubscriptionStatus,
+			&i.ProductID,
+			&i.ProductName,
+			&i.PlanName,
+			&i.CurrentPeriodStart,
+			&i.CurrentPeriodEnd,
+			&i.CancelAtPeriodEnd,
+			&i.CanceledAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Metadata,
+			&i.PastDueSince,
+			&i.DunningPhase,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listQuotasDueForRollover = `-- name: ListQuotasDueForRollover :many
+SELECT q.id, q.organization_id, q.max_seats, q.period_start, q.period_end, q.last_synced_at, q.created_at, q.updated_at, q.seat_grace_ends_at FROM subscription_billing.quota_tracking q
+INNER JOIN subscription_billing.subscriptions s ON s.organization_id = q.organization_id
+WHERE q.period_end < $1
+  AND q.period_end > q.period_start
+  AND s.subscription_status IN ('active', 'trialing')
+  AND s.cancel_at_period_end IS NOT TRUE
+  AND q.organization_id > $2
+ORDER BY q.organization_id
+LIMIT $3
+`
+
+type ListQuotasDueForRolloverParams struct {
+	Now                 pgtype.Timestamp `json:"now"`
+	AfterOrganizationID int32            `json:"after_organization_id"`
+	BatchSize           int32            `json:"batch_size"`
+}
+
+// Page through renewing organizations whose quota period has ended
+func (q *Queries) ListQuotasDueForRollover(ctx context.Context, arg ListQuotasDueForRolloverParams) ([]SubscriptionBillingQuotaTracking, error) {
+	rows, err := q.db.Query(ctx, listQuotasDueForRollover, arg.Now, arg.AfterOrganizationID, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SubscriptionBillingQuotaTracking{}
+	for rows.Next() {
+		var i SubscriptionBillingQuotaTracking
 		if err := rows.Scan(
 			&i.ID,
 			&i.OrganizationID,
@@ -267,9 +285,58 @@ func (q *Queries) ListQuotasNearLimit(ctx context.Context, invoiceCount int32) (
 			&i.LastSyncedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
-			&i.InvoiceCount,
+			&i.SeatGraceEndsAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSubscriptionsAfterID = `-- name: ListSubscriptionsAfterID :many
+SELECT id, organization_id, external_customer_id, subscription_id, subscription_status, product_id, product_name, plan_name, current_period_start, current_period_end, cancel_at_period_end, canceled_at, created_at, updated_at, metadata, past_due_since, dunning_phase FROM subscription_billing.subscriptions
+WHERE id > $1
+ORDER BY id
+LIMIT $2
+`
+
+type ListSubscriptionsAfterIDParams struct {
+	AfterID   int32 `json:"after_id"`
+	BatchSize int32 `json:"batch_size"`
+}
+
+// Page through all subscriptions in id order for background reconciliation
+func (q *Queries) ListSubscriptionsAfterID(ctx context.Context, arg ListSubscriptionsAfterIDParams) ([]SubscriptionBillingSubscription, error) {
+	rows, err := q.db.Query(ctx, listSubscriptionsAfterID, arg.AfterID, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SubscriptionBillingSubscription{}
+	for rows.Next() {
+		var i SubscriptionBillingSubscription
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrganizationID,
+			&i.ExternalCustomerID,
+			&i.SubscriptionID,
 			&i.SubscriptionStatus,
+			&i.ProductID,
 			&i.ProductName,
+			&i.PlanName,
+			&i.CurrentPeriodStart,
+			&i.CurrentPeriodEnd,
+			&i.CancelAtPeriodEnd,
+			&i.CanceledAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Metadata,
+			&i.PastDueSince,
+			&i.DunningPhase,
 		); err != nil {
 			return nil, err
 		}
@@ -284,29 +351,54 @@ func (q *Queries) ListQuotasNearLimit(ctx context.Context, invoiceCount int32) (
 const resetQuotaForPeriod = `-- name: ResetQuotaForPeriod :one
 UPDATE subscription_billing.quota_tracking
 SET
-    invoice_count = $2,
-    period_start = $3,
-    period_end = $4,
+    period_start = $2,
+    period_end = $3,
     updated_at = CURRENT_TIMESTAMP
 WHERE organization_id = $1
-RETURNING id, organization_id, max_seats, period_start, period_end, last_synced_at, created_at, updated_at, invoice_count
+RETURNING id, organization_id, max_seats, period_start, period_end, last_synced_at, created_at, updated_at, seat_grace_ends_at
 `
 
 type ResetQuotaForPeriodParams struct {
 	OrganizationID int32            `json:"organization_id"`
-	InvoiceCount   int32            `json:"invoice_count"`
 	PeriodStart    pgtype.Timestamp `json:"period_start"`
 	PeriodEnd      pgtype.Timestamp `json:"period_end"`
 }
 
-// Reset quota counters for a new billing period
+// Move quota tracking to a new billing period
 func (q *Queries) ResetQuotaForPeriod(ctx context.Context, arg ResetQuotaForPeriodParams) (SubscriptionBillingQuotaTracking, error) {
-	row := q.db.QueryRow(ctx, resetQuotaForPeriod,
-		arg.OrganizationID,
-		arg.InvoiceCount,
-		arg.PeriodStart,
-		arg.PeriodEnd,
+	row := q.db.QueryRow(ctx, resetQuotaForPeriod, arg.OrganizationID, arg.PeriodStart, arg.PeriodEnd)
+	var i SubscriptionBillingQuotaTracking
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.MaxSeats,
+		&i.PeriodStart,
+		&i.PeriodEnd,
+		&i.LastSyncedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SeatGraceEndsAt,
 	)
+	return i, err
+}
+
+const setQuotaSeatGrace = `-- name: SetQuotaSeatGrace :one
+UPDATE subscription_billing.quota_tracking
+SET
+    seat_grace_ends_at = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE organization_id = $1
+RETURNING id, organization_id, max_seats, period_start, period_end, last_synced_at, created_at, updated_at, seat_grace_ends_at
+`
+
+type SetQuotaSeatGraceParams struct {
+	OrganizationID  int32            `json:"organization_id"`
+	SeatGraceEndsAt pgtype.Timestamp `json:"seat_grace_ends_at"`
+}
+
+// Start or end the seat grace period of an organization over its seat limit
+func (q *Queries) SetQuotaSeatGrace(ctx context.Context, arg SetQuotaSeatGraceParams) (SubscriptionBillingQuotaTracking, error) {
+	row := q.db.QueryRow(ctx, setQuotaSeatGrace, arg.OrganizationID, arg.SeatGraceEndsAt)
 	var i SubscriptionBillingQuotaTracking
 	err := row.Scan(
 		&i.ID,
@@ -317,7 +409,50 @@ func (q *Queries) ResetQuotaForPeriod(ctx context.Context, arg ResetQuotaForPeri
 		&i.LastSyncedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.InvoiceCount,
+		&i.SeatGraceEndsAt,
+	)
+	return i, err
+}
+
+const setSubscriptionDunningPhase = `-- name: SetSubscriptionDunningPhase :one
+UPDATE subscription_billing.subscriptions
+SET
+    dunning_phase = $1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE organization_id = $2
+  AND subscription_status = 'past_due'
+  AND dunning_phase IS DISTINCT FROM $1
+RETURNING id, organization_id, external_customer_id, subscription_id, subscription_status, product_id, product_name, plan_name, current_period_start, current_period_end, cancel_at_period_end, canceled_at, created_at, updated_at, metadata, past_due_since, dunning_phase
+`
+
+type SetSubscriptionDunningPhaseParams struct {
+	DunningPhase   pgtype.Text `json:"dunning_phase"`
+	OrganizationID int32       `json:"organization_id"`
+}
+
+// Record the dunning phase announced for a past_due subscription; returns no
+// row when the phase was already recorded, so each phase is announced once
+func (q *Queries) SetSubscriptionDunningPhase(ctx context.Context, arg SetSubscriptionDunningPhaseParams) (SubscriptionBillingSubscription, error) {
+	row := q.db.QueryRow(ctx, setSubscriptionDunningPhase, arg.DunningPhase, arg.OrganizationID)
+	var i SubscriptionBillingSubscription
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.ExternalCustomerID,
+		&i.SubscriptionID,
+		&i.SubscriptionStatus,
+		&i.ProductID,
+		&i.ProductName,
+		&i.PlanName,
+		&i.CurrentPeriodStart,
+		&i.CurrentPeriodEnd,
+		&i.CancelAtPeriodEnd,
+		&i.CanceledAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Metadata,
+		&i.PastDueSince,
+		&i.DunningPhase,
 	)
 	return i, err
 }
@@ -325,29 +460,26 @@ func (q *Queries) ResetQuotaForPeriod(ctx context.Context, arg ResetQuotaForPeri
 const upsertQuota = `-- name: UpsertQuota :one
 INSERT INTO subscription_billing.quota_tracking (
     organization_id,
-    invoice_count,
     max_seats,
     period_start,
     period_end,
     last_synced_at,
     updated_at
 ) VALUES (
-    $1, $2, $3, $4, $5, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+    $1, $2, $3, $4, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
 )
 ON CONFLICT (organization_id)
 DO UPDATE SET
-    invoice_count = EXCLUDED.invoice_count,
     max_seats = EXCLUDED.max_seats,
     period_start = EXCLUDED.period_start,
     period_end = EXCLUDED.period_end,
     last_synced_at = CURRENT_TIMESTAMP,
     updated_at = CURRENT_TIMESTAMP
-RETURNING id, organization_id, max_seats, period_start, period_end, last_synced_at, created_at, updated_at, invoice_count
+RETURNING id, organization_id, max_seats, period_start, period_end, last_synced_at, created_at, updated_at, seat_grace_ends_at
 `
 
 type UpsertQuotaParams struct {
 	OrganizationID int32            `json:"organization_id"`
-	InvoiceCount   int32            `json:"invoice_count"`
 	MaxSeats       pgtype.Int4      `json:"max_seats"`
 	PeriodStart    pgtype.Timestamp `json:"period_start"`
 	PeriodEnd      pgtype.Timestamp `json:"period_end"`
@@ -357,7 +489,6 @@ type UpsertQuotaParams struct {
 func (q *Queries) UpsertQuota(ctx context.Context, arg UpsertQuotaParams) (SubscriptionBillingQuotaTracking, error) {
 	row := q.db.QueryRow(ctx, upsertQuota,
 		arg.OrganizationID,
-		arg.InvoiceCount,
 		arg.MaxSeats,
 		arg.PeriodStart,
 		arg.PeriodEnd,
@@ -372,7 +503,7 @@ func (q *Queries) UpsertQuota(ctx context.Context, arg UpsertQuotaParams) (Subsc
 		&i.LastSyncedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.InvoiceCount,
+		&i.SeatGraceEndsAt,
 	)
 	return i, err
 }
@@ -391,9 +522,12 @@ INSERT INTO subscription_billing.subscriptions (
     cancel_at_period_end,
     canceled_at,
     metadata,
+    past_due_since,
     updated_at
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, CURRENT_TIMESTAMP
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12,
+    CASE WHEN $4 = 'past_due' THEN CURRENT_TIMESTAMP END,
+    CURRENT_TIMESTAMP
 )
 ON CONFLICT (organization_id)
 DO UPDATE SET
@@ -408,8 +542,12 @@ DO UPDATE SET
     cancel_at_period_end = EXCLUDED.cancel_at_period_end,
     canceled_at = EXCLUDED.canceled_at,
     metadata = EXCLUDED.metadata,
+    past_due_since = CASE WHEN EXCLUDED.subscription_status = 'past_due'
+        THEN COALESCE(subscription_billing.subscriptions.past_due_since, EXCLUDED.past_due_since) END,
+    dunning_phase = CASE WHEN EXCLUDED.subscription_status = 'past_due'
+        THEN subscription_billing.subscriptions.dunning_phase END,
     updated_at = CURRENT_TIMESTAMP
-RETURNING id, organization_id, external_customer_id, subscription_id, subscription_status, product_id, product_name, plan_name, current_period_start, current_period_end, cancel_at_period_end, canceled_at, created_at, updated_at, metadata
+RETURNING id, organization_id, external_customer_id, subscription_id, subscription_status, product_id, product_name, plan_name, current_period_start, current_period_end, cancel_at_period_end, canceled_at, created_at, updated_at, metadata, past_due_since, dunning_phase
 `
 
 type UpsertSubscriptionParams struct {
@@ -460,6 +598,8 @@ func (q *Queries) UpsertSubscription(ctx context.Context, arg UpsertSubscription
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Metadata,
+		&i.PastDueSince,
+		&i.DunningPhase,
 	)
 	return i, err
 }
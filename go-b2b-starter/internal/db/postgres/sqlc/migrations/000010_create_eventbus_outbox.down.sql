@@ -0,0 +1,3 @@
+-- Drop eventbus outbox
+DROP TABLE IF EXISTS eventbus.outbox CASCADE;
+DROP SCHEMA IF EXISTS eventbus CASCADE;
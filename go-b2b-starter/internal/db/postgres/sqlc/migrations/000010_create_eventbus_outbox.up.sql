@@ -0,0 +1,36 @@
+-- Create eventbus schema for durable event delivery (transactional outbox)
+CREATE SCHEMA IF NOT EXISTS eventbus;
+
+-- Outbox table: events are written here in the same transaction as the domain
+-- write, then dispatched to subscribers by the relay worker (at-least-once)
+CREATE TABLE eventbus.outbox (
+    id BIGSERIAL PRIMARY KEY,
+
+    -- Event identity
+    event_id VARCHAR(100) NOT NULL UNIQUE,
+    event_name VARCHAR(255) NOT NULL,
+
+    -- Serialized event (full JSON of the event struct) and its metadata map
+    payload JSONB NOT NULL,
+    metadata JSONB DEFAULT '{}'::jsonb,
+    occurred_at TIMESTAMP NOT NULL,
+
+    -- Delivery state
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    locked_until TIMESTAMP,                      -- Lease held by a relay worker
+    dispatched_at TIMESTAMP,                     -- NULL until all handlers succeeded
+
+    -- Audit timestamps
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Relay polls pending rows in insertion order
+CREATE INDEX idx_outbox_pending ON eventbus.outbox(next_attempt_at, id) WHERE dispatched_at IS NULL;
+CREATE INDEX idx_outbox_event_name ON eventbus.outbox(event_name);
+
+-- Comments for documentation
+COMMENT ON SCHEMA eventbus IS 'Durable storage for the application event bus';
+COMMENT ON TABLE eventbus.outbox IS 'Transactional outbox of domain events awaiting dispatch to subscribers';
+COMMENT ON COLUMN eventbus.outbox.locked_until IS 'Relay lease expiry; rows with an expired lease are reclaimed by another worker';
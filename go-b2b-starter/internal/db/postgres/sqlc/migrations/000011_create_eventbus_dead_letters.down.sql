@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS trigger_dead_letters_updated_at ON eventbus.dead_letters;
+DROP TABLE IF EXISTS eventbus.dead_letters;
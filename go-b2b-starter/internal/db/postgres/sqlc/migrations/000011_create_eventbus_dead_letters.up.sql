@@ -0,0 +1,38 @@
+-- Dead-letter storage: events a handler could not process within its retry policy
+CREATE TABLE eventbus.dead_letters (
+    id BIGSERIAL PRIMARY KEY,
+
+    -- Event identity and the handler that failed
+    event_id VARCHAR(100) NOT NULL,
+    event_name VARCHAR(255) NOT NULL,
+    handler_name VARCHAR(255) NOT NULL,
+
+    -- Serialized event (full JSON of the event struct) and its metadata map
+    payload JSONB NOT NULL,
+    metadata JSONB DEFAULT '{}'::jsonb,
+
+    -- Failure details
+    attempts INT NOT NULL,
+    last_error TEXT NOT NULL,
+    failed_at TIMESTAMP NOT NULL,
+
+    -- Audit timestamps
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    -- One dead letter per event and handler; repeated failures update it
+    CONSTRAINT uq_dead_letters_event_handler UNIQUE (event_id, handler_name)
+);
+
+CREATE INDEX idx_dead_letters_event_name ON eventbus.dead_letters(event_name);
+CREATE INDEX idx_dead_letters_handler_name ON eventbus.dead_letters(handler_name);
+CREATE INDEX idx_dead_letters_created_at ON eventbus.dead_letters(created_at DESC);
+
+-- Trigger to automatically update updated_at (function from organizations schema migration)
+CREATE TRIGGER trigger_dead_letters_updated_at
+    BEFORE UPDATE ON eventbus.dead_letters
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Comments for documentation
+COMMENT ON TABLE eventbus.dead_letters IS 'Events that exhausted a handler retry policy, kept for inspection, replay or discard';
+COMMENT ON COLUMN eventbus.dead_letters.handler_name IS 'Name the handler was subscribed under; replay targets only this handler';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS eventbus.idx_outbox_partition_pending;
+ALTER TABLE eventbus.outbox DROP COLUMN IF EXISTS partition_key;
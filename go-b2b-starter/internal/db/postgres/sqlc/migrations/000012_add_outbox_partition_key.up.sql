@@ -0,0 +1,8 @@
+-- Partition key for per-key ordered delivery: the relay never dispatches an
+-- event while an earlier event with the same key is still pending
+ALTER TABLE eventbus.outbox ADD COLUMN partition_key VARCHAR(255);
+
+CREATE INDEX idx_outbox_partition_pending ON eventbus.outbox(partition_key, id)
+    WHERE dispatched_at IS NULL AND partition_key IS NOT NULL;
+
+COMMENT ON COLUMN eventbus.outbox.partition_key IS 'Events with the same key are dispatched one at a time in id order';
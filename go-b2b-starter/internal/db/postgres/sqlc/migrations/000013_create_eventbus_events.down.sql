@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS trigger_events_append_only ON eventbus.events;
+DROP FUNCTION IF EXISTS eventbus.reject_event_mutation();
+DROP TABLE IF EXISTS eventbus.events;
@@ -0,0 +1,37 @@
+-- Append-only event store: every published event, kept for audit and replay
+CREATE TABLE eventbus.events (
+    id BIGSERIAL PRIMARY KEY,
+
+    -- Event identity (BaseEvent)
+    event_id VARCHAR(100) NOT NULL UNIQUE,
+    event_name VARCHAR(255) NOT NULL,
+    partition_key VARCHAR(255),
+
+    -- Serialized event (full JSON of the event struct) and its metadata map
+    payload JSONB NOT NULL,
+    metadata JSONB DEFAULT '{}'::jsonb,
+
+    -- When the event happened (BaseEvent.CreatedAt) and when it was stored
+    occurred_at TIMESTAMP NOT NULL,
+    recorded_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Replay scans by time range, optionally filtered by event name
+CREATE INDEX idx_events_occurred_at ON eventbus.events(occurred_at, id);
+CREATE INDEX idx_events_name_occurred_at ON eventbus.events(event_name, occurred_at, id);
+
+-- Enforce append-only semantics
+CREATE OR REPLACE FUNCTION eventbus.reject_event_mutation()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'eventbus.events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_events_append_only
+    BEFORE UPDATE OR DELETE ON eventbus.events
+    FOR EACH ROW EXECUTE FUNCTION eventbus.reject_event_mutation();
+
+-- Comments for documentation
+COMMENT ON TABLE eventbus.events IS 'Append-only store of every published event, used for audit and replay';
+COMMENT ON COLUMN eventbus.events.occurred_at IS 'Event creation time (BaseEvent.CreatedAt); replay ranges filter on this column';
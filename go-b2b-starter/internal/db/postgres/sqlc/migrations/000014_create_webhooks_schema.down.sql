@@ -0,0 +1,7 @@
+-- Drop webhooks schema
+DROP TRIGGER IF EXISTS trigger_webhook_deliveries_updated_at ON webhooks.deliveries;
+DROP TRIGGER IF EXISTS trigger_webhook_endpoints_updated_at ON webhooks.endpoints;
+DROP TABLE IF EXISTS webhooks.delivery_attempts;
+DROP TABLE IF EXISTS webhooks.deliveries;
+DROP TABLE IF EXISTS webhooks.endpoints;
+DROP SCHEMA IF EXISTS webhooks;
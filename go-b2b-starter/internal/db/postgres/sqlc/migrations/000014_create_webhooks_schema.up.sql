@@ -0,0 +1,82 @@
+-- Outbound webhooks: customer endpoints notified about their organization's events
+CREATE SCHEMA IF NOT EXISTS webhooks;
+
+-- Endpoints registered by an organization
+CREATE TABLE webhooks.endpoints (
+    id SERIAL PRIMARY KEY,
+    organization_id INTEGER NOT NULL REFERENCES organizations.organizations(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    description VARCHAR(500) NOT NULL DEFAULT '',
+
+    -- Event types delivered to the endpoint; empty means all
+    event_types TEXT[] NOT NULL DEFAULT '{}',
+
+    -- Signing secret (Standard Webhooks HMAC-SHA256 key)
+    secret VARCHAR(100) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_webhook_endpoints_organization ON webhooks.endpoints(organization_id);
+
+-- One delivery per endpoint and event; retried until it succeeds or runs out of attempts
+CREATE TABLE webhooks.deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    endpoint_id INTEGER NOT NULL REFERENCES webhooks.endpoints(id) ON DELETE CASCADE,
+    organization_id INTEGER NOT NULL REFERENCES organizations.organizations(id) ON DELETE CASCADE,
+
+    -- Event identity; event_id is sent as the webhook-id header
+    event_id VARCHAR(100) NOT NULL,
+    event_type VARCHAR(255) NOT NULL,
+    payload JSONB NOT NULL,
+
+    -- Delivery state
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    locked_until TIMESTAMP,
+    last_status_code INT,
+    last_error TEXT,
+    delivered_at TIMESTAMP,
+
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uq_webhook_deliveries_endpoint_event UNIQUE (endpoint_id, event_id),
+    CONSTRAINT valid_delivery_status CHECK (status IN ('pending', 'succeeded', 'failed'))
+);
+
+CREATE INDEX idx_webhook_deliveries_endpoint ON webhooks.deliveries(endpoint_id, created_at DESC);
+CREATE INDEX idx_webhook_deliveries_pending ON webhooks.deliveries(next_attempt_at) WHERE status = 'pending';
+
+-- Log of every HTTP attempt made for a delivery
+CREATE TABLE webhooks.delivery_attempts (
+    id BIGSERIAL PRIMARY KEY,
+    delivery_id BIGINT NOT NULL REFERENCES webhooks.deliveries(id) ON DELETE CASCADE,
+    attempt INT NOT NULL,
+    status_code INT,
+    error TEXT,
+    duration_ms INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_webhook_delivery_attempts_delivery ON webhooks.delivery_attempts(delivery_id, attempt);
+
+-- Triggers to automatically update updated_at (function from organizations schema migration)
+CREATE TRIGGER trigger_webhook_endpoints_updated_at
+    BEFORE UPDATE ON webhooks.endpoints
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER trigger_webhook_deliveries_updated_at
+    BEFORE UPDATE ON webhooks.deliveries
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Comments for documentation
+COMMENT ON TABLE webhooks.endpoints IS 'Customer webhook endpoints, per organization';
+COMMENT ON COLUMN webhooks.endpoints.event_types IS 'Event types delivered to the endpoint; empty means all';
+COMMENT ON COLUMN webhooks.endpoints.secret IS 'Signing secret: whsec_ followed by the base64-encoded HMAC-SHA256 key of the Standard Webhooks signature';
+COMMENT ON TABLE webhooks.deliveries IS 'Outbound webhook messages and their delivery state';
+COMMENT ON COLUMN webhooks.deliveries.status IS 'Delivery status: pending, succeeded, failed';
+COMMENT ON TABLE webhooks.delivery_attempts IS 'Log of HTTP attempts made for each webhook delivery';
@@ -0,0 +1,3 @@
+-- Drop billing webhook events
+DROP TRIGGER IF EXISTS trigger_billing_webhook_events_updated_at ON subscription_billing.webhook_events;
+DROP TABLE IF EXISTS subscription_billing.webhook_events;
@@ -0,0 +1,37 @@
+-- Inbound billing provider webhooks, recorded by webhook ID for idempotency and replay
+CREATE TABLE subscription_billing.webhook_events (
+    id BIGSERIAL PRIMARY KEY,
+
+    -- Provider identity of the webhook (Webhook-Id header)
+    provider VARCHAR(50) NOT NULL,
+    webhook_id VARCHAR(255) NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+
+    -- Raw request body exactly as received
+    payload BYTEA NOT NULL,
+
+    -- Processing state
+    status VARCHAR(20) NOT NULL DEFAULT 'processing',
+    attempts INT NOT NULL DEFAULT 1,
+    last_error TEXT,
+
+    received_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    processed_at TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uq_billing_webhook_events_provider_webhook UNIQUE (provider, webhook_id),
+    CONSTRAINT valid_billing_webhook_event_status CHECK (status IN ('processing', 'processed', 'failed'))
+);
+
+CREATE INDEX idx_billing_webhook_events_received ON subscription_billing.webhook_events(received_at DESC);
+CREATE INDEX idx_billing_webhook_events_failed ON subscription_billing.webhook_events(status) WHERE status = 'failed';
+
+-- Trigger to automatically update updated_at (function from organizations schema migration)
+CREATE TRIGGER trigger_billing_webhook_events_updated_at
+    BEFORE UPDATE ON subscription_billing.webhook_events
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Comments for documentation
+COMMENT ON TABLE subscription_billing.webhook_events IS 'Inbound billing provider webhooks, one row per webhook ID';
+COMMENT ON COLUMN subscription_billing.webhook_events.payload IS 'Raw request body, kept for auditing and replay';
+COMMENT ON COLUMN subscription_billing.webhook_events.status IS 'Processing status: processing, processed, failed';
@@ -0,0 +1,19 @@
+-- Restore the single invoice_count column from the invoice.processed meter
+ALTER TABLE subscription_billing.quota_tracking
+ADD COLUMN invoice_count INT NOT NULL DEFAULT 0;
+
+UPDATE subscription_billing.quota_tracking q
+SET invoice_count = LEAST(GREATEST(m.allowance - m.consumed, 0), 2147483647)::INT
+FROM subscription_billing.meter_quotas m
+WHERE m.organization_id = q.organization_id
+  AND m.meter_slug = 'invoice.processed';
+
+COMMENT ON COLUMN subscription_billing.quota_tracking.invoice_count IS 'Remaining invoices in current billing period (decremented on use)';
+
+-- Drop usage ledger and meter quotas
+DROP TRIGGER IF EXISTS trigger_usage_ledger_append_only ON subscription_billing.usage_ledger;
+DROP FUNCTION IF EXISTS subscription_billing.reject_usage_ledger_mutation();
+DROP TABLE IF EXISTS subscription_billing.usage_ledger;
+
+DROP TRIGGER IF EXISTS trigger_meter_quotas_updated_at ON subscription_billing.meter_quotas;
+DROP TABLE IF EXISTS subscription_billing.meter_quotas;
@@ -0,0 +1,100 @@
+-- Multi-meter quotas: one allowance per organization and meter slug,
+-- replacing the single invoice_count column of quota_tracking
+CREATE TABLE subscription_billing.meter_quotas (
+    id SERIAL PRIMARY KEY,
+    organization_id INT NOT NULL REFERENCES organizations.organizations(id) ON DELETE CASCADE,
+
+    -- Meter identity, e.g. document.processed, ocr.page, llm.token, storage.byte
+    meter_slug VARCHAR(100) NOT NULL,
+
+    -- Allowance granted for the period (from product metadata) and usage so far
+    allowance BIGINT NOT NULL DEFAULT 0,
+    consumed BIGINT NOT NULL DEFAULT 0,
+
+    -- Quota period (matches the subscription period)
+    period_start TIMESTAMP NOT NULL,
+    period_end TIMESTAMP NOT NULL,
+
+    -- Sync tracking
+    last_synced_at TIMESTAMP,
+
+    -- Audit timestamps
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uq_meter_quotas_organization_meter UNIQUE (organization_id, meter_slug),
+    CONSTRAINT valid_meter_quota_amounts CHECK (allowance >= 0 AND consumed >= 0)
+);
+
+CREATE INDEX idx_meter_quotas_period_end ON subscription_billing.meter_quotas(period_end);
+
+-- Trigger to automatically update updated_at (function from organizations schema migration)
+CREATE TRIGGER trigger_meter_quotas_updated_at
+    BEFORE UPDATE ON subscription_billing.meter_quotas
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Append-only usage ledger: one row per consumption
+CREATE TABLE subscription_billing.usage_ledger (
+    id BIGSERIAL PRIMARY KEY,
+    organization_id INT NOT NULL REFERENCES organizations.organizations(id) ON DELETE CASCADE,
+    meter_slug VARCHAR(100) NOT NULL,
+    amount BIGINT NOT NULL,
+
+    -- Member whose action consumed the quota, if any
+    account_id INT REFERENCES organizations.accounts(id) ON DELETE SET NULL,
+
+    -- Caller-supplied idempotency key, e.g. the document ID
+    reference VARCHAR(255),
+
+    -- Quota period the usage was counted against
+    period_start TIMESTAMP NOT NULL,
+    period_end TIMESTAMP NOT NULL,
+
+    metadata JSONB DEFAULT '{}'::jsonb,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT valid_usage_ledger_amount CHECK (amount > 0)
+);
+
+CREATE INDEX idx_usage_ledger_org_created ON subscription_billing.usage_ledger(organization_id, created_at);
+CREATE INDEX idx_usage_ledger_org_meter_created ON subscription_billing.usage_ledger(organization_id, meter_slug, created_at);
+CREATE UNIQUE INDEX uq_usage_ledger_reference
+    ON subscription_billing.usage_ledger(organization_id, meter_slug, reference)
+    WHERE reference IS NOT NULL;
+
+-- Enforce append-only semantics; only foreign key actions (organization
+-- deleted, account removed) may change rows
+CREATE OR REPLACE FUNCTION subscription_billing.reject_usage_ledger_mutation()
+RETURNS TRIGGER AS $$
+BEGIN
+    -- Referential actions run inside the foreign key trigger
+    IF pg_trigger_depth() > 1 THEN
+        IF TG_OP = 'DELETE' THEN
+            RETURN OLD;
+        END IF;
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'subscription_billing.usage_ledger is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_usage_ledger_append_only
+    BEFORE UPDATE OR DELETE ON subscription_billing.usage_ledger
+    FOR EACH ROW EXECUTE FUNCTION subscription_billing.reject_usage_ledger_mutation();
+
+-- Carry the remaining invoice count over as the invoice.processed meter
+INSERT INTO subscription_billing.meter_quotas (
+    organization_id, meter_slug, allowance, consumed, period_start, period_end, last_synced_at
+)
+SELECT organization_id, 'invoice.processed', GREATEST(invoice_count, 0), 0, period_start, period_end, last_synced_at
+FROM subscription_billing.quota_tracking;
+
+ALTER TABLE subscription_billing.quota_tracking
+DROP COLUMN invoice_count;
+
+-- Comments for documentation
+COMMENT ON TABLE subscription_billing.meter_quotas IS 'Usage allowance and consumption per organization and meter for the current period';
+COMMENT ON COLUMN subscription_billing.meter_quotas.allowance IS 'Units allowed in the period, from product metadata or provider meter grants';
+COMMENT ON COLUMN subscription_billing.meter_quotas.consumed IS 'Units consumed in the period; reset when a new period starts';
+COMMENT ON TABLE subscription_billing.usage_ledger IS 'Append-only record of every quota consumption';
+COMMENT ON COLUMN subscription_billing.usage_ledger.reference IS 'Optional idempotency key, unique per organization and meter';
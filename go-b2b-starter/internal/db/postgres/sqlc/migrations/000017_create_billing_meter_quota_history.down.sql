@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS trigger_meter_quotas_archive_period ON subscription_billing.meter_quotas;
+DROP FUNCTION IF EXISTS subscription_billing.archive_meter_quota_period();
+DROP TABLE IF EXISTS subscription_billing.meter_quota_history;
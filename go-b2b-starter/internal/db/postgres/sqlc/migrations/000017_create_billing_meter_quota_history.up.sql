@@ -0,0 +1,45 @@
+-- Consumption of closed billing periods, one row per organization, meter and period
+CREATE TABLE subscription_billing.meter_quota_history (
+    id BIGSERIAL PRIMARY KEY,
+    organization_id INT NOT NULL REFERENCES organizations.organizations(id) ON DELETE CASCADE,
+    meter_slug VARCHAR(100) NOT NULL,
+
+    -- Allowance and consumption when the period closed
+    allowance BIGINT NOT NULL,
+    consumed BIGINT NOT NULL,
+
+    -- The closed period
+    period_start TIMESTAMP NOT NULL,
+    period_end TIMESTAMP NOT NULL,
+
+    archived_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uq_meter_quota_history_period UNIQUE (organization_id, meter_slug, period_start)
+);
+
+CREATE INDEX idx_meter_quota_history_org_period ON subscription_billing.meter_quota_history(organization_id, period_start DESC);
+
+-- Archive a meter's consumption whenever it moves to another period, whether
+-- by the rollover job, a renewal webhook or reconciliation
+CREATE OR REPLACE FUNCTION subscription_billing.archive_meter_quota_period()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF OLD.period_start IS DISTINCT FROM NEW.period_start THEN
+        INSERT INTO subscription_billing.meter_quota_history (
+            organization_id, meter_slug, allowance, consumed, period_start, period_end
+        ) VALUES (
+            OLD.organization_id, OLD.meter_slug, OLD.allowance, OLD.consumed, OLD.period_start, OLD.period_end
+        )
+        ON CONFLICT (organization_id, meter_slug, period_start) DO NOTHING;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_meter_quotas_archive_period
+    BEFORE UPDATE OF period_start ON subscription_billing.meter_quotas
+    FOR EACH ROW EXECUTE FUNCTION subscription_billing.archive_meter_quota_period();
+
+-- Comments for documentation
+COMMENT ON TABLE subscription_billing.meter_quota_history IS 'Meter allowance and consumption of closed billing periods';
+COMMENT ON COLUMN subscription_billing.meter_quota_history.consumed IS 'Units consumed in the period when it was closed';
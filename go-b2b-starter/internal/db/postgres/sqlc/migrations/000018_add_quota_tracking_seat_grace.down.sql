@@ -0,0 +1,2 @@
+ALTER TABLE subscription_billing.quota_tracking
+    DROP COLUMN IF EXISTS seat_grace_ends_at;
@@ -0,0 +1,6 @@
+-- Seat grace: set when a plan change leaves an organization with more active
+-- members than its seat limit; existing members keep access until it ends
+ALTER TABLE subscription_billing.quota_tracking
+    ADD COLUMN seat_grace_ends_at TIMESTAMP;
+
+COMMENT ON COLUMN subscription_billing.quota_tracking.seat_grace_ends_at IS 'End of the grace period for an organization over its seat limit, NULL when within the limit';
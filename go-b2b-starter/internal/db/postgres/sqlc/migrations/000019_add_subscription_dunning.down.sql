@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS subscription_billing.idx_subscriptions_past_due;
+
+ALTER TABLE subscription_billing.subscriptions
+    DROP COLUMN IF EXISTS dunning_phase,
+    DROP COLUMN IF EXISTS past_due_since;
@@ -0,0 +1,18 @@
+-- Dunning: past_due_since is set when a subscription becomes past_due and
+-- cleared when it leaves that status; the dunning phase (grace, read-only,
+-- blocked) is derived from it. dunning_phase is the last phase announced by
+-- a reminder event, so each transition is announced once.
+ALTER TABLE subscription_billing.subscriptions
+    ADD COLUMN past_due_since TIMESTAMP,
+    ADD COLUMN dunning_phase VARCHAR(20);
+
+-- Subscriptions already past due start their dunning at their last update
+UPDATE subscription_billing.subscriptions
+SET past_due_since = updated_at
+WHERE subscription_status = 'past_due';
+
+CREATE INDEX idx_subscriptions_past_due ON subscription_billing.subscriptions(id)
+    WHERE subscription_status = 'past_due';
+
+COMMENT ON COLUMN subscription_billing.subscriptions.past_due_since IS 'When the subscription became past_due, NULL in any other status';
+COMMENT ON COLUMN subscription_billing.subscriptions.dunning_phase IS 'Last dunning phase announced by a reminder event, NULL when not past_due';
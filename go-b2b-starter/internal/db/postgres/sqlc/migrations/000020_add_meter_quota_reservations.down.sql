@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS subscription_billing.quota_reservations;
+
+ALTER TABLE subscription_billing.meter_quotas
+    DROP CONSTRAINT IF EXISTS valid_meter_quota_reserved,
+    DROP COLUMN IF EXISTS reserved;
@@ -0,0 +1,30 @@
+-- Quota reservations: units held for work in progress, e.g. a request gated by
+-- the paywall's RequireQuota middleware. reserved counts the held units of a
+-- meter so the availability check stays a single-row condition; a reservation
+-- is either committed (moved to consumed and the usage ledger) or released.
+ALTER TABLE subscription_billing.meter_quotas
+    ADD COLUMN reserved BIGINT NOT NULL DEFAULT 0,
+    ADD CONSTRAINT valid_meter_quota_reserved CHECK (reserved >= 0);
+
+CREATE TABLE subscription_billing.quota_reservations (
+    id BIGSERIAL PRIMARY KEY,
+    organization_id INT NOT NULL REFERENCES organizations.organizations(id) ON DELETE CASCADE,
+    meter_slug VARCHAR(100) NOT NULL,
+    amount BIGINT NOT NULL,
+
+    -- Member and source entity recorded in the usage ledger on commit
+    account_id INT REFERENCES organizations.accounts(id) ON DELETE SET NULL,
+    reference VARCHAR(255),
+
+    -- Reservations left behind by a crashed process are released after this
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT valid_quota_reservation_amount CHECK (amount > 0)
+);
+
+CREATE INDEX idx_quota_reservations_org_meter_expires
+    ON subscription_billing.quota_reservations(organization_id, meter_slug, expires_at);
+
+COMMENT ON COLUMN subscription_billing.meter_quotas.reserved IS 'Units held by open quota reservations, not yet consumed';
+COMMENT ON TABLE subscription_billing.quota_reservations IS 'Open quota reservations; deleted when committed or released';
@@ -0,0 +1,303 @@
+-- name: GetMeterQuota :one
+-- Get the quota of one meter for an organization
+SELECT * FROM subscription_billing.meter_quotas
+WHERE organization_id = $1 AND meter_slug = $2
+LIMIT 1;
+
+-- name: ListMeterQuotasByOrgID :many
+-- List all meter quotas of an organization
+SELECT * FROM subscription_billing.meter_quotas
+WHERE organization_id = $1
+ORDER BY meter_slug;
+
+-- name: UpsertMeterQuotaAllowance :one
+-- Set the allowance of a meter; consumption restarts when the period changes
+INSERT INTO subscription_billing.meter_quotas (
+    organization_id,
+    meter_slug,
+    allowance,
+    consumed,
+    period_start,
+    period_end,
+    last_synced_at,
+    updated_at
+) VALUES (
+    $1, $2, $3, 0, $4, $5, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+)
+ON CONFLICT (organization_id, meter_slug)
+DO UPDATE SET
+    allowance = EXCLUDED.allowance,
+    consumed = CASE
+        WHEN subscription_billing.meter_quotas.period_start IS DISTINCT FROM EXCLUDED.period_start
+        THEN 0
+        ELSE subscription_billing.meter_quotas.consumed
+    END,
+    period_start = EXCLUDED.period_start,
+    period_end = EXCLUDED.period_end,
+    last_synced_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: SetMeterQuotaRemaining :one
+-- Set the remaining units of a meter (provider credit balance) keeping consumption
+INSERT INTO subscription_billing.meter_quotas (
+    organization_id,
+    meter_slug,
+    allowance,
+    consumed,
+    period_start,
+    period_end,
+    last_synced_at,
+    updated_at
+) VALUES (
+    $1, $2, $3, 0, $4, $5, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+)
+ON CONFLICT (organization_id, meter_slug)
+DO UPDATE SET
+    allowance = subscription_billing.meter_quotas.consumed + EXCLUDED.allowance,
+    last_synced_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: ClearMeterQuotaAllowancesExcept :exec
+-- Zero the allowance of meters no longer granted by the organization's product
+UPDATE subscription_billing.meter_quotas
+SET
+    allowance = 0,
+    last_synced_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE organization_id = sqlc.arg(organization_id)
+  AND NOT (meter_slug = ANY(sqlc.arg(meter_slugs)::text[]));
+
+-- name: ConsumeMeterQuota :one
+-- Atomically consume units of a meter and append the usage to the ledger.
+-- Units held by open reservations are not available. Returns no row when the
+-- meter is missing or the allowance would be exceeded; a repeated reference
+-- fails with a unique violation and consumes nothing.
+WITH charged AS (
+    UPDATE subscription_billing.meter_quotas
+    SET
+        consumed = consumed + sqlc.arg(amount),
+        updated_at = CURRENT_TIMESTAMP
+    WHERE organization_id = sqlc.arg(organization_id)
+      AND meter_slug = sqlc.arg(meter_slug)
+      AND consumed + reserved + sqlc.arg(amount) <= allowance
+    RETURNING *
+), entry AS (
+    INSERT INTO subscription_billing.usage_ledger (
+        organization_id,
+        meter_slug,
+        amount,
+        account_id,
+        reference,
+        period_start,
+        period_end,
+        metadata
+    )
+    SELECT organization_id, meter_slug, sqlc.arg(amount), sqlc.narg(account_id), sqlc.narg(reference), period_start, period_end, sqlc.arg(metadata)
+    FROM charged
+    RETURNING id
+)
+SELECT
+    charged.id,
+    charged.organization_id,
+    charged.meter_slug,
+    charged.allowance,
+    charged.consumed,
+    charged.period_start,
+    charged.period_end,
+    charged.last_synced_at,
+    charged.created_at,
+    charged.updated_at,
+    charged.reserved,
+    entry.id AS ledger_entry_id
+FROM charged, entry;
+
+-- name: ReserveMeterQuota :one
+-- Atomically hold units of a meter for work in progress, until the
+-- reservation is committed or released. Returns no row when the meter is
+-- missing or the units not consumed or held elsewhere are insufficient.
+WITH held AS (
+    UPDATE subscription_billing.meter_quotas
+    SET
+        reserved = reserved + sqlc.arg(amount),
+        updated_at = CURRENT_TIMESTAMP
+    WHERE organization_id = sqlc.arg(organization_id)
+      AND meter_slug = sqlc.arg(meter_slug)
+      AND consumed + reserved + sqlc.arg(amount) <= allowance
+    RETURNING *
+), reservation AS (
+    INSERT INTO subscription_billing.quota_reservations (
+        organization_id,
+        meter_slug,
+        amount,
+        account_id,
+        reference,
+        expires_at
+    )
+    SELECT organization_id, meter_slug, sqlc.arg(amount), sqlc.narg(account_id), sqlc.narg(reference), sqlc.arg(expires_at)
+    FROM held
+    RETURNING *
+)
+SELECT
+    reservation.id,
+    reservation.organization_id,
+    reservation.meter_slug,
+    reservation.amount,
+    reservation.account_id,
+    reservation.reference,
+    reservation.expires_at,
+    reservation.created_at,
+    held.allowance,
+    held.consumed,
+    held.reserved,
+    held.period_end
+FROM held, reservation;
+
+-- name: CommitQuotaReservation :one
+-- Consume the units of a reservation and append them to the usage ledger,
+-- under the given reference or else the one given when reserving. Returns no
+-- row when the reservation is gone (released or expired); a repeated
+-- reference fails with a unique violation and commits nothing.
+WITH reservation AS (
+    DELETE FROM subscription_billing.quota_reservations
+    WHERE id = sqlc.arg(id)
+    RETURNING *
+), charged AS (
+    UPDATE subscription_billing.meter_quotas m
+    SET
+        consumed = m.consumed + reservation.amount,
+        reserved = GREATEST(m.reserved - reservation.amount, 0),
+        updated_at = CURRENT_TIMESTAMP
+    FROM reservation
+    WHERE m.organization_id = reservation.organization_id
+      AND m.meter_slug = reservation.meter_slug
+    RETURNING m.*
+), entry AS (
+    INSERT INTO subscription_billing.usage_ledger (
+        organization_id,
+        meter_slug,
+        amount,
+        account_id,
+        reference,
+        period_start,
+        period_end,
+        metadata
+    )
+    SELECT reservation.organization_id, reservation.meter_slug, reservation.amount, reservation.account_id, COALESCE(sqlc.narg(reference), reservation.reference), charged.period_start, charged.period_end, sqlc.arg(metadata)
+    FROM reservation, charged
+    RETURNING id
+)
+SELECT
+    charged.id,
+    charged.organization_id,
+    charged.meter_slug,
+    charged.allowance,
+    charged.consumed,
+    charged.period_start,
+    charged.period_end,
+    charged.last_synced_at,
+    charged.created_at,
+    charged.updated_at,
+    charged.reserved,
+    entry.id AS ledger_entry_id
+FROM charged, entry;
+
+-- name: ReleaseQuotaReservation :execrows
+-- Return the units of a reservation to its meter without consuming them;
+-- affects no row when the reservation is already gone
+WITH reservation AS (
+    DELETE FROM subscription_billing.quota_reservations
+    WHERE id = sqlc.arg(id)
+    RETURNING organization_id, meter_slug, amount
+)
+UPDATE subscription_billing.meter_quotas m
+SET
+    reserved = GREATEST(m.reserved - reservation.amount, 0),
+    updated_at = CURRENT_TIMESTAMP
+FROM reservation
+WHERE m.organization_id = reservation.organization_id
+  AND m.meter_slug = reservation.meter_slug;
+
+-- name: ReleaseExpiredQuotaReservations :one
+-- Release an organization's reservations of a meter that expired before now,
+-- e.g. left behind by a crashed process; returns how many were released
+WITH expired AS (
+    DELETE FROM subscription_billing.quota_reservations
+    WHERE organization_id = sqlc.arg(organization_id)
+      AND meter_slug = sqlc.arg(meter_slug)
+      AND expires_at <= sqlc.arg(now)
+    RETURNING amount
+), released AS (
+    UPDATE subscription_billing.meter_quotas
+    SET
+        reserved = GREATEST(reserved - (SELECT SUM(amount) FROM expired), 0),
+        updated_at = CURRENT_TIMESTAMP
+    WHERE organization_id = sqlc.arg(organization_id)
+      AND meter_slug = sqlc.arg(meter_slug)
+      AND EXISTS (SELECT 1 FROM expired)
+    RETURNING id
+)
+SELECT COUNT(*)::bigint AS released FROM expired;
+
+-- name: ResetMeterQuotasForPeriod :many
+-- Start a new billing period for every meter of an organization; meters
+-- already in that period keep their consumption, so a retry is harmless
+UPDATE subscription_billing.meter_quotas
+SET
+    consumed = 0,
+    period_start = $2,
+    period_end = $3,
+    updated_at = CURRENT_TIMESTAMP
+WHERE organization_id = $1
+  AND period_start IS DISTINCT FROM $2
+RETURNING *;
+
+-- name: ListMeterQuotasNearLimit :many
+-- List active organizations with few units left on a meter (for alerting)
+SELECT
+    m.id, m.organization_id, m.meter_slug, m.allowance, m.consumed, m.period_start, m.period_end, m.last_synced_at, m.created_at, m.updated_at, m.reserved,
+    s.subscription_status,
+    s.product_name
+FROM subscription_billing.meter_quotas m
+INNER JOIN subscription_billing.subscriptions s ON m.organization_id = s.organization_id
+WHERE
+    s.subscription_status = 'active'
+    AND m.meter_slug = $1
+    AND m.allowance - m.consumed <= $2
+ORDER BY m.allowance - m.consumed ASC;
+
+-- name: SummarizeUsageLedgerByDay :many
+-- Sum an organization's usage in [from_time, to_time) per day, meter and
+-- account, optionally for one meter only
+SELECT
+    date_trunc('day', l.created_at)::timestamp AS usage_day,
+    l.meter_slug,
+    l.account_id,
+    a.email AS account_email,
+    SUM(l.amount)::bigint AS amount,
+    COUNT(*)::bigint AS entries
+FROM subscription_billing.usage_ledger l
+LEFT JOIN organizations.accounts a ON l.account_id = a.id
+WHERE l.organization_id = @organization_id
+  AND l.created_at >= @from_time
+  AND l.created_at < @to_time
+  AND (sqlc.narg(meter_slug)::text IS NULL OR l.meter_slug = sqlc.narg(meter_slug))
+GROUP BY usage_day, l.meter_slug, l.account_id, a.email
+ORDER BY usage_day, l.meter_slug, l.account_id NULLS FIRST;
+
+-- name: ListUsageLedgerEntriesAfterID :many
+-- Page through an organization's usage ledger entries in [from_time, to_time)
+-- in id order, optionally for one meter only (for CSV export)
+SELECT
+    l.id, l.organization_id, l.meter_slug, l.amount, l.account_id, l.reference, l.period_start, l.period_end, l.metadata, l.created_at,
+    a.email AS account_email
+FROM subscription_billing.usage_ledger l
+LEFT JOIN organizations.accounts a ON l.account_id = a.id
+WHERE l.organization_id = @organization_id
+  AND l.created_at >= @from_time
+  AND l.created_at < @to_time
+  AND (sqlc.narg(meter_slug)::text IS NULL OR l.meter_slug = sqlc.narg(meter_slug))
+  AND l.id > @after_id
+ORDER BY l.id
+LIMIT @batch_size;
@@ -0,0 +1,42 @@
+-- Inbound billing webhook queries
+
+-- name: ClaimBillingWebhookEvent :one
+-- Record a webhook for processing. Returns no row when the webhook was
+-- already processed or another delivery of it is still being processed;
+-- failed or abandoned attempts are claimed again.
+INSERT INTO subscription_billing.webhook_events (
+    provider,
+    webhook_id,
+    event_type,
+    payload
+) VALUES (
+    $1, $2, $3, $4
+)
+ON CONFLICT (provider, webhook_id) DO UPDATE
+SET
+    status = 'processing',
+    attempts = subscription_billing.webhook_events.attempts + 1,
+    last_error = NULL
+WHERE subscription_billing.webhook_events.status = 'failed'
+   OR (subscription_billing.webhook_events.status = 'processing'
+       AND subscription_billing.webhook_events.updated_at < CURRENT_TIMESTAMP - INTERVAL '5 minutes')
+RETURNING *;
+
+-- name: GetBillingWebhookEventByID :one
+SELECT * FROM subscription_billing.webhook_events
+WHERE id = $1;
+
+-- name: MarkBillingWebhookEventProcessed :exec
+UPDATE subscription_billing.webhook_events
+SET
+    status = 'processed',
+    last_error = NULL,
+    processed_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: MarkBillingWebhookEventFailed :exec
+UPDATE subscription_billing.webhook_events
+SET
+    status = 'failed',
+    last_error = $2
+WHERE id = $1;
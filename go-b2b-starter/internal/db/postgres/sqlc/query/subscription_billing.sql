@@ -25,9 +25,12 @@ INSERT INTO subscription_billing.subscriptions (
     cancel_at_period_end,
     canceled_at,
     metadata,
+    past_due_since,
     updated_at
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, CURRENT_TIMESTAMP
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12,
+    CASE WHEN $4 = 'past_due' THEN CURRENT_TIMESTAMP END,
+    CURRENT_TIMESTAMP
 )
 ON CONFLICT (organization_id)
 DO UPDATE SET
@@ -42,6 +45,10 @@ DO UPDATE SET
     cancel_at_period_end = EXCLUDED.cancel_at_period_end,
     canceled_at = EXCLUDED.canceled_at,
     metadata = EXCLUDED.metadata,
+    past_due_since = CASE WHEN EXCLUDED.subscription_status = 'past_due'
+        THEN COALESCE(subscription_billing.subscriptions.past_due_since, EXCLUDED.past_due_since) END,
+    dunning_phase = CASE WHEN EXCLUDED.subscription_status = 'past_due'
+        THEN subscription_billing.subscriptions.dunning_phase END,
     updated_at = CURRENT_TIMESTAMP
 RETURNING *;
 
@@ -60,18 +67,16 @@ LIMIT 1;
 -- Create or update quota tracking
 INSERT INTO subscription_billing.quota_tracking (
     organization_id,
-    invoice_count,
     max_seats,
     period_start,
     period_end,
     last_synced_at,
     updated_at
 ) VALUES (
-    $1, $2, $3, $4, $5, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+    $1, $2, $3, $4, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
 )
 ON CONFLICT (organization_id)
 DO UPDATE SET
-    invoice_count = EXCLUDED.invoice_count,
     max_seats = EXCLUDED.max_seats,
     period_start = EXCLUDED.period_start,
     period_end = EXCLUDED.period_end,
@@ -79,22 +84,21 @@ DO UPDATE SET
     updated_at = CURRENT_TIMESTAMP
 RETURNING *;
 
--- name: DecrementInvoiceCount :one
--- Decrement invoice count by 1 (called after successful invoice processing)
+-- name: ResetQuotaForPeriod :one
+-- Move quota tracking to a new billing period
 UPDATE subscription_billing.quota_tracking
 SET
-    invoice_count = invoice_count - 1,
+    period_start = $2,
+    period_end = $3,
     updated_at = CURRENT_TIMESTAMP
 WHERE organization_id = $1
 RETURNING *;
 
--- name: ResetQuotaForPeriod :one
--- Reset quota counters for a new billing period
+-- name: SetQuotaSeatGrace :one
+-- Start or end the seat grace period of an organization over its seat limit
 UPDATE subscription_billing.quota_tracking
 SET
-    invoice_count = $2,
-    period_start = $3,
-    period_end = $4,
+    seat_grace_ends_at = $2,
     updated_at = CURRENT_TIMESTAMP
 WHERE organization_id = $1
 RETURNING *;
@@ -103,16 +107,12 @@ RETURNING *;
 -- Get combined subscription and quota status for fast quota checks
 SELECT
     s.subscription_status,
+    s.product_id,
     s.current_period_start,
     s.current_period_end,
     s.cancel_at_period_end,
-    q.invoice_count,
-    q.max_seats,
-    CASE
-        WHEN s.subscription_status = 'active' AND q.invoice_count > 0
-        THEN TRUE
-        ELSE FALSE
-    END AS can_process_invoice
+    s.past_due_since,
+    q.max_seats
 FROM subscription_billing.subscriptions s
 INNER JOIN subscription_billing.quota_tracking q ON s.organization_id = q.organization_id
 WHERE s.organization_id = $1
@@ -124,15 +124,41 @@ SELECT * FROM subscription_billing.subscriptions
 WHERE subscription_status = 'active'
 ORDER BY created_at DESC;
 
--- name: ListQuotasNearLimit :many
--- List organizations approaching their quota limit (for alerting)
-SELECT
-    q.*,
-    s.subscription_status,
-    s.product_name
-FROM subscription_billing.quota_tracking q
-INNER JOIN subscription_billing.subscriptions s ON q.organization_id = s.organization_id
-WHERE
-    s.subscription_status = 'active'
-    AND q.invoice_count <= $1
-ORDER BY q.invoice_count ASC;
+-- name: ListSubscriptionsAfterID :many
+-- Page through all subscriptions in id order for background reconciliation
+SELECT * FROM subscription_billing.subscriptions
+WHERE id > @after_id
+ORDER BY id
+LIMIT @batch_size;
+
+-- name: ListPastDueSubscriptionsAfterID :many
+-- Page through past_due subscriptions in id order for dunning reminders
+SELECT * FROM subscription_billing.subscriptions
+WHERE subscription_status = 'past_due'
+  AND id > @after_id
+ORDER BY id
+LIMIT @batch_size;
+
+-- name: SetSubscriptionDunningPhase :one
+-- Record the dunning phase announced for a past_due subscription; returns no
+-- row when the phase was already recorded, so each phase is announced once
+UPDATE subscription_billing.subscriptions
+SET
+    dunning_phase = @dunning_phase,
+    updated_at = CURRENT_TIMESTAMP
+WHERE organization_id = @organization_id
+  AND subscription_status = 'past_due'
+  AND dunning_phase IS DISTINCT FROM @dunning_phase
+RETURNING *;
+
+-- name: ListQuotasDueForRollover :many
+-- Page through renewing organizations whose quota period has ended
+SELECT q.* FROM subscription_billing.quota_tracking q
+INNER JOIN subscription_billing.subscriptions s ON s.organization_id = q.organization_id
+WHERE q.period_end < @now
+  AND q.period_end > q.period_start
+  AND s.subscription_status IN ('active', 'trialing')
+  AND s.cancel_at_period_end IS NOT TRUE
+  AND q.organization_id > @after_organization_id
+ORDER BY q.organization_id
+LIMIT @batch_size;
@@ -0,0 +1,136 @@
+-- Outbound webhook queries
+
+-- name: CreateWebhookEndpoint :one
+INSERT INTO webhooks.endpoints (
+    organization_id,
+    url,
+    description,
+    event_types,
+    secret,
+    enabled
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetWebhookEndpointByID :one
+SELECT * FROM webhooks.endpoints
+WHERE id = $1 AND organization_id = $2;
+
+-- name: ListWebhookEndpointsByOrganization :many
+SELECT * FROM webhooks.endpoints
+WHERE organization_id = $1
+ORDER BY created_at DESC;
+
+-- name: ListWebhookEndpointsForEvent :many
+-- Enabled endpoints of an organization that accept the event type
+SELECT * FROM webhooks.endpoints
+WHERE organization_id = sqlc.arg(organization_id)
+  AND enabled = TRUE
+  AND (cardinality(event_types) = 0 OR sqlc.arg(event_type)::text = ANY(event_types))
+ORDER BY id;
+
+-- name: UpdateWebhookEndpoint :one
+UPDATE webhooks.endpoints
+SET
+    url = $3,
+    description = $4,
+    event_types = $5,
+    enabled = $6
+WHERE id = $1 AND organization_id = $2
+RETURNING *;
+
+-- name: UpdateWebhookEndpointSecret :one
+UPDATE webhooks.endpoints
+SET secret = $3
+WHERE id = $1 AND organization_id = $2
+RETURNING *;
+
+-- name: DeleteWebhookEndpoint :exec
+DELETE FROM webhooks.endpoints
+WHERE id = $1 AND organization_id = $2;
+
+-- name: CreateWebhookDelivery :exec
+-- Queue a delivery; an event is delivered to an endpoint only once
+INSERT INTO webhooks.deliveries (
+    endpoint_id,
+    organization_id,
+    event_id,
+    event_type,
+    payload
+) VALUES (
+    $1, $2, $3, $4, $5
+) ON CONFLICT (endpoint_id, event_id) DO NOTHING;
+
+-- name: ClaimDueWebhookDeliveries :many
+-- Lease due deliveries to this worker; expired leases are reclaimed
+UPDATE webhooks.deliveries
+SET locked_until = CURRENT_TIMESTAMP + make_interval(secs => sqlc.arg(lease_seconds)::float8)
+WHERE id IN (
+    SELECT d.id FROM webhooks.deliveries d
+    WHERE d.status = 'pending'
+      AND d.next_attempt_at <= CURRENT_TIMESTAMP
+      AND (d.locked_until IS NULL OR d.locked_until < CURRENT_TIMESTAMP)
+    ORDER BY d.next_attempt_at
+    LIMIT sqlc.arg(batch_size)::int
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: MarkWebhookDeliverySucceeded :exec
+UPDATE webhooks.deliveries
+SET
+    status = 'succeeded',
+    attempts = attempts + 1,
+    last_status_code = $2,
+    last_error = NULL,
+    delivered_at = CURRENT_TIMESTAMP,
+    locked_until = NULL
+WHERE id = $1;
+
+-- name: MarkWebhookDeliveryFailed :exec
+-- Record a failed attempt: status stays 'pending' with a retry delay, or becomes 'failed'
+UPDATE webhooks.deliveries
+SET
+    status = sqlc.arg(status),
+    attempts = attempts + 1,
+    last_status_code = sqlc.arg(last_status_code),
+    last_error = sqlc.arg(last_error),
+    next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => sqlc.arg(retry_in_seconds)::float8),
+    locked_until = NULL
+WHERE id = sqlc.arg(id);
+
+-- name: GetWebhookDeliveryByID :one
+SELECT * FROM webhooks.deliveries
+WHERE id = $1 AND organization_id = $2;
+
+-- name: ListWebhookDeliveriesByEndpoint :many
+SELECT * FROM webhooks.deliveries
+WHERE endpoint_id = $1 AND organization_id = $2
+ORDER BY created_at DESC
+LIMIT $3 OFFSET $4;
+
+-- name: RequeueWebhookDelivery :one
+-- Queue a delivery again for an immediate attempt
+UPDATE webhooks.deliveries
+SET
+    status = 'pending',
+    next_attempt_at = CURRENT_TIMESTAMP,
+    locked_until = NULL
+WHERE id = $1 AND organization_id = $2
+RETURNING *;
+
+-- name: CreateWebhookDeliveryAttempt :exec
+INSERT INTO webhooks.delivery_attempts (
+    delivery_id,
+    attempt,
+    status_code,
+    error,
+    duration_ms
+) VALUES (
+    $1, $2, $3, $4, $5
+);
+
+-- name: ListWebhookDeliveryAttempts :many
+SELECT * FROM webhooks.delivery_attempts
+WHERE delivery_id = $1
+ORDER BY attempt;
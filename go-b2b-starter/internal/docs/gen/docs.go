@@ -24,9 +24,184 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/api/subscriptions/checkout": {
+            "post": {
+                "description": "Creates a hosted checkout at the billing provider for the chosen product, with the organization attached as the external customer. Redirect the user to the returned URL; after payment the provider redirects to BILLING_CHECKOUT_SUCCESS_URL with the checkout ID as session_id, for POST /api/subscriptions/verify-payment.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Create a checkout session",
+                "parameters": [
+                    {
+                        "description": "Product to subscribe to",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_modules_billing.CreateCheckoutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Checkout session with the payment URL",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_moasq_go-b2b-starter_internal_modules_billing_domain.CheckoutSession"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request parameters or missing organization context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_moasq_go-b2b-starter_pkg_httperr.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found at the billing provider",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_moasq_go-b2b-starter_pkg_httperr.HTTPError"
+                        }
+                    },
+                    "429": {
+                        "description": "Billing provider rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_moasq_go-b2b-starter_pkg_httperr.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_moasq_go-b2b-starter_pkg_httperr.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/subscriptions/entitlements": {
+            "get": {
+                "description": "Returns the plan of the organization's subscription and what it can use: boolean features, numeric limits (e.g. max_seats) and meter allowances. Use it to gate the UI. Entitlements are empty while the subscription is not active.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Get plan entitlements",
+                "responses": {
+                    "200": {
+                        "description": "Plan entitlements",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_moasq_go-b2b-starter_internal_modules_billing_domain.Entitlements"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing organization context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_moasq_go-b2b-starter_pkg_httperr.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_moasq_go-b2b-starter_pkg_httperr.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/subscriptions/portal": {
+            "post": {
+                "description": "Returns a short-lived link to the billing provider's customer portal, where the organization manages its subscription, payment methods and invoices. Changes made in the portal are synced back through webhooks.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Create a customer portal session",
+                "responses": {
+                    "200": {
+                        "description": "Customer portal URL",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_moasq_go-b2b-starter_internal_modules_billing_domain.CustomerPortalSession"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing organization context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_moasq_go-b2b-starter_pkg_httperr.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Organization has no billing customer yet",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_moasq_go-b2b-starter_pkg_httperr.HTTPError"
+                        }
+                    },
+                    "429": {
+                        "description": "Billing provider rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_moasq_go-b2b-starter_pkg_httperr.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_moasq_go-b2b-starter_pkg_httperr.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/subscriptions/seats": {
+            "get": {
+                "description": "Count the organization's active members against the seat limit of its plan. After a downgrade leaves more members than seats, the state is \"grace\" until the grace period ends and \"over_limit\" afterwards; members keep access, but no members can be added.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Get seat usage",
+                "responses": {
+                    "200": {
+                        "description": "Used and available seats",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_moasq_go-b2b-starter_internal_modules_billing_domain.SeatUsage"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing organization context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_moasq_go-b2b-starter_pkg_httperr.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_moasq_go-b2b-starter_pkg_httperr.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
         "/api/subscriptions/status": {
             "get": {
-                "description": "Retrieve the current subscription billing status and invoice quota information for the organization",
+                "description": "Retrieve the current subscription billing status and allowance and consumption of each metered quota for the organization",
                 "consumes": [
                     "application/json"
                 ],
@@ -59,6 +234,119 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/subscriptions/usage": {
+            "get": {
+                "description": "Sums the quota the organization consumed, from the usage ledger. Group the usage by day (UTC), meter and/or account with group_by, e.g. group_by=day,meter; without grouping all usage is one total. The range defaults to the current quota period and may span up to 366 days.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Get a usage report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start of the range, inclusive (RFC3339 or YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the range, exclusive (RFC3339); a date (YYYY-MM-DD) includes that day",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only this meter, e.g. invoice.processed",
+                        "name": "meter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated groupings: day, meter, account",
+                        "name": "group_by",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Usage totals",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_moasq_go-b2b-starter_internal_modules_billing_domain.UsageReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid range or grouping, or missing organization context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_moasq_go-b2b-starter_pkg_httperr.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_moasq_go-b2b-starter_pkg_httperr.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/subscriptions/usage/export": {
+            "get": {
+                "description": "Downloads every usage ledger entry of the organization in the range as CSV, one row per quota consumption with the member, the source entity (reference) and the quota period it was counted against. Takes the same range and meter filters as GET /api/subscriptions/usage.",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Export usage as CSV",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start of the range, inclusive (RFC3339 or YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the range, exclusive (RFC3339); a date (YYYY-MM-DD) includes that day",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only this meter, e.g. invoice.processed",
+                        "name": "meter",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "CSV with the columns id, created_at, meter_slug, amount, account_id, account_email, reference, period_start, period_end, metadata",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid range, or missing organization context",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_moasq_go-b2b-starter_pkg_httperr.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_moasq_go-b2b-starter_pkg_httperr.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
         "/api/subscriptions/verify-payment": {
             "post": {
                 "description": "Verifies a payment by checking the Polar checkout session and updates subscription status. This is the primary mechanism for \"Verification on Redirect\" pattern when user returns from payment page.",
@@ -265,6 +553,13 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "402": {
+                        "description": "Seat limit reached; upgrade the plan or remove a member",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
                         "description": "Failed to add member",
                         "schema": {
@@ -425,6 +720,13 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "402": {
+                        "description": "Seat limit reached",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
                         "description": "Failed to bootstrap organization",
                         "schema": {
@@ -472,6 +774,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/github_com_moasq_go-b2b-starter_pkg_httperr.HTTPError"
                         }
                     },
+                    "402": {
+                        "description": "Plan does not include AI chat, or the token quota is exhausted",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_moasq_go-b2b-starter_internal_modules_paywall.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -658,6 +966,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/github_com_moasq_go-b2b-starter_pkg_httperr.HTTPError"
                         }
                     },
+                    "402": {
+                        "description": "Document quota exhausted",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_moasq_go-b2b-starter_internal_modules_paywall.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -904,28 +1218,255 @@ const docTemplate = `{
         "github_com_moasq_go-b2b-starter_internal_modules_billing_domain.BillingStatus": {
             "type": "object",
             "properties": {
-                "canProcessInvoices": {
-                    "type": "boolean"
-                },
                 "checkedAt": {
                     "type": "string"
                 },
+                "dunningPhase": {
+                    "description": "DunningPhase is set while the subscription is past_due",
+                    "type": "string"
+                },
+                "dunningPhaseEndsAt": {
+                    "type": "string"
+                },
                 "externalID": {
                     "type": "string"
                 },
                 "hasActiveSubscription": {
+                    "description": "also true during the dunning grace period",
                     "type": "boolean"
                 },
-                "invoiceCount": {
-                    "description": "Remaining invoices",
+                "organizationID": {
                     "type": "integer",
                     "format": "int32"
                 },
+                "pastDueSince": {
+                    "type": "string"
+                },
+                "plan": {
+                    "description": "plan key in the plan catalog, empty outside it",
+                    "type": "string"
+                },
+                "quotas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_moasq_go-b2b-starter_internal_modules_billing_domain.MeterQuota"
+                    }
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "subscriptionStatus": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_moasq_go-b2b-starter_internal_modules_billing_domain.CheckoutSession": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_moasq_go-b2b-starter_internal_modules_billing_domain.CustomerPortalSession": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_moasq_go-b2b-starter_internal_modules_billing_domain.Entitlements": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "checkedAt": {
+                    "type": "string"
+                },
+                "features": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "boolean"
+                    }
+                },
+                "limits": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "meters": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
                 "organizationID": {
                     "type": "integer",
                     "format": "int32"
                 },
-                "reason": {
+                "plan": {
+                    "description": "plan key, empty when the product is not in the catalog",
+                    "type": "string"
+                },
+                "planName": {
+                    "type": "string"
+                },
+                "productID": {
+                    "type": "string"
+                },
+                "subscriptionStatus": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_moasq_go-b2b-starter_internal_modules_billing_domain.MeterQuota": {
+            "type": "object",
+            "properties": {
+                "allowance": {
+                    "description": "Units granted for the period",
+                    "type": "integer"
+                },
+                "consumed": {
+                    "description": "Units used so far in the period",
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "format": "int32"
+                },
+                "lastSyncedAt": {
+                    "type": "string"
+                },
+                "meterSlug": {
+                    "type": "string"
+                },
+                "organizationID": {
+                    "type": "integer",
+                    "format": "int32"
+                },
+                "periodEnd": {
+                    "type": "string"
+                },
+                "periodStart": {
+                    "type": "string"
+                },
+                "reserved": {
+                    "description": "Units held by open reservations",
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_moasq_go-b2b-starter_internal_modules_billing_domain.SeatUsage": {
+            "type": "object",
+            "properties": {
+                "availableSeats": {
+                    "type": "integer"
+                },
+                "checkedAt": {
+                    "type": "string"
+                },
+                "graceEndsAt": {
+                    "type": "string"
+                },
+                "maxSeats": {
+                    "type": "integer",
+                    "format": "int32"
+                },
+                "organizationID": {
+                    "type": "integer",
+                    "format": "int32"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "usedSeats": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_moasq_go-b2b-starter_internal_modules_billing_domain.UsageReport": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "generatedAt": {
+                    "type": "string"
+                },
+                "groupBy": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "meterSlug": {
+                    "type": "string"
+                },
+                "organizationID": {
+                    "type": "integer",
+                    "format": "int32"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "totalAmount": {
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "totalEntries": {
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "totals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_moasq_go-b2b-starter_internal_modules_billing_domain.UsageTotal"
+                    }
+                }
+            }
+        },
+        "github_com_moasq_go-b2b-starter_internal_modules_billing_domain.UsageTotal": {
+            "type": "object",
+            "properties": {
+                "accountEmail": {
+                    "type": "string"
+                },
+                "accountID": {
+                    "type": "integer",
+                    "format": "int32"
+                },
+                "amount": {
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "day": {
+                    "type": "string"
+                },
+                "entries": {
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "meterSlug": {
                     "type": "string"
                 }
             }
@@ -1295,6 +1836,47 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_moasq_go-b2b-starter_internal_modules_paywall.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "dunning_phase": {
+                    "description": "DunningPhase is the dunning phase of a past due subscription\n(\"read_only\" or \"blocked\"). Optional - only set for past due payments.",
+                    "type": "string"
+                },
+                "dunning_phase_ends_at": {
+                    "description": "DunningPhaseEndsAt is when a read-only organization will be blocked.\nOptional - only set in the read-only phase.",
+                    "type": "string"
+                },
+                "error": {
+                    "description": "Error is the error code (e.g., \"subscription_required\", \"payment_failed\")",
+                    "type": "string"
+                },
+                "feature": {
+                    "description": "Feature is the plan feature the request needs.\nOptional - only set when the plan does not include it.",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Message is a human-readable description of the error.",
+                    "type": "string"
+                },
+                "meter": {
+                    "description": "Meter is the meter whose quota the request needs.\nOptional - only set when the quota is exceeded.",
+                    "type": "string"
+                },
+                "read_only": {
+                    "description": "ReadOnly is set when the organization can still read, and only\nwrites are refused until the subscription is active again.",
+                    "type": "boolean"
+                },
+                "status": {
+                    "description": "Status is the subscription status that caused the error.\nOptional - helps the client understand the specific issue.",
+                    "type": "string"
+                },
+                "upgrade_url": {
+                    "description": "UpgradeURL is the URL where the user can update their subscription.\nOptional - only included when configured.",
+                    "type": "string"
+                }
+            }
+        },
         "github_com_moasq_go-b2b-starter_pkg_httperr.HTTPError": {
             "type": "object",
             "properties": {
@@ -1482,6 +2064,17 @@ const docTemplate = `{
                 }
             }
         },
+        "internal_modules_billing.CreateCheckoutRequest": {
+            "type": "object",
+            "required": [
+                "product_id"
+            ],
+            "properties": {
+                "product_id": {
+                    "type": "string"
+                }
+            }
+        },
         "internal_modules_billing.VerifyPaymentRequest": {
             "type": "object",
             "required": [
@@ -6,6 +6,8 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/moasq/go-b2b-starter/internal/platform/eventbus"
 )
 
 // OrganizationResolver looks up organization by provider org ID.
@@ -197,6 +199,9 @@ func (m *Middleware) RequireOrganization() gin.HandlerFunc {
 		c.Set("account_id", accountID)
 		c.Set("stytch_org_id", identity.OrganizationID)
 
+		// Tag events published by this request with the organization
+		c.Request = c.Request.WithContext(eventbus.WithOrganizationID(c.Request.Context(), orgID))
+
 		c.Next()
 	}
 }
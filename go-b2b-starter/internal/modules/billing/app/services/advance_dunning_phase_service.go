@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain/events"
+)
+
+// AdvanceDunningPhase announces the dunning phase a past_due organization has
+// reached with a reminder event. The phase is recorded before it is announced,
+// so concurrent callers and API instances announce each transition once.
+func (s *billingService) AdvanceDunningPhase(ctx context.Context, organizationID int32) (string, error) {
+	subscription, err := s.repo.GetSubscriptionByOrgID(ctx, organizationID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get subscription: %w", err)
+	}
+
+	phase, phaseEndsAt := s.dunningPhase(subscription.SubscriptionStatus, subscription.PastDueSince, time.Now())
+	if phase == "" || phase == subscription.DunningPhase {
+		return "", nil
+	}
+
+	// Step 1: Record the phase; another caller may have announced it meanwhile
+	_, changed, err := s.repo.SetDunningPhase(ctx, organizationID, phase)
+	if err != nil {
+		return "", err
+	}
+	if !changed {
+		return "", nil
+	}
+	dunningPhasesEntered.WithLabelValues(phase).Inc()
+
+	s.logger.Info("Subscription entered dunning phase", map[string]any{
+		"organization_id": organizationID,
+		"subscription_id": subscription.SubscriptionID,
+		"phase":           phase,
+		"previous_phase":  subscription.DunningPhase,
+		"past_due_since":  *subscription.PastDueSince,
+	})
+
+	// Step 2: Remind the organization; the phase is stored, so failures are only logged
+	event := events.NewDunningReminder(
+		organizationID,
+		subscription.SubscriptionID,
+		phase,
+		subscription.DunningPhase,
+		subscription.ProductID,
+		*subscription.PastDueSince,
+		phaseEndsAt,
+	)
+	if err := s.eventBus.Publish(ctx, event); err != nil {
+		s.logger.Warn("Failed to publish dunning reminder event", map[string]any{
+			"organization_id": organizationID,
+			"phase":           phase,
+			"error":           err.Error(),
+		})
+	}
+
+	return phase, nil
+}
+
+// dunningPolicy is the configured dunning policy
+func (s *billingService) dunningPolicy() domain.DunningPolicy {
+	return domain.DunningPolicy{
+		GracePeriod:    s.config.DunningGracePeriod,
+		ReadOnlyPeriod: s.config.DunningReadOnlyPeriod,
+	}
+}
+
+// dunningPhase returns the dunning phase of a subscription at now and when it
+// ends. The phase is empty unless the subscription is past_due.
+func (s *billingService) dunningPhase(status string, pastDueSince *time.Time, now time.Time) (string, *time.Time) {
+	if status != "past_due" || pastDueSince == nil {
+		return "", nil
+	}
+	return s.dunningPolicy().Phase(*pastDueSince, now)
+}
+
+// grantsAccess reports whether a subscription allows full access: active, or
+// past_due within the dunning grace period
+func (s *billingService) grantsAccess(status *domain.QuotaStatus) bool {
+	if status.SubscriptionStatus == "active" {
+		return true
+	}
+	phase, _ := s.dunningPhase(status.SubscriptionStatus, status.PastDueSince, time.Now())
+	return phase == domain.DunningPhaseGrace
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+)
+
+// CheckQuota performs a read-only verification that an organization can
+// consume amount units of a meter.
+// This method does NOT consume quota - call Consume after the work succeeded.
+// Before refusing, it re-syncs from the billing provider in case a webhook was missed.
+func (s *billingService) CheckQuota(ctx context.Context, organizationID int32, meterSlug string, amount int64) (*domain.QuotaCheck, error) {
+	if amount <= 0 {
+		return nil, domain.ErrInvalidUsageAmount
+	}
+
+	// Step 1: Check database subscription and meter status (read-only)
+	quotaStatus, err := s.repo.GetQuotaStatus(ctx, organizationID)
+	if err != nil {
+		return &domain.QuotaCheck{
+			OrganizationID: organizationID,
+			MeterSlug:      meterSlug,
+			Requested:      amount,
+			Allowed:        false,
+			Reason:         "no active subscription",
+			CheckedAt:      time.Now(),
+		}, domain.ErrSubscriptionNotFound
+	}
+
+	quota, err := s.getMeterQuotaIfExists(ctx, organizationID, meterSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: Check if we need fallback API verification
+	if s.needsFallbackVerification(quotaStatus, quota, amount) {
+		s.logger.Info("Quota insufficient or subscription inactive, performing fallback API verification", map[string]any{
+			"organization_id": organizationID,
+			"meter_slug":      meterSlug,
+			"amount":          amount,
+		})
+
+		// Sync from provider and re-check
+		if err := s.SyncSubscriptionFromPolar(ctx, organizationID); err != nil {
+			s.logger.Error("Fallback sync failed, using database data", map[string]any{
+				"organization_id": organizationID,
+				"error":           err.Error(),
+			})
+		} else {
+			// Re-fetch status after sync
+			quotaStatus, err = s.repo.GetQuotaStatus(ctx, organizationID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get quota after sync: %w", err)
+			}
+			quota, err = s.getMeterQuotaIfExists(ctx, organizationID, meterSlug)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Step 3: Verify quota is available (NO consumption here)
+	check := &domain.QuotaCheck{
+		OrganizationID:     organizationID,
+		MeterSlug:          meterSlug,
+		SubscriptionStatus: quotaStatus.SubscriptionStatus,
+		Requested:          amount,
+		CheckedAt:          time.Now(),
+	}
+	if quota != nil {
+		check.Allowance = quota.Allowance
+		check.Consumed = quota.Consumed
+		check.Remaining = quota.Remaining()
+		check.PeriodEnd = quota.PeriodEnd
+	}
+
+	switch {
+	case !s.grantsAccess(quotaStatus):
+		check.Reason = fmt.Sprintf("subscription status: %s", quotaStatus.SubscriptionStatus)
+		return check, domain.ErrSubscriptionNotActive
+	case quota == nil:
+		check.Reason = fmt.Sprintf("plan does not include %s", meterSlug)
+		return check, domain.ErrQuotaExceeded
+	case check.Remaining < amount:
+		check.Reason = fmt.Sprintf("%s quota exceeded", meterSlug)
+		return check, domain.ErrQuotaExceeded
+	}
+
+	check.Allowed = true
+	check.Reason = "quota available"
+	return check, nil
+}
+
+// getMeterQuotaIfExists returns the meter quota, or nil if the organization has none
+func (s *billingService) getMeterQuotaIfExists(ctx context.Context, organizationID int32, meterSlug string) (*domain.MeterQuota, error) {
+	quota, err := s.repo.GetMeterQuota(ctx, organizationID, meterSlug)
+	if err != nil {
+		if errors.Is(err, domain.ErrMeterQuotaNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get meter quota: %w", err)
+	}
+	return quota, nil
+}
+
+func (s *billingService) needsFallbackVerification(status *domain.QuotaStatus, quota *domain.MeterQuota, amount int64) bool {
+	// Perform fallback if the check would otherwise be refused:
+	// 1. Subscription is inactive (or past its dunning grace period) but we're checking
+	// 2. The meter is missing or has fewer units left than requested
+
+	return !s.grantsAccess(status) || quota == nil || quota.Remaining() < amount
+}
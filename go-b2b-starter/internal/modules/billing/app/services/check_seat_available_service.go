@@ -0,0 +1,27 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+)
+
+// CheckSeatAvailable verifies that one more member fits within the seat
+// limit. Organizations in grace or over the limit keep their members but
+// cannot add new ones.
+//
+// The check reads the current member count, so two concurrent additions can
+// both take the last seat; the grace period absorbs that overshoot.
+func (s *billingService) CheckSeatAvailable(ctx context.Context, organizationID int32) (*domain.SeatUsage, error) {
+	usage, err := s.GetSeatUsage(ctx, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get seat usage: %w", err)
+	}
+
+	if !usage.CanAddMember() {
+		return usage, fmt.Errorf("%w: %d of %d seats used", domain.ErrSeatLimitReached, usage.UsedSeats, usage.MaxSeats)
+	}
+
+	return usage, nil
+}
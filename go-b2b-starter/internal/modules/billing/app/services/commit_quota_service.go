@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+)
+
+// CommitQuota consumes the units of a reservation after the work they paid
+// for succeeded and appends them to the usage ledger, like Consume. When the
+// reservation expired in the meantime, the units are consumed directly.
+func (s *billingService) CommitQuota(ctx context.Context, reservation *domain.QuotaReservation) (*domain.QuotaCheck, error) {
+	usage := &domain.UsageRecord{
+		OrganizationID: reservation.OrganizationID,
+		MeterSlug:      reservation.MeterSlug,
+		Amount:         reservation.Amount,
+		AccountID:      reservation.AccountID,
+		Reference:      reservation.Reference,
+		Metadata:       reservation.Metadata,
+	}
+
+	quota, err := s.repo.CommitQuotaReservation(ctx, reservation)
+	switch {
+	case errors.Is(err, domain.ErrReservationNotFound):
+		s.logger.Warn("Quota reservation expired before commit, consuming directly", map[string]any{
+			"organization_id": reservation.OrganizationID,
+			"meter_slug":      reservation.MeterSlug,
+			"reservation_id":  reservation.ID,
+		})
+		return s.Consume(ctx, usage)
+
+	case errors.Is(err, domain.ErrUsageAlreadyRecorded):
+		s.logger.Info("Usage already recorded, releasing reservation", map[string]any{
+			"organization_id": reservation.OrganizationID,
+			"meter_slug":      reservation.MeterSlug,
+			"reference":       reservation.Reference,
+		})
+		if err := s.ReleaseQuota(ctx, reservation); err != nil {
+			return nil, err
+		}
+
+		quota, err = s.repo.GetMeterQuota(ctx, reservation.OrganizationID, reservation.MeterSlug)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get meter quota: %w", err)
+		}
+		return s.consumedCheck(usage, quota, "usage already recorded"), nil
+
+	case err != nil:
+		s.logger.Error("Failed to commit quota reservation", map[string]any{
+			"organization_id": reservation.OrganizationID,
+			"meter_slug":      reservation.MeterSlug,
+			"reservation_id":  reservation.ID,
+			"error":           err.Error(),
+		})
+		return nil, err
+	}
+
+	// Report the usage to the billing provider (best-effort), as Consume does
+	go s.ingestMeterEvent(context.Background(), usage.OrganizationID, usage.MeterSlug, usage.Amount)
+
+	s.publishQuotaExhausted(ctx, usage, quota)
+
+	return s.consumedCheck(usage, quota, "quota consumed successfully"), nil
+}
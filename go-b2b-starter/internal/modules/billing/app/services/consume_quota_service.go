@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+)
+
+// Consume charges units of a meter after the work they pay for succeeded and
+// appends them to the usage ledger.
+// The availability check and the charge are a single atomic statement, so
+// concurrent calls never overdraw the allowance. Repeating a usage Reference
+// is a no-op, so callers can safely retry.
+func (s *billingService) Consume(ctx context.Context, usage *domain.UsageRecord) (*domain.QuotaCheck, error) {
+	if usage.Amount <= 0 {
+		return nil, domain.ErrInvalidUsageAmount
+	}
+
+	s.logger.Info("Consuming meter quota for organization", map[string]any{
+		"organization_id": usage.OrganizationID,
+		"meter_slug":      usage.MeterSlug,
+		"amount":          usage.Amount,
+	})
+
+	// Step 1: Consume quota and record usage (atomic database operation)
+	quota, err := s.repo.ConsumeMeterQuota(ctx, usage)
+	if errors.Is(err, domain.ErrUsageAlreadyRecorded) {
+		s.logger.Info("Usage already recorded, not consuming again", map[string]any{
+			"organization_id": usage.OrganizationID,
+			"meter_slug":      usage.MeterSlug,
+			"reference":       usage.Reference,
+		})
+
+		quota, err = s.repo.GetMeterQuota(ctx, usage.OrganizationID, usage.MeterSlug)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get meter quota: %w", err)
+		}
+		return s.consumedCheck(usage, quota, "usage already recorded"), nil
+	}
+	if err != nil {
+		if errors.Is(err, domain.ErrQuotaExceeded) || errors.Is(err, domain.ErrMeterQuotaNotFound) {
+			return &domain.QuotaCheck{
+				OrganizationID: usage.OrganizationID,
+				MeterSlug:      usage.MeterSlug,
+				Requested:      usage.Amount,
+				Allowed:        false,
+				Reason:         fmt.Sprintf("%s quota exceeded", usage.MeterSlug),
+				CheckedAt:      time.Now(),
+			}, domain.ErrQuotaExceeded
+		}
+
+		s.logger.Error("Failed to consume meter quota", map[string]any{
+			"organization_id": usage.OrganizationID,
+			"meter_slug":      usage.MeterSlug,
+			"error":           err.Error(),
+		})
+		return nil, fmt.Errorf("failed to consume meter quota: %w", err)
+	}
+
+	s.logger.Info("Successfully consumed meter quota locally", map[string]any{
+		"organization_id": usage.OrganizationID,
+		"meter_slug":      usage.MeterSlug,
+		"amount":          usage.Amount,
+		"consumed":        quota.Consumed,
+		"remaining":       quota.Remaining(),
+	})
+
+	// Step 2: Ingest meter event to the billing provider (best-effort)
+	// Local tracking is maintained for fast quota checks, the provider tracks actual billing
+	go s.ingestMeterEvent(context.Background(), usage.OrganizationID, usage.MeterSlug, usage.Amount)
+
+	// Announce when this consumption used up the meter's allowance
+	s.publishQuotaExhausted(ctx, usage, quota)
+
+	// Step 3: Return updated quota status
+	return s.consumedCheck(usage, quota, "quota consumed successfully"), nil
+}
+
+func (s *billingService) consumedCheck(usage *domain.UsageRecord, quota *domain.MeterQuota, reason string) *domain.QuotaCheck {
+	return &domain.QuotaCheck{
+		OrganizationID: usage.OrganizationID,
+		MeterSlug:      usage.MeterSlug,
+		Requested:      usage.Amount,
+		Allowance:      quota.Allowance,
+		Consumed:       quota.Consumed,
+		Remaining:      quota.Remaining(),
+		PeriodEnd:      quota.PeriodEnd,
+		Allowed:        true,
+		Reason:         reason,
+		CheckedAt:      time.Now(),
+	}
+}
+
+// ingestMeterEvent ingests a meter event to the billing provider for usage-based billing
+// This runs in a background goroutine and uses best-effort approach
+// Failures are logged but don't affect the main operation since local tracking is maintained
+func (s *billingService) ingestMeterEvent(ctx context.Context, organizationID int32, meterSlug string, amount int64) {
+	// Use background context with timeout (independent of request context)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	// Get organization's external customer ID (Stytch org ID)
+	externalID, err := s.orgAdapter.GetStytchOrgID(ctx, organizationID)
+	if err != nil {
+		s.logger.Error("Failed to get external customer ID for meter event", map[string]any{
+			"organization_id": organizationID,
+			"error":           err.Error(),
+		})
+		return
+	}
+
+	// Event name MUST match the provider meter filter exactly (with dot)
+	if err := s.billingProvider.IngestMeterEvent(ctx, externalID, meterSlug, amount); err != nil {
+		s.logger.Error("Failed to ingest meter event", map[string]any{
+			"organization_id": organizationID,
+			"external_id":     externalID,
+			"meter_slug":      meterSlug,
+			"error":           err.Error(),
+		})
+		return
+	}
+
+	s.logger.Info("Successfully ingested meter event", map[string]any{
+		"organization_id": organizationID,
+		"external_id":     externalID,
+		"event_name":      meterSlug,
+		"amount":          amount,
+	})
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+)
+
+// CreateCheckoutSession creates a provider checkout for a product, with the
+// organization attached as the external customer. The subscription is stored
+// once the provider's webhook arrives or the payment is verified on redirect.
+//
+// Only products of the plan catalog that are for sale can be bought, and an
+// organization with a subscription changes plans through the customer portal
+// rather than subscribing a second time.
+func (s *billingService) CreateCheckoutSession(ctx context.Context, organizationID int32, productID string) (*domain.CheckoutSession, error) {
+	productID = strings.TrimSpace(productID)
+	if productID == "" {
+		return nil, fmt.Errorf("%w: product ID is required", domain.ErrProductNotFound)
+	}
+	if !s.catalog.ForSale(productID) {
+		return nil, fmt.Errorf("%w: %s is not a plan product for sale", domain.ErrProductNotFound, productID)
+	}
+
+	subscription, err := s.repo.GetSubscriptionByOrgID(ctx, organizationID)
+	switch {
+	case err == nil:
+		if !isEndedStatus(subscription.SubscriptionStatus) {
+			return nil, fmt.Errorf("%w: organization %d is %s on %s",
+				domain.ErrSubscriptionExists, organizationID, subscription.SubscriptionStatus, subscription.ProductID)
+		}
+	case !errors.Is(err, domain.ErrSubscriptionNotFound):
+		return nil, fmt.Errorf("failed to load subscription: %w", err)
+	}
+
+	externalCustomerID, err := s.orgAdapter.GetStytchOrgID(ctx, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve external customer ID: %w", err)
+	}
+
+	session, err := s.billingProvider.CreateCheckoutSession(ctx, &domain.CheckoutRequest{
+		ExternalCustomerID: externalCustomerID,
+		ProductID:          productID,
+		SuccessURL:         s.config.CheckoutSuccessURL,
+		CancelURL:          s.config.CheckoutCancelURL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create checkout session: %w", err)
+	}
+
+	s.logger.Info("Checkout session created", map[string]any{
+		"organization_id": organizationID,
+		"product_id":      productID,
+		"session_id":      session.ID,
+	})
+
+	return session, nil
+}
+
+// isEndedStatus reports whether a subscription status is final, so the
+// organization may subscribe again
+func isEndedStatus(status string) bool {
+	return status == "canceled" || status == "incomplete_expired"
+}
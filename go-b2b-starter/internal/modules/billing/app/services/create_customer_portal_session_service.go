@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+)
+
+// CreateCustomerPortalSession returns a link to the provider's customer portal
+// for the organization. Changes made there reach the local database through
+// the provider's webhooks.
+func (s *billingService) CreateCustomerPortalSession(ctx context.Context, organizationID int32) (*domain.CustomerPortalSession, error) {
+	externalCustomerID, err := s.orgAdapter.GetStytchOrgID(ctx, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve external customer ID: %w", err)
+	}
+
+	session, err := s.billingProvider.CreateCustomerPortalSession(ctx, externalCustomerID, s.config.PortalReturnURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create customer portal session: %w", err)
+	}
+
+	s.logger.Info("Customer portal session created", map[string]any{
+		"organization_id": organizationID,
+	})
+
+	return session, nil
+}
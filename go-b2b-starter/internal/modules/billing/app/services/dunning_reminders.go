@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/config"
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+	logger "github.com/moasq/go-b2b-starter/internal/platform/logger/domain"
+)
+
+// dunningTimeout bounds the dunning phase update of one organization
+const dunningTimeout = 10 * time.Second
+
+// DunningReminders announces the dunning phase transitions of past_due
+// subscriptions. The phase advances with time rather than with a webhook, so
+// the grace to read-only and read-only to blocked transitions are found here.
+type DunningReminders interface {
+	// Start launches the background job; it does nothing when reminders are disabled
+	Start() error
+
+	// Close stops the job, waiting for the organization in progress
+	Close() error
+
+	// RunOnce advances the dunning phase of every past_due subscription
+	RunOnce(ctx context.Context) (*domain.DunningReport, error)
+}
+
+// dunningReminders pages through past_due subscriptions in ID order. Only
+// the database is read, so no provider rate limit applies.
+//
+// Each phase is recorded before it is announced, so running the job on
+// several API instances announces every transition once.
+type dunningReminders struct {
+	service BillingService
+	repo    domain.SubscriptionRepository
+	config  config.Config
+	logger  logger.Logger
+
+	mu      sync.Mutex
+	started bool
+	closed  bool
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+func NewDunningReminders(
+	service BillingService,
+	repo domain.SubscriptionRepository,
+	cfg config.Config,
+	logger logger.Logger,
+) DunningReminders {
+	return &dunningReminders{
+		service: service,
+		repo:    repo,
+		config:  cfg,
+		logger:  logger,
+		done:    make(chan struct{}),
+	}
+}
+
+// Start launches the reminder loop
+func (d *dunningReminders) Start() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return fmt.Errorf("dunning reminders are closed")
+	}
+	if d.started {
+		return nil
+	}
+	if !d.config.DunningRemindersEnabled {
+		d.logger.Info("Dunning reminders disabled")
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	d.started = true
+
+	go d.run(ctx)
+
+	d.logger.Info("Dunning reminders started", map[string]any{
+		"interval":         d.config.DunningRemindersInterval.String(),
+		"batch_size":       d.config.DunningRemindersBatchSize,
+		"grace_period":     d.config.DunningGracePeriod.String(),
+		"read_only_period": d.config.DunningReadOnlyPeriod.String(),
+	})
+
+	return nil
+}
+
+// Close stops the loop. A pass in progress ends after the current
+// organization; the rest are picked up by the next pass.
+func (d *dunningReminders) Close() error {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return nil
+	}
+	d.closed = true
+	started := d.started
+	cancel := d.cancel
+	d.mu.Unlock()
+
+	if started {
+		cancel()
+		<-d.done
+	}
+	return nil
+}
+
+// run is the reminder loop
+func (d *dunningReminders) run(ctx context.Context) {
+	defer close(d.done)
+
+	ticker := time.NewTicker(d.config.DunningRemindersInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		report, err := d.RunOnce(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				d.logger.Error("Dunning reminder pass failed", map[string]any{
+					"announced": report.Announced,
+					"error":     err.Error(),
+				})
+			}
+			continue
+		}
+
+		if report.Announced > 0 || report.Failed > 0 {
+			d.logger.Info("Dunning reminder pass completed", map[string]any{
+				"checked":   report.Checked,
+				"announced": report.Announced,
+				"failed":    report.Failed,
+				"duration":  report.FinishedAt.Sub(report.StartedAt).String(),
+			})
+		}
+	}
+}
+
+// RunOnce advances the dunning phase of every past_due subscription. An
+// organization that fails is counted and skipped; only listing errors and
+// cancellation end the pass.
+func (d *dunningReminders) RunOnce(ctx context.Context) (*domain.DunningReport, error) {
+	report := &domain.DunningReport{StartedAt: time.Now()}
+
+	var afterID int32
+	for {
+		batch, err := d.repo.ListPastDueSubscriptionsAfter(ctx, afterID, d.config.DunningRemindersBatchSize)
+		if err != nil {
+			dunningRuns.WithLabelValues(runResult(ctx)).Inc()
+			return report, err
+		}
+
+		for _, subscription := range batch {
+			afterID = subscription.ID
+
+			if ctx.Err() != nil {
+				dunningRuns.WithLabelValues(runResult(ctx)).Inc()
+				return report, ctx.Err()
+			}
+
+			report.Checked++
+
+			advanceCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), dunningTimeout)
+			phase, err := d.service.AdvanceDunningPhase(advanceCtx, subscription.OrganizationID)
+			cancel()
+
+			switch {
+			case err != nil:
+				report.Failed++
+				dunningFailed.Inc()
+				d.logger.Error("Failed to advance dunning phase", map[string]any{
+					"organization_id": subscription.OrganizationID,
+					"error":           err.Error(),
+				})
+			case phase != "":
+				report.Announced++
+			}
+		}
+
+		if len(batch) < int(d.config.DunningRemindersBatchSize) {
+			break
+		}
+	}
+
+	report.FinishedAt = time.Now()
+	dunningRuns.WithLabelValues("completed").Inc()
+
+	return report, nil
+}
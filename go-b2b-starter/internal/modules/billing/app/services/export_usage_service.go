@@ -0,0 +1,40 @@
+package services
+
+import (
+	"context"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+)
+
+// usageExportBatchSize is the number of ledger entries read per page of an export
+const usageExportBatchSize = 500
+
+// ExportUsage pages through the usage ledger entries selected by query, so an
+// export of any size is streamed without loading it at once. An error from
+// visit ends the export.
+func (s *billingService) ExportUsage(ctx context.Context, query *domain.UsageQuery, visit func([]domain.UsageLedgerEntry) error) error {
+	query, err := s.resolveUsageQuery(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	var afterID int64
+	for {
+		entries, err := s.repo.ListUsageEntriesAfter(ctx, query, afterID, usageExportBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+
+		if err := visit(entries); err != nil {
+			return err
+		}
+
+		if len(entries) < usageExportBatchSize {
+			return nil
+		}
+		afterID = entries[len(entries)-1].ID
+	}
+}
@@ -16,30 +16,49 @@ func (s *billingService) GetBillingStatus(ctx context.Context, organizationID in
 		return &domain.BillingStatus{
 			OrganizationID:        organizationID,
 			HasActiveSubscription: false,
-			CanProcessInvoices:    false,
-			InvoiceCount:          0,
+			Quotas:                []domain.MeterQuota{},
 			Reason:                "no active subscription found",
 			CheckedAt:             time.Now(),
 		}, nil
 	}
 
-	// Build billing status from quota status
-	return &domain.BillingStatus{
-		OrganizationID:        organizationID,
-		HasActiveSubscription: quotaStatus.SubscriptionStatus == "active",
-		CanProcessInvoices:    quotaStatus.CanProcessInvoice,
-		InvoiceCount:          quotaStatus.InvoiceCount,
-		Reason:                s.buildStatusReason(quotaStatus),
-		CheckedAt:             time.Now(),
-	}, nil
+	// Get per-meter allowance and consumption
+	quotas, err := s.repo.ListMeterQuotas(ctx, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list meter quotas: %w", err)
+	}
+
+	// Build billing status from quota status; a past_due subscription keeps
+	// access during the dunning grace period
+	now := time.Now()
+	status := &domain.BillingStatus{
+		OrganizationID:     organizationID,
+		SubscriptionStatus: quotaStatus.SubscriptionStatus,
+		Plan:               s.planKey(quotaStatus.ProductID),
+		Quotas:             quotas,
+		CheckedAt:          now,
+	}
+	status.DunningPhase, status.DunningPhaseEndsAt = s.dunningPhase(quotaStatus.SubscriptionStatus, quotaStatus.PastDueSince, now)
+	if status.DunningPhase != "" {
+		status.PastDueSince = quotaStatus.PastDueSince
+	}
+	status.HasActiveSubscription = quotaStatus.SubscriptionStatus == "active" || status.DunningPhase == domain.DunningPhaseGrace
+	status.Reason = s.buildStatusReason(status)
+
+	return status, nil
 }
 
-func (s *billingService) buildStatusReason(status *domain.QuotaStatus) string {
-	if !status.CanProcessInvoice {
-		if status.SubscriptionStatus != "active" {
-			return fmt.Sprintf("subscription status: %s", status.SubscriptionStatus)
-		}
-		return "invoice quota exceeded"
+func (s *billingService) buildStatusReason(status *domain.BillingStatus) string {
+	switch status.DunningPhase {
+	case domain.DunningPhaseGrace:
+		return fmt.Sprintf("payment past due: full access until %s", status.DunningPhaseEndsAt.Format(time.RFC3339))
+	case domain.DunningPhaseReadOnly:
+		return fmt.Sprintf("payment past due: read-only access until %s", status.DunningPhaseEndsAt.Format(time.RFC3339))
+	case domain.DunningPhaseBlocked:
+		return "payment past due: access blocked"
+	}
+	if status.SubscriptionStatus != "active" {
+		return fmt.Sprintf("subscription status: %s", status.SubscriptionStatus)
 	}
 	return "ok"
 }
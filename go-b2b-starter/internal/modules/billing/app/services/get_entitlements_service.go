@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+)
+
+// Entitlements resolves the organization's plan through the plan catalog. The
+// meter allowances and seat limit are the stored ones, which include customer
+// overrides and meter grants, rather than the plan's defaults.
+func (s *billingService) Entitlements(ctx context.Context, organizationID int32) (*domain.Entitlements, error) {
+	entitlements := &domain.Entitlements{
+		OrganizationID: organizationID,
+		Features:       map[string]bool{},
+		Limits:         map[string]int64{},
+		Meters:         map[string]int64{},
+		CheckedAt:      time.Now(),
+	}
+
+	subscription, err := s.repo.GetSubscriptionByOrgID(ctx, organizationID)
+	if errors.Is(err, domain.ErrSubscriptionNotFound) {
+		return entitlements, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entitlements.ProductID = subscription.ProductID
+	entitlements.SubscriptionStatus = subscription.SubscriptionStatus
+	// A past_due subscription keeps its features during the dunning grace
+	// period, like the paywall keeps letting the organization through
+	entitlements.Active = isActiveStatus(subscription.SubscriptionStatus) || s.grantsAccess(&domain.QuotaStatus{
+		SubscriptionStatus: subscription.SubscriptionStatus,
+		PastDueSince:       subscription.PastDueSince,
+	})
+	entitlements.PlanName = subscription.ProductName
+	if plan, ok := s.catalog.PlanForProduct(subscription.ProductID); ok {
+		entitlements.Plan = plan.Key
+		entitlements.PlanName = plan.Name
+	}
+
+	if !entitlements.Active {
+		return entitlements, nil
+	}
+
+	planEntitlements, _ := s.productEntitlements(subscription.ProductID, productMetadataOf(subscription))
+	for feature, enabled := range planEntitlements.Features {
+		entitlements.Features[feature] = enabled
+	}
+	for name, limit := range planEntitlements.Limits {
+		entitlements.Limits[name] = limit
+	}
+
+	quota, err := s.repo.GetQuotaByOrgID(ctx, organizationID)
+	switch {
+	case err == nil:
+		if quota.MaxSeats > 0 {
+			entitlements.Limits[domain.LimitMaxSeats] = int64(quota.MaxSeats)
+		} else {
+			delete(entitlements.Limits, domain.LimitMaxSeats)
+		}
+	case !errors.Is(err, domain.ErrQuotaNotFound):
+		return nil, err
+	}
+
+	meterQuotas, err := s.repo.ListMeterQuotas(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	for _, meterQuota := range meterQuotas {
+		if meterQuota.Allowance > 0 {
+			entitlements.Meters[meterQuota.MeterSlug] = meterQuota.Allowance
+		}
+	}
+
+	return entitlements, nil
+}
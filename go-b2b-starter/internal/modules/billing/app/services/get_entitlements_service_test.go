@@ -0,0 +1,66 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+	"github.com/moasq/go-b2b-starter/internal/platform/polar/polartest"
+)
+
+// TestEntitlementsDuringDunning checks that a past_due organization keeps its
+// plan features during the dunning grace period only
+func TestEntitlementsDuringDunning(t *testing.T) {
+	ctx := context.Background()
+
+	fake := polartest.NewServer()
+	defer fake.Close()
+	fake.SetProducts(polartest.Product{
+		ID:       testProductID,
+		Name:     "Pro",
+		Metadata: map[string]string{"feature:ai_chat": "true"},
+	})
+
+	env := newPolarEnv(t, fake)
+
+	tests := []struct {
+		name         string
+		status       string
+		pastDueSince time.Duration // before now; zero when not past_due
+		want         bool
+	}{
+		{name: "active", status: "active", want: true},
+		{name: "trialing", status: "trialing", want: true},
+		{name: "past due in grace", status: "past_due", pastDueSince: time.Hour, want: true},
+		{name: "past due read-only", status: "past_due", pastDueSince: 4 * 24 * time.Hour, want: false},
+		{name: "past due blocked", status: "past_due", pastDueSince: 8 * 24 * time.Hour, want: false},
+		{name: "canceled", status: "canceled", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subscription := &domain.Subscription{
+				OrganizationID:     testOrganizationID,
+				ExternalCustomerID: testStytchOrgID,
+				SubscriptionID:     "sub_1",
+				SubscriptionStatus: tt.status,
+				ProductID:          testProductID,
+			}
+			if tt.pastDueSince > 0 {
+				since := time.Now().Add(-tt.pastDueSince)
+				subscription.PastDueSince = &since
+			}
+			if _, err := env.repo.UpsertSubscription(ctx, subscription); err != nil {
+				t.Fatalf("UpsertSubscription: %v", err)
+			}
+
+			entitlements, err := env.billing.Entitlements(ctx, testOrganizationID)
+			if err != nil {
+				t.Fatalf("Entitlements: %v", err)
+			}
+			if got := entitlements.HasFeature("ai_chat"); got != tt.want {
+				t.Errorf("HasFeature(ai_chat) = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+)
+
+// GetSeatUsage counts the organization's active members against the seat
+// limit of its plan. An organization without a plan has no seat limit.
+func (s *billingService) GetSeatUsage(ctx context.Context, organizationID int32) (*domain.SeatUsage, error) {
+	used, err := s.orgAdapter.CountActiveMembers(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	quota, err := s.repo.GetQuotaByOrgID(ctx, organizationID)
+	if errors.Is(err, domain.ErrQuotaNotFound) {
+		return buildSeatUsage(&domain.QuotaTracking{OrganizationID: organizationID}, used, time.Now()), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	quota, err = s.syncSeatGrace(ctx, quota, used)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildSeatUsage(quota, used, time.Now()), nil
+}
+
+// syncSeatGrace starts the grace period when the organization is over its
+// seat limit and ends it once the organization is back within the limit
+func (s *billingService) syncSeatGrace(ctx context.Context, quota *domain.QuotaTracking, used int64) (*domain.QuotaTracking, error) {
+	overLimit := quota.MaxSeats > 0 && used > int64(quota.MaxSeats)
+
+	switch {
+	case overLimit && quota.SeatGraceEndsAt == nil:
+		endsAt := time.Now().Add(s.config.SeatGracePeriod)
+		updated, err := s.repo.SetSeatGrace(ctx, quota.OrganizationID, &endsAt)
+		if err != nil {
+			return nil, err
+		}
+		s.logger.Warn("Organization over its seat limit, grace period started", map[string]any{
+			"organization_id": quota.OrganizationID,
+			"max_seats":       quota.MaxSeats,
+			"used_seats":      used,
+			"grace_ends_at":   endsAt,
+		})
+		return updated, nil
+	case !overLimit && quota.SeatGraceEndsAt != nil:
+		updated, err := s.repo.SetSeatGrace(ctx, quota.OrganizationID, nil)
+		if err != nil {
+			return nil, err
+		}
+		s.logger.Info("Organization back within its seat limit", map[string]any{
+			"organization_id": quota.OrganizationID,
+			"max_seats":       quota.MaxSeats,
+			"used_seats":      used,
+		})
+		return updated, nil
+	}
+
+	return quota, nil
+}
+
+// buildSeatUsage derives the seat state from the limit and the members counted
+func buildSeatUsage(quota *domain.QuotaTracking, used int64, now time.Time) *domain.SeatUsage {
+	usage := &domain.SeatUsage{
+		OrganizationID: quota.OrganizationID,
+		MaxSeats:       quota.MaxSeats,
+		UsedSeats:      used,
+		CheckedAt:      now,
+	}
+
+	maxSeats := int64(quota.MaxSeats)
+	switch {
+	case maxSeats <= 0:
+		usage.State = domain.SeatStateUnlimited
+	case used < maxSeats:
+		usage.State = domain.SeatStateAvailable
+		usage.AvailableSeats = maxSeats - used
+	case used == maxSeats:
+		usage.State = domain.SeatStateFull
+	case quota.SeatGraceEndsAt != nil && now.Before(*quota.SeatGraceEndsAt):
+		usage.State = domain.SeatStateGrace
+		usage.GraceEndsAt = quota.SeatGraceEndsAt
+	default:
+		usage.State = domain.SeatStateOverLimit
+		usage.GraceEndsAt = quota.SeatGraceEndsAt
+	}
+
+	return usage
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+)
+
+const (
+	// maxUsageRange bounds the time range of a usage report or export
+	maxUsageRange = 366 * 24 * time.Hour
+
+	// defaultUsageRange is the range reported for an organization without a quota period
+	defaultUsageRange = 30 * 24 * time.Hour
+)
+
+// GetUsageReport sums the organization's usage ledger over a time range. The
+// ledger is summed per day, meter and account in the database and regrouped
+// here, so every grouping is served by one query.
+func (s *billingService) GetUsageReport(ctx context.Context, query *domain.UsageQuery) (*domain.UsageReport, error) {
+	query, err := s.resolveUsageQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	daily, err := s.repo.SummarizeUsage(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.UsageReport{
+		OrganizationID: query.OrganizationID,
+		From:           query.From,
+		To:             query.To,
+		MeterSlug:      query.MeterSlug,
+		GroupBy:        query.GroupBy,
+		Totals:         groupUsage(daily, query.GroupBy),
+		GeneratedAt:    time.Now(),
+	}
+	for _, total := range report.Totals {
+		report.TotalAmount += total.Amount
+		report.TotalEntries += total.Entries
+	}
+
+	return report, nil
+}
+
+// resolveUsageQuery validates a usage query and fills in its defaults: the
+// range ends now and starts with the current quota period, or 30 days earlier
+// when the organization has none
+func (s *billingService) resolveUsageQuery(ctx context.Context, query *domain.UsageQuery) (*domain.UsageQuery, error) {
+	resolved := *query
+	resolved.GroupBy = nil
+	for _, group := range query.GroupBy {
+		group = strings.ToLower(strings.TrimSpace(group))
+		switch group {
+		case domain.UsageGroupDay, domain.UsageGroupMeter, domain.UsageGroupAccount:
+		default:
+			return nil, fmt.Errorf("%w: unknown grouping %q, expected %s, %s or %s", domain.ErrInvalidUsageQuery,
+				group, domain.UsageGroupDay, domain.UsageGroupMeter, domain.UsageGroupAccount)
+		}
+		if !slices.Contains(resolved.GroupBy, group) {
+			resolved.GroupBy = append(resolved.GroupBy, group)
+		}
+	}
+
+	if resolved.To.IsZero() {
+		resolved.To = time.Now()
+	}
+	if resolved.From.IsZero() {
+		resolved.From = resolved.To.Add(-defaultUsageRange)
+
+		quota, err := s.repo.GetQuotaByOrgID(ctx, query.OrganizationID)
+		if err != nil && !errors.Is(err, domain.ErrQuotaNotFound) {
+			return nil, err
+		}
+		if err == nil && !quota.PeriodStart.IsZero() && quota.PeriodStart.Before(resolved.To) {
+			resolved.From = quota.PeriodStart
+		}
+	}
+	resolved.From, resolved.To = resolved.From.UTC(), resolved.To.UTC()
+
+	if !resolved.From.Before(resolved.To) {
+		return nil, fmt.Errorf("%w: from must be before to", domain.ErrInvalidUsageQuery)
+	}
+	if resolved.To.Sub(resolved.From) > maxUsageRange {
+		return nil, fmt.Errorf("%w: range must not exceed %d days", domain.ErrInvalidUsageQuery, int(maxUsageRange.Hours()/24))
+	}
+
+	return &resolved, nil
+}
+
+// usageGroupKey identifies a group of a usage report
+type usageGroupKey struct {
+	day       time.Time
+	meterSlug string
+	accountID int32
+}
+
+// groupUsage regroups daily usage totals by the requested groupings, keeping
+// only the fields of those groupings. The groups are ordered by day, meter
+// and account, with usage without a member first.
+func groupUsage(daily []domain.UsageTotal, groupBy []string) []domain.UsageTotal {
+	byDay := slices.Contains(groupBy, domain.UsageGroupDay)
+	byMeter := slices.Contains(groupBy, domain.UsageGroupMeter)
+	byAccount := slices.Contains(groupBy, domain.UsageGroupAccount)
+
+	index := make(map[usageGroupKey]int)
+	totals := []domain.UsageTotal{}
+
+	for _, usage := range daily {
+		var key usageGroupKey
+		var total domain.UsageTotal
+		if byDay && usage.Day != nil {
+			key.day = *usage.Day
+			total.Day = usage.Day
+		}
+		if byMeter {
+			key.meterSlug = usage.MeterSlug
+			total.MeterSlug = usage.MeterSlug
+		}
+		if byAccount && usage.AccountID != nil {
+			key.accountID = *usage.AccountID
+			total.AccountID = usage.AccountID
+			total.AccountEmail = usage.AccountEmail
+		}
+
+		i, ok := index[key]
+		if !ok {
+			i = len(totals)
+			index[key] = i
+			totals = append(totals, total)
+		}
+		totals[i].Amount += usage.Amount
+		totals[i].Entries += usage.Entries
+	}
+
+	sort.SliceStable(totals, func(a, b int) bool {
+		ta, tb := totals[a], totals[b]
+		if da, db := dayOf(ta), dayOf(tb); !da.Equal(db) {
+			return da.Before(db)
+		}
+		if ta.MeterSlug != tb.MeterSlug {
+			return ta.MeterSlug < tb.MeterSlug
+		}
+		if (ta.AccountID == nil) != (tb.AccountID == nil) {
+			return ta.AccountID == nil
+		}
+		return ta.AccountID != nil && *ta.AccountID < *tb.AccountID
+	})
+
+	return totals
+}
+
+func dayOf(total domain.UsageTotal) time.Time {
+	if total.Day == nil {
+		return time.Time{}
+	}
+	return *total.Day
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain/events"
+	"github.com/moasq/go-b2b-starter/internal/platform/eventbus"
+)
+
+// storedSubscription loads the subscription as stored before an update, so
+// its lifecycle transition can be announced afterwards. Nil when there is none.
+func (s *billingService) storedSubscription(ctx context.Context, organizationID int32) *domain.Subscription {
+	stored, err := s.repo.GetSubscriptionByOrgID(ctx, organizationID)
+	if err != nil {
+		if !errors.Is(err, domain.ErrSubscriptionNotFound) {
+			s.logger.Warn("Could not load stored subscription for lifecycle events", map[string]any{
+				"organization_id": organizationID,
+				"error":           err.Error(),
+			})
+		}
+		return nil
+	}
+	return stored
+}
+
+// publishLifecycleEvents announces the lifecycle transitions between the
+// stored and the updated subscription: activated, past due, canceled and plan
+// changed, and the dunning phase of a past_due subscription. The update has
+// been stored at this point, so publish failures are only logged.
+func (s *billingService) publishLifecycleEvents(ctx context.Context, previous, current *domain.Subscription) {
+	var previousStatus, previousProductID string
+	if previous != nil {
+		previousStatus = previous.SubscriptionStatus
+		previousProductID = previous.ProductID
+	}
+
+	var lifecycle []eventbus.Event
+	switch status := current.SubscriptionStatus; {
+	case isActiveStatus(status) && !isActiveStatus(previousStatus):
+		lifecycle = append(lifecycle, events.NewSubscriptionActivated(
+			current.OrganizationID,
+			current.SubscriptionID,
+			status,
+			previousStatus,
+			current.ProductID,
+			s.planKey(current.ProductID),
+			current.CurrentPeriodEnd,
+		))
+	case status == "past_due" && previousStatus != "past_due":
+		lifecycle = append(lifecycle, events.NewSubscriptionPastDue(
+			current.OrganizationID,
+			current.SubscriptionID,
+			previousStatus,
+			current.ProductID,
+			current.CurrentPeriodEnd,
+		))
+	case status == "canceled" && previousStatus != "canceled":
+		lifecycle = append(lifecycle, events.NewSubscriptionCanceled(
+			current.OrganizationID,
+			current.SubscriptionID,
+			previousStatus,
+			current.ProductID,
+			current.CanceledAt,
+		))
+	}
+
+	if previousProductID != "" && current.ProductID != "" && previousProductID != current.ProductID {
+		lifecycle = append(lifecycle, events.NewSubscriptionPlanChanged(
+			current.OrganizationID,
+			current.SubscriptionID,
+			current.SubscriptionStatus,
+			previousProductID,
+			current.ProductID,
+			s.planKey(previousProductID),
+			s.planKey(current.ProductID),
+		))
+	}
+
+	for _, event := range lifecycle {
+		if err := s.eventBus.Publish(ctx, event); err != nil {
+			s.logger.Warn("Failed to publish billing lifecycle event", map[string]any{
+				"event":           event.EventName(),
+				"organization_id": current.OrganizationID,
+				"subscription_id": current.SubscriptionID,
+				"error":           err.Error(),
+			})
+		}
+	}
+
+	// Remind of the first dunning phase now rather than on the next reminder pass
+	if current.SubscriptionStatus == "past_due" {
+		if _, err := s.AdvanceDunningPhase(ctx, current.OrganizationID); err != nil {
+			s.logger.Warn("Failed to advance dunning phase", map[string]any{
+				"organization_id": current.OrganizationID,
+				"error":           err.Error(),
+			})
+		}
+	}
+}
+
+// publishQuotaExhausted announces that a consumption used up the last units of
+// a meter. Only the consumption that crossed the allowance publishes it.
+func (s *billingService) publishQuotaExhausted(ctx context.Context, usage *domain.UsageRecord, quota *domain.MeterQuota) {
+	if quota.Allowance <= 0 || quota.Consumed < quota.Allowance || quota.Consumed-usage.Amount >= quota.Allowance {
+		return
+	}
+
+	event := events.NewQuotaExhausted(usage.OrganizationID, usage.MeterSlug, quota.Allowance, quota.Consumed, quota.PeriodEnd)
+	if err := s.eventBus.Publish(ctx, event); err != nil {
+		s.logger.Warn("Failed to publish quota exhausted event", map[string]any{
+			"organization_id": usage.OrganizationID,
+			"meter_slug":      usage.MeterSlug,
+			"error":           err.Error(),
+		})
+	}
+}
+
+// planKey names the catalog plan of a product, empty outside the catalog
+func (s *billingService) planKey(productID string) string {
+	if plan, ok := s.catalog.PlanForProduct(productID); ok {
+		return plan.Key
+	}
+	return ""
+}
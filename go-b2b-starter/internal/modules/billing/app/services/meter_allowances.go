@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+	logger "github.com/moasq/go-b2b-starter/internal/platform/logger/domain"
+)
+
+// meterMetadataKeys maps product metadata keys to the meter they grant an
+// allowance for. Any other meter is granted with a "quota:<meter slug>" key.
+var meterMetadataKeys = map[string]string{
+	"invoice_count":  domain.MeterInvoiceProcessed,
+	"document_count": domain.MeterDocumentProcessed,
+	"ocr_pages":      domain.MeterOCRPage,
+	"llm_tokens":     domain.MeterLLMToken,
+	"storage_bytes":  domain.MeterStorageByte,
+}
+
+const meterMetadataPrefix = "quota:"
+
+// meterAllowancesFromMetadata reads the meter allowances granted by product
+// or customer metadata. Invalid values are logged and skipped.
+func meterAllowancesFromMetadata(metadata map[string]string, log logger.Logger) map[string]int64 {
+	allowances := make(map[string]int64)
+	for key, value := range metadata {
+		meterSlug, ok := meterMetadataKeys[key]
+		if !ok {
+			slug, found := strings.CutPrefix(key, meterMetadataPrefix)
+			if !found || slug == "" {
+				continue
+			}
+			meterSlug = slug
+		}
+
+		amount, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil || amount < 0 {
+			log.Warn("Ignoring invalid meter allowance in metadata", map[string]any{
+				"key":   key,
+				"value": value,
+			})
+			continue
+		}
+		allowances[meterSlug] = amount
+	}
+	return allowances
+}
+
+// productEntitlements returns the entitlements of a subscription's product: the
+// configured plan's when the plans file lists the product, otherwise those of
+// the product metadata the subscription carries, otherwise those of the plan
+// derived from the provider's product list. ok is false when none is known.
+func (s *billingService) productEntitlements(productID string, productMetadata map[string]string) (entitlements domain.PlanEntitlements, ok bool) {
+	plan, found := s.catalog.PlanForProduct(productID)
+	if found && plan.Source == domain.PlanSourceFile {
+		return plan.Entitlements, true
+	}
+	if len(productMetadata) > 0 {
+		return entitlementsFromMetadata(productMetadata, s.logger), true
+	}
+	if found {
+		return plan.Entitlements, true
+	}
+	return domain.PlanEntitlements{}, false
+}
+
+// applyProductQuotas stores the seat limit and meter allowances granted by a
+// subscription's product for its current period. Meters the product no longer
+// grants keep their row but lose their allowance.
+//
+// When the product's entitlements cannot be resolved, the current seat limit
+// and allowances are kept: an unknown product must not revoke paid quotas.
+func (s *billingService) applyProductQuotas(ctx context.Context, organizationID int32, productID string, productMetadata map[string]string, periodStart, periodEnd time.Time) (map[string]int64, error) {
+	// The stored quota, nil when there is none yet
+	var current *domain.QuotaTracking
+	if quota, err := s.repo.GetQuotaByOrgID(ctx, organizationID); err == nil {
+		current = quota
+	}
+
+	// A provider period that ended before its renewal arrived must not move
+	// quotas back from the successor period the rollover job already started
+	if current != nil && rolledOverPast(current, periodEnd) {
+		periodStart, periodEnd = current.PeriodStart, current.PeriodEnd
+	}
+
+	entitlements, resolved := s.productEntitlements(productID, productMetadata)
+	maxSeats := maxSeatsOf(entitlements)
+	if !resolved {
+		s.logger.Warn("Entitlements of product unknown, keeping current quotas", map[string]any{
+			"organization_id": organizationID,
+			"product_id":      productID,
+		})
+		if current != nil {
+			maxSeats = current.MaxSeats
+		}
+	}
+
+	now := time.Now()
+	quota := &domain.QuotaTracking{
+		OrganizationID: organizationID,
+		MaxSeats:       maxSeats,
+		PeriodStart:    periodStart,
+		PeriodEnd:      periodEnd,
+		LastSyncedAt:   &now,
+	}
+	stored, err := s.repo.UpsertQuota(ctx, quota)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert quota: %w", err)
+	}
+
+	// A downgrade can leave more members than seats: start the grace period
+	// now rather than on the next seat check. Members keep access either way.
+	if used, err := s.orgAdapter.CountActiveMembers(ctx, organizationID); err != nil {
+		s.logger.Warn("Could not count members for seat grace", map[string]any{
+			"organization_id": organizationID,
+			"error":           err.Error(),
+		})
+	} else if _, err := s.syncSeatGrace(ctx, stored, used); err != nil {
+		s.logger.Warn("Could not update seat grace", map[string]any{
+			"organization_id": organizationID,
+			"error":           err.Error(),
+		})
+	}
+
+	if !resolved {
+		return nil, nil
+	}
+
+	allowances := entitlements.Meters
+	if len(allowances) == 0 {
+		s.logger.Warn("No meter allowances found for product", map[string]any{
+			"organization_id":  organizationID,
+			"product_id":       productID,
+			"product_metadata": productMetadata,
+		})
+	}
+
+	meterSlugs := make([]string, 0, len(allowances))
+	for meterSlug, allowance := range allowances {
+		if _, err := s.repo.UpsertMeterAllowance(ctx, organizationID, meterSlug, allowance, periodStart, periodEnd); err != nil {
+			return nil, fmt.Errorf("failed to upsert %s allowance: %w", meterSlug, err)
+		}
+		meterSlugs = append(meterSlugs, meterSlug)
+	}
+
+	if err := s.repo.ClearMeterAllowancesExcept(ctx, organizationID, meterSlugs); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Applied product quotas", map[string]any{
+		"organization_id": organizationID,
+		"max_seats":       maxSeats,
+		"allowances":      allowances,
+	})
+
+	return allowances, nil
+}
+
+// maxSeatsOf returns the seat limit of entitlements, 0 (unlimited) if none
+func maxSeatsOf(entitlements domain.PlanEntitlements) int32 {
+	maxSeats := entitlements.Limits[domain.LimitMaxSeats]
+	if maxSeats > math.MaxInt32 {
+		return math.MaxInt32
+	}
+	return int32(maxSeats)
+}
+
+// rolledOverPast reports whether a quota already runs in a period after
+// periodEnd, started by the rollover job before the provider renewed
+func rolledOverPast(quota *domain.QuotaTracking, periodEnd time.Time) bool {
+	return periodEnd.Before(time.Now()) && !quota.PeriodStart.Before(periodEnd)
+}
+
+// currentPeriod returns the organization's quota period, or an empty period
+// starting now when no quota has been stored yet
+func (s *billingService) currentPeriod(ctx context.Context, organizationID int32) (time.Time, time.Time) {
+	if quota, err := s.repo.GetQuotaByOrgID(ctx, organizationID); err == nil {
+		return quota.PeriodStart, quota.PeriodEnd
+	}
+	now := time.Now()
+	return now, now
+}
+
+// productMetadataOf returns the product metadata a BillingProvider attached
+// to a fetched subscription
+func productMetadataOf(subscription *domain.Subscription) map[string]string {
+	switch metadata := subscription.Metadata["product_metadata"].(type) {
+	case map[string]string:
+		return metadata
+	case map[string]any:
+		values := make(map[string]string, len(metadata))
+		for key, value := range metadata {
+			values[key] = fmt.Sprint(value)
+		}
+		return values
+	}
+	return nil
+}
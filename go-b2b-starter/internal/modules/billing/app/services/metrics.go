@@ -0,0 +1,93 @@
+package services
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Prometheus series of the billing background jobs, exported on /metrics
+var (
+	reconcileRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "billing",
+		Name:      "reconcile_runs_total",
+		Help:      "Reconciliation passes, by outcome.",
+	}, []string{"result"})
+
+	reconcileChecked = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "billing",
+		Name:      "reconcile_subscriptions_checked_total",
+		Help:      "Subscriptions compared with the billing provider.",
+	})
+
+	reconcileDrift = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "billing",
+		Name:      "reconcile_drift_total",
+		Help:      "Stored values found out of sync with the billing provider, per field.",
+	}, []string{"field"})
+
+	reconcileRepaired = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "billing",
+		Name:      "reconcile_repaired_total",
+		Help:      "Subscriptions whose drift was repaired.",
+	})
+
+	reconcileFailed = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "billing",
+		Name:      "reconcile_failed_total",
+		Help:      "Subscriptions that could not be compared or repaired.",
+	})
+
+	reconcileRateLimited = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "billing",
+		Name:      "reconcile_rate_limited_total",
+		Help:      "Provider calls rejected for exceeding the provider rate limit.",
+	})
+
+	reconcileLastCompleted = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "billing",
+		Name:      "reconcile_last_completed_timestamp_seconds",
+		Help:      "Unix time the last complete reconciliation pass finished.",
+	})
+
+	rolloverRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "billing",
+		Name:      "rollover_runs_total",
+		Help:      "Quota period rollover passes, by outcome.",
+	}, []string{"result"})
+
+	periodsRolledOver = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "billing",
+		Name:      "periods_rolled_over_total",
+		Help:      "Quota periods moved to a new billing period, by source of the new period.",
+	}, []string{"source"})
+
+	rolloverSkipped = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "billing",
+		Name:      "rollover_skipped_total",
+		Help:      "Ended quota periods left to lapse or already renewed.",
+	})
+
+	rolloverFailed = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "billing",
+		Name:      "rollover_failed_total",
+		Help:      "Ended quota periods that could not be rolled over.",
+	})
+
+	dunningRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "billing",
+		Name:      "dunning_runs_total",
+		Help:      "Dunning reminder passes, by outcome.",
+	}, []string{"result"})
+
+	dunningPhasesEntered = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "billing",
+		Name:      "dunning_phases_entered_total",
+		Help:      "Past due organizations that entered a dunning phase, by phase.",
+	}, []string{"phase"})
+
+	dunningFailed = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "billing",
+		Name:      "dunning_failed_total",
+		Help:      "Past due subscriptions whose dunning phase could not be advanced.",
+	})
+)
@@ -3,12 +3,17 @@ package services
 import (
 	"go.uber.org/dig"
 
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/config"
 	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain/events"
 	"github.com/moasq/go-b2b-starter/internal/modules/billing/infra/polar"
 	"github.com/moasq/go-b2b-starter/internal/modules/billing/infra/repositories"
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/infra/stripe"
 	"github.com/moasq/go-b2b-starter/internal/db/adapters"
+	"github.com/moasq/go-b2b-starter/internal/platform/eventbus"
 	logger "github.com/moasq/go-b2b-starter/internal/platform/logger/domain"
 	polarpkg "github.com/moasq/go-b2b-starter/internal/platform/polar"
+	stripepkg "github.com/moasq/go-b2b-starter/internal/platform/stripe"
 )
 
 // Module handles dependency injection for billing services
@@ -21,6 +26,35 @@ func NewModule() *Module {
 
 // Configure registers all services in the dependency container
 func (m *Module) Configure(container *dig.Container) error {
+	// Register event types; the event bus rejects unregistered events
+	if err := eventbus.Register[*events.SubscriptionChanged](events.SubscriptionChangedEventType); err != nil {
+		return err
+	}
+	if err := eventbus.Register[*events.SubscriptionReconciled](events.SubscriptionReconciledEventType); err != nil {
+		return err
+	}
+	if err := eventbus.Register[*events.PeriodRolledOver](events.PeriodRolledOverEventType); err != nil {
+		return err
+	}
+	if err := eventbus.Register[*events.SubscriptionActivated](events.SubscriptionActivatedEventType); err != nil {
+		return err
+	}
+	if err := eventbus.Register[*events.SubscriptionPastDue](events.SubscriptionPastDueEventType); err != nil {
+		return err
+	}
+	if err := eventbus.Register[*events.SubscriptionCanceled](events.SubscriptionCanceledEventType); err != nil {
+		return err
+	}
+	if err := eventbus.Register[*events.SubscriptionPlanChanged](events.SubscriptionPlanChangedEventType); err != nil {
+		return err
+	}
+	if err := eventbus.Register[*events.QuotaExhausted](events.QuotaExhaustedEventType); err != nil {
+		return err
+	}
+	if err := eventbus.Register[*events.DunningReminder](events.DunningReminderEventType); err != nil {
+		return err
+	}
+
 	// Register OrganizationAdapter (uses legacy adapter store for now)
 	if err := container.Provide(func(orgStore adapters.OrganizationStore) domain.OrganizationAdapter {
 		return repositories.NewOrganizationAdapter(orgStore)
@@ -28,10 +62,30 @@ func (m *Module) Configure(container *dig.Container) error {
 		return err
 	}
 
-	// Register BillingProvider (Polar implementation)
-	if err := container.Provide(func(client *polarpkg.Client, log logger.Logger) domain.BillingProvider {
-		return polar.NewPolarAdapter(client, log)
-	}); err != nil {
+	// Register the selected billing provider: its API adapter, webhook
+	// verifier and webhook parser. The other provider is never constructed,
+	// so its settings are not required.
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	if err := container.Provide(func() config.Config { return cfg }); err != nil {
+		return err
+	}
+
+	switch cfg.Provider {
+	case config.ProviderStripe:
+		if err := provideStripe(container); err != nil {
+			return err
+		}
+	default:
+		if err := providePolar(container); err != nil {
+			return err
+		}
+	}
+
+	// Register the plan catalog: the plans file and the provider's products
+	if err := container.Provide(NewPlanCatalog); err != nil {
 		return err
 	}
 
@@ -40,12 +94,75 @@ func (m *Module) Configure(container *dig.Container) error {
 		repo domain.SubscriptionRepository,
 		orgAdapter domain.OrganizationAdapter,
 		billingProvider domain.BillingProvider,
+		webhookParser domain.WebhookParser,
+		catalog PlanCatalog,
+		eventBus eventbus.EventBus,
+		cfg config.Config,
 		logger logger.Logger,
 	) BillingService {
-		return NewBillingService(repo, orgAdapter, billingProvider, logger)
+		return NewBillingService(repo, orgAdapter, billingProvider, webhookParser, catalog, eventBus, cfg, logger)
 	}); err != nil {
 		return err
 	}
 
+	// Register WebhookService
+	if err := container.Provide(NewWebhookService); err != nil {
+		return err
+	}
+
+	// Register the background jobs: subscription reconciliation, quota period
+	// rollover and dunning reminders
+	if err := container.Provide(NewReconciler); err != nil {
+		return err
+	}
+	if err := container.Provide(NewPeriodRollover); err != nil {
+		return err
+	}
+	if err := container.Provide(NewDunningReminders); err != nil {
+		return err
+	}
+
 	return nil
 }
+
+// providePolar registers the Polar.sh implementations of the provider interfaces
+func providePolar(container *dig.Container) error {
+	if err := container.Provide(func(client *polarpkg.Client, log logger.Logger) domain.BillingProvider {
+		return polar.NewPolarAdapter(client, log)
+	}); err != nil {
+		return err
+	}
+
+	if err := container.Provide(func(cfg *polarpkg.Config) domain.WebhookVerifier {
+		return polar.NewWebhookVerifier(cfg)
+	}); err != nil {
+		return err
+	}
+
+	return container.Provide(func(log logger.Logger) domain.WebhookParser {
+		return polar.NewWebhookParser(log)
+	})
+}
+
+// provideStripe registers the Stripe implementations of the provider interfaces
+func provideStripe(container *dig.Container) error {
+	if err := container.Provide(stripe.NewProductCache); err != nil {
+		return err
+	}
+
+	if err := container.Provide(func(client *stripepkg.Client, products *stripe.ProductCache, log logger.Logger) domain.BillingProvider {
+		return stripe.NewStripeAdapter(client, products, log)
+	}); err != nil {
+		return err
+	}
+
+	if err := container.Provide(func(cfg *stripepkg.Config) domain.WebhookVerifier {
+		return stripe.NewWebhookVerifier(cfg)
+	}); err != nil {
+		return err
+	}
+
+	return container.Provide(func(products *stripe.ProductCache, log logger.Logger) domain.WebhookParser {
+		return stripe.NewWebhookParser(products, log)
+	})
+}
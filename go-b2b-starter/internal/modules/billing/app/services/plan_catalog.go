@@ -0,0 +1,382 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/config"
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+	logger "github.com/moasq/go-b2b-starter/internal/platform/logger/domain"
+)
+
+// catalogSyncTimeout bounds the product listing of one catalog sync
+const catalogSyncTimeout = time.Minute
+
+// Product metadata keys of plans derived from the provider's products
+const (
+	planMetadataKey       = "plan"     // plan key; products sharing it form one plan
+	featureMetadataPrefix = "feature:" // "feature:ai_chat": "true"
+	limitMetadataPrefix   = "limit:"   // "limit:max_projects": "10"
+)
+
+// PlanCatalog maps provider products to named plans and their entitlements.
+//
+// Plans come from two sources: the plans file (BILLING_PLANS_FILE), which is
+// authoritative for the products it lists, and the provider's product list,
+// from whose metadata a plan is derived for every other product.
+type PlanCatalog interface {
+	// PlanForProduct returns the plan a provider product belongs to
+	PlanForProduct(productID string) (*domain.Plan, bool)
+
+	// ForSale reports whether a product belongs to a plan and, once the
+	// provider's products were listed, is among them
+	ForSale(productID string) bool
+
+	// Plans lists the configured plans followed by the provider-derived plans
+	Plans() []domain.Plan
+
+	// Sync lists the provider's products and rebuilds the provider-derived plans
+	Sync(ctx context.Context) error
+
+	// Start syncs the catalog in the background, at startup and then periodically;
+	// it does nothing when the catalog sync is disabled
+	Start() error
+
+	// Close stops the background sync
+	Close() error
+}
+
+type planCatalog struct {
+	provider domain.BillingProvider
+	config   config.Config
+	logger   logger.Logger
+
+	// configured holds the plans of the plans file, loaded once
+	configured []domain.Plan
+
+	mu        sync.RWMutex
+	synced    []domain.Plan
+	byProduct map[string]*domain.Plan
+	listed    map[string]bool // products of the last listing, nil before one
+
+	lifecycle sync.Mutex
+	started   bool
+	closed    bool
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+// NewPlanCatalog loads the plans file. An unreadable or invalid plans file
+// fails startup rather than granting the wrong entitlements.
+func NewPlanCatalog(provider domain.BillingProvider, cfg config.Config, logger logger.Logger) (PlanCatalog, error) {
+	c := &planCatalog{
+		provider: provider,
+		config:   cfg,
+		logger:   logger,
+		done:     make(chan struct{}),
+	}
+
+	if cfg.PlansFile != "" {
+		plans, err := config.LoadPlans(cfg.PlansFile)
+		if err != nil {
+			return nil, err
+		}
+		for _, plan := range plans {
+			c.configured = append(c.configured, planFromConfig(plan))
+		}
+		logger.Info("Loaded billing plans file", map[string]any{
+			"file":  cfg.PlansFile,
+			"plans": len(c.configured),
+		})
+	}
+
+	c.rebuild(nil)
+	return c, nil
+}
+
+// PlanForProduct returns a copy of the product's plan
+func (c *planCatalog) PlanForProduct(productID string) (*domain.Plan, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	plan, ok := c.byProduct[productID]
+	if !ok {
+		return nil, false
+	}
+	planCopy := *plan
+	return &planCopy, true
+}
+
+// ForSale checks the product against the plans and the last product listing
+func (c *planCatalog) ForSale(productID string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if _, ok := c.byProduct[productID]; !ok {
+		return false
+	}
+	return c.listed == nil || c.listed[productID]
+}
+
+// Plans lists copies of the plans; their entitlement maps are shared
+func (c *planCatalog) Plans() []domain.Plan {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	plans := make([]domain.Plan, 0, len(c.configured)+len(c.synced))
+	plans = append(plans, c.configured...)
+	return append(plans, c.synced...)
+}
+
+// Sync replaces the provider-derived plans with those of the current product
+// list. On failure the previous plans are kept.
+func (c *planCatalog) Sync(ctx context.Context) error {
+	products, err := c.provider.ListProducts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list provider products: %w", err)
+	}
+
+	c.rebuild(products)
+	return nil
+}
+
+// rebuild derives a plan from every product the plans file does not list and
+// indexes all plans by product
+func (c *planCatalog) rebuild(products []domain.CatalogProduct) {
+	byProduct := make(map[string]*domain.Plan)
+	for i := range c.configured {
+		for _, productID := range c.configured[i].ProductIDs {
+			byProduct[productID] = &c.configured[i]
+		}
+	}
+
+	listed := make(map[string]bool, len(products))
+	var synced []domain.Plan
+	index := make(map[string]int)
+	for _, product := range products {
+		listed[product.ID] = true
+		if _, ok := byProduct[product.ID]; ok {
+			continue
+		}
+
+		key := strings.TrimSpace(product.Metadata[planMetadataKey])
+		if key == "" {
+			key = product.ID
+		}
+		if i, ok := index[key]; ok {
+			// Another product of the plan, e.g. the yearly one
+			synced[i].ProductIDs = append(synced[i].ProductIDs, product.ID)
+			continue
+		}
+
+		index[key] = len(synced)
+		synced = append(synced, domain.Plan{
+			Key:          key,
+			Name:         product.Name,
+			ProductIDs:   []string{product.ID},
+			Entitlements: entitlementsFromMetadata(product.Metadata, c.logger),
+			Source:       domain.PlanSourceProvider,
+		})
+	}
+	for i := range synced {
+		for _, productID := range synced[i].ProductIDs {
+			byProduct[productID] = &synced[i]
+		}
+	}
+
+	if len(products) == 0 {
+		listed = nil
+	} else {
+		for _, plan := range c.configured {
+			for _, productID := range plan.ProductIDs {
+				if !listed[productID] {
+					c.logger.Warn("Configured plan product is not for sale at the billing provider", map[string]any{
+						"plan":       plan.Key,
+						"product_id": productID,
+					})
+				}
+			}
+		}
+	}
+
+	c.mu.Lock()
+	c.synced = synced
+	c.byProduct = byProduct
+	c.listed = listed
+	c.mu.Unlock()
+}
+
+// Start launches the sync loop
+func (c *planCatalog) Start() error {
+	c.lifecycle.Lock()
+	defer c.lifecycle.Unlock()
+
+	if c.closed {
+		return fmt.Errorf("plan catalog is closed")
+	}
+	if c.started {
+		return nil
+	}
+	if !c.config.CatalogSyncEnabled {
+		c.logger.Info("Plan catalog sync disabled")
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.started = true
+
+	go c.run(ctx)
+
+	c.logger.Info("Plan catalog sync started", map[string]any{
+		"interval": c.config.CatalogSyncInterval.String(),
+	})
+
+	return nil
+}
+
+// Close stops the loop, waiting for a sync in progress
+func (c *planCatalog) Close() error {
+	c.lifecycle.Lock()
+	if c.closed {
+		c.lifecycle.Unlock()
+		return nil
+	}
+	c.closed = true
+	started := c.started
+	cancel := c.cancel
+	c.lifecycle.Unlock()
+
+	if started {
+		cancel()
+		<-c.done
+	}
+	return nil
+}
+
+// run is the sync loop; the first sync runs at startup, so the catalog is
+// complete before the first interval has passed
+func (c *planCatalog) run(ctx context.Context) {
+	defer close(c.done)
+
+	ticker := time.NewTicker(c.config.CatalogSyncInterval)
+	defer ticker.Stop()
+
+	for {
+		syncCtx, cancel := context.WithTimeout(ctx, catalogSyncTimeout)
+		err := c.Sync(syncCtx)
+		cancel()
+
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			c.logger.Error("Plan catalog sync failed", map[string]any{
+				"error": err.Error(),
+			})
+		default:
+			c.mu.RLock()
+			synced := len(c.synced)
+			c.mu.RUnlock()
+			c.logger.Info("Plan catalog synced", map[string]any{
+				"configured_plans": len(c.configured),
+				"provider_plans":   synced,
+			})
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// planFromConfig maps a plan of the plans file
+func planFromConfig(plan config.PlanConfig) domain.Plan {
+	entitlements := domain.PlanEntitlements{
+		Features: make(map[string]bool, len(plan.Features)),
+		Limits:   make(map[string]int64, len(plan.Limits)),
+		Meters:   make(map[string]int64, len(plan.Meters)),
+	}
+	for feature, enabled := range plan.Features {
+		entitlements.Features[feature] = enabled
+	}
+	for name, limit := range plan.Limits {
+		entitlements.Limits[name] = limit
+	}
+	for meterSlug, allowance := range plan.Meters {
+		entitlements.Meters[meterSlug] = allowance
+	}
+
+	name := plan.Name
+	if name == "" {
+		name = plan.Key
+	}
+
+	return domain.Plan{
+		Key:          strings.TrimSpace(plan.Key),
+		Name:         name,
+		ProductIDs:   append([]string(nil), plan.ProductIDs...),
+		Entitlements: entitlements,
+		Source:       domain.PlanSourceFile,
+	}
+}
+
+// entitlementsFromMetadata reads the entitlements granted by product metadata:
+// "feature:<name>" booleans, "limit:<name>" and max_seats numeric limits, and
+// the meter allowances. Invalid values are logged and skipped.
+func entitlementsFromMetadata(metadata map[string]string, log logger.Logger) domain.PlanEntitlements {
+	entitlements := domain.PlanEntitlements{
+		Features: make(map[string]bool),
+		Limits:   make(map[string]int64),
+		Meters:   meterAllowancesFromMetadata(metadata, log),
+	}
+
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := strings.TrimSpace(metadata[key])
+
+		if feature, ok := strings.CutPrefix(key, featureMetadataPrefix); ok && feature != "" {
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				log.Warn("Ignoring invalid feature in metadata", map[string]any{
+					"key":   key,
+					"value": value,
+				})
+				continue
+			}
+			entitlements.Features[feature] = enabled
+			continue
+		}
+
+		name, ok := strings.CutPrefix(key, limitMetadataPrefix)
+		if !ok && key == domain.LimitMaxSeats {
+			name, ok = key, true
+		}
+		if !ok || name == "" {
+			continue
+		}
+		limit, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || limit < 0 {
+			log.Warn("Ignoring invalid limit in metadata", map[string]any{
+				"key":   key,
+				"value": value,
+			})
+			continue
+		}
+		entitlements.Limits[name] = limit
+	}
+
+	return entitlements
+}
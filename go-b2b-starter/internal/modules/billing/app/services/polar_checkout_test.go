@@ -0,0 +1,461 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/app/services"
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/config"
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+	polaradapter "github.com/moasq/go-b2b-starter/internal/modules/billing/infra/polar"
+	"github.com/moasq/go-b2b-starter/internal/platform/eventbus"
+	"github.com/moasq/go-b2b-starter/internal/platform/logger"
+	"github.com/moasq/go-b2b-starter/internal/platform/polar"
+	"github.com/moasq/go-b2b-starter/internal/platform/polar/polartest"
+)
+
+const (
+	testOrganizationID = int32(1)
+	testStytchOrgID    = "org_1"
+	testProductID      = "prod_pro"
+)
+
+// TestPolarCheckoutToQuotas runs a checkout against the fake Polar API: the
+// checkout is created and paid, the signed subscription webhook is received
+// over HTTP, and the subscription and quota rows it stores are checked.
+func TestPolarCheckoutToQuotas(t *testing.T) {
+	ctx := context.Background()
+
+	fake := polartest.NewServer()
+	defer fake.Close()
+	fake.SetProducts(polartest.Product{
+		ID:   testProductID,
+		Name: "Pro",
+		Metadata: map[string]string{
+			"plan":            "pro",
+			"document_count":  "100",
+			"max_seats":       "5",
+			"feature:ai_chat": "true",
+		},
+	})
+
+	env := newPolarEnv(t, fake)
+
+	session, err := env.billing.CreateCheckoutSession(ctx, testOrganizationID, testProductID)
+	if err != nil {
+		t.Fatalf("CreateCheckoutSession: %v", err)
+	}
+	created, ok := fake.CheckoutSession(session.ID)
+	if !ok {
+		t.Fatalf("checkout %s not created at the fake", session.ID)
+	}
+	if created.ExternalCustomerID != testStytchOrgID || created.ProductID != testProductID {
+		t.Fatalf("checkout = %+v, want customer %s and product %s", created, testStytchOrgID, testProductID)
+	}
+
+	if _, err := fake.CompleteCheckout(session.ID); err != nil {
+		t.Fatalf("CompleteCheckout: %v", err)
+	}
+	if err := fake.SendSubscriptionWebhook(ctx, env.webhookURL, "subscription.created", testStytchOrgID); err != nil {
+		t.Fatalf("SendSubscriptionWebhook: %v", err)
+	}
+
+	subscription, err := env.repo.GetSubscriptionByOrgID(ctx, testOrganizationID)
+	if err != nil {
+		t.Fatalf("subscription not stored: %v", err)
+	}
+	if subscription.SubscriptionStatus != "active" || subscription.ProductID != testProductID {
+		t.Errorf("subscription = %s on %s, want active on %s",
+			subscription.SubscriptionStatus, subscription.ProductID, testProductID)
+	}
+
+	quota, err := env.repo.GetQuotaByOrgID(ctx, testOrganizationID)
+	if err != nil {
+		t.Fatalf("quota not stored: %v", err)
+	}
+	if quota.MaxSeats != 5 {
+		t.Errorf("max seats = %d, want 5", quota.MaxSeats)
+	}
+
+	meterQuota, err := env.repo.GetMeterQuota(ctx, testOrganizationID, domain.MeterDocumentProcessed)
+	if err != nil {
+		t.Fatalf("meter quota not stored: %v", err)
+	}
+	if meterQuota.Allowance != 100 {
+		t.Errorf("%s allowance = %d, want 100", domain.MeterDocumentProcessed, meterQuota.Allowance)
+	}
+
+	if statuses := env.webhooks.statuses(); !slices.Equal(statuses, []string{"processed"}) {
+		t.Errorf("webhook statuses = %v, want [processed]", statuses)
+	}
+
+	// The redirect verification reads the paid checkout back from the fake
+	status, err := env.billing.VerifyPaymentFromCheckout(ctx, session.ID)
+	if err != nil {
+		t.Fatalf("VerifyPaymentFromCheckout: %v", err)
+	}
+	if !status.HasActiveSubscription {
+		t.Errorf("verified status = %+v, want an active subscription", status)
+	}
+
+	// A subscriber changes plans through the portal instead
+	if _, err := env.billing.CreateCheckoutSession(ctx, testOrganizationID, testProductID); !errors.Is(err, domain.ErrSubscriptionExists) {
+		t.Errorf("second checkout error = %v, want %v", err, domain.ErrSubscriptionExists)
+	}
+}
+
+// TestPolarWebhookSignature checks that webhooks signed by the fake are
+// accepted and that others are rejected before anything is recorded.
+func TestPolarWebhookSignature(t *testing.T) {
+	ctx := context.Background()
+
+	fake := polartest.NewServer()
+	defer fake.Close()
+	fake.SetSubscription(polartest.Subscription{ExternalCustomerID: testStytchOrgID, ProductID: testProductID})
+
+	env := newPolarEnv(t, fake)
+
+	payload, err := polartest.WebhookPayload("customer.updated", map[string]any{
+		"external_id": testStytchOrgID,
+		"metadata":    map[string]string{},
+	})
+	if err != nil {
+		t.Fatalf("WebhookPayload: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		headers http.Header
+		wantErr error
+	}{
+		{
+			name:    "wrong secret",
+			headers: polartest.SignWebhook("polar_whs_other", "msg_1", time.Now(), payload),
+			wantErr: domain.ErrWebhookSignatureInvalid,
+		},
+		{
+			name:    "stale timestamp",
+			headers: polartest.SignWebhook(polartest.WebhookSecret, "msg_2", time.Now().Add(-time.Hour), payload),
+			wantErr: domain.ErrWebhookTimestampStale,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := env.webhookService.ReceiveWebhook(ctx, tt.headers, payload); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ReceiveWebhook error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+	if statuses := env.webhooks.statuses(); len(statuses) != 0 {
+		t.Errorf("rejected webhooks were recorded: %v", statuses)
+	}
+
+	if err := fake.SendSubscriptionWebhook(ctx, env.webhookURL, "subscription.updated", testStytchOrgID); err != nil {
+		t.Fatalf("signed webhook rejected: %v", err)
+	}
+}
+
+// polarEnv wires the billing services to the fake Polar API, with in-memory
+// repositories in place of the database
+type polarEnv struct {
+	billing        services.BillingService
+	webhookService services.WebhookService
+	repo           *memorySubscriptionRepository
+	webhooks       *memoryWebhookRepository
+	webhookURL     string
+}
+
+func newPolarEnv(t *testing.T, fake *polartest.Server) *polarEnv {
+	t.Helper()
+
+	log := logger.New(logger.WithLevel(logger.ErrorLevel))
+	cfg := config.Config{
+		Provider:              config.ProviderPolar,
+		CheckoutSuccessURL:    "https://app.example.com/billing/success",
+		SeatGracePeriod:       7 * 24 * time.Hour,
+		DunningGracePeriod:    3 * 24 * time.Hour,
+		DunningReadOnlyPeriod: 4 * 24 * time.Hour,
+	}
+
+	client, err := polar.NewClient(fake.Config())
+	if err != nil {
+		t.Fatalf("polar.NewClient: %v", err)
+	}
+	provider := polaradapter.NewPolarAdapter(client, log)
+
+	catalog, err := services.NewPlanCatalog(provider, cfg, log)
+	if err != nil {
+		t.Fatalf("NewPlanCatalog: %v", err)
+	}
+	if err := catalog.Sync(context.Background()); err != nil {
+		t.Fatalf("catalog sync: %v", err)
+	}
+
+	env := &polarEnv{
+		repo:     newMemorySubscriptionRepository(),
+		webhooks: &memoryWebhookRepository{},
+	}
+	orgs := memoryOrganizations{testOrganizationID: testStytchOrgID}
+	env.billing = services.NewBillingService(env.repo, orgs, provider, polaradapter.NewWebhookParser(log),
+		catalog, eventbus.NewInMemoryEventBus(), cfg, log)
+	env.webhookService = services.NewWebhookService(env.webhooks, polaradapter.NewWebhookVerifier(fake.Config()),
+		env.billing, log)
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if _, err := env.webhookService.ReceiveWebhook(r.Context(), r.Header, payload); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(receiver.Close)
+	env.webhookURL = receiver.URL
+
+	return env
+}
+
+// memoryOrganizations maps internal organization IDs to Stytch org IDs
+type memoryOrganizations map[int32]string
+
+func (m memoryOrganizations) GetStytchOrgID(_ context.Context, organizationID int32) (string, error) {
+	stytchOrgID, ok := m[organizationID]
+	if !ok {
+		return "", errors.New("organization not found")
+	}
+	return stytchOrgID, nil
+}
+
+func (m memoryOrganizations) GetOrganizationIDByStytchOrgID(_ context.Context, stytchOrgID string) (int32, error) {
+	for organizationID, id := range m {
+		if id == stytchOrgID {
+			return organizationID, nil
+		}
+	}
+	return 0, errors.New("organization not found")
+}
+
+func (m memoryOrganizations) CountActiveMembers(context.Context, int32) (int64, error) {
+	return 1, nil
+}
+
+// memorySubscriptionRepository keeps the subscription, quota and meter quota
+// rows written by a checkout. Other operations are not implemented and panic
+// through the nil embedded interface.
+type memorySubscriptionRepository struct {
+	domain.SubscriptionRepository
+
+	mu            sync.Mutex
+	subscriptions map[int32]domain.Subscription
+	quotas        map[int32]domain.QuotaTracking
+	meterQuotas   map[int32]map[string]domain.MeterQuota
+}
+
+func newMemorySubscriptionRepository() *memorySubscriptionRepository {
+	return &memorySubscriptionRepository{
+		subscriptions: make(map[int32]domain.Subscription),
+		quotas:        make(map[int32]domain.QuotaTracking),
+		meterQuotas:   make(map[int32]map[string]domain.MeterQuota),
+	}
+}
+
+func (r *memorySubscriptionRepository) GetSubscriptionByOrgID(_ context.Context, organizationID int32) (*domain.Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	subscription, ok := r.subscriptions[organizationID]
+	if !ok {
+		return nil, domain.ErrSubscriptionNotFound
+	}
+	return &subscription, nil
+}
+
+func (r *memorySubscriptionRepository) UpsertSubscription(_ context.Context, subscription *domain.Subscription) (*domain.Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *subscription
+	if stored.SubscriptionStatus == "past_due" && stored.PastDueSince == nil {
+		now := time.Now()
+		stored.PastDueSince = &now
+	}
+	r.subscriptions[subscription.OrganizationID] = stored
+	return &stored, nil
+}
+
+func (r *memorySubscriptionRepository) GetQuotaByOrgID(_ context.Context, organizationID int32) (*domain.QuotaTracking, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	quota, ok := r.quotas[organizationID]
+	if !ok {
+		return nil, domain.ErrQuotaNotFound
+	}
+	return &quota, nil
+}
+
+func (r *memorySubscriptionRepository) UpsertQuota(_ context.Context, quota *domain.QuotaTracking) (*domain.QuotaTracking, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *quota
+	stored.SeatGraceEndsAt = r.quotas[quota.OrganizationID].SeatGraceEndsAt
+	r.quotas[quota.OrganizationID] = stored
+	return &stored, nil
+}
+
+func (r *memorySubscriptionRepository) SetSeatGrace(_ context.Context, organizationID int32, endsAt *time.Time) (*domain.QuotaTracking, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	quota, ok := r.quotas[organizationID]
+	if !ok {
+		return nil, domain.ErrQuotaNotFound
+	}
+	quota.SeatGraceEndsAt = endsAt
+	r.quotas[organizationID] = quota
+	return &quota, nil
+}
+
+func (r *memorySubscriptionRepository) GetMeterQuota(_ context.Context, organizationID int32, meterSlug string) (*domain.MeterQuota, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	quota, ok := r.meterQuotas[organizationID][meterSlug]
+	if !ok {
+		return nil, domain.ErrMeterQuotaNotFound
+	}
+	return &quota, nil
+}
+
+func (r *memorySubscriptionRepository) ListMeterQuotas(_ context.Context, organizationID int32) ([]domain.MeterQuota, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	quotas := make([]domain.MeterQuota, 0, len(r.meterQuotas[organizationID]))
+	for _, quota := range r.meterQuotas[organizationID] {
+		quotas = append(quotas, quota)
+	}
+	slices.SortFunc(quotas, func(a, b domain.MeterQuota) int {
+		return strings.Compare(a.MeterSlug, b.MeterSlug)
+	})
+	return quotas, nil
+}
+
+func (r *memorySubscriptionRepository) UpsertMeterAllowance(_ context.Context, organizationID int32, meterSlug string, allowance int64, periodStart, periodEnd time.Time) (*domain.MeterQuota, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.meterQuotas[organizationID] == nil {
+		r.meterQuotas[organizationID] = make(map[string]domain.MeterQuota)
+	}
+	quota := r.meterQuotas[organizationID][meterSlug]
+	if !quota.PeriodStart.Equal(periodStart) {
+		quota.Consumed = 0
+	}
+	quota.OrganizationID = organizationID
+	quota.MeterSlug = meterSlug
+	quota.Allowance = allowance
+	quota.PeriodStart = periodStart
+	quota.PeriodEnd = periodEnd
+	r.meterQuotas[organizationID][meterSlug] = quota
+	return &quota, nil
+}
+
+func (r *memorySubscriptionRepository) ClearMeterAllowancesExcept(_ context.Context, organizationID int32, meterSlugs []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for meterSlug, quota := range r.meterQuotas[organizationID] {
+		if !slices.Contains(meterSlugs, meterSlug) {
+			quota.Allowance = 0
+			r.meterQuotas[organizationID][meterSlug] = quota
+		}
+	}
+	return nil
+}
+
+// memoryWebhookRepository records received webhooks by provider and webhook ID
+type memoryWebhookRepository struct {
+	mu       sync.Mutex
+	webhooks []domain.ReceivedWebhook
+}
+
+func (r *memoryWebhookRepository) Claim(_ context.Context, provider, webhookID, eventType string, payload []byte) (*domain.ReceivedWebhook, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, webhook := range r.webhooks {
+		if webhook.Provider == provider && webhook.WebhookID == webhookID {
+			return &webhook, false, nil
+		}
+	}
+
+	webhook := domain.ReceivedWebhook{
+		ID:         int64(len(r.webhooks) + 1),
+		Provider:   provider,
+		WebhookID:  webhookID,
+		EventType:  eventType,
+		Payload:    payload,
+		Status:     "processing",
+		Attempts:   1,
+		ReceivedAt: time.Now(),
+	}
+	r.webhooks = append(r.webhooks, webhook)
+	return &webhook, true, nil
+}
+
+func (r *memoryWebhookRepository) GetByID(_ context.Context, id int64) (*domain.ReceivedWebhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id < 1 || id > int64(len(r.webhooks)) {
+		return nil, domain.ErrWebhookNotFound
+	}
+	webhook := r.webhooks[id-1]
+	return &webhook, nil
+}
+
+func (r *memoryWebhookRepository) MarkProcessed(_ context.Context, id int64) error {
+	return r.mark(id, "processed", "")
+}
+
+func (r *memoryWebhookRepository) MarkFailed(_ context.Context, id int64, reason string) error {
+	return r.mark(id, "failed", reason)
+}
+
+func (r *memoryWebhookRepository) mark(id int64, status, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id < 1 || id > int64(len(r.webhooks)) {
+		return domain.ErrWebhookNotFound
+	}
+	r.webhooks[id-1].Status = status
+	r.webhooks[id-1].LastError = reason
+	return nil
+}
+
+// statuses lists the status of every recorded webhook in arrival order
+func (r *memoryWebhookRepository) statuses() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	statuses := make([]string, 0, len(r.webhooks))
+	for _, webhook := range r.webhooks {
+		statuses = append(statuses, webhook.Status)
+	}
+	return statuses
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain/events"
+)
+
+// ReconcileSubscription compares a stored subscription and its quota with the
+// billing provider and repairs any drift with the same writes a subscription
+// webhook would have made.
+func (s *billingService) ReconcileSubscription(ctx context.Context, stored *domain.Subscription) (*domain.ReconcileResult, error) {
+	result := &domain.ReconcileResult{
+		OrganizationID: stored.OrganizationID,
+		SubscriptionID: stored.SubscriptionID,
+	}
+
+	// Step 1: Fetch the provider's view of the subscription
+	current, err := s.fetchProviderSubscription(ctx, stored.OrganizationID)
+	if errors.Is(err, domain.ErrSubscriptionNotFound) {
+		if stored.SubscriptionStatus == "canceled" {
+			return result, nil
+		}
+
+		// Not repaired: an external ID mismatch would otherwise cancel a
+		// paying customer, so this needs a human to look at it
+		result.Drift = []domain.SubscriptionDrift{{
+			Field:    domain.DriftMissingAtProvider,
+			Stored:   stored.SubscriptionStatus,
+			Provider: "none",
+		}}
+		s.logger.Warn("Subscription not found at billing provider", map[string]any{
+			"organization_id": stored.OrganizationID,
+			"subscription_id": stored.SubscriptionID,
+			"status":          stored.SubscriptionStatus,
+		})
+		s.publishSubscriptionReconciled(ctx, stored.SubscriptionStatus, result)
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.SubscriptionID = current.SubscriptionID
+
+	// Step 2: Compare subscription and quota
+	subscriptionDrift := compareSubscriptions(stored, current)
+
+	productMetadata := productMetadataOf(current)
+	quotaDrift, err := s.compareQuotas(ctx, current, productMetadata)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Drift = append(subscriptionDrift, quotaDrift...)
+	if len(result.Drift) == 0 {
+		return result, nil
+	}
+
+	// Step 3: Repair drift
+	if len(subscriptionDrift) > 0 {
+		if _, err := s.repo.UpsertSubscription(ctx, current); err != nil {
+			return nil, fmt.Errorf("failed to save subscription: %w", err)
+		}
+	}
+
+	// Product and period changes move the quotas as well
+	if len(quotaDrift) > 0 || hasDrift(subscriptionDrift, domain.DriftProduct, domain.DriftPeriodStart, domain.DriftPeriodEnd) {
+		if _, err := s.applyProductQuotas(ctx, current.OrganizationID, current.ProductID, productMetadata,
+			current.CurrentPeriodStart, current.CurrentPeriodEnd); err != nil {
+			return nil, fmt.Errorf("failed to save quota: %w", err)
+		}
+	}
+	result.Repaired = true
+
+	s.logger.Warn("Repaired subscription drift", map[string]any{
+		"organization_id": current.OrganizationID,
+		"subscription_id": current.SubscriptionID,
+		"drift":           result.Drift,
+	})
+
+	// Step 4: Announce the correction
+	if len(subscriptionDrift) > 0 {
+		s.publishSubscriptionChanged(ctx, current)
+		s.publishLifecycleEvents(ctx, stored, current)
+	}
+	s.publishSubscriptionReconciled(ctx, current.SubscriptionStatus, result)
+
+	return result, nil
+}
+
+// compareSubscriptions lists the fields a webhook would have updated that
+// differ between the stored and the provider subscription
+func compareSubscriptions(stored, current *domain.Subscription) []domain.SubscriptionDrift {
+	var drift []domain.SubscriptionDrift
+	add := func(field, storedValue, providerValue string) {
+		if storedValue != providerValue {
+			drift = append(drift, domain.SubscriptionDrift{Field: field, Stored: storedValue, Provider: providerValue})
+		}
+	}
+
+	add(domain.DriftSubscriptionID, stored.SubscriptionID, current.SubscriptionID)
+	add(domain.DriftStatus, stored.SubscriptionStatus, current.SubscriptionStatus)
+	add(domain.DriftProduct, stored.ProductID, current.ProductID)
+	add(domain.DriftPeriodStart, formatDriftTime(stored.CurrentPeriodStart), formatDriftTime(current.CurrentPeriodStart))
+	add(domain.DriftPeriodEnd, formatDriftTime(stored.CurrentPeriodEnd), formatDriftTime(current.CurrentPeriodEnd))
+	add(domain.DriftCancelAtPeriodEnd, strconv.FormatBool(stored.CancelAtPeriodEnd), strconv.FormatBool(current.CancelAtPeriodEnd))
+
+	return drift
+}
+
+// compareQuotas checks the seat limit and quota period, and that every meter
+// granted by the product exists for the current period. Allowance values are
+// not compared: customer metadata and meter grants adjust them legitimately.
+func (s *billingService) compareQuotas(ctx context.Context, current *domain.Subscription, productMetadata map[string]string) ([]domain.SubscriptionDrift, error) {
+	var drift []domain.SubscriptionDrift
+	entitlements, _ := s.productEntitlements(current.ProductID, productMetadata)
+	periodStart := formatDriftTime(current.CurrentPeriodStart)
+	periodEnd := formatDriftTime(current.CurrentPeriodEnd)
+
+	quota, err := s.repo.GetQuotaByOrgID(ctx, current.OrganizationID)
+	if err == nil && rolledOverPast(quota, current.CurrentPeriodEnd) {
+		// Rolled over ahead of the provider's renewal: not drift
+		periodStart = formatDriftTime(quota.PeriodStart)
+		periodEnd = formatDriftTime(quota.PeriodEnd)
+	}
+	switch {
+	case errors.Is(err, domain.ErrQuotaNotFound):
+		drift = append(drift, domain.SubscriptionDrift{
+			Field:    domain.DriftMaxSeats,
+			Stored:   "none",
+			Provider: strconv.Itoa(int(maxSeatsOf(entitlements))),
+		})
+	case err != nil:
+		return nil, fmt.Errorf("failed to get quota: %w", err)
+	default:
+		if maxSeats := maxSeatsOf(entitlements); quota.MaxSeats != maxSeats {
+			drift = append(drift, domain.SubscriptionDrift{
+				Field:    domain.DriftMaxSeats,
+				Stored:   strconv.Itoa(int(quota.MaxSeats)),
+				Provider: strconv.Itoa(int(maxSeats)),
+			})
+		}
+		stored := formatDriftTime(quota.PeriodStart) + "/" + formatDriftTime(quota.PeriodEnd)
+		if expected := periodStart + "/" + periodEnd; stored != expected {
+			drift = append(drift, domain.SubscriptionDrift{Field: domain.DriftQuotaPeriod, Stored: stored, Provider: expected})
+		}
+	}
+
+	meterQuotas, err := s.repo.ListMeterQuotas(ctx, current.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	storedMeters := make(map[string]domain.MeterQuota, len(meterQuotas))
+	for _, meterQuota := range meterQuotas {
+		storedMeters[meterQuota.MeterSlug] = meterQuota
+	}
+
+	allowances := entitlements.Meters
+	meterSlugs := make([]string, 0, len(allowances))
+	for meterSlug := range allowances {
+		meterSlugs = append(meterSlugs, meterSlug)
+	}
+	sort.Strings(meterSlugs)
+
+	for _, meterSlug := range meterSlugs {
+		meterQuota, ok := storedMeters[meterSlug]
+		switch {
+		case !ok:
+			drift = append(drift, domain.SubscriptionDrift{
+				Field:    domain.DriftMeterAllowances,
+				Stored:   meterSlug + " missing",
+				Provider: fmt.Sprintf("%s %d", meterSlug, allowances[meterSlug]),
+			})
+		case formatDriftTime(meterQuota.PeriodStart) != periodStart:
+			drift = append(drift, domain.SubscriptionDrift{
+				Field:    domain.DriftMeterAllowances,
+				Stored:   meterSlug + " period " + formatDriftTime(meterQuota.PeriodStart),
+				Provider: meterSlug + " period " + periodStart,
+			})
+		}
+	}
+
+	return drift, nil
+}
+
+// formatDriftTime renders a timestamp at second precision; the database and
+// the providers store different sub-second precision
+func formatDriftTime(t time.Time) string {
+	if t.IsZero() {
+		return "none"
+	}
+	return t.UTC().Truncate(time.Second).Format(time.RFC3339)
+}
+
+func hasDrift(drift []domain.SubscriptionDrift, fields ...string) bool {
+	for _, d := range drift {
+		for _, field := range fields {
+			if d.Field == field {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// publishSubscriptionReconciled announces a reconciliation finding. The
+// repair has been stored at this point, so a publish failure is only logged.
+func (s *billingService) publishSubscriptionReconciled(ctx context.Context, status string, result *domain.ReconcileResult) {
+	drift := make([]events.DriftField, 0, len(result.Drift))
+	for _, d := range result.Drift {
+		drift = append(drift, events.DriftField{Field: d.Field, Stored: d.Stored, Provider: d.Provider})
+	}
+
+	event := events.NewSubscriptionReconciled(result.OrganizationID, result.SubscriptionID, status, drift, result.Repaired)
+	if err := s.eventBus.Publish(ctx, event); err != nil {
+		s.logger.Warn("Failed to publish subscription reconciled event", map[string]any{
+			"organization_id": result.OrganizationID,
+			"subscription_id": result.SubscriptionID,
+			"error":           err.Error(),
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/config"
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+	logger "github.com/moasq/go-b2b-starter/internal/platform/logger/domain"
+)
+
+const (
+	// reconcileTimeout bounds the comparison and repair of one subscription
+	reconcileTimeout = 30 * time.Second
+
+	// reconcileRateLimitAttempts is how often a rate limited subscription is
+	// tried before it is counted as failed for the pass
+	reconcileRateLimitAttempts = 3
+)
+
+// Reconciler periodically compares every stored subscription with the billing
+// provider, so a missed webhook is repaired without waiting for the paywall's
+// lazy refresh
+type Reconciler interface {
+	// Start launches the background job; it does nothing when reconciliation is disabled
+	Start() error
+
+	// Close stops the job, waiting for the subscription in progress
+	Close() error
+
+	// RunOnce makes a single pass over all subscriptions
+	RunOnce(ctx context.Context) (*domain.ReconcileReport, error)
+}
+
+// reconciler pages through subscriptions in ID order and reconciles them one
+// at a time, spacing provider calls to stay within the provider rate limit.
+//
+// Repairs are idempotent, so running it on several API instances is safe but
+// multiplies provider calls; set BILLING_RECONCILE_ENABLED on one instance.
+type reconciler struct {
+	service BillingService
+	repo    domain.SubscriptionRepository
+	config  config.Config
+	logger  logger.Logger
+
+	mu      sync.Mutex
+	started bool
+	closed  bool
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+func NewReconciler(
+	service BillingService,
+	repo domain.SubscriptionRepository,
+	cfg config.Config,
+	logger logger.Logger,
+) Reconciler {
+	return &reconciler{
+		service: service,
+		repo:    repo,
+		config:  cfg,
+		logger:  logger,
+		done:    make(chan struct{}),
+	}
+}
+
+// Start launches the reconciliation loop
+func (r *reconciler) Start() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return fmt.Errorf("subscription reconciler is closed")
+	}
+	if r.started {
+		return nil
+	}
+	if !r.config.ReconcileEnabled {
+		r.logger.Info("Subscription reconciliation disabled")
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.started = true
+
+	go r.run(ctx)
+
+	r.logger.Info("Subscription reconciler started", map[string]any{
+		"interval":            r.config.ReconcileInterval.String(),
+		"batch_size":          r.config.ReconcileBatchSize,
+		"requests_per_second": r.config.ReconcileRequestsPerSecond,
+	})
+
+	return nil
+}
+
+// Close stops the loop. A pass in progress ends after the current
+// subscription; the next pass starts over.
+func (r *reconciler) Close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	r.closed = true
+	started := r.started
+	cancel := r.cancel
+	r.mu.Unlock()
+
+	if started {
+		cancel()
+		<-r.done
+	}
+	return nil
+}
+
+// run is the reconciliation loop; the first pass starts one interval after startup
+func (r *reconciler) run(ctx context.Context) {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.config.ReconcileInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		report, err := r.RunOnce(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				r.logger.Error("Subscription reconciliation failed", map[string]any{
+					"checked": report.Checked,
+					"error":   err.Error(),
+				})
+			}
+			continue
+		}
+
+		r.logger.Info("Subscription reconciliation completed", map[string]any{
+			"checked":  report.Checked,
+			"drifted":  report.Drifted,
+			"repaired": report.Repaired,
+			"failed":   report.Failed,
+			"duration": report.FinishedAt.Sub(report.StartedAt).String(),
+		})
+	}
+}
+
+// RunOnce reconciles every stored subscription. A subscription that fails is
+// counted and skipped; only listing errors and cancellation end the pass.
+func (r *reconciler) RunOnce(ctx context.Context) (*domain.ReconcileReport, error) {
+	report := &domain.ReconcileReport{StartedAt: time.Now()}
+
+	// Space provider calls evenly to stay within the rate limit
+	limiter := time.NewTicker(time.Duration(float64(time.Second) / r.config.ReconcileRequestsPerSecond))
+	defer limiter.Stop()
+
+	var afterID int32
+	for {
+		batch, err := r.repo.ListSubscriptionsAfter(ctx, afterID, r.config.ReconcileBatchSize)
+		if err != nil {
+			reconcileRuns.WithLabelValues(runResult(ctx)).Inc()
+			return report, fmt.Errorf("failed to list subscriptions: %w", err)
+		}
+
+		for i := range batch {
+			subscription := &batch[i]
+			afterID = subscription.ID
+
+			result, err := r.reconcile(ctx, limiter, subscription)
+			if ctx.Err() != nil {
+				reconcileRuns.WithLabelValues(runResult(ctx)).Inc()
+				return report, ctx.Err()
+			}
+
+			report.Checked++
+			reconcileChecked.Inc()
+
+			if err != nil {
+				report.Failed++
+				reconcileFailed.Inc()
+				r.logger.Error("Failed to reconcile subscription", map[string]any{
+					"organization_id": subscription.OrganizationID,
+					"subscription_id": subscription.SubscriptionID,
+					"error":           err.Error(),
+				})
+				continue
+			}
+
+			if len(result.Drift) > 0 {
+				report.Drifted++
+				for _, drift := range result.Drift {
+					reconcileDrift.WithLabelValues(drift.Field).Inc()
+				}
+			}
+			if result.Repaired {
+				report.Repaired++
+				reconcileRepaired.Inc()
+			}
+		}
+
+		if len(batch) < int(r.config.ReconcileBatchSize) {
+			break
+		}
+	}
+
+	report.FinishedAt = time.Now()
+	reconcileRuns.WithLabelValues("completed").Inc()
+	reconcileLastCompleted.Set(float64(report.FinishedAt.Unix()))
+
+	return report, nil
+}
+
+// reconcile waits for the rate limiter and reconciles one subscription,
+// backing off when the provider reports its rate limit exceeded
+func (r *reconciler) reconcile(ctx context.Context, limiter *time.Ticker, subscription *domain.Subscription) (*domain.ReconcileResult, error) {
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-limiter.C:
+		}
+
+		// A started repair finishes even if shutdown was requested meanwhile
+		reconcileCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), reconcileTimeout)
+		result, err := r.service.ReconcileSubscription(reconcileCtx, subscription)
+		cancel()
+
+		if !errors.Is(err, domain.ErrProviderRateLimited) {
+			return result, err
+		}
+
+		reconcileRateLimited.Inc()
+		if attempt == reconcileRateLimitAttempts {
+			return nil, err
+		}
+		r.logger.Warn("Billing provider rate limit reached, backing off", map[string]any{
+			"organization_id": subscription.OrganizationID,
+			"attempt":         attempt,
+			"retry_in":        r.config.ReconcileRateLimitBackoff.String(),
+		})
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(r.config.ReconcileRateLimitBackoff):
+		}
+	}
+}
+
+// runResult labels a pass that ended early
+func runResult(ctx context.Context) string {
+	if ctx.Err() != nil {
+		return "canceled"
+	}
+	return "failed"
+}
@@ -23,8 +23,7 @@ func (s *billingService) RefreshSubscriptionStatus(ctx context.Context, organiza
 		return &domain.BillingStatus{
 			OrganizationID:        organizationID,
 			HasActiveSubscription: false,
-			CanProcessInvoices:    false,
-			InvoiceCount:          0,
+			Quotas:                []domain.MeterQuota{},
 			Reason:                "no active subscription found",
 			CheckedAt:             time.Now(),
 		}, nil
@@ -45,12 +44,12 @@ func (s *billingService) RefreshSubscriptionStatus(ctx context.Context, organiza
 	s.logger.Info("Subscription status refreshed", map[string]any{
 		"organization_id":         organizationID,
 		"has_active_subscription": billingStatus.HasActiveSubscription,
-		"invoice_count":           billingStatus.InvoiceCount,
+		"meter_quotas":            len(billingStatus.Quotas),
 	})
 
 	// Console log for refresh completion
-	fmt.Printf("🔄 SUBSCRIPTION REFRESHED - Org: %d | Active: %v | Invoice Count: %d | Reason: %s\n",
-		organizationID, billingStatus.HasActiveSubscription, billingStatus.InvoiceCount, billingStatus.Reason)
+	fmt.Printf("🔄 SUBSCRIPTION REFRESHED - Org: %d | Active: %v | Meters: %d | Reason: %s\n",
+		organizationID, billingStatus.HasActiveSubscription, len(billingStatus.Quotas), billingStatus.Reason)
 
 	return billingStatus, nil
 }
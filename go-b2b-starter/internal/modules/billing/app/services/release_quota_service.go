@@ -0,0 +1,24 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+)
+
+// ReleaseQuota returns the units of a reservation to its meter after the work
+// failed. Releasing a reservation that is already gone is a no-op.
+func (s *billingService) ReleaseQuota(ctx context.Context, reservation *domain.QuotaReservation) error {
+	err := s.repo.ReleaseQuotaReservation(ctx, reservation.ID)
+	if err != nil && !errors.Is(err, domain.ErrReservationNotFound) {
+		s.logger.Error("Failed to release quota reservation", map[string]any{
+			"organization_id": reservation.OrganizationID,
+			"meter_slug":      reservation.MeterSlug,
+			"reservation_id":  reservation.ID,
+			"error":           err.Error(),
+		})
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+)
+
+// ReserveQuota holds units of a meter before the work they pay for runs, so
+// concurrent requests cannot overdraw the allowance while it is in progress.
+// Held units are unavailable to other reservations and to Consume until the
+// reservation is committed, released or expires.
+func (s *billingService) ReserveQuota(ctx context.Context, usage *domain.UsageRecord) (*domain.QuotaReservation, error) {
+	if usage.Amount <= 0 {
+		return nil, domain.ErrInvalidUsageAmount
+	}
+
+	// Units held by requests that never finished are free again once expired
+	now := time.Now().UTC()
+	released, err := s.repo.ReleaseExpiredReservations(ctx, usage.OrganizationID, usage.MeterSlug, now)
+	if err != nil {
+		s.logger.Warn("Failed to release expired quota reservations", map[string]any{
+			"organization_id": usage.OrganizationID,
+			"meter_slug":      usage.MeterSlug,
+			"error":           err.Error(),
+		})
+	} else if released > 0 {
+		s.logger.Info("Released expired quota reservations", map[string]any{
+			"organization_id": usage.OrganizationID,
+			"meter_slug":      usage.MeterSlug,
+			"released":        released,
+		})
+	}
+
+	expiresAt := now.Add(s.config.QuotaReservationTTL)
+	reservation, err := s.repo.ReserveMeterQuota(ctx, usage, expiresAt)
+	if !errors.Is(err, domain.ErrQuotaExceeded) && !errors.Is(err, domain.ErrMeterQuotaNotFound) {
+		return reservation, err
+	}
+
+	// Before refusing, re-check with the provider in case a webhook was missed
+	if _, err := s.CheckQuota(ctx, usage.OrganizationID, usage.MeterSlug, usage.Amount); err != nil {
+		return nil, err
+	}
+
+	reservation, err = s.repo.ReserveMeterQuota(ctx, usage, expiresAt)
+	if errors.Is(err, domain.ErrMeterQuotaNotFound) {
+		return nil, domain.ErrQuotaExceeded
+	}
+	return reservation, err
+}
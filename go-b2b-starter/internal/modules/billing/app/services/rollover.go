@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/config"
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+	logger "github.com/moasq/go-b2b-starter/internal/platform/logger/domain"
+)
+
+// rolloverTimeout bounds the rollover of one organization's quota period
+const rolloverTimeout = 30 * time.Second
+
+// PeriodRollover starts the next quota period for renewing subscriptions whose
+// period has ended, so quotas reset on time even when the renewal webhook is late
+type PeriodRollover interface {
+	// Start launches the background job; it does nothing when rollover is disabled
+	Start() error
+
+	// Close stops the job, waiting for the organization in progress
+	Close() error
+
+	// RunOnce rolls over every quota period that has ended
+	RunOnce(ctx context.Context) (*domain.RolloverReport, error)
+}
+
+// periodRollover pages through ended quota periods in organization ID order
+// and rolls them over one at a time, spacing provider calls to stay within
+// the provider rate limit.
+//
+// A rollover is idempotent: meters already in the new period keep their
+// consumption, so running the job on several API instances is safe.
+type periodRollover struct {
+	service BillingService
+	repo    domain.SubscriptionRepository
+	config  config.Config
+	logger  logger.Logger
+
+	mu      sync.Mutex
+	started bool
+	closed  bool
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+func NewPeriodRollover(
+	service BillingService,
+	repo domain.SubscriptionRepository,
+	cfg config.Config,
+	logger logger.Logger,
+) PeriodRollover {
+	return &periodRollover{
+		service: service,
+		repo:    repo,
+		config:  cfg,
+		logger:  logger,
+		done:    make(chan struct{}),
+	}
+}
+
+// Start launches the rollover loop
+func (r *periodRollover) Start() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return fmt.Errorf("quota period rollover is closed")
+	}
+	if r.started {
+		return nil
+	}
+	if !r.config.RolloverEnabled {
+		r.logger.Info("Quota period rollover disabled")
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.started = true
+
+	go r.run(ctx)
+
+	r.logger.Info("Quota period rollover started", map[string]any{
+		"interval":            r.config.RolloverInterval.String(),
+		"batch_size":          r.config.RolloverBatchSize,
+		"requests_per_second": r.config.RolloverRequestsPerSecond,
+	})
+
+	return nil
+}
+
+// Close stops the loop. A pass in progress ends after the current
+// organization; the rest are picked up by the next pass.
+func (r *periodRollover) Close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	r.closed = true
+	started := r.started
+	cancel := r.cancel
+	r.mu.Unlock()
+
+	if started {
+		cancel()
+		<-r.done
+	}
+	return nil
+}
+
+// run is the rollover loop
+func (r *periodRollover) run(ctx context.Context) {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.config.RolloverInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		report, err := r.RunOnce(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				r.logger.Error("Quota period rollover failed", map[string]any{
+					"rolled_over": report.RolledOver,
+					"error":       err.Error(),
+				})
+			}
+			continue
+		}
+
+		if report.RolledOver > 0 || report.Failed > 0 {
+			r.logger.Info("Quota period rollover completed", map[string]any{
+				"rolled_over": report.RolledOver,
+				"skipped":     report.Skipped,
+				"failed":      report.Failed,
+				"duration":    report.FinishedAt.Sub(report.StartedAt).String(),
+			})
+		}
+	}
+}
+
+// RunOnce rolls over every quota period that ended before the pass started.
+// An organization that fails is counted and skipped; only listing errors and
+// cancellation end the pass.
+func (r *periodRollover) RunOnce(ctx context.Context) (*domain.RolloverReport, error) {
+	report := &domain.RolloverReport{StartedAt: time.Now()}
+
+	// Space provider calls evenly to stay within the rate limit
+	limiter := time.NewTicker(time.Duration(float64(time.Second) / r.config.RolloverRequestsPerSecond))
+	defer limiter.Stop()
+
+	var afterOrganizationID int32
+	for {
+		batch, err := r.repo.ListQuotasDueForRollover(ctx, report.StartedAt, afterOrganizationID, r.config.RolloverBatchSize)
+		if err != nil {
+			rolloverRuns.WithLabelValues(runResult(ctx)).Inc()
+			return report, err
+		}
+
+		for _, quota := range batch {
+			afterOrganizationID = quota.OrganizationID
+
+			select {
+			case <-ctx.Done():
+				rolloverRuns.WithLabelValues(runResult(ctx)).Inc()
+				return report, ctx.Err()
+			case <-limiter.C:
+			}
+
+			// A started rollover finishes even if shutdown was requested meanwhile
+			rolloverCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), rolloverTimeout)
+			rollover, err := r.service.RolloverQuotaPeriod(rolloverCtx, quota.OrganizationID)
+			cancel()
+
+			switch {
+			case errors.Is(err, domain.ErrPeriodNotEnded), errors.Is(err, domain.ErrSubscriptionNotActive):
+				report.Skipped++
+				rolloverSkipped.Inc()
+			case err != nil:
+				report.Failed++
+				rolloverFailed.Inc()
+				r.logger.Error("Failed to roll quota period over", map[string]any{
+					"organization_id": quota.OrganizationID,
+					"period_end":      quota.PeriodEnd,
+					"error":           err.Error(),
+				})
+			default:
+				report.RolledOver++
+				periodsRolledOver.WithLabelValues(rollover.Source).Inc()
+			}
+		}
+
+		if len(batch) < int(r.config.RolloverBatchSize) {
+			break
+		}
+	}
+
+	report.FinishedAt = time.Now()
+	rolloverRuns.WithLabelValues("completed").Inc()
+
+	return report, nil
+}
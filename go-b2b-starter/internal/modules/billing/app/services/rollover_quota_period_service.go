@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain/events"
+)
+
+// RolloverQuotaPeriod starts the next billing period for an organization whose
+// quota period has ended.
+// The provider's renewed period is used when it has one; otherwise the period
+// is advanced locally, so a late renewal webhook never leaves a renewing
+// customer at zero. Meters are reset, with the closed period archived to the
+// meter quota history, and allowances restored from the plan.
+func (s *billingService) RolloverQuotaPeriod(ctx context.Context, organizationID int32) (*domain.PeriodRollover, error) {
+	now := time.Now()
+
+	// Step 1: Load the closed period
+	quota, err := s.repo.GetQuotaByOrgID(ctx, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quota: %w", err)
+	}
+	if quota.PeriodEnd.After(now) {
+		return nil, domain.ErrPeriodNotEnded
+	}
+
+	subscription, err := s.repo.GetSubscriptionByOrgID(ctx, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscription: %w", err)
+	}
+
+	closed, err := s.repo.ListMeterQuotas(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: Prefer the provider's renewed period
+	source := domain.RolloverSourceLocal
+	var periodStart, periodEnd time.Time
+
+	current, err := s.fetchProviderSubscription(ctx, organizationID)
+	if err != nil {
+		s.logger.Warn("Could not fetch subscription from provider, rolling quota period over locally", map[string]any{
+			"organization_id": organizationID,
+			"error":           err.Error(),
+		})
+	} else {
+		if len(compareSubscriptions(subscription, current)) > 0 {
+			if _, err := s.repo.UpsertSubscription(ctx, current); err != nil {
+				return nil, fmt.Errorf("failed to save subscription: %w", err)
+			}
+			s.publishSubscriptionChanged(ctx, current)
+			s.publishLifecycleEvents(ctx, subscription, current)
+		}
+		subscription = current
+
+		if current.CurrentPeriodEnd.After(now) {
+			source = domain.RolloverSourceProvider
+			periodStart, periodEnd = current.CurrentPeriodStart, current.CurrentPeriodEnd
+		}
+	}
+
+	// Step 3: Only renewing subscriptions get a new period; others lapse
+	if !isActiveStatus(subscription.SubscriptionStatus) {
+		return nil, domain.ErrSubscriptionNotActive
+	}
+	if source == domain.RolloverSourceLocal {
+		if subscription.CancelAtPeriodEnd {
+			return nil, domain.ErrSubscriptionNotActive
+		}
+		periodStart, periodEnd, err = advancePeriod(quota.PeriodStart, quota.PeriodEnd, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Step 4: Reset meters, then restore the plan's allowances. When neither
+	// the plan catalog nor stored product metadata know the product, the
+	// meters keep their allowances.
+	if _, err := s.repo.ResetMeterPeriods(ctx, organizationID, periodStart, periodEnd); err != nil {
+		return nil, err
+	}
+	productMetadata := productMetadataOf(subscription)
+	if _, known := s.productEntitlements(subscription.ProductID, productMetadata); known {
+		if _, err := s.applyProductQuotas(ctx, organizationID, subscription.ProductID, productMetadata, periodStart, periodEnd); err != nil {
+			return nil, err
+		}
+	} else if _, err := s.repo.ResetQuotaPeriod(ctx, organizationID, periodStart, periodEnd); err != nil {
+		return nil, err
+	}
+
+	// The provider may only have extended the running period
+	if !periodStart.After(quota.PeriodStart) {
+		return nil, domain.ErrPeriodNotEnded
+	}
+
+	rollover := &domain.PeriodRollover{
+		OrganizationID:      organizationID,
+		PreviousPeriodStart: quota.PeriodStart,
+		PreviousPeriodEnd:   quota.PeriodEnd,
+		PeriodStart:         periodStart,
+		PeriodEnd:           periodEnd,
+		Source:              source,
+		Usage:               closed,
+	}
+
+	s.logger.Info("Rolled quota period over", map[string]any{
+		"organization_id": organizationID,
+		"period_start":    periodStart,
+		"period_end":      periodEnd,
+		"source":          source,
+	})
+
+	// Step 5: Announce the new period
+	s.publishPeriodRolledOver(ctx, rollover)
+
+	return rollover, nil
+}
+
+// advancePeriod steps a closed period forward until it contains now
+func advancePeriod(start, end, now time.Time) (time.Time, time.Time, error) {
+	if !end.After(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("cannot advance empty quota period %s - %s", start, end)
+	}
+	for !end.After(now) {
+		start, end = nextPeriod(start, end)
+	}
+	return start, end, nil
+}
+
+// nextPeriod estimates the billing period following [start, end): whole
+// calendar months when the period spans them, otherwise the same length.
+// The renewal webhook or reconciliation later sets the exact end.
+func nextPeriod(start, end time.Time) (time.Time, time.Time) {
+	months := (end.Year()-start.Year())*12 + int(end.Month()) - int(start.Month())
+	if months > 0 && start.AddDate(0, months, 0).Equal(end) {
+		return end, end.AddDate(0, months, 0)
+	}
+	return end, end.Add(end.Sub(start))
+}
+
+// isActiveStatus reports whether a subscription status grants access
+func isActiveStatus(status string) bool {
+	return status == "active" || status == "trialing"
+}
+
+// publishPeriodRolledOver announces a new quota period. The rollover has been
+// stored at this point, so a publish failure is only logged.
+func (s *billingService) publishPeriodRolledOver(ctx context.Context, rollover *domain.PeriodRollover) {
+	usage := make([]events.MeterUsage, 0, len(rollover.Usage))
+	for _, quota := range rollover.Usage {
+		usage = append(usage, events.MeterUsage{
+			MeterSlug: quota.MeterSlug,
+			Allowance: quota.Allowance,
+			Consumed:  quota.Consumed,
+		})
+	}
+
+	event := events.NewPeriodRolledOver(
+		rollover.OrganizationID,
+		rollover.PreviousPeriodStart,
+		rollover.PreviousPeriodEnd,
+		rollover.PeriodStart,
+		rollover.PeriodEnd,
+		rollover.Source,
+		usage,
+	)
+	if err := s.eventBus.Publish(ctx, event); err != nil {
+		s.logger.Warn("Failed to publish period rolled over event", map[string]any{
+			"organization_id": rollover.OrganizationID,
+			"error":           err.Error(),
+		})
+	}
+}
@@ -3,14 +3,17 @@ package services
 import (
 	"context"
 
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/config"
 	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+	"github.com/moasq/go-b2b-starter/internal/platform/eventbus"
 	logger "github.com/moasq/go-b2b-starter/internal/platform/logger/domain"
 )
 
 // BillingService handles subscription management and quota verification.
 //
-// This service manages the billing lifecycle with Polar.sh via event-driven webhooks.
-// It does NOT expose direct API calls to Polar during request handling - instead,
+// This service manages the billing lifecycle with the billing provider (Polar.sh or Stripe)
+// via event-driven webhooks.
+// It does NOT expose direct API calls to the provider during request handling - instead,
 // subscription state is synced via webhooks and stored locally for fast reads.
 //
 // Architecture:
@@ -25,38 +28,92 @@ import (
 //	                                 │ from local DB   │
 //	                                 └─────────────────┘
 type BillingService interface {
-	// ProcessWebhookEvent processes a Polar webhook event and updates local database
-	// Handles: subscription.created, subscription.updated, subscription.canceled, customer.updated
+	// ProcessWebhookEvent processes a billing provider webhook event and updates local database
+	// The provider's WebhookParser maps the payload; handles subscription upserts and
+	// cancellations, customer updates and meter grants
 	ProcessWebhookEvent(ctx context.Context, eventType string, payload map[string]any) error
 
 	// GetBillingStatus retrieves the current billing and quota status for an organization
 	// This is a read-only operation from the local database
 	GetBillingStatus(ctx context.Context, organizationID int32) (*domain.BillingStatus, error)
 
-	// CheckQuotaAvailability performs a read-only check of quota availability
-	// Does NOT consume quota - use ConsumeInvoiceQuota after successful processing
-	// Performs database-first check with fallback to Polar API if needed
-	// Returns BillingStatus indicating if invoice processing is allowed
-	CheckQuotaAvailability(ctx context.Context, organizationID int32) (*domain.BillingStatus, error)
-
-	// ConsumeInvoiceQuota explicitly consumes one invoice quota after successful processing
-	// Should be called after invoice has been successfully processed
-	// Can be called asynchronously in background for better performance
-	// Returns updated quota status
-	ConsumeInvoiceQuota(ctx context.Context, organizationID int32) (*domain.BillingStatus, error)
-
-	// VerifyAndConsumeQuota verifies quota availability and consumes one invoice quota
-	// Performs database-first check with fallback to Polar API if needed
-	// Returns BillingStatus with detailed verification result
-	// Automatically increments quota count on success
-	// DEPRECATED: Use CheckQuotaAvailability + ConsumeInvoiceQuota pattern for better control
-	VerifyAndConsumeQuota(ctx context.Context, organizationID int32) (*domain.BillingStatus, error)
+	// CheckQuota performs a read-only check that amount units of a meter can be consumed
+	// Does NOT consume quota - use Consume after successful processing
+	// Performs database-first check with fallback to the provider API before refusing
+	// Returns ErrQuotaExceeded or ErrSubscriptionNotActive together with the check result
+	CheckQuota(ctx context.Context, organizationID int32, meterSlug string, amount int64) (*domain.QuotaCheck, error)
+
+	// Consume charges units of a meter after successful processing and appends
+	// them to the usage ledger; the usage is then reported to the provider
+	// Atomic: returns ErrQuotaExceeded without consuming when the allowance is insufficient
+	// Idempotent per usage Reference
+	Consume(ctx context.Context, usage *domain.UsageRecord) (*domain.QuotaCheck, error)
+
+	// ReserveQuota holds amount units of a meter for work in progress, re-checking with the
+	// provider before refusing; held units are unavailable until committed, released or expired
+	// Returns ErrQuotaExceeded or ErrSubscriptionNotActive without holding anything
+	ReserveQuota(ctx context.Context, usage *domain.UsageRecord) (*domain.QuotaReservation, error)
+
+	// CommitQuota consumes a reservation after successful processing and appends it to the
+	// usage ledger, like Consume; an expired reservation is consumed directly
+	CommitQuota(ctx context.Context, reservation *domain.QuotaReservation) (*domain.QuotaCheck, error)
+
+	// ReleaseQuota returns a reservation's units to its meter after failed processing
+	ReleaseQuota(ctx context.Context, reservation *domain.QuotaReservation) error
+
+	// GetUsageReport sums the organization's usage ledger over a time range, grouped by
+	// any of day, meter and account; the range defaults to the current quota period
+	// Returns ErrInvalidUsageQuery (wrapped) for an invalid range or grouping
+	GetUsageReport(ctx context.Context, query *domain.UsageQuery) (*domain.UsageReport, error)
+
+	// ExportUsage passes the usage ledger entries selected by query to visit, page by page
+	// in ID order, for CSV export; the range defaults like GetUsageReport's
+	ExportUsage(ctx context.Context, query *domain.UsageQuery, visit func([]domain.UsageLedgerEntry) error) error
 
 	// SyncSubscriptionFromPolar forces a sync of subscription data from Polar API
 	// Used as fallback when webhook data is missing or stale
-	// TODO: Implement periodic background sync job for all subscriptions
+	// The Reconciler syncs all subscriptions periodically via ReconcileSubscription
 	SyncSubscriptionFromPolar(ctx context.Context, organizationID int32) error
 
+	// ReconcileSubscription compares a stored subscription and its quota with the provider
+	// and repairs drift, publishing subscription.reconciled for each correction
+	// Returns domain.ErrProviderRateLimited (wrapped) when the provider throttled the lookup
+	ReconcileSubscription(ctx context.Context, stored *domain.Subscription) (*domain.ReconcileResult, error)
+
+	// RolloverQuotaPeriod starts the next quota period for an organization whose period has ended
+	// Uses the provider's renewed period when available, otherwise advances the period locally
+	// Archives the closed period's meter consumption and publishes billing.period_rolled_over
+	// Returns ErrPeriodNotEnded, or ErrSubscriptionNotActive when the subscription does not renew
+	RolloverQuotaPeriod(ctx context.Context, organizationID int32) (*domain.PeriodRollover, error)
+
+	// AdvanceDunningPhase publishes subscription.dunning_reminder when a past_due organization
+	// entered a new dunning phase (grace, read_only, blocked) under the configured policy
+	// Returns the newly announced phase, empty when the phase did not change
+	AdvanceDunningPhase(ctx context.Context, organizationID int32) (string, error)
+
+	// GetSeatUsage counts the organization's active members against its plan's seat limit
+	// Starts the seat grace period when a plan change left the organization over the limit
+	GetSeatUsage(ctx context.Context, organizationID int32) (*domain.SeatUsage, error)
+
+	// Entitlements returns the features, limits and meter allowances the organization
+	// can use under its current plan, resolved through the plan catalog
+	// An organization without an active subscription has no entitlements (not an error);
+	// a past_due one keeps them during the dunning grace period
+	Entitlements(ctx context.Context, organizationID int32) (*domain.Entitlements, error)
+
+	// CheckSeatAvailable verifies that another member fits within the seat limit
+	// Returns ErrSeatLimitReached (wrapped) together with the seat usage when it does not
+	CheckSeatAvailable(ctx context.Context, organizationID int32) (*domain.SeatUsage, error)
+
+	// CreateCheckoutSession creates a provider checkout for a product with the organization
+	// attached as the external customer; redirect the user to the session URL
+	// Returns ErrProductNotFound (wrapped) when the provider rejects the product
+	CreateCheckoutSession(ctx context.Context, organizationID int32, productID string) (*domain.CheckoutSession, error)
+
+	// CreateCustomerPortalSession returns a link to the provider's customer portal
+	// Returns ErrCustomerNotFound (wrapped) when the organization never checked out
+	CreateCustomerPortalSession(ctx context.Context, organizationID int32) (*domain.CustomerPortalSession, error)
+
 	// VerifyPaymentFromCheckout verifies a payment by checking the Polar checkout session
 	// This is the primary mechanism for "Verification on Redirect" pattern
 	// Called when user returns from payment page with session_id
@@ -74,6 +131,10 @@ type billingService struct {
 	repo            domain.SubscriptionRepository
 	orgAdapter      domain.OrganizationAdapter
 	billingProvider domain.BillingProvider
+	webhookParser   domain.WebhookParser
+	catalog         PlanCatalog
+	eventBus        eventbus.EventBus
+	config          config.Config
 	logger          logger.Logger
 }
 
@@ -81,12 +142,20 @@ func NewBillingService(
 	repo domain.SubscriptionRepository,
 	orgAdapter domain.OrganizationAdapter,
 	billingProvider domain.BillingProvider,
+	webhookParser domain.WebhookParser,
+	catalog PlanCatalog,
+	eventBus eventbus.EventBus,
+	cfg config.Config,
 	logger logger.Logger,
 ) BillingService {
 	return &billingService{
 		repo:            repo,
 		orgAdapter:      orgAdapter,
 		billingProvider: billingProvider,
+		webhookParser:   webhookParser,
+		catalog:         catalog,
+		eventBus:        eventBus,
+		config:          cfg,
 		logger:          logger,
 	}
 }
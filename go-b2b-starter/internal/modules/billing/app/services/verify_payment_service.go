@@ -3,7 +3,6 @@ package services
 import (
 	"context"
 	"fmt"
-	"strconv"
 	"time"
 
 	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
@@ -23,7 +22,6 @@ func (s *billingService) VerifyPaymentFromCheckout(ctx context.Context, sessionI
 	if checkoutSession.Status != "succeeded" {
 		return &domain.BillingStatus{
 			HasActiveSubscription: false,
-			CanProcessInvoices:    false,
 			Reason:                fmt.Sprintf("checkout session status is %s (expected: succeeded)", checkoutSession.Status),
 			CheckedAt:             time.Now(),
 		}, nil
@@ -49,54 +47,44 @@ func (s *billingService) VerifyPaymentFromCheckout(ctx context.Context, sessionI
 
 	// Step 6: Upsert subscription to database
 	subscription.OrganizationID = organizationID
+	previous := s.storedSubscription(ctx, organizationID)
 	_, err = s.repo.UpsertSubscription(ctx, subscription)
 	if err != nil {
 		return nil, fmt.Errorf("failed to save subscription: %w", err)
 	}
 
-	// Step 7: Extract and upsert quota information
-	invoiceCountMax := int32(0)
-	if metadata, ok := subscription.Metadata["invoice_count_max"].(int32); ok {
-		invoiceCountMax = metadata
-	} else if val, ok := subscription.Metadata["invoice_count_max"].(string); ok {
-		if count, err := strconv.ParseInt(val, 10, 32); err == nil {
-			invoiceCountMax = int32(count)
-		}
+	// Step 7: Apply seat limit and meter allowances from product metadata
+	allowances, err := s.applyProductQuotas(ctx, organizationID, subscription.ProductID, productMetadataOf(subscription),
+		subscription.CurrentPeriodStart, subscription.CurrentPeriodEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save quota: %w", err)
 	}
 
-	// Create or update quota tracking
-	quota := &domain.QuotaTracking{
-		OrganizationID: organizationID,
-		InvoiceCount:   invoiceCountMax,
-		PeriodStart:    subscription.CurrentPeriodStart,
-		PeriodEnd:      subscription.CurrentPeriodEnd,
-		LastSyncedAt:   &time.Time{},
-	}
-	*quota.LastSyncedAt = time.Now()
+	s.publishLifecycleEvents(ctx, previous, subscription)
 
-	_, err = s.repo.UpsertQuota(ctx, quota)
+	quotas, err := s.repo.ListMeterQuotas(ctx, organizationID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to save quota: %w", err)
+		return nil, fmt.Errorf("failed to list meter quotas: %w", err)
 	}
 
 	s.logger.Info("Payment verified from checkout session", map[string]any{
 		"session_id":      sessionID,
 		"organization_id": organizationID,
 		"subscription_id": subscription.SubscriptionID,
-		"invoice_count":   invoiceCountMax,
+		"allowances":      allowances,
 	})
 
 	// Console log for verification completion
-	fmt.Printf("✅ PAYMENT VERIFIED - Session: %s | Org: %d | Subscription: %s | Invoice Count: %d | Status: %s\n",
-		sessionID, organizationID, subscription.SubscriptionID, invoiceCountMax, subscription.SubscriptionStatus)
+	fmt.Printf("✅ PAYMENT VERIFIED - Session: %s | Org: %d | Subscription: %s | Meters: %d | Status: %s\n",
+		sessionID, organizationID, subscription.SubscriptionID, len(allowances), subscription.SubscriptionStatus)
 
 	// Step 8: Return billing status
 	return &domain.BillingStatus{
 		OrganizationID:        organizationID,
 		ExternalID:            externalCustomerID,
 		HasActiveSubscription: subscription.SubscriptionStatus == "active" || subscription.SubscriptionStatus == "trialing",
-		CanProcessInvoices:    (subscription.SubscriptionStatus == "active" || subscription.SubscriptionStatus == "trialing") && invoiceCountMax > 0,
-		InvoiceCount:          invoiceCountMax,
+		SubscriptionStatus:    subscription.SubscriptionStatus,
+		Quotas:                quotas,
 		Reason:                "Payment verified successfully",
 		CheckedAt:             time.Now(),
 	}, nil
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+	logger "github.com/moasq/go-b2b-starter/internal/platform/logger/domain"
+)
+
+// WebhookResult reports what happened to a received webhook
+type WebhookResult struct {
+	WebhookID string `json:"webhook_id"`
+	EventType string `json:"event_type"`
+	// Duplicate is true when the webhook was already processed (or is being
+	// processed) and this delivery was ignored
+	Duplicate bool `json:"duplicate"`
+}
+
+// WebhookService receives billing provider webhooks.
+//
+// Every webhook is verified, recorded by its webhook ID together with the
+// raw payload, and then handed to BillingService.ProcessWebhookEvent. The
+// provider retries deliveries until it gets a 2xx response; the record makes
+// those retries idempotent and keeps the payload for auditing and replay.
+type WebhookService interface {
+	// Provider names the billing provider whose webhooks are received
+	Provider() string
+
+	// ReceiveWebhook verifies and processes one webhook request.
+	// Returns ErrWebhookSignatureInvalid, ErrWebhookTimestampStale or
+	// ErrInvalidWebhookPayload (wrapped) when the request is rejected.
+	ReceiveWebhook(ctx context.Context, headers http.Header, payload []byte) (*WebhookResult, error)
+
+	// ReplayWebhook processes a recorded webhook again from its stored payload
+	ReplayWebhook(ctx context.Context, id int64) (*WebhookResult, error)
+}
+
+// webhookEnvelope is the body of a Standard Webhooks message
+type webhookEnvelope struct {
+	Type string         `json:"type"`
+	Data map[string]any `json:"data"`
+}
+
+type webhookService struct {
+	repo           domain.WebhookRepository
+	verifier       domain.WebhookVerifier
+	billingService BillingService
+	logger         logger.Logger
+}
+
+func NewWebhookService(
+	repo domain.WebhookRepository,
+	verifier domain.WebhookVerifier,
+	billingService BillingService,
+	logger logger.Logger,
+) WebhookService {
+	return &webhookService{
+		repo:           repo,
+		verifier:       verifier,
+		billingService: billingService,
+		logger:         logger,
+	}
+}
+
+func (s *webhookService) Provider() string {
+	return s.verifier.Provider()
+}
+
+func (s *webhookService) ReceiveWebhook(ctx context.Context, headers http.Header, payload []byte) (*WebhookResult, error) {
+	webhookID, err := s.verifier.Verify(headers, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope, err := parseWebhookEnvelope(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &WebhookResult{WebhookID: webhookID, EventType: envelope.Type}
+
+	webhook, claimed, err := s.repo.Claim(ctx, s.verifier.Provider(), webhookID, envelope.Type, payload)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		s.logger.Info("Ignoring duplicate webhook delivery", map[string]any{
+			"provider":   s.verifier.Provider(),
+			"webhook_id": webhookID,
+			"event_type": envelope.Type,
+		})
+		result.Duplicate = true
+		return result, nil
+	}
+
+	if err := s.process(ctx, webhook, envelope); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *webhookService) ReplayWebhook(ctx context.Context, id int64) (*WebhookResult, error) {
+	webhook, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope, err := parseWebhookEnvelope(webhook.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Replaying webhook", map[string]any{
+		"id":         webhook.ID,
+		"provider":   webhook.Provider,
+		"webhook_id": webhook.WebhookID,
+		"event_type": envelope.Type,
+		"status":     webhook.Status,
+	})
+
+	if err := s.process(ctx, webhook, envelope); err != nil {
+		return nil, err
+	}
+
+	return &WebhookResult{WebhookID: webhook.WebhookID, EventType: envelope.Type}, nil
+}
+
+// process runs the webhook through the billing service and records the outcome
+func (s *webhookService) process(ctx context.Context, webhook *domain.ReceivedWebhook, envelope *webhookEnvelope) error {
+	processErr := s.billingService.ProcessWebhookEvent(ctx, envelope.Type, envelope.Data)
+
+	// Record the outcome even if the request was cancelled meanwhile
+	recordCtx := context.WithoutCancel(ctx)
+
+	if processErr != nil {
+		s.logger.Error("Webhook processing failed", map[string]any{
+			"id":         webhook.ID,
+			"webhook_id": webhook.WebhookID,
+			"event_type": envelope.Type,
+			"attempts":   webhook.Attempts,
+			"error":      processErr.Error(),
+		})
+		if err := s.repo.MarkFailed(recordCtx, webhook.ID, processErr.Error()); err != nil {
+			s.logger.Error("Failed to record webhook failure", map[string]any{
+				"id":    webhook.ID,
+				"error": err.Error(),
+			})
+		}
+		return fmt.Errorf("failed to process webhook %s: %w", webhook.WebhookID, processErr)
+	}
+
+	if err := s.repo.MarkProcessed(recordCtx, webhook.ID); err != nil {
+		// Processing is idempotent, so a redelivery after this is harmless
+		return err
+	}
+
+	return nil
+}
+
+func parseWebhookEnvelope(payload []byte) (*webhookEnvelope, error) {
+	var envelope webhookEnvelope
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidWebhookPayload, err)
+	}
+	if envelope.Type == "" {
+		return nil, fmt.Errorf("%w: missing event type", domain.ErrInvalidWebhookPayload)
+	}
+	return &envelope, nil
+}
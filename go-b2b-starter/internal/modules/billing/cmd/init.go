@@ -1,23 +1,85 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
+
 	"go.uber.org/dig"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/app/services"
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/infra/adapters"
+	"github.com/moasq/go-b2b-starter/internal/modules/paywall"
+	"github.com/moasq/go-b2b-starter/internal/platform/eventbus"
 )
 
+// statusCacheHandlerName names the status cache invalidation in dead letters
+const statusCacheHandlerName = "billing.status_cache"
+
 //
 // The billing module handles subscription lifecycle management with Polar.sh:
 //   - Webhook processing for subscription events
 //   - Quota tracking and consumption
 //   - Billing status queries
+//   - Periodic reconciliation with the provider
+//   - Quota period rollover when a period ends
+//   - Dunning reminders while a payment is past due
+//   - Plan catalog and entitlements, synced from the provider's products
+//   - The paywall's cached subscription statuses, invalidated on changes
 //
 // Communication is event-driven:
 //   - Polar sends webhook → billing processes event → updates local DB
 //   - Paywall middleware reads from local DB (no external API calls)
+//   - The reconciler repairs whatever a missed webhook left stale
 func Init(container *dig.Container) error {
 	// Register all dependencies
 	if err := ProvideDependencies(container); err != nil {
 		return err
 	}
 
+	// Drop cached subscription statuses when a subscription changes
+	if err := container.Invoke(func(dlq *eventbus.DeadLetterQueue, cache *paywall.StatusCache) error {
+		if cache == nil {
+			return nil
+		}
+		invalidator := adapters.NewStatusCacheInvalidator(cache)
+		for _, eventType := range adapters.StatusCacheEventTypes {
+			if err := dlq.Subscribe(eventType, statusCacheHandlerName, invalidator.HandleEvent); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to wire subscription status cache invalidation: %w", err)
+	}
+
 	return nil
 }
+
+// Start launches the background jobs: plan catalog sync, subscription
+// reconciliation, quota period rollover and dunning reminders
+func Start(container *dig.Container) error {
+	return container.Invoke(func(catalog services.PlanCatalog, reconciler services.Reconciler, rollover services.PeriodRollover,
+		dunning services.DunningReminders) error {
+		if err := catalog.Start(); err != nil {
+			return fmt.Errorf("failed to start plan catalog sync: %w", err)
+		}
+		if err := reconciler.Start(); err != nil {
+			return fmt.Errorf("failed to start subscription reconciler: %w", err)
+		}
+		if err := rollover.Start(); err != nil {
+			return fmt.Errorf("failed to start quota period rollover: %w", err)
+		}
+		if err := dunning.Start(); err != nil {
+			return fmt.Errorf("failed to start dunning reminders: %w", err)
+		}
+		return nil
+	})
+}
+
+// Close stops the background jobs, waiting for the organization in progress
+func Close(container *dig.Container) error {
+	return container.Invoke(func(catalog services.PlanCatalog, reconciler services.Reconciler, rollover services.PeriodRollover,
+		dunning services.DunningReminders) error {
+		return errors.Join(catalog.Close(), reconciler.Close(), rollover.Close(), dunning.Close())
+	})
+}
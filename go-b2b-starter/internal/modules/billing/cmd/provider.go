@@ -6,8 +6,12 @@ import (
 	"go.uber.org/dig"
 
 	"github.com/moasq/go-b2b-starter/internal/modules/billing/app/services"
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/config"
 	"github.com/moasq/go-b2b-starter/internal/modules/billing/infra/adapters"
+	orgDomain "github.com/moasq/go-b2b-starter/internal/modules/organizations/domain"
 	"github.com/moasq/go-b2b-starter/internal/modules/paywall"
+	"github.com/moasq/go-b2b-starter/internal/platform/logger"
+	"github.com/moasq/go-b2b-starter/internal/platform/redis"
 )
 
 // ProvideDependencies registers all billing module dependencies
@@ -18,14 +22,51 @@ func ProvideDependencies(container *dig.Container) error {
 		return fmt.Errorf("failed to configure billing services: %w", err)
 	}
 
+	// Register the Redis cache of the paywall's subscription statuses; nil
+	// when BILLING_STATUS_CACHE_ENABLED is off
+	if err := container.Provide(func(svc services.BillingService, cfg config.Config, redisClient redis.Client, log logger.Logger) *paywall.StatusCache {
+		if !cfg.StatusCacheEnabled {
+			return nil
+		}
+		return paywall.NewStatusCache(adapters.NewStatusProviderAdapter(svc), redisClient, log, paywall.StatusCacheConfig{
+			ActiveTTL:   cfg.StatusCacheTTL,
+			InactiveTTL: cfg.StatusCacheInactiveTTL,
+		})
+	}); err != nil {
+		return fmt.Errorf("failed to provide subscription status cache: %w", err)
+	}
+
 	// Register SubscriptionStatusProvider for the paywall middleware
 	// This adapter bridges the billing module to the pkg/paywall middleware
 	// Communication is event-driven: webhooks → billing → DB → paywall reads
-	if err := container.Provide(func(svc services.BillingService) paywall.SubscriptionStatusProvider {
+	if err := container.Provide(func(svc services.BillingService, cache *paywall.StatusCache) paywall.SubscriptionStatusProvider {
+		if cache != nil {
+			return cache
+		}
 		return adapters.NewStatusProviderAdapter(svc)
 	}); err != nil {
 		return fmt.Errorf("failed to provide subscription status provider: %w", err)
 	}
 
+	// Register EntitlementProvider and QuotaProvider for the paywall's
+	// RequireEntitlement and RequireQuota middleware
+	if err := container.Provide(func(svc services.BillingService) paywall.EntitlementProvider {
+		return adapters.NewEntitlementProviderAdapter(svc)
+	}); err != nil {
+		return fmt.Errorf("failed to provide entitlement provider: %w", err)
+	}
+	if err := container.Provide(func(svc services.BillingService, cache *paywall.StatusCache, log logger.Logger) paywall.QuotaProvider {
+		return adapters.NewQuotaProviderAdapter(svc, cache, log)
+	}); err != nil {
+		return fmt.Errorf("failed to provide quota provider: %w", err)
+	}
+
+	// Register SeatLimiter for the organizations module's member management
+	if err := container.Provide(func(svc services.BillingService) orgDomain.SeatLimiter {
+		return adapters.NewSeatLimiterAdapter(svc)
+	}); err != nil {
+		return fmt.Errorf("failed to provide seat limiter: %w", err)
+	}
+
 	return nil
 }
@@ -0,0 +1,244 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Supported billing providers
+const (
+	ProviderPolar  = "polar"
+	ProviderStripe = "stripe"
+)
+
+// Config holds configuration for the billing module
+type Config struct {
+	// Provider selects the billing provider: "polar" or "stripe".
+	// Only the selected provider's settings (POLAR_* or STRIPE_*) are required.
+	Provider string `mapstructure:"BILLING_PROVIDER"`
+
+	// ReconcileEnabled turns on the background job that compares every stored
+	// subscription with the provider and repairs drift from missed webhooks
+	ReconcileEnabled bool `mapstructure:"BILLING_RECONCILE_ENABLED"`
+
+	// ReconcileInterval is the time between two reconciliation passes
+	ReconcileInterval time.Duration `mapstructure:"BILLING_RECONCILE_INTERVAL"`
+
+	// ReconcileBatchSize is the number of subscriptions loaded per page
+	ReconcileBatchSize int32 `mapstructure:"BILLING_RECONCILE_BATCH_SIZE"`
+
+	// ReconcileRequestsPerSecond caps the provider API calls of a pass
+	ReconcileRequestsPerSecond float64 `mapstructure:"BILLING_RECONCILE_REQUESTS_PER_SECOND"`
+
+	// ReconcileRateLimitBackoff is the pause after the provider rejected a
+	// call for exceeding its rate limit
+	ReconcileRateLimitBackoff time.Duration `mapstructure:"BILLING_RECONCILE_RATE_LIMIT_BACKOFF"`
+
+	// RolloverEnabled turns on the background job that starts the next quota
+	// period for renewing subscriptions whose period has ended
+	RolloverEnabled bool `mapstructure:"BILLING_ROLLOVER_ENABLED"`
+
+	// RolloverInterval is how often ended quota periods are looked for
+	RolloverInterval time.Duration `mapstructure:"BILLING_ROLLOVER_INTERVAL"`
+
+	// RolloverBatchSize is the number of ended quota periods loaded per page
+	RolloverBatchSize int32 `mapstructure:"BILLING_ROLLOVER_BATCH_SIZE"`
+
+	// RolloverRequestsPerSecond caps the provider API calls of a rollover pass
+	RolloverRequestsPerSecond float64 `mapstructure:"BILLING_ROLLOVER_REQUESTS_PER_SECOND"`
+
+	// SeatGracePeriod is how long an organization left over its seat limit by
+	// a plan change is in grace before it is reported over the limit. Members
+	// keep access either way; only new members are refused.
+	SeatGracePeriod time.Duration `mapstructure:"BILLING_SEAT_GRACE_PERIOD"`
+
+	// DunningGracePeriod is how long a past_due organization keeps full access,
+	// with warnings, while the provider retries the payment
+	DunningGracePeriod time.Duration `mapstructure:"BILLING_DUNNING_GRACE_PERIOD"`
+
+	// DunningReadOnlyPeriod is how long a past_due organization keeps read-only
+	// access after the grace period, before access is blocked
+	DunningReadOnlyPeriod time.Duration `mapstructure:"BILLING_DUNNING_READ_ONLY_PERIOD"`
+
+	// DunningRemindersEnabled turns on the background job that announces the
+	// dunning phase transitions of past_due subscriptions as reminder events
+	DunningRemindersEnabled bool `mapstructure:"BILLING_DUNNING_REMINDERS_ENABLED"`
+
+	// DunningRemindersInterval is how often past_due subscriptions are checked
+	// for a new dunning phase
+	DunningRemindersInterval time.Duration `mapstructure:"BILLING_DUNNING_REMINDERS_INTERVAL"`
+
+	// DunningRemindersBatchSize is the number of past_due subscriptions loaded per page
+	DunningRemindersBatchSize int32 `mapstructure:"BILLING_DUNNING_REMINDERS_BATCH_SIZE"`
+
+	// QuotaReservationTTL is how long units reserved for a request stay held
+	// when the reservation is neither committed nor released, e.g. because the
+	// process crashed mid-request
+	QuotaReservationTTL time.Duration `mapstructure:"BILLING_QUOTA_RESERVATION_TTL"`
+
+	// StatusCacheEnabled caches the subscription status read by the paywall in
+	// Redis; billing lifecycle events invalidate it
+	StatusCacheEnabled bool `mapstructure:"BILLING_STATUS_CACHE_ENABLED"`
+
+	// StatusCacheTTL is how long a status that grants access is cached
+	StatusCacheTTL time.Duration `mapstructure:"BILLING_STATUS_CACHE_TTL"`
+
+	// StatusCacheInactiveTTL is how long a status that refuses access is
+	// cached, and how long a lazy-guard refresh with the provider is reused
+	StatusCacheInactiveTTL time.Duration `mapstructure:"BILLING_STATUS_CACHE_INACTIVE_TTL"`
+
+	// CheckoutSuccessURL is where the provider redirects after a successful
+	// checkout; the checkout ID is appended as session_id for verify-payment
+	CheckoutSuccessURL string `mapstructure:"BILLING_CHECKOUT_SUCCESS_URL"`
+
+	// CheckoutCancelURL is where the customer returns when leaving a checkout
+	CheckoutCancelURL string `mapstructure:"BILLING_CHECKOUT_CANCEL_URL"`
+
+	// PortalReturnURL is where the customer portal links back to
+	PortalReturnURL string `mapstructure:"BILLING_PORTAL_RETURN_URL"`
+
+	// PlansFile is the plan catalog file (YAML, JSON or TOML) mapping provider
+	// products to named plans and their entitlements. Without it, plans are
+	// derived from the metadata of the provider's products.
+	PlansFile string `mapstructure:"BILLING_PLANS_FILE"`
+
+	// CatalogSyncEnabled turns on the background job that lists the
+	// provider's products into the plan catalog
+	CatalogSyncEnabled bool `mapstructure:"BILLING_CATALOG_SYNC_ENABLED"`
+
+	// CatalogSyncInterval is the time between two product catalog syncs
+	CatalogSyncInterval time.Duration `mapstructure:"BILLING_CATALOG_SYNC_INTERVAL"`
+}
+
+// LoadConfig reads configuration from file or environment variables.
+func LoadConfig() (Config, error) {
+	var cfg Config
+
+	viper.SetConfigName("app")
+	viper.SetConfigType("env")
+	viper.AddConfigPath(".")
+	viper.AutomaticEnv()
+
+	// Set default values
+	viper.SetDefault("BILLING_PROVIDER", ProviderPolar)
+	viper.SetDefault("BILLING_RECONCILE_ENABLED", true)
+	viper.SetDefault("BILLING_RECONCILE_INTERVAL", "1h")
+	viper.SetDefault("BILLING_RECONCILE_BATCH_SIZE", 100)
+	viper.SetDefault("BILLING_RECONCILE_REQUESTS_PER_SECOND", 2)
+	viper.SetDefault("BILLING_RECONCILE_RATE_LIMIT_BACKOFF", "1m")
+	viper.SetDefault("BILLING_ROLLOVER_ENABLED", true)
+	viper.SetDefault("BILLING_ROLLOVER_INTERVAL", "5m")
+	viper.SetDefault("BILLING_ROLLOVER_BATCH_SIZE", 100)
+	viper.SetDefault("BILLING_ROLLOVER_REQUESTS_PER_SECOND", 2)
+	viper.SetDefault("BILLING_SEAT_GRACE_PERIOD", "336h")
+	viper.SetDefault("BILLING_DUNNING_GRACE_PERIOD", "72h")
+	viper.SetDefault("BILLING_DUNNING_READ_ONLY_PERIOD", "96h")
+	viper.SetDefault("BILLING_DUNNING_REMINDERS_ENABLED", true)
+	viper.SetDefault("BILLING_DUNNING_REMINDERS_INTERVAL", "5m")
+	viper.SetDefault("BILLING_DUNNING_REMINDERS_BATCH_SIZE", 100)
+	viper.SetDefault("BILLING_QUOTA_RESERVATION_TTL", "10m")
+	viper.SetDefault("BILLING_STATUS_CACHE_ENABLED", true)
+	viper.SetDefault("BILLING_STATUS_CACHE_TTL", "30s")
+	viper.SetDefault("BILLING_STATUS_CACHE_INACTIVE_TTL", "5s")
+	viper.SetDefault("BILLING_CHECKOUT_SUCCESS_URL", "http://localhost:3000/billing/success")
+	viper.SetDefault("BILLING_CHECKOUT_CANCEL_URL", "http://localhost:3000/billing")
+	viper.SetDefault("BILLING_PORTAL_RETURN_URL", "http://localhost:3000/billing")
+	viper.SetDefault("BILLING_PLANS_FILE", "")
+	viper.SetDefault("BILLING_CATALOG_SYNC_ENABLED", true)
+	viper.SetDefault("BILLING_CATALOG_SYNC_INTERVAL", "1h")
+
+	// Best-effort: ignore missing file, allow env-only usage
+	if err := viper.ReadInConfig(); err == nil {
+		_ = err
+	}
+
+	if err := viper.Unmarshal(&cfg); err != nil {
+		return cfg, fmt.Errorf("unable to decode billing config: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	switch c.Provider {
+	case ProviderPolar, ProviderStripe:
+	default:
+		return fmt.Errorf("unsupported billing provider %q (BILLING_PROVIDER), expected %q or %q",
+			c.Provider, ProviderPolar, ProviderStripe)
+	}
+
+	if c.ReconcileEnabled {
+		if c.ReconcileInterval <= 0 {
+			return fmt.Errorf("billing reconcile interval must be positive (BILLING_RECONCILE_INTERVAL)")
+		}
+		if c.ReconcileBatchSize <= 0 {
+			return fmt.Errorf("billing reconcile batch size must be positive (BILLING_RECONCILE_BATCH_SIZE)")
+		}
+		if c.ReconcileRequestsPerSecond <= 0 {
+			return fmt.Errorf("billing reconcile request rate must be positive (BILLING_RECONCILE_REQUESTS_PER_SECOND)")
+		}
+		if c.ReconcileRateLimitBackoff <= 0 {
+			return fmt.Errorf("billing reconcile rate limit backoff must be positive (BILLING_RECONCILE_RATE_LIMIT_BACKOFF)")
+		}
+	}
+
+	if c.RolloverEnabled {
+		if c.RolloverInterval <= 0 {
+			return fmt.Errorf("billing rollover interval must be positive (BILLING_ROLLOVER_INTERVAL)")
+		}
+		if c.RolloverBatchSize <= 0 {
+			return fmt.Errorf("billing rollover batch size must be positive (BILLING_ROLLOVER_BATCH_SIZE)")
+		}
+		if c.RolloverRequestsPerSecond <= 0 {
+			return fmt.Errorf("billing rollover request rate must be positive (BILLING_ROLLOVER_REQUESTS_PER_SECOND)")
+		}
+	}
+
+	if c.SeatGracePeriod < 0 {
+		return fmt.Errorf("billing seat grace period must not be negative (BILLING_SEAT_GRACE_PERIOD)")
+	}
+
+	if c.DunningGracePeriod < 0 {
+		return fmt.Errorf("billing dunning grace period must not be negative (BILLING_DUNNING_GRACE_PERIOD)")
+	}
+	if c.DunningReadOnlyPeriod < 0 {
+		return fmt.Errorf("billing dunning read-only period must not be negative (BILLING_DUNNING_READ_ONLY_PERIOD)")
+	}
+	if c.DunningRemindersEnabled {
+		if c.DunningRemindersInterval <= 0 {
+			return fmt.Errorf("billing dunning reminders interval must be positive (BILLING_DUNNING_REMINDERS_INTERVAL)")
+		}
+		if c.DunningRemindersBatchSize <= 0 {
+			return fmt.Errorf("billing dunning reminders batch size must be positive (BILLING_DUNNING_REMINDERS_BATCH_SIZE)")
+		}
+	}
+
+	if c.QuotaReservationTTL <= 0 {
+		return fmt.Errorf("billing quota reservation TTL must be positive (BILLING_QUOTA_RESERVATION_TTL)")
+	}
+
+	if c.StatusCacheEnabled {
+		if c.StatusCacheTTL <= 0 {
+			return fmt.Errorf("billing status cache TTL must be positive (BILLING_STATUS_CACHE_TTL)")
+		}
+		if c.StatusCacheInactiveTTL <= 0 {
+			return fmt.Errorf("billing status cache inactive TTL must be positive (BILLING_STATUS_CACHE_INACTIVE_TTL)")
+		}
+	}
+
+	if c.CheckoutSuccessURL == "" {
+		return fmt.Errorf("billing checkout success URL is required (BILLING_CHECKOUT_SUCCESS_URL)")
+	}
+
+	if c.CatalogSyncEnabled && c.CatalogSyncInterval <= 0 {
+		return fmt.Errorf("billing catalog sync interval must be positive (BILLING_CATALOG_SYNC_INTERVAL)")
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// PlanConfig is a plan of the plan catalog file:
+//
+//	plans:
+//	  - key: pro
+//	    name: Pro
+//	    product_ids: [prod_monthly, prod_yearly]
+//	    features:
+//	      ai_chat: true
+//	    limits:
+//	      max_seats: 25
+//	    meters:
+//	      invoice.processed: 1000
+type PlanConfig struct {
+	Key        string           `mapstructure:"key"`
+	Name       string           `mapstructure:"name"`
+	ProductIDs []string         `mapstructure:"product_ids"`
+	Features   map[string]bool  `mapstructure:"features"`
+	Limits     map[string]int64 `mapstructure:"limits"`
+	Meters     map[string]int64 `mapstructure:"meters"`
+}
+
+// LoadPlans reads the plan catalog file. The format follows the file
+// extension (.yaml, .json or .toml).
+func LoadPlans(path string) ([]PlanConfig, error) {
+	// Meter slugs contain dots, so keys must not be split on them
+	v := viper.NewWithOptions(viper.KeyDelimiter("::"))
+	v.SetConfigFile(path)
+
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("unable to read billing plans file %s (BILLING_PLANS_FILE): %w", path, err)
+	}
+
+	var file struct {
+		Plans []PlanConfig `mapstructure:"plans"`
+	}
+	if err := v.Unmarshal(&file); err != nil {
+		return nil, fmt.Errorf("unable to decode billing plans file %s: %w", path, err)
+	}
+
+	if err := validatePlans(file.Plans); err != nil {
+		return nil, fmt.Errorf("invalid billing plans file %s: %w", path, err)
+	}
+
+	return file.Plans, nil
+}
+
+// validatePlans checks that plan keys are set and unique and that every
+// product belongs to one plan
+func validatePlans(plans []PlanConfig) error {
+	keys := make(map[string]bool, len(plans))
+	products := make(map[string]string)
+
+	for i, plan := range plans {
+		key := strings.TrimSpace(plan.Key)
+		if key == "" {
+			return fmt.Errorf("plan %d has no key", i+1)
+		}
+		if keys[key] {
+			return fmt.Errorf("plan %q is defined twice", key)
+		}
+		keys[key] = true
+
+		if len(plan.ProductIDs) == 0 {
+			return fmt.Errorf("plan %q has no product_ids", key)
+		}
+		for _, productID := range plan.ProductIDs {
+			if other, ok := products[productID]; ok {
+				return fmt.Errorf("product %s belongs to plans %q and %q", productID, other, key)
+			}
+			products[productID] = key
+		}
+
+		for name, limit := range plan.Limits {
+			if limit < 0 {
+				return fmt.Errorf("plan %q limit %s must not be negative", key, name)
+			}
+		}
+		for meterSlug, allowance := range plan.Meters {
+			if allowance < 0 {
+				return fmt.Errorf("plan %q meter %s allowance must not be negative", key, meterSlug)
+			}
+		}
+	}
+
+	return nil
+}
@@ -9,11 +9,38 @@ var (
 	// ErrSubscriptionNotActive is returned when a subscription exists but is not active
 	ErrSubscriptionNotActive = errors.New("subscription is not active")
 
+	// ErrSubscriptionExists is returned when a checkout is requested by an organization that already has a subscription
+	ErrSubscriptionExists = errors.New("subscription already exists")
+
 	// ErrQuotaNotFound is returned when quota tracking record cannot be found
 	ErrQuotaNotFound = errors.New("quota not found")
 
-	// ErrQuotaExceeded is returned when invoice quota has been exceeded
-	ErrQuotaExceeded = errors.New("invoice quota exceeded")
+	// ErrQuotaExceeded is returned when a meter quota has been exceeded
+	ErrQuotaExceeded = errors.New("quota exceeded")
+
+	// ErrMeterQuotaNotFound is returned when an organization has no quota for a meter
+	ErrMeterQuotaNotFound = errors.New("meter quota not found")
+
+	// ErrInvalidUsageAmount is returned when a quota check or consumption is not positive
+	ErrInvalidUsageAmount = errors.New("usage amount must be positive")
+
+	// ErrUsageAlreadyRecorded is returned when a usage reference was already consumed
+	ErrUsageAlreadyRecorded = errors.New("usage already recorded")
+
+	// ErrReservationNotFound is returned when a quota reservation was already committed, released or expired
+	ErrReservationNotFound = errors.New("quota reservation not found")
+
+	// ErrInvalidUsageQuery is returned when a usage report has an invalid range or grouping
+	ErrInvalidUsageQuery = errors.New("invalid usage query")
+
+	// ErrSeatLimitReached is returned when adding a member would exceed the plan's seat limit
+	ErrSeatLimitReached = errors.New("seat limit reached")
+
+	// ErrProviderRateLimited is returned when the billing provider rejected a request for exceeding its rate limit
+	ErrProviderRateLimited = errors.New("billing provider rate limit exceeded")
+
+	// ErrPeriodNotEnded is returned when a quota period rollover finds the period still running
+	ErrPeriodNotEnded = errors.New("quota period has not ended")
 
 	// ErrInvalidWebhookPayload is returned when webhook payload cannot be parsed
 	ErrInvalidWebhookPayload = errors.New("invalid webhook payload")
@@ -21,9 +48,21 @@ var (
 	// ErrWebhookSignatureInvalid is returned when webhook signature verification fails
 	ErrWebhookSignatureInvalid = errors.New("webhook signature invalid")
 
+	// ErrWebhookTimestampStale is returned when a webhook timestamp is outside the accepted tolerance
+	ErrWebhookTimestampStale = errors.New("webhook timestamp outside tolerance")
+
+	// ErrWebhookNotFound is returned when a recorded webhook cannot be found
+	ErrWebhookNotFound = errors.New("webhook not found")
+
 	// ErrQuotaDataStale is returned when quota data hasn't been synced recently
 	ErrQuotaDataStale = errors.New("quota data is stale")
 
 	// ErrCheckoutSessionNotFound is returned when a checkout session cannot be found
 	ErrCheckoutSessionNotFound = errors.New("checkout session not found")
+
+	// ErrProductNotFound is returned when the billing provider does not know a product or it cannot be sold
+	ErrProductNotFound = errors.New("product not found")
+
+	// ErrCustomerNotFound is returned when an organization has no customer at the billing provider yet
+	ErrCustomerNotFound = errors.New("billing customer not found")
 )
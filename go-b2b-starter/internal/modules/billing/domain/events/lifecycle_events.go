@@ -0,0 +1,182 @@
+package events
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moasq/go-b2b-starter/internal/platform/eventbus"
+)
+
+// Billing lifecycle events, published after the state change was stored.
+// Unlike subscription.changed they are only published on the transition.
+const (
+	SubscriptionActivatedEventType   = "subscription.activated"
+	SubscriptionPastDueEventType     = "subscription.past_due"
+	SubscriptionCanceledEventType    = "subscription.canceled"
+	SubscriptionPlanChangedEventType = "subscription.plan_changed"
+	QuotaExhaustedEventType          = "quota.exhausted"
+	DunningReminderEventType         = "subscription.dunning_reminder"
+)
+
+// SubscriptionActivated is published when an organization's subscription
+// starts granting access: a new subscription, a recovered payment or a
+// reactivation. PreviousStatus is empty for a new subscription.
+type SubscriptionActivated struct {
+	eventbus.BaseEvent
+	OrganizationID   int32     `json:"organization_id"`
+	SubscriptionID   string    `json:"subscription_id"`
+	Status           string    `json:"status"` // "active" or "trialing"
+	PreviousStatus   string    `json:"previous_status,omitempty"`
+	ProductID        string    `json:"product_id"`
+	Plan             string    `json:"plan,omitempty"`
+	CurrentPeriodEnd time.Time `json:"current_period_end"`
+}
+
+func NewSubscriptionActivated(organizationID int32, subscriptionID, status, previousStatus, productID, plan string,
+	periodEnd time.Time) *SubscriptionActivated {
+	return &SubscriptionActivated{
+		BaseEvent:        newLifecycleEvent(SubscriptionActivatedEventType, organizationID),
+		OrganizationID:   organizationID,
+		SubscriptionID:   subscriptionID,
+		Status:           status,
+		PreviousStatus:   previousStatus,
+		ProductID:        productID,
+		Plan:             plan,
+		CurrentPeriodEnd: periodEnd,
+	}
+}
+
+// SubscriptionPastDue is published when a renewal payment of an organization
+// failed and the provider is retrying it
+type SubscriptionPastDue struct {
+	eventbus.BaseEvent
+	OrganizationID   int32     `json:"organization_id"`
+	SubscriptionID   string    `json:"subscription_id"`
+	PreviousStatus   string    `json:"previous_status,omitempty"`
+	ProductID        string    `json:"product_id"`
+	CurrentPeriodEnd time.Time `json:"current_period_end"`
+}
+
+func NewSubscriptionPastDue(organizationID int32, subscriptionID, previousStatus, productID string,
+	periodEnd time.Time) *SubscriptionPastDue {
+	return &SubscriptionPastDue{
+		BaseEvent:        newLifecycleEvent(SubscriptionPastDueEventType, organizationID),
+		OrganizationID:   organizationID,
+		SubscriptionID:   subscriptionID,
+		PreviousStatus:   previousStatus,
+		ProductID:        productID,
+		CurrentPeriodEnd: periodEnd,
+	}
+}
+
+// SubscriptionCanceled is published when an organization's subscription ended.
+// A cancellation scheduled for the period end is published when it takes effect.
+type SubscriptionCanceled struct {
+	eventbus.BaseEvent
+	OrganizationID int32      `json:"organization_id"`
+	SubscriptionID string     `json:"subscription_id"`
+	PreviousStatus string     `json:"previous_status,omitempty"`
+	ProductID      string     `json:"product_id"`
+	CanceledAt     *time.Time `json:"canceled_at,omitempty"`
+}
+
+func NewSubscriptionCanceled(organizationID int32, subscriptionID, previousStatus, productID string,
+	canceledAt *time.Time) *SubscriptionCanceled {
+	return &SubscriptionCanceled{
+		BaseEvent:      newLifecycleEvent(SubscriptionCanceledEventType, organizationID),
+		OrganizationID: organizationID,
+		SubscriptionID: subscriptionID,
+		PreviousStatus: previousStatus,
+		ProductID:      productID,
+		CanceledAt:     canceledAt,
+	}
+}
+
+// SubscriptionPlanChanged is published when an organization's subscription
+// moved to another product, an upgrade or a downgrade. Plan keys are empty for
+// products outside the plan catalog.
+type SubscriptionPlanChanged struct {
+	eventbus.BaseEvent
+	OrganizationID    int32  `json:"organization_id"`
+	SubscriptionID    string `json:"subscription_id"`
+	Status            string `json:"status"`
+	PreviousProductID string `json:"previous_product_id"`
+	ProductID         string `json:"product_id"`
+	PreviousPlan      string `json:"previous_plan,omitempty"`
+	Plan              string `json:"plan,omitempty"`
+}
+
+func NewSubscriptionPlanChanged(organizationID int32, subscriptionID, status, previousProductID, productID,
+	previousPlan, plan string) *SubscriptionPlanChanged {
+	return &SubscriptionPlanChanged{
+		BaseEvent:         newLifecycleEvent(SubscriptionPlanChangedEventType, organizationID),
+		OrganizationID:    organizationID,
+		SubscriptionID:    subscriptionID,
+		Status:            status,
+		PreviousProductID: previousProductID,
+		ProductID:         productID,
+		PreviousPlan:      previousPlan,
+		Plan:              plan,
+	}
+}
+
+// QuotaExhausted is published when consumption used up the allowance of a
+// meter for the period; further consumption is refused until the period
+// rolls over or the allowance is raised
+type QuotaExhausted struct {
+	eventbus.BaseEvent
+	OrganizationID int32     `json:"organization_id"`
+	MeterSlug      string    `json:"meter_slug"`
+	Allowance      int64     `json:"allowance"`
+	Consumed       int64     `json:"consumed"`
+	PeriodEnd      time.Time `json:"period_end"`
+}
+
+func NewQuotaExhausted(organizationID int32, meterSlug string, allowance, consumed int64, periodEnd time.Time) *QuotaExhausted {
+	return &QuotaExhausted{
+		BaseEvent:      newLifecycleEvent(QuotaExhaustedEventType, organizationID),
+		OrganizationID: organizationID,
+		MeterSlug:      meterSlug,
+		Allowance:      allowance,
+		Consumed:       consumed,
+		PeriodEnd:      periodEnd,
+	}
+}
+
+// DunningReminder is published when a past_due organization enters a dunning
+// phase: grace (access continues), read_only (writes are refused) or blocked.
+// PhaseEndsAt is when the next phase starts, nil once blocked.
+type DunningReminder struct {
+	eventbus.BaseEvent
+	OrganizationID int32      `json:"organization_id"`
+	SubscriptionID string     `json:"subscription_id"`
+	Phase          string     `json:"phase"`
+	PreviousPhase  string     `json:"previous_phase,omitempty"`
+	ProductID      string     `json:"product_id"`
+	PastDueSince   time.Time  `json:"past_due_since"`
+	PhaseEndsAt    *time.Time `json:"phase_ends_at,omitempty"`
+}
+
+func NewDunningReminder(organizationID int32, subscriptionID, phase, previousPhase, productID string,
+	pastDueSince time.Time, phaseEndsAt *time.Time) *DunningReminder {
+	return &DunningReminder{
+		BaseEvent:      newLifecycleEvent(DunningReminderEventType, organizationID),
+		OrganizationID: organizationID,
+		SubscriptionID: subscriptionID,
+		Phase:          phase,
+		PreviousPhase:  previousPhase,
+		ProductID:      productID,
+		PastDueSince:   pastDueSince,
+		PhaseEndsAt:    phaseEndsAt,
+	}
+}
+
+func newLifecycleEvent(name string, organizationID int32) eventbus.BaseEvent {
+	return eventbus.BaseEvent{
+		ID:        uuid.New().String(),
+		Name:      name,
+		CreatedAt: time.Now(),
+		Meta:      make(map[string]interface{}),
+		Partition: subscriptionPartition(organizationID),
+	}
+}
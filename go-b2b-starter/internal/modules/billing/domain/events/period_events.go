@@ -0,0 +1,52 @@
+package events
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moasq/go-b2b-starter/internal/platform/eventbus"
+)
+
+const (
+	PeriodRolledOverEventType = "billing.period_rolled_over"
+)
+
+// MeterUsage is the allowance and consumption of a meter in a closed period
+type MeterUsage struct {
+	MeterSlug string `json:"meter_slug"`
+	Allowance int64  `json:"allowance"`
+	Consumed  int64  `json:"consumed"`
+}
+
+// PeriodRolledOver is published when an organization's quota moved to a new
+// billing period and its meters were reset
+type PeriodRolledOver struct {
+	eventbus.BaseEvent
+	OrganizationID      int32        `json:"organization_id"`
+	PreviousPeriodStart time.Time    `json:"previous_period_start"`
+	PreviousPeriodEnd   time.Time    `json:"previous_period_end"`
+	PeriodStart         time.Time    `json:"period_start"`
+	PeriodEnd           time.Time    `json:"period_end"`
+	Source              string       `json:"source"` // "provider" or "local"
+	Usage               []MeterUsage `json:"usage"`
+}
+
+func NewPeriodRolledOver(organizationID int32, previousStart, previousEnd, periodStart, periodEnd time.Time,
+	source string, usage []MeterUsage) *PeriodRolledOver {
+	return &PeriodRolledOver{
+		BaseEvent: eventbus.BaseEvent{
+			ID:        uuid.New().String(),
+			Name:      PeriodRolledOverEventType,
+			CreatedAt: time.Now(),
+			Meta:      make(map[string]interface{}),
+			Partition: subscriptionPartition(organizationID),
+		},
+		OrganizationID:      organizationID,
+		PreviousPeriodStart: previousStart,
+		PreviousPeriodEnd:   previousEnd,
+		PeriodStart:         periodStart,
+		PeriodEnd:           periodEnd,
+		Source:              source,
+		Usage:               usage,
+	}
+}
@@ -0,0 +1,92 @@
+package events
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moasq/go-b2b-starter/internal/platform/eventbus"
+)
+
+const (
+	SubscriptionChangedEventType    = "subscription.changed"
+	SubscriptionReconciledEventType = "subscription.reconciled"
+)
+
+// SubscriptionChanged is published whenever a billing webhook changed the
+// locally stored subscription of an organization
+type SubscriptionChanged struct {
+	eventbus.BaseEvent
+	OrganizationID     int32      `json:"organization_id"`
+	SubscriptionID     string     `json:"subscription_id"`
+	Status             string     `json:"status"`
+	ProductID          string     `json:"product_id"`
+	ProductName        string     `json:"product_name"`
+	CurrentPeriodStart time.Time  `json:"current_period_start"`
+	CurrentPeriodEnd   time.Time  `json:"current_period_end"`
+	CancelAtPeriodEnd  bool       `json:"cancel_at_period_end"`
+	CanceledAt         *time.Time `json:"canceled_at,omitempty"`
+}
+
+func NewSubscriptionChanged(organizationID int32, subscriptionID, status, productID, productName string,
+	periodStart, periodEnd time.Time, cancelAtPeriodEnd bool, canceledAt *time.Time) *SubscriptionChanged {
+	return &SubscriptionChanged{
+		BaseEvent: eventbus.BaseEvent{
+			ID:        uuid.New().String(),
+			Name:      SubscriptionChangedEventType,
+			CreatedAt: time.Now(),
+			Meta:      make(map[string]interface{}),
+			Partition: subscriptionPartition(organizationID),
+		},
+		OrganizationID:     organizationID,
+		SubscriptionID:     subscriptionID,
+		Status:             status,
+		ProductID:          productID,
+		ProductName:        productName,
+		CurrentPeriodStart: periodStart,
+		CurrentPeriodEnd:   periodEnd,
+		CancelAtPeriodEnd:  cancelAtPeriodEnd,
+		CanceledAt:         canceledAt,
+	}
+}
+
+// DriftField is a stored value that disagreed with the billing provider
+type DriftField struct {
+	Field    string `json:"field"`
+	Stored   string `json:"stored"`
+	Provider string `json:"provider"`
+}
+
+// SubscriptionReconciled is published when background reconciliation found
+// an organization's stored subscription or quota out of sync with the billing
+// provider, typically after a missed webhook
+type SubscriptionReconciled struct {
+	eventbus.BaseEvent
+	OrganizationID int32        `json:"organization_id"`
+	SubscriptionID string       `json:"subscription_id"`
+	Status         string       `json:"status"`
+	Drift          []DriftField `json:"drift"`
+	Repaired       bool         `json:"repaired"`
+}
+
+func NewSubscriptionReconciled(organizationID int32, subscriptionID, status string, drift []DriftField, repaired bool) *SubscriptionReconciled {
+	return &SubscriptionReconciled{
+		BaseEvent: eventbus.BaseEvent{
+			ID:        uuid.New().String(),
+			Name:      SubscriptionReconciledEventType,
+			CreatedAt: time.Now(),
+			Meta:      make(map[string]interface{}),
+			Partition: subscriptionPartition(organizationID),
+		},
+		OrganizationID: organizationID,
+		SubscriptionID: subscriptionID,
+		Status:         status,
+		Drift:          drift,
+		Repaired:       repaired,
+	}
+}
+
+// subscriptionPartition keeps the billing events of one organization in order
+func subscriptionPartition(organizationID int32) string {
+	return fmt.Sprintf("billing:%d", organizationID)
+}
@@ -1,6 +1,10 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"net/http"
+	"time"
+)
 
 // SubscriptionRepository provides database operations for subscriptions and quotas
 type SubscriptionRepository interface {
@@ -8,20 +12,89 @@ type SubscriptionRepository interface {
 	GetSubscriptionByOrgID(ctx context.Context, organizationID int32) (*Subscription, error)
 	UpsertSubscription(ctx context.Context, subscription *Subscription) (*Subscription, error)
 	DeleteSubscription(ctx context.Context, organizationID int32) error
+	// ListSubscriptionsAfter pages through all subscriptions in ID order,
+	// returning up to limit subscriptions with an ID above afterID
+	ListSubscriptionsAfter(ctx context.Context, afterID int32, limit int32) ([]Subscription, error)
+	// ListPastDueSubscriptionsAfter pages through the past_due subscriptions in ID order
+	ListPastDueSubscriptionsAfter(ctx context.Context, afterID int32, limit int32) ([]Subscription, error)
+	// SetDunningPhase records the dunning phase announced for a past_due
+	// subscription. It returns changed=false when the phase was already
+	// recorded or the subscription is no longer past_due.
+	SetDunningPhase(ctx context.Context, organizationID int32, phase string) (subscription *Subscription, changed bool, err error)
 
 	// Quota operations
 	GetQuotaByOrgID(ctx context.Context, organizationID int32) (*QuotaTracking, error)
 	UpsertQuota(ctx context.Context, quota *QuotaTracking) (*QuotaTracking, error)
-	DecrementInvoiceCount(ctx context.Context, organizationID int32) (*QuotaTracking, error)
+	// ResetQuotaPeriod moves an organization's quota tracking to a new period
+	ResetQuotaPeriod(ctx context.Context, organizationID int32, periodStart, periodEnd time.Time) (*QuotaTracking, error)
+	// ListQuotasDueForRollover pages through the quotas of renewing
+	// subscriptions whose period ended before now, in organization ID order
+	ListQuotasDueForRollover(ctx context.Context, now time.Time, afterOrganizationID int32, limit int32) ([]QuotaTracking, error)
+	// SetSeatGrace starts the seat grace period ending at endsAt, or ends it when endsAt is nil
+	SetSeatGrace(ctx context.Context, organizationID int32, endsAt *time.Time) (*QuotaTracking, error)
+
+	// Meter quota operations
+	GetMeterQuota(ctx context.Context, organizationID int32, meterSlug string) (*MeterQuota, error)
+	ListMeterQuotas(ctx context.Context, organizationID int32) ([]MeterQuota, error)
+	// UpsertMeterAllowance sets a meter's allowance; consumption restarts
+	// when periodStart differs from the stored period
+	UpsertMeterAllowance(ctx context.Context, organizationID int32, meterSlug string, allowance int64, periodStart, periodEnd time.Time) (*MeterQuota, error)
+	// SetMeterRemaining sets the units left on a meter, keeping consumption
+	SetMeterRemaining(ctx context.Context, organizationID int32, meterSlug string, remaining int64, periodStart, periodEnd time.Time) (*MeterQuota, error)
+	// ClearMeterAllowancesExcept zeroes the allowance of every other meter
+	ClearMeterAllowancesExcept(ctx context.Context, organizationID int32, meterSlugs []string) error
+	// ResetMeterPeriods moves every meter to a new period, zeroing consumption;
+	// the closed period is archived to the meter quota history
+	ResetMeterPeriods(ctx context.Context, organizationID int32, periodStart, periodEnd time.Time) ([]MeterQuota, error)
+	// ConsumeMeterQuota atomically consumes units and appends them to the
+	// usage ledger. It returns ErrQuotaExceeded, ErrMeterQuotaNotFound or
+	// ErrUsageAlreadyRecorded without consuming anything.
+	ConsumeMeterQuota(ctx context.Context, usage *UsageRecord) (*MeterQuota, error)
+
+	// Quota reservation operations
+	// ReserveMeterQuota atomically holds units of a meter until expiresAt. It
+	// returns ErrQuotaExceeded or ErrMeterQuotaNotFound without holding anything.
+	ReserveMeterQuota(ctx context.Context, usage *UsageRecord, expiresAt time.Time) (*QuotaReservation, error)
+	// CommitQuotaReservation consumes the reserved units and appends them to the
+	// usage ledger with the reservation's reference and metadata. It returns
+	// ErrReservationNotFound or ErrUsageAlreadyRecorded without consuming anything.
+	CommitQuotaReservation(ctx context.Context, reservation *QuotaReservation) (*MeterQuota, error)
+	// ReleaseQuotaReservation returns the reserved units to the meter; it
+	// returns ErrReservationNotFound when the reservation is already gone
+	ReleaseQuotaReservation(ctx context.Context, reservationID int64) error
+	// ReleaseExpiredReservations releases the reservations of a meter that
+	// expired before now and returns how many were released
+	ReleaseExpiredReservations(ctx context.Context, organizationID int32, meterSlug string, now time.Time) (int64, error)
+
+	// Usage ledger operations
+	// SummarizeUsage sums the usage selected by query per day, meter and
+	// account, ignoring the query's groupings
+	SummarizeUsage(ctx context.Context, query *UsageQuery) ([]UsageTotal, error)
+	// ListUsageEntriesAfter pages through the ledger entries selected by query
+	// in ID order, returning up to limit entries with an ID above afterID
+	ListUsageEntriesAfter(ctx context.Context, query *UsageQuery, afterID int64, limit int32) ([]UsageLedgerEntry, error)
 
 	// Combined operations
 	GetQuotaStatus(ctx context.Context, organizationID int32) (*QuotaStatus, error)
 }
 
+// WebhookRepository records inbound provider webhooks for idempotency and replay
+type WebhookRepository interface {
+	// Claim records the webhook and marks it as processing. It returns
+	// claimed=false when the webhook was already processed or is being
+	// processed by a concurrent delivery.
+	Claim(ctx context.Context, provider, webhookID, eventType string, payload []byte) (webhook *ReceivedWebhook, claimed bool, err error)
+	GetByID(ctx context.Context, id int64) (*ReceivedWebhook, error)
+	MarkProcessed(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64, reason string) error
+}
+
 // OrganizationAdapter provides access to organization data
 type OrganizationAdapter interface {
 	GetStytchOrgID(ctx context.Context, organizationID int32) (string, error)
 	GetOrganizationIDByStytchOrgID(ctx context.Context, stytchOrgID string) (int32, error)
+	// CountActiveMembers counts the organization's active accounts, each taking a seat
+	CountActiveMembers(ctx context.Context, organizationID int32) (int64, error)
 }
 
 // BillingProvider defines operations for external billing providers
@@ -30,5 +103,32 @@ type BillingProvider interface {
 	GetSubscription(ctx context.Context, externalCustomerID string) (*Subscription, error)
 	GetCheckoutSession(ctx context.Context, sessionID string) (*CheckoutSessionResponse, error)
 	GetCheckoutSessionWithPolling(ctx context.Context, sessionID string) (*CheckoutSessionResponse, error)
-	IngestMeterEvent(ctx context.Context, externalCustomerID string, meterSlug string, amount int32) error
+	IngestMeterEvent(ctx context.Context, externalCustomerID string, meterSlug string, amount int64) error
+	// CreateCheckoutSession creates a hosted checkout for a product. Returns
+	// ErrProductNotFound (wrapped) when the provider rejects the product.
+	CreateCheckoutSession(ctx context.Context, req *CheckoutRequest) (*CheckoutSession, error)
+	// CreateCustomerPortalSession creates a customer portal link. Returns
+	// ErrCustomerNotFound (wrapped) when the customer never checked out.
+	CreateCustomerPortalSession(ctx context.Context, externalCustomerID string, returnURL string) (*CustomerPortalSession, error)
+	// ListProducts lists the products for sale, with their metadata, for the plan catalog
+	ListProducts(ctx context.Context) ([]CatalogProduct, error)
+}
+
+// WebhookVerifier authenticates inbound webhooks of a billing provider
+type WebhookVerifier interface {
+	// Provider names the billing provider the webhooks come from
+	Provider() string
+	// Verify checks the request signature and freshness and returns the
+	// webhook ID. It returns ErrWebhookSignatureInvalid or
+	// ErrWebhookTimestampStale (wrapped) when the request is rejected.
+	Verify(headers http.Header, payload []byte) (webhookID string, err error)
+}
+
+// WebhookParser maps the webhook payloads of a billing provider into
+// provider-agnostic event data
+type WebhookParser interface {
+	// Parse classifies a webhook by its event type and extracts its data,
+	// fetching from the provider what the payload only refers to by ID.
+	// Event types without local effect are returned as WebhookIgnored.
+	Parse(ctx context.Context, eventType string, payload map[string]any) (*ParsedWebhookEvent, error)
 }
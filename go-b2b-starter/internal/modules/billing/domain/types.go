@@ -19,42 +19,321 @@ type Subscription struct {
 	Metadata           map[string]any
 	CreatedAt          time.Time
 	UpdatedAt          time.Time
+	// PastDueSince is when the subscription became past_due; nil in any other status
+	PastDueSince *time.Time
+	// DunningPhase is the last dunning phase announced by a reminder event
+	DunningPhase string
 }
 
 // QuotaTracking represents usage quota tracking for an organization
 type QuotaTracking struct {
 	ID             int32
 	OrganizationID int32
-	InvoiceCount   int32 // Remaining invoices (decremented on use)
 	MaxSeats       int32
 	PeriodStart    time.Time
 	PeriodEnd      time.Time
 	LastSyncedAt   *time.Time
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
+	// SeatGraceEndsAt is set while the organization has more active members
+	// than MaxSeats after a plan change
+	SeatGraceEndsAt *time.Time
 }
 
 // QuotaStatus represents the combined subscription and quota status
 // This is returned from the GetQuotaStatus database query
 type QuotaStatus struct {
 	SubscriptionStatus string
+	ProductID          string
 	CurrentPeriodStart time.Time
 	CurrentPeriodEnd   time.Time
 	CancelAtPeriodEnd  bool
-	InvoiceCount       int32 // Remaining invoices
+	PastDueSince       *time.Time
 	MaxSeats           int32
-	CanProcessInvoice  bool
+}
+
+// Meter slugs billed by the platform. Product metadata grants an allowance
+// per meter, and consumption is reported to the billing provider under the
+// same slug, so provider meters must filter on these event names.
+const (
+	MeterInvoiceProcessed  = "invoice.processed"
+	MeterDocumentProcessed = "document.processed"
+	MeterOCRPage           = "ocr.page"
+	MeterLLMToken          = "llm.token"
+	MeterStorageByte       = "storage.byte"
+)
+
+// MeterQuota is the allowance and consumption of one meter for an
+// organization in the current billing period
+type MeterQuota struct {
+	ID             int32
+	OrganizationID int32
+	MeterSlug      string
+	Allowance      int64 // Units granted for the period
+	Consumed       int64 // Units used so far in the period
+	Reserved       int64 // Units held by open reservations
+	PeriodStart    time.Time
+	PeriodEnd      time.Time
+	LastSyncedAt   *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// Remaining returns the units left in the period that are not held by a
+// reservation
+func (q *MeterQuota) Remaining() int64 {
+	if remaining := q.Allowance - q.Consumed - q.Reserved; remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// UsageRecord describes units to charge against a meter. It is appended to
+// the usage ledger when the consumption succeeds.
+type UsageRecord struct {
+	OrganizationID int32
+	MeterSlug      string
+	Amount         int64
+	AccountID      int32          // Optional: member whose action consumed the quota
+	Reference      string         // Optional: idempotency key, e.g. the document ID
+	Metadata       map[string]any // Optional
+}
+
+// QuotaReservation holds units of a meter for work in progress until it is
+// committed, which consumes them, or released. A reservation that is neither
+// is released once it expires.
+type QuotaReservation struct {
+	ID             int64
+	OrganizationID int32
+	MeterSlug      string
+	Amount         int64
+	AccountID      int32          // Optional: member whose action consumes the quota
+	Reference      string         // Optional: idempotency key, recorded on commit
+	Metadata       map[string]any // Optional: recorded in the usage ledger on commit
+	Remaining      int64          // Units left on the meter after the reservation
+	PeriodEnd      time.Time      // When the meter's allowance resets
+	ExpiresAt      time.Time
+	CreatedAt      time.Time
+}
+
+// QuotaCheck is the outcome of checking or consuming a meter quota
+type QuotaCheck struct {
+	OrganizationID     int32
+	MeterSlug          string
+	SubscriptionStatus string
+	Requested          int64
+	Allowance          int64
+	Consumed           int64
+	Remaining          int64
+	PeriodEnd          time.Time
+	Allowed            bool
+	Reason             string
+	CheckedAt          time.Time
+}
+
+// Usage report groupings
+const (
+	UsageGroupDay     = "day"     // UTC calendar day of the consumption
+	UsageGroupMeter   = "meter"   // meter slug
+	UsageGroupAccount = "account" // member whose action consumed the quota
+)
+
+// UsageQuery selects an organization's usage ledger entries recorded in
+// [From, To)
+type UsageQuery struct {
+	OrganizationID int32
+	From           time.Time
+	To             time.Time
+	MeterSlug      string   // Optional: only this meter
+	GroupBy        []string // UsageGroup* values; none sums all usage into one total
+}
+
+// UsageTotal is the usage of one group of a usage report. Only the fields of
+// the report's groupings are set.
+type UsageTotal struct {
+	Day          *time.Time
+	MeterSlug    string
+	AccountID    *int32 // nil for usage without a member, e.g. background jobs
+	AccountEmail string
+	Amount       int64 // units consumed
+	Entries      int64 // ledger entries summed
+}
+
+// UsageReport is an organization's usage in [From, To), grouped as requested
+type UsageReport struct {
+	OrganizationID int32
+	From           time.Time
+	To             time.Time
+	MeterSlug      string
+	GroupBy        []string
+	Totals         []UsageTotal
+	TotalAmount    int64
+	TotalEntries   int64
+	GeneratedAt    time.Time
+}
+
+// UsageLedgerEntry is one recorded quota consumption
+type UsageLedgerEntry struct {
+	ID             int64
+	OrganizationID int32
+	MeterSlug      string
+	Amount         int64
+	AccountID      *int32
+	AccountEmail   string
+	Reference      string // source entity, e.g. the document ID
+	PeriodStart    time.Time
+	PeriodEnd      time.Time
+	Metadata       map[string]any
+	CreatedAt      time.Time
+}
+
+// Seat states of an organization
+const (
+	SeatStateUnlimited = "unlimited"  // the plan grants no seat limit
+	SeatStateAvailable = "available"  // members can be added
+	SeatStateFull      = "full"       // every seat is taken
+	SeatStateGrace     = "grace"      // over the limit after a plan change, members keep access
+	SeatStateOverLimit = "over_limit" // over the limit and the grace period has ended
+)
+
+// SeatUsage is the seat accounting of an organization. Every active account
+// takes a seat; MaxSeats 0 means the plan does not limit seats.
+type SeatUsage struct {
+	OrganizationID int32
+	MaxSeats       int32
+	UsedSeats      int64
+	AvailableSeats int64
+	State          string
+	GraceEndsAt    *time.Time
+	CheckedAt      time.Time
+}
+
+// CanAddMember reports whether another member fits within the seat limit
+func (u *SeatUsage) CanAddMember() bool {
+	return u.State == SeatStateUnlimited || u.State == SeatStateAvailable
+}
+
+// Dunning phases of a past_due subscription. The phase is empty for any
+// other status.
+const (
+	DunningPhaseGrace    = "grace"     // access continues, the organization is warned
+	DunningPhaseReadOnly = "read_only" // reads are allowed, writes are refused
+	DunningPhaseBlocked  = "blocked"   // access is refused until the payment succeeds
+)
+
+// DunningPolicy sets how long a past_due organization keeps access while the
+// provider retries the payment: GracePeriod of full access, then
+// ReadOnlyPeriod of read-only access, then a full block
+type DunningPolicy struct {
+	GracePeriod    time.Duration
+	ReadOnlyPeriod time.Duration
+}
+
+// Phase returns the dunning phase at now of a subscription past due since
+// pastDueSince, and when that phase ends (nil once blocked)
+func (p DunningPolicy) Phase(pastDueSince, now time.Time) (string, *time.Time) {
+	graceEndsAt := pastDueSince.Add(p.GracePeriod)
+	if now.Before(graceEndsAt) {
+		return DunningPhaseGrace, &graceEndsAt
+	}
+	blockedAt := graceEndsAt.Add(p.ReadOnlyPeriod)
+	if now.Before(blockedAt) {
+		return DunningPhaseReadOnly, &blockedAt
+	}
+	return DunningPhaseBlocked, nil
 }
 
 // BillingStatus represents the overall billing status for quota verification
 type BillingStatus struct {
 	OrganizationID        int32
 	ExternalID            string
-	HasActiveSubscription bool
-	CanProcessInvoices    bool
-	InvoiceCount          int32 // Remaining invoices
-	Reason                string
-	CheckedAt             time.Time
+	HasActiveSubscription bool // also true during the dunning grace period
+	SubscriptionStatus    string
+	Plan                  string // plan key in the plan catalog, empty outside it
+	// DunningPhase is set while the subscription is past_due
+	DunningPhase       string
+	PastDueSince       *time.Time
+	DunningPhaseEndsAt *time.Time
+	Quotas             []MeterQuota
+	Reason             string
+	CheckedAt          time.Time
+}
+
+// Fields compared by subscription reconciliation
+const (
+	DriftSubscriptionID    = "subscription_id"
+	DriftStatus            = "status"
+	DriftProduct           = "product_id"
+	DriftPeriodStart       = "current_period_start"
+	DriftPeriodEnd         = "current_period_end"
+	DriftCancelAtPeriodEnd = "cancel_at_period_end"
+	DriftMaxSeats          = "max_seats"
+	DriftQuotaPeriod       = "quota_period"
+	DriftMeterAllowances   = "meter_allowances"
+	DriftMissingAtProvider = "missing_at_provider"
+)
+
+// SubscriptionDrift is a value stored locally that disagrees with the
+// billing provider
+type SubscriptionDrift struct {
+	Field    string
+	Stored   string
+	Provider string
+}
+
+// ReconcileResult is the outcome of comparing one stored subscription with
+// the billing provider
+type ReconcileResult struct {
+	OrganizationID int32
+	SubscriptionID string
+	Drift          []SubscriptionDrift
+	Repaired       bool
+}
+
+// ReconcileReport summarizes a reconciliation pass over all subscriptions
+type ReconcileReport struct {
+	Checked    int
+	Drifted    int
+	Repaired   int
+	Failed     int
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// Sources of a rolled over quota period
+const (
+	RolloverSourceProvider = "provider" // the provider had already renewed the subscription
+	RolloverSourceLocal    = "local"    // estimated from the closed period; corrected by the renewal webhook
+)
+
+// PeriodRollover describes an organization's quota moving to a new billing
+// period. Usage is the allowance and consumption of the closed period.
+type PeriodRollover struct {
+	OrganizationID      int32
+	PreviousPeriodStart time.Time
+	PreviousPeriodEnd   time.Time
+	PeriodStart         time.Time
+	PeriodEnd           time.Time
+	Source              string
+	Usage               []MeterQuota
+}
+
+// RolloverReport summarizes a pass over the quota periods that have ended
+type RolloverReport struct {
+	RolledOver int
+	Skipped    int
+	Failed     int
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// DunningReport summarizes a pass over the past_due subscriptions
+type DunningReport struct {
+	Checked    int
+	Announced  int
+	Failed     int
+	StartedAt  time.Time
+	FinishedAt time.Time
 }
 
 // WebhookEvent represents a Polar webhook event
@@ -63,6 +342,28 @@ type WebhookEvent struct {
 	Payload   map[string]any
 }
 
+// ReceivedWebhook statuses
+const (
+	ReceivedWebhookProcessing = "processing"
+	ReceivedWebhookProcessed  = "processed"
+	ReceivedWebhookFailed     = "failed"
+)
+
+// ReceivedWebhook is an inbound provider webhook as recorded on receipt.
+// Provider and WebhookID identify it, so redeliveries map to the same record.
+type ReceivedWebhook struct {
+	ID          int64
+	Provider    string
+	WebhookID   string
+	EventType   string
+	Payload     []byte // raw request body
+	Status      string
+	Attempts    int32
+	LastError   string
+	ReceivedAt  time.Time
+	ProcessedAt *time.Time
+}
+
 // SubscriptionEventData represents parsed subscription data from webhook
 type SubscriptionEventData struct {
 	SubscriptionID     string
@@ -85,6 +386,88 @@ type MeterGrantEventData struct {
 	AvailableCredits   int32
 }
 
+// WebhookEventKind classifies a provider webhook by its effect on local state
+type WebhookEventKind string
+
+const (
+	WebhookSubscriptionUpserted WebhookEventKind = "subscription_upserted"
+	WebhookSubscriptionCanceled WebhookEventKind = "subscription_canceled"
+	WebhookCustomerUpdated      WebhookEventKind = "customer_updated"
+	WebhookMeterGrant           WebhookEventKind = "meter_grant"
+	WebhookIgnored              WebhookEventKind = "ignored"
+)
+
+// ParsedWebhookEvent is a provider webhook mapped into provider-agnostic data.
+// Subscription is set for the subscription and customer kinds, MeterGrant
+// for WebhookMeterGrant.
+type ParsedWebhookEvent struct {
+	Type         string // provider event type, e.g. "subscription.updated"
+	Kind         WebhookEventKind
+	Subscription *SubscriptionEventData
+	MeterGrant   *MeterGrantEventData
+}
+
+// Plan sources: plans configured in the plan file override the plans derived
+// from the metadata of the provider's products
+const (
+	PlanSourceFile     = "file"
+	PlanSourceProvider = "provider"
+)
+
+// LimitMaxSeats is the numeric limit on the active members of an organization
+const LimitMaxSeats = "max_seats"
+
+// PlanEntitlements are the capabilities a plan grants
+type PlanEntitlements struct {
+	Features map[string]bool  // boolean features, e.g. "ai_chat"
+	Limits   map[string]int64 // numeric limits, e.g. "max_seats"
+	Meters   map[string]int64 // meter allowance per period, by meter slug
+}
+
+// Plan is a named plan of the plan catalog and the provider products it is
+// sold as, e.g. a monthly and a yearly product
+type Plan struct {
+	Key          string
+	Name         string
+	ProductIDs   []string
+	Entitlements PlanEntitlements
+	Source       string // PlanSourceFile or PlanSourceProvider
+}
+
+// CatalogProduct is a product listed by the billing provider
+type CatalogProduct struct {
+	ID       string
+	Name     string
+	Metadata map[string]string
+}
+
+// Entitlements are the capabilities an organization can use now. They are
+// empty while the organization has no active subscription; a past_due
+// subscription keeps them during the dunning grace period.
+type Entitlements struct {
+	OrganizationID     int32
+	Plan               string // plan key, empty when the product is not in the catalog
+	PlanName           string
+	ProductID          string
+	SubscriptionStatus string
+	Active             bool
+	Features           map[string]bool
+	Limits             map[string]int64
+	Meters             map[string]int64
+	CheckedAt          time.Time
+}
+
+// HasFeature reports whether a boolean feature is enabled
+func (e *Entitlements) HasFeature(feature string) bool {
+	return e.Active && e.Features[feature]
+}
+
+// Limit returns a numeric limit; ok is false when the plan sets no such limit
+func (e *Entitlements) Limit(name string) (limit int64, ok bool) {
+	limit, ok = e.Limits[name]
+	return limit, ok
+}
+
 // CheckoutSessionResponse represents a Polar checkout session
 type CheckoutSessionResponse struct {
 	ID             string
@@ -95,3 +478,27 @@ type CheckoutSessionResponse struct {
 	Amount         int64
 	CreatedAt      time.Time
 }
+
+// CheckoutRequest asks the billing provider for a hosted checkout of a product.
+// The checkout is attached to the organization as the provider's external customer.
+type CheckoutRequest struct {
+	ExternalCustomerID string // Stytch org ID
+	ProductID          string // provider product ID
+	SuccessURL         string // redirect after payment; the provider appends the checkout ID as session_id
+	CancelURL          string // redirect when the customer leaves the checkout
+}
+
+// CheckoutSession is a hosted checkout created by the billing provider.
+// The customer pays at URL.
+type CheckoutSession struct {
+	ID        string
+	URL       string
+	ExpiresAt *time.Time
+}
+
+// CustomerPortalSession is a short-lived link to the billing provider's customer
+// portal, where the customer manages its subscription, payment methods and invoices
+type CustomerPortalSession struct {
+	URL       string
+	ExpiresAt *time.Time
+}
@@ -3,6 +3,7 @@ package billing
 import (
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
@@ -15,21 +16,30 @@ import (
 	"github.com/moasq/go-b2b-starter/pkg/httperr"
 )
 
+// maxWebhookBodyBytes bounds the size of an inbound webhook body
+const maxWebhookBodyBytes = 1 << 20
+
 type Handler struct {
 	billingService billingServices.BillingService
+	webhookService billingServices.WebhookService
 	logger         logger.Logger
 }
 
-func NewHandler(billingService billingServices.BillingService, log logger.Logger) *Handler {
+func NewHandler(
+	billingService billingServices.BillingService,
+	webhookService billingServices.WebhookService,
+	log logger.Logger,
+) *Handler {
 	return &Handler{
 		billingService: billingService,
+		webhookService: webhookService,
 		logger:         log,
 	}
 }
 
 // GetBillingStatus godoc
 // @Summary Get current billing and quota status
-// @Description Retrieve the current subscription billing status and invoice quota information for the organization
+// @Description Retrieve the current subscription billing status and the allowance and consumption of each metered quota for the organization
 // @Tags subscriptions
 // @Accept json
 // @Produce json
@@ -58,8 +68,7 @@ func (h *Handler) GetBillingStatus(c *gin.Context) {
 			c.JSON(http.StatusOK, domain.BillingStatus{
 				OrganizationID:        reqCtx.OrganizationID,
 				HasActiveSubscription: false,
-				CanProcessInvoices:    false,
-				InvoiceCount:          0,
+				Quotas:                []domain.MeterQuota{},
 				Reason:                "No active subscription found",
 				CheckedAt:             time.Now(),
 			})
@@ -77,6 +86,208 @@ func (h *Handler) GetBillingStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, billingStatus)
 }
 
+// GetSeatUsage godoc
+// @Summary Get seat usage
+// @Description Count the organization's active members against the seat limit of its plan. After a downgrade leaves more members than seats, the state is "grace" until the grace period ends and "over_limit" afterwards; members keep access, but no members can be added.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Success 200 {object} domain.SeatUsage "Used and available seats"
+// @Failure 400 {object} httperr.HTTPError "Missing organization context"
+// @Failure 500 {object} httperr.HTTPError "Internal server error"
+// @Router /api/subscriptions/seats [get]
+func (h *Handler) GetSeatUsage(c *gin.Context) {
+	reqCtx := auth.GetRequestContext(c)
+	if reqCtx == nil {
+		c.JSON(http.StatusBadRequest, httperr.NewHTTPError(
+			http.StatusBadRequest,
+			"missing_context",
+			"Organization context is required",
+		))
+		return
+	}
+
+	usage, err := h.billingService.GetSeatUsage(c.Request.Context(), reqCtx.OrganizationID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, httperr.NewHTTPError(
+			http.StatusInternalServerError,
+			"seat_usage_failed",
+			fmt.Sprintf("Failed to retrieve seat usage: %v", err),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, usage)
+}
+
+// GetEntitlements godoc
+// @Summary Get plan entitlements
+// @Description Returns the plan of the organization's subscription and what it can use: boolean features, numeric limits (e.g. max_seats) and meter allowances. Use it to gate the UI. Entitlements are empty while the subscription is not active.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Success 200 {object} domain.Entitlements "Plan entitlements"
+// @Failure 400 {object} httperr.HTTPError "Missing organization context"
+// @Failure 500 {object} httperr.HTTPError "Internal server error"
+// @Router /api/subscriptions/entitlements [get]
+func (h *Handler) GetEntitlements(c *gin.Context) {
+	reqCtx := auth.GetRequestContext(c)
+	if reqCtx == nil {
+		c.JSON(http.StatusBadRequest, httperr.NewHTTPError(
+			http.StatusBadRequest,
+			"missing_context",
+			"Organization context is required",
+		))
+		return
+	}
+
+	entitlements, err := h.billingService.Entitlements(c.Request.Context(), reqCtx.OrganizationID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, httperr.NewHTTPError(
+			http.StatusInternalServerError,
+			"entitlements_failed",
+			fmt.Sprintf("Failed to retrieve entitlements: %v", err),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, entitlements)
+}
+
+// CreateCheckoutRequest represents the request payload for creating a checkout session
+type CreateCheckoutRequest struct {
+	ProductID string `json:"product_id" binding:"required"`
+}
+
+// CreateCheckoutSession godoc
+// @Summary Create a checkout session
+// @Description Creates a hosted checkout at the billing provider for the chosen product, with the organization attached as the external customer. Redirect the user to the returned URL; after payment the provider redirects to BILLING_CHECKOUT_SUCCESS_URL with the checkout ID as session_id, for POST /api/subscriptions/verify-payment.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param request body CreateCheckoutRequest true "Product to subscribe to"
+// @Success 201 {object} domain.CheckoutSession "Checkout session with the payment URL"
+// @Failure 400 {object} httperr.HTTPError "Invalid request parameters or missing organization context"
+// @Failure 404 {object} httperr.HTTPError "Product is not a plan product for sale"
+// @Failure 409 {object} httperr.HTTPError "Organization already has a subscription; change plans through the customer portal"
+// @Failure 429 {object} httperr.HTTPError "Billing provider rate limit exceeded"
+// @Failure 500 {object} httperr.HTTPError "Internal server error"
+// @Router /api/subscriptions/checkout [post]
+func (h *Handler) CreateCheckoutSession(c *gin.Context) {
+	reqCtx := auth.GetRequestContext(c)
+	if reqCtx == nil {
+		c.JSON(http.StatusBadRequest, httperr.NewHTTPError(
+			http.StatusBadRequest,
+			"missing_context",
+			"Organization context is required",
+		))
+		return
+	}
+
+	var req CreateCheckoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, httperr.NewHTTPError(
+			http.StatusBadRequest,
+			"invalid_request",
+			fmt.Sprintf("Invalid request: %v", err),
+		))
+		return
+	}
+
+	session, err := h.billingService.CreateCheckoutSession(c.Request.Context(), reqCtx.OrganizationID, req.ProductID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrProductNotFound):
+			c.JSON(http.StatusNotFound, httperr.NewHTTPError(
+				http.StatusNotFound,
+				"product_not_found",
+				fmt.Sprintf("Product not found: %s", req.ProductID),
+			))
+		case errors.Is(err, domain.ErrSubscriptionExists):
+			c.JSON(http.StatusConflict, httperr.NewHTTPError(
+				http.StatusConflict,
+				"subscription_exists",
+				"Organization already has a subscription; change plans through the customer portal",
+			))
+		case errors.Is(err, domain.ErrProviderRateLimited):
+			c.JSON(http.StatusTooManyRequests, httperr.NewHTTPError(
+				http.StatusTooManyRequests,
+				"provider_rate_limited",
+				"Billing provider is busy, please retry shortly",
+			))
+		default:
+			h.logger.Error("Failed to create checkout session", map[string]any{
+				"organization_id": reqCtx.OrganizationID,
+				"product_id":      req.ProductID,
+				"error":           err.Error(),
+			})
+			c.JSON(http.StatusInternalServerError, httperr.NewHTTPError(
+				http.StatusInternalServerError,
+				"checkout_failed",
+				"Failed to create checkout session",
+			))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, session)
+}
+
+// CreateCustomerPortalSession godoc
+// @Summary Create a customer portal session
+// @Description Returns a short-lived link to the billing provider's customer portal, where the organization manages its subscription, payment methods and invoices. Changes made in the portal are synced back through webhooks.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Success 200 {object} domain.CustomerPortalSession "Customer portal URL"
+// @Failure 400 {object} httperr.HTTPError "Missing organization context"
+// @Failure 404 {object} httperr.HTTPError "Organization has no billing customer yet"
+// @Failure 429 {object} httperr.HTTPError "Billing provider rate limit exceeded"
+// @Failure 500 {object} httperr.HTTPError "Internal server error"
+// @Router /api/subscriptions/portal [post]
+func (h *Handler) CreateCustomerPortalSession(c *gin.Context) {
+	reqCtx := auth.GetRequestContext(c)
+	if reqCtx == nil {
+		c.JSON(http.StatusBadRequest, httperr.NewHTTPError(
+			http.StatusBadRequest,
+			"missing_context",
+			"Organization context is required",
+		))
+		return
+	}
+
+	session, err := h.billingService.CreateCustomerPortalSession(c.Request.Context(), reqCtx.OrganizationID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrCustomerNotFound):
+			c.JSON(http.StatusNotFound, httperr.NewHTTPError(
+				http.StatusNotFound,
+				"customer_not_found",
+				"Organization has no billing customer yet, complete a checkout first",
+			))
+		case errors.Is(err, domain.ErrProviderRateLimited):
+			c.JSON(http.StatusTooManyRequests, httperr.NewHTTPError(
+				http.StatusTooManyRequests,
+				"provider_rate_limited",
+				"Billing provider is busy, please retry shortly",
+			))
+		default:
+			h.logger.Error("Failed to create customer portal session", map[string]any{
+				"organization_id": reqCtx.OrganizationID,
+				"error":           err.Error(),
+			})
+			c.JSON(http.StatusInternalServerError, httperr.NewHTTPError(
+				http.StatusInternalServerError,
+				"portal_failed",
+				"Failed to create customer portal session",
+			))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
 // VerifyPaymentRequest represents the request payload for verifying a payment
 type VerifyPaymentRequest struct {
 	SessionID string `json:"session_id" binding:"required"`
@@ -161,8 +372,7 @@ func (h *Handler) VerifyPayment(c *gin.Context) {
 	h.logger.Info("[VerifyPayment] Billing service returned status", map[string]any{
 		"session_id":              req.SessionID,
 		"has_active_subscription": billingStatus.HasActiveSubscription,
-		"can_process_invoices":    billingStatus.CanProcessInvoices,
-		"invoice_count":           billingStatus.InvoiceCount,
+		"meter_quotas":            len(billingStatus.Quotas),
 		"reason":                  billingStatus.Reason,
 	})
 
@@ -183,8 +393,89 @@ func (h *Handler) VerifyPayment(c *gin.Context) {
 	h.logger.Info("[VerifyPayment] Payment verification completed successfully", map[string]any{
 		"session_id":      req.SessionID,
 		"organization_id": billingStatus.OrganizationID,
-		"invoice_count":   billingStatus.InvoiceCount,
+		"meter_quotas":    len(billingStatus.Quotas),
 	})
 
 	c.JSON(http.StatusOK, billingStatus)
 }
+
+// HandleProviderWebhook godoc
+// @Summary Receive a billing provider webhook
+// @Description Receives subscription and meter webhooks from the configured billing provider, at /api/webhooks/polar or /api/webhooks/stripe. Polar requests are authenticated with the Standard Webhooks headers (Webhook-Id, Webhook-Timestamp, Webhook-Signature), Stripe requests with the Stripe-Signature header; stale timestamps are rejected. Each webhook ID is processed once, redeliveries are acknowledged without reprocessing, and the raw payload is stored for auditing and replay.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param provider path string true "Billing provider" Enums(polar, stripe)
+// @Param Webhook-Id header string false "Webhook ID (Polar)"
+// @Param Webhook-Timestamp header string false "Unix timestamp of the delivery (Polar)"
+// @Param Webhook-Signature header string false "Signature, e.g. v1,<base64> (Polar)"
+// @Param Stripe-Signature header string false "Signature, e.g. t=<unix>,v1=<hex> (Stripe)"
+// @Success 200 {object} billingServices.WebhookResult "Webhook processed or already processed"
+// @Failure 400 {object} httperr.HTTPError "Malformed payload or stale timestamp"
+// @Failure 401 {object} httperr.HTTPError "Invalid signature"
+// @Failure 413 {object} httperr.HTTPError "Payload too large"
+// @Failure 500 {object} httperr.HTTPError "Processing failed; the provider retries the delivery"
+// @Router /api/webhooks/{provider} [post]
+func (h *Handler) HandleProviderWebhook(c *gin.Context) {
+	// Signatures are computed over the exact bytes received
+	payload, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookBodyBytes))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, httperr.NewHTTPError(
+				http.StatusRequestEntityTooLarge,
+				"payload_too_large",
+				"Webhook payload is too large",
+			))
+			return
+		}
+		c.JSON(http.StatusBadRequest, httperr.NewHTTPError(
+			http.StatusBadRequest,
+			"invalid_request",
+			"Failed to read webhook payload",
+		))
+		return
+	}
+
+	result, err := h.webhookService.ReceiveWebhook(c.Request.Context(), c.Request.Header, payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrWebhookSignatureInvalid):
+			h.logger.Warn("Rejected webhook with invalid signature", map[string]any{
+				"provider": h.webhookService.Provider(),
+				"error":    err.Error(),
+			})
+			c.JSON(http.StatusUnauthorized, httperr.NewHTTPError(
+				http.StatusUnauthorized,
+				"invalid_signature",
+				"Webhook signature verification failed",
+			))
+		case errors.Is(err, domain.ErrWebhookTimestampStale):
+			h.logger.Warn("Rejected webhook with stale timestamp", map[string]any{
+				"provider": h.webhookService.Provider(),
+				"error":    err.Error(),
+			})
+			c.JSON(http.StatusBadRequest, httperr.NewHTTPError(
+				http.StatusBadRequest,
+				"stale_timestamp",
+				"Webhook timestamp is outside the accepted tolerance",
+			))
+		case errors.Is(err, domain.ErrInvalidWebhookPayload):
+			c.JSON(http.StatusBadRequest, httperr.NewHTTPError(
+				http.StatusBadRequest,
+				"invalid_payload",
+				err.Error(),
+			))
+		default:
+			// A non-2xx response makes the provider retry the delivery
+			c.JSON(http.StatusInternalServerError, httperr.NewHTTPError(
+				http.StatusInternalServerError,
+				"webhook_processing_failed",
+				"Failed to process webhook",
+			))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
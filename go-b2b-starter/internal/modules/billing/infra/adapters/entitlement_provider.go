@@ -0,0 +1,41 @@
+package adapters
+
+import (
+	"context"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/app/services"
+	"github.com/moasq/go-b2b-starter/internal/modules/paywall"
+)
+
+// EntitlementProviderAdapter adapts the BillingService to the paywall's
+// EntitlementProvider, so RequireEntitlement gates routes on plan features
+// resolved through the plan catalog.
+type EntitlementProviderAdapter struct {
+	service services.BillingService
+}
+
+func NewEntitlementProviderAdapter(service services.BillingService) paywall.EntitlementProvider {
+	return &EntitlementProviderAdapter{service: service}
+}
+
+// GetEntitlements implements paywall.EntitlementProvider.
+//
+// An organization without an active subscription gets no features; a past_due
+// one keeps them during the dunning grace period.
+func (a *EntitlementProviderAdapter) GetEntitlements(ctx context.Context, organizationID int32) (*paywall.Entitlements, error) {
+	entitlements, err := a.service.Entitlements(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	features := make(map[string]bool, len(entitlements.Features))
+	for feature, enabled := range entitlements.Features {
+		features[feature] = enabled
+	}
+
+	return &paywall.Entitlements{
+		OrganizationID: entitlements.OrganizationID,
+		Plan:           entitlements.Plan,
+		Features:       features,
+	}, nil
+}
@@ -0,0 +1,112 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/app/services"
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+	"github.com/moasq/go-b2b-starter/internal/modules/paywall"
+	"github.com/moasq/go-b2b-starter/internal/platform/logger"
+)
+
+// QuotaProviderAdapter adapts the BillingService to the paywall's
+// QuotaProvider, so RequireQuota reserves and consumes meter quotas and
+// records the usage in the usage ledger.
+//
+// Committing or releasing a reservation changes the usage reported in the
+// paywall's quota headers, so the organization's cached status is dropped
+// afterwards. cache is nil when the status cache is disabled.
+type QuotaProviderAdapter struct {
+	service services.BillingService
+	cache   *paywall.StatusCache
+	logger  logger.Logger
+}
+
+func NewQuotaProviderAdapter(service services.BillingService, cache *paywall.StatusCache, log logger.Logger) paywall.QuotaProvider {
+	return &QuotaProviderAdapter{service: service, cache: cache, logger: log}
+}
+
+// ReserveQuota implements paywall.QuotaProvider.
+//
+// It maps the billing errors to paywall.ErrQuotaExceeded and
+// paywall.ErrSubscriptionInactive, which the middleware turns into 402.
+func (a *QuotaProviderAdapter) ReserveQuota(ctx context.Context, request *paywall.QuotaRequest) (*paywall.QuotaReservation, error) {
+	reservation, err := a.service.ReserveQuota(ctx, &domain.UsageRecord{
+		OrganizationID: request.OrganizationID,
+		MeterSlug:      request.Meter,
+		Amount:         request.Amount,
+		AccountID:      request.AccountID,
+		Reference:      request.Reference,
+		Metadata:       request.Metadata,
+	})
+	switch {
+	case errors.Is(err, domain.ErrQuotaExceeded), errors.Is(err, domain.ErrMeterQuotaNotFound):
+		return nil, paywall.ErrQuotaExceeded
+	case errors.Is(err, domain.ErrSubscriptionNotActive):
+		return nil, paywall.ErrSubscriptionInactive
+	case errors.Is(err, domain.ErrSubscriptionNotFound):
+		return nil, paywall.ErrNoSubscription
+	case err != nil:
+		return nil, err
+	}
+
+	return &paywall.QuotaReservation{
+		ID:             reservation.ID,
+		OrganizationID: reservation.OrganizationID,
+		AccountID:      reservation.AccountID,
+		Meter:          reservation.MeterSlug,
+		Amount:         reservation.Amount,
+		Reference:      reservation.Reference,
+		Metadata:       reservation.Metadata,
+		Remaining:      reservation.Remaining,
+		ResetsAt:       reservation.PeriodEnd,
+	}, nil
+}
+
+// CommitQuota implements paywall.QuotaProvider.
+func (a *QuotaProviderAdapter) CommitQuota(ctx context.Context, reservation *paywall.QuotaReservation) error {
+	if _, err := a.service.CommitQuota(ctx, toDomainReservation(reservation)); err != nil {
+		return err
+	}
+	a.invalidateStatus(ctx, reservation)
+	return nil
+}
+
+// ReleaseQuota implements paywall.QuotaProvider.
+func (a *QuotaProviderAdapter) ReleaseQuota(ctx context.Context, reservation *paywall.QuotaReservation) error {
+	if err := a.service.ReleaseQuota(ctx, toDomainReservation(reservation)); err != nil {
+		return err
+	}
+	a.invalidateStatus(ctx, reservation)
+	return nil
+}
+
+// invalidateStatus drops the cached status of the reservation's organization.
+// The usage is already settled, so a failure is logged rather than returned;
+// the status then refreshes when its TTL runs out.
+func (a *QuotaProviderAdapter) invalidateStatus(ctx context.Context, reservation *paywall.QuotaReservation) {
+	if a.cache == nil {
+		return
+	}
+	if err := a.cache.Invalidate(ctx, reservation.OrganizationID); err != nil {
+		a.logger.Warn("failed to invalidate cached subscription status after quota usage", map[string]any{
+			"organization_id": reservation.OrganizationID,
+			"meter":           reservation.Meter,
+			"error":           err.Error(),
+		})
+	}
+}
+
+// toDomainReservation maps a paywall reservation back to the billing domain
+func toDomainReservation(reservation *paywall.QuotaReservation) *domain.QuotaReservation {
+	return &domain.QuotaReservation{
+		ID:             reservation.ID,
+		OrganizationID: reservation.OrganizationID,
+		MeterSlug:      reservation.Meter,
+		Amount:         reservation.Amount,
+		AccountID:      reservation.AccountID,
+		Reference:      reservation.Reference,
+		Metadata:       reservation.Metadata,
+	}
+}
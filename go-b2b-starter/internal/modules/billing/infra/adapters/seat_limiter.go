@@ -0,0 +1,38 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/app/services"
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+	orgDomain "github.com/moasq/go-b2b-starter/internal/modules/organizations/domain"
+)
+
+// SeatLimiterAdapter adapts the BillingService to the organizations module's
+// SeatLimiter, so member management enforces the plan's seat limit without
+// depending on the billing module.
+type SeatLimiterAdapter struct {
+	service services.BillingService
+}
+
+func NewSeatLimiterAdapter(service services.BillingService) orgDomain.SeatLimiter {
+	return &SeatLimiterAdapter{service: service}
+}
+
+// CheckSeatAvailable implements orgDomain.SeatLimiter.
+//
+// It maps domain.ErrSeatLimitReached to *orgDomain.SeatLimitError carrying
+// the seat usage, which the member handler turns into 402 Payment Required.
+func (a *SeatLimiterAdapter) CheckSeatAvailable(ctx context.Context, organizationID int32) error {
+	usage, err := a.service.CheckSeatAvailable(ctx, organizationID)
+	if errors.Is(err, domain.ErrSeatLimitReached) {
+		return &orgDomain.SeatLimitError{
+			OrganizationID: usage.OrganizationID,
+			MaxSeats:       usage.MaxSeats,
+			UsedSeats:      usage.UsedSeats,
+			State:          usage.State,
+		}
+	}
+	return err
+}
@@ -0,0 +1,64 @@
+package adapters
+
+import (
+	"context"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain/events"
+	"github.com/moasq/go-b2b-starter/internal/modules/paywall"
+	"github.com/moasq/go-b2b-starter/internal/platform/eventbus"
+)
+
+// StatusCacheEventTypes are the billing events after which an organization's
+// cached subscription status may be stale
+var StatusCacheEventTypes = []string{
+	events.SubscriptionChangedEventType,
+	events.SubscriptionReconciledEventType,
+	events.SubscriptionActivatedEventType,
+	events.SubscriptionPastDueEventType,
+	events.SubscriptionCanceledEventType,
+	events.SubscriptionPlanChangedEventType,
+	events.DunningReminderEventType,
+	events.PeriodRolledOverEventType,
+	events.QuotaExhaustedEventType,
+}
+
+// StatusCacheInvalidator drops the paywall's cached subscription status of an
+// organization when a billing event announces a change to it.
+type StatusCacheInvalidator struct {
+	cache *paywall.StatusCache
+}
+
+func NewStatusCacheInvalidator(cache *paywall.StatusCache) *StatusCacheInvalidator {
+	return &StatusCacheInvalidator{cache: cache}
+}
+
+// HandleEvent invalidates the cached status of the event's organization.
+// Events without an organization are ignored.
+func (i *StatusCacheInvalidator) HandleEvent(ctx context.Context, event eventbus.Event) error {
+	var organizationID int32
+	switch e := event.(type) {
+	case *events.SubscriptionChanged:
+		organizationID = e.OrganizationID
+	case *events.SubscriptionReconciled:
+		organizationID = e.OrganizationID
+	case *events.SubscriptionActivated:
+		organizationID = e.OrganizationID
+	case *events.SubscriptionPastDue:
+		organizationID = e.OrganizationID
+	case *events.SubscriptionCanceled:
+		organizationID = e.OrganizationID
+	case *events.SubscriptionPlanChanged:
+		organizationID = e.OrganizationID
+	case *events.DunningReminder:
+		organizationID = e.OrganizationID
+	case *events.PeriodRolledOver:
+		organizationID = e.OrganizationID
+	case *events.QuotaExhausted:
+		organizationID = e.OrganizationID
+	}
+	if organizationID == 0 {
+		return nil
+	}
+
+	return i.cache.Invalidate(ctx, organizationID)
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 
 	"github.com/moasq/go-b2b-starter/internal/modules/billing/app/services"
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
 	"github.com/moasq/go-b2b-starter/internal/modules/paywall"
 )
 
@@ -33,13 +34,20 @@ func (a *StatusProviderAdapter) GetSubscriptionStatus(ctx context.Context, organ
 
 	// Map BillingStatus to SubscriptionStatus
 	status := &paywall.SubscriptionStatus{
-		OrganizationID: billingStatus.OrganizationID,
-		IsActive:       billingStatus.HasActiveSubscription,
-		Reason:         billingStatus.Reason,
+		OrganizationID:     billingStatus.OrganizationID,
+		IsActive:           billingStatus.HasActiveSubscription,
+		Reason:             billingStatus.Reason,
+		DunningPhase:       billingStatus.DunningPhase,
+		DunningPhaseEndsAt: billingStatus.DunningPhaseEndsAt,
+		Plan:               billingStatus.Plan,
+		Quotas:             toQuotaUsage(billingStatus.Quotas),
 	}
 
-	// Determine status string from reason
-	if billingStatus.HasActiveSubscription {
+	// Determine status string from reason; a past due subscription stays
+	// past_due even while the dunning grace period keeps it active
+	if billingStatus.DunningPhase != "" {
+		status.Status = paywall.StatusPastDue
+	} else if billingStatus.HasActiveSubscription {
 		status.Status = paywall.StatusActive
 	} else if billingStatus.Reason == "no active subscription found" {
 		status.Status = paywall.StatusNone
@@ -65,13 +73,20 @@ func (a *StatusProviderAdapter) RefreshSubscriptionStatus(ctx context.Context, o
 
 	// Map BillingStatus to SubscriptionStatus
 	status := &paywall.SubscriptionStatus{
-		OrganizationID: billingStatus.OrganizationID,
-		IsActive:       billingStatus.HasActiveSubscription,
-		Reason:         billingStatus.Reason,
+		OrganizationID:     billingStatus.OrganizationID,
+		IsActive:           billingStatus.HasActiveSubscription,
+		Reason:             billingStatus.Reason,
+		DunningPhase:       billingStatus.DunningPhase,
+		DunningPhaseEndsAt: billingStatus.DunningPhaseEndsAt,
+		Plan:               billingStatus.Plan,
+		Quotas:             toQuotaUsage(billingStatus.Quotas),
 	}
 
-	// Determine status string from reason
-	if billingStatus.HasActiveSubscription {
+	// Determine status string from reason; a past due subscription stays
+	// past_due even while the dunning grace period keeps it active
+	if billingStatus.DunningPhase != "" {
+		status.Status = paywall.StatusPastDue
+	} else if billingStatus.HasActiveSubscription {
 		status.Status = paywall.StatusActive
 	} else if billingStatus.Reason == "no active subscription found" {
 		status.Status = paywall.StatusNone
@@ -83,6 +98,20 @@ func (a *StatusProviderAdapter) RefreshSubscriptionStatus(ctx context.Context, o
 	return status, nil
 }
 
+// toQuotaUsage maps the meter quotas of a billing status to quota usage.
+func toQuotaUsage(quotas []domain.MeterQuota) []paywall.QuotaUsage {
+	usage := make([]paywall.QuotaUsage, 0, len(quotas))
+	for _, quota := range quotas {
+		usage = append(usage, paywall.QuotaUsage{
+			Meter:     quota.MeterSlug,
+			Allowance: quota.Allowance,
+			Remaining: quota.Remaining(),
+			ResetsAt:  quota.PeriodEnd,
+		})
+	}
+	return usage
+}
+
 // parseStatusFromReason attempts to extract a subscription status from the reason string.
 func parseStatusFromReason(reason string) string {
 	// Check for common status patterns in reason
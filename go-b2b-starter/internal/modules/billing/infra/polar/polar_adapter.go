@@ -3,9 +3,9 @@ package polar
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"strconv"
 	"strings"
 	"time"
 
@@ -36,6 +36,9 @@ func (p *polarAdapter) GetSubscription(ctx context.Context, externalCustomerID s
 
 	resp, err := p.client.Get(ctx, endpoint)
 	if err != nil {
+		if errors.Is(err, polarpkg.ErrRateLimited) {
+			return nil, fmt.Errorf("%w: %w", domain.ErrProviderRateLimited, err)
+		}
 		return nil, fmt.Errorf("failed to call Polar API: %w", err)
 	}
 	defer resp.Body.Close()
@@ -87,19 +90,10 @@ func (p *polarAdapter) GetSubscription(ctx context.Context, externalCustomerID s
 		canceledAt = &t
 	}
 
-	// Parse quota limit from product metadata
-	invoiceCountMax := int32(0)
-	if val, ok := polarSub.Product.Metadata["invoice_count"]; ok {
-		if count, err := strconv.ParseInt(val, 10, 32); err == nil {
-			invoiceCountMax = int32(count)
-		}
-	}
-
 	// Log subscription sync
 	p.logger.Info("polar subscription sync completed", loggerdomain.Fields{
 		"customer_id":       externalCustomerID,
 		"subscription_id":   polarSub.ID,
-		"invoice_count_max": invoiceCountMax,
 		"status":            polarSub.Status,
 		"product_name":      polarSub.Product.Name,
 	})
@@ -115,7 +109,6 @@ func (p *polarAdapter) GetSubscription(ctx context.Context, externalCustomerID s
 		CurrentPeriodEnd:   currentPeriodEnd,
 		CanceledAt:         canceledAt,
 		Metadata: map[string]any{
-			"invoice_count_max":    invoiceCountMax,
 			"product_metadata":     polarSub.Product.Metadata,
 			"customer_metadata":    polarSub.Customer.Metadata,
 		},
@@ -325,9 +318,9 @@ func isRetryableError(err error) bool {
 }
 
 // IngestMeterEvent ingests a meter event to Polar for usage-based billing
-// This notifies Polar about invoice processing to consume meter credits
-// Meter: "Invoice Processing"
-func (p *polarAdapter) IngestMeterEvent(ctx context.Context, externalCustomerID string, meterSlug string, amount int32) error {
+// This notifies Polar about usage of a meter to consume its credits
+// The event name is the meter slug, e.g. "invoice.processed"
+func (p *polarAdapter) IngestMeterEvent(ctx context.Context, externalCustomerID string, meterSlug string, amount int64) error {
 	// Call Polar API to ingest meter event
 	// POST /v1/events/ingest endpoint for event ingestion
 	endpoint := "/v1/events/ingest"
@@ -375,6 +368,175 @@ func (p *polarAdapter) IngestMeterEvent(ctx context.Context, externalCustomerID
 	return nil
 }
 
+// CreateCheckoutSession creates a Polar checkout for a product
+// The external customer ID (Stytch org ID) is attached to the checkout, so the
+// customer Polar creates on payment maps back to the organization
+func (p *polarAdapter) CreateCheckoutSession(ctx context.Context, req *domain.CheckoutRequest) (*domain.CheckoutSession, error) {
+	body := map[string]any{
+		"products":             []string{req.ProductID},
+		"external_customer_id": req.ExternalCustomerID,
+		"success_url":          withCheckoutID(req.SuccessURL),
+		"metadata": map[string]any{
+			"external_customer_id": req.ExternalCustomerID,
+		},
+	}
+	if req.CancelURL != "" {
+		// Polar shows a back button to the return URL
+		body["return_url"] = req.CancelURL
+	}
+
+	resp, err := p.client.Post(ctx, "/v1/checkouts/", body)
+	if err != nil {
+		if errors.Is(err, polarpkg.ErrRateLimited) {
+			return nil, fmt.Errorf("%w: %w", domain.ErrProviderRateLimited, err)
+		}
+		if isClientError(err) {
+			return nil, fmt.Errorf("%w: %s: %w", domain.ErrProductNotFound, req.ProductID, err)
+		}
+		return nil, fmt.Errorf("failed to call Polar checkout API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		ID        string `json:"id"`
+		URL       string `json:"url"`
+		ExpiresAt string `json:"expires_at"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode checkout response: %w", err)
+	}
+
+	p.logger.Info("polar checkout session created", loggerdomain.Fields{
+		"session_id":           result.ID,
+		"product_id":           req.ProductID,
+		"external_customer_id": req.ExternalCustomerID,
+	})
+
+	session := &domain.CheckoutSession{
+		ID:  result.ID,
+		URL: result.URL,
+	}
+	if expiresAt, err := parseTime(result.ExpiresAt); err == nil {
+		session.ExpiresAt = &expiresAt
+	}
+
+	return session, nil
+}
+
+// CreateCustomerPortalSession creates a Polar customer session and returns its
+// customer portal URL
+func (p *polarAdapter) CreateCustomerPortalSession(ctx context.Context, externalCustomerID string, returnURL string) (*domain.CustomerPortalSession, error) {
+	body := map[string]any{
+		"external_customer_id": externalCustomerID,
+	}
+	if returnURL != "" {
+		body["return_url"] = returnURL
+	}
+
+	resp, err := p.client.Post(ctx, "/v1/customer-sessions/", body)
+	if err != nil {
+		if errors.Is(err, polarpkg.ErrRateLimited) {
+			return nil, fmt.Errorf("%w: %w", domain.ErrProviderRateLimited, err)
+		}
+		// Polar only knows customers that completed a checkout
+		if isClientError(err) {
+			return nil, fmt.Errorf("%w: %s: %w", domain.ErrCustomerNotFound, externalCustomerID, err)
+		}
+		return nil, fmt.Errorf("failed to call Polar customer sessions API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		CustomerPortalURL string `json:"customer_portal_url"`
+		ExpiresAt         string `json:"expires_at"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode customer session response: %w", err)
+	}
+
+	p.logger.Info("polar customer portal session created", loggerdomain.Fields{
+		"external_customer_id": externalCustomerID,
+	})
+
+	session := &domain.CustomerPortalSession{URL: result.CustomerPortalURL}
+	if expiresAt, err := parseTime(result.ExpiresAt); err == nil {
+		session.ExpiresAt = &expiresAt
+	}
+
+	return session, nil
+}
+
+// ListProducts lists the unarchived Polar products for the plan catalog.
+// Meter credit benefits are read into invoice_count like in webhooks.
+func (p *polarAdapter) ListProducts(ctx context.Context) ([]domain.CatalogProduct, error) {
+	var products []domain.CatalogProduct
+
+	for page := 1; ; page++ {
+		resp, err := p.client.Get(ctx, fmt.Sprintf("/v1/products/?is_archived=false&limit=100&page=%d", page))
+		if err != nil {
+			if errors.Is(err, polarpkg.ErrRateLimited) {
+				return nil, fmt.Errorf("%w: %w", domain.ErrProviderRateLimited, err)
+			}
+			return nil, fmt.Errorf("failed to call Polar products API: %w", err)
+		}
+
+		var result struct {
+			Items      []map[string]any `json:"items"`
+			Pagination struct {
+				MaxPage int `json:"max_page"`
+			} `json:"pagination"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode products response: %w", err)
+		}
+
+		for _, item := range result.Items {
+			id, _ := toString(item["id"])
+			if id == "" {
+				continue
+			}
+			name, _ := toString(item["name"])
+			metadata := stringMapFrom(item["metadata"])
+			if metadata == nil {
+				metadata = make(map[string]string)
+			}
+			if invoiceCount := extractInvoiceCountFromProduct(item); invoiceCount != "" && metadata["invoice_count"] == "" {
+				metadata["invoice_count"] = invoiceCount
+			}
+			products = append(products, domain.CatalogProduct{ID: id, Name: name, Metadata: metadata})
+		}
+
+		if page >= result.Pagination.MaxPage {
+			break
+		}
+	}
+
+	return products, nil
+}
+
+// withCheckoutID appends the checkout ID to a success URL as session_id, for
+// the "Verification on Redirect" call; Polar fills in the {CHECKOUT_ID} placeholder
+func withCheckoutID(successURL string) string {
+	const placeholder = "{CHECKOUT_ID}"
+	if successURL == "" || strings.Contains(successURL, placeholder) {
+		return successURL
+	}
+	separator := "?"
+	if strings.Contains(successURL, "?") {
+		separator = "&"
+	}
+	return successURL + separator + "session_id=" + placeholder
+}
+
+// isClientError reports whether Polar rejected a request as invalid
+// (404 unknown resource, 422 validation error)
+func isClientError(err error) bool {
+	errStr := err.Error()
+	return strings.Contains(errStr, "(HTTP 404)") || strings.Contains(errStr, "(HTTP 422)")
+}
+
 func parseTime(s string) (time.Time, error) {
 	// Parse ISO 8601 timestamp
 	return time.Parse(time.RFC3339, s)
@@ -0,0 +1,78 @@
+package polar
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+	polarpkg "github.com/moasq/go-b2b-starter/internal/platform/polar"
+)
+
+// ProviderName identifies Polar as the source of recorded webhooks
+const ProviderName = "polar"
+
+// Standard Webhooks headers sent by Polar
+const (
+	headerWebhookID        = "Webhook-Id"
+	headerWebhookTimestamp = "Webhook-Timestamp"
+	headerWebhookSignature = "Webhook-Signature"
+)
+
+// Ensure webhookVerifier implements domain.WebhookVerifier at compile time
+var _ domain.WebhookVerifier = (*webhookVerifier)(nil)
+
+type webhookVerifier struct {
+	secret    string
+	tolerance time.Duration
+	now       func() time.Time
+}
+
+// NewWebhookVerifier verifies Polar webhooks with the configured webhook secret
+func NewWebhookVerifier(config *polarpkg.Config) domain.WebhookVerifier {
+	return &webhookVerifier{
+		secret:    config.WebhookSecret,
+		tolerance: config.WebhookTolerance,
+		now:       time.Now,
+	}
+}
+
+func (v *webhookVerifier) Provider() string {
+	return ProviderName
+}
+
+func (v *webhookVerifier) Verify(headers http.Header, payload []byte) (string, error) {
+	webhookID := headers.Get(headerWebhookID)
+	timestamp := headers.Get(headerWebhookTimestamp)
+	signatures := headers.Get(headerWebhookSignature)
+
+	if webhookID == "" || timestamp == "" || signatures == "" {
+		return "", fmt.Errorf("%w: missing webhook headers", domain.ErrWebhookSignatureInvalid)
+	}
+
+	// Check freshness first so an old, validly signed request cannot be replayed
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid timestamp %q", domain.ErrWebhookTimestampStale, timestamp)
+	}
+	if v.tolerance > 0 {
+		age := v.now().Sub(time.Unix(seconds, 0))
+		if age > v.tolerance || age < -v.tolerance {
+			return "", fmt.Errorf("%w: timestamp is %s off", domain.ErrWebhookTimestampStale, age.Round(time.Second))
+		}
+	}
+
+	// The header may carry several space-separated signatures (e.g. during
+	// secret rotation); one valid signature is enough
+	lastErr := fmt.Errorf("no signature in header")
+	for _, signature := range strings.Fields(signatures) {
+		lastErr = polarpkg.VerifyWebhookSignature(v.secret, webhookID, timestamp, payload, signature)
+		if lastErr == nil {
+			return webhookID, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %v", domain.ErrWebhookSignatureInvalid, lastErr)
+}
@@ -45,3 +45,12 @@ func (a *organizationAdapter) GetOrganizationIDByStytchOrgID(ctx context.Context
 
 	return org.ID, nil
 }
+
+func (a *organizationAdapter) CountActiveMembers(ctx context.Context, organizationID int32) (int64, error) {
+	stats, err := a.orgStore.GetOrganizationStats(ctx, organizationID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get organization stats: %w", err)
+	}
+
+	return stats.ActiveAccountCount, nil
+}
@@ -28,7 +28,7 @@ func NewSubscriptionRepository(store sqlc.Store) domain.SubscriptionRepository {
 func (r *subscriptionRepository) GetSubscriptionByOrgID(ctx context.Context, organizationID int32) (*domain.Subscription, error) {
 	result, err := r.store.GetSubscriptionByOrgID(ctx, organizationID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, sqlc.ErrRecordNotFound) {
 			return nil, domain.ErrSubscriptionNotFound
 		}
 		return nil, fmt.Errorf("failed to get subscription: %w", err)
@@ -74,10 +74,57 @@ func (r *subscriptionRepository) DeleteSubscription(ctx context.Context, organiz
 	return nil
 }
 
+func (r *subscriptionRepository) ListSubscriptionsAfter(ctx context.Context, afterID int32, limit int32) ([]domain.Subscription, error) {
+	results, err := r.store.ListSubscriptionsAfterID(ctx, sqlc.ListSubscriptionsAfterIDParams{
+		AfterID:   afterID,
+		BatchSize: limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+
+	subscriptions := make([]domain.Subscription, 0, len(results))
+	for i := range results {
+		subscriptions = append(subscriptions, *r.mapToDomainSubscription(&results[i]))
+	}
+	return subscriptions, nil
+}
+
+func (r *subscriptionRepository) ListPastDueSubscriptionsAfter(ctx context.Context, afterID int32, limit int32) ([]domain.Subscription, error) {
+	results, err := r.store.ListPastDueSubscriptionsAfterID(ctx, sqlc.ListPastDueSubscriptionsAfterIDParams{
+		AfterID:   afterID,
+		BatchSize: limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list past due subscriptions: %w", err)
+	}
+
+	subscriptions := make([]domain.Subscription, 0, len(results))
+	for i := range results {
+		subscriptions = append(subscriptions, *r.mapToDomainSubscription(&results[i]))
+	}
+	return subscriptions, nil
+}
+
+func (r *subscriptionRepository) SetDunningPhase(ctx context.Context, organizationID int32, phase string) (*domain.Subscription, bool, error) {
+	result, err := r.store.SetSubscriptionDunningPhase(ctx, sqlc.SetSubscriptionDunningPhaseParams{
+		DunningPhase:   helpers.ToPgText(phase),
+		OrganizationID: organizationID,
+	})
+	if err != nil {
+		if errors.Is(err, sqlc.ErrRecordNotFound) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to set dunning phase: %w", err)
+	}
+
+	return r.mapToDomainSubscription(&result), true, nil
+}
+
 func (r *subscriptionRepository) GetQuotaByOrgID(ctx context.Context, organizationID int32) (*domain.QuotaTracking, error) {
 	result, err := r.store.GetQuotaByOrgID(ctx, organizationID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, sqlc.ErrRecordNotFound) {
 			return nil, domain.ErrQuotaNotFound
 		}
 		return nil, fmt.Errorf("failed to get quota: %w", err)
@@ -89,7 +136,6 @@ func (r *subscriptionRepository) GetQuotaByOrgID(ctx context.Context, organizati
 func (r *subscriptionRepository) UpsertQuota(ctx context.Context, quota *domain.QuotaTracking) (*domain.QuotaTracking, error) {
 	params := sqlc.UpsertQuotaParams{
 		OrganizationID: quota.OrganizationID,
-		InvoiceCount:   quota.InvoiceCount,
 		MaxSeats:       helpers.ToPgInt4(quota.MaxSeats),
 		PeriodStart:    toPgTimestamp(quota.PeriodStart),
 		PeriodEnd:      toPgTimestamp(quota.PeriodEnd),
@@ -103,10 +149,49 @@ func (r *subscriptionRepository) UpsertQuota(ctx context.Context, quota *domain.
 	return r.mapToDomainQuota(&result), nil
 }
 
-func (r *subscriptionRepository) DecrementInvoiceCount(ctx context.Context, organizationID int32) (*domain.QuotaTracking, error) {
-	result, err := r.store.DecrementInvoiceCount(ctx, organizationID)
+func (r *subscriptionRepository) ResetQuotaPeriod(ctx context.Context, organizationID int32, periodStart, periodEnd time.Time) (*domain.QuotaTracking, error) {
+	result, err := r.store.ResetQuotaForPeriod(ctx, sqlc.ResetQuotaForPeriodParams{
+		OrganizationID: organizationID,
+		PeriodStart:    toPgTimestamp(periodStart),
+		PeriodEnd:      toPgTimestamp(periodEnd),
+	})
+	if err != nil {
+		if errors.Is(err, sqlc.ErrRecordNotFound) {
+			return nil, domain.ErrQuotaNotFound
+		}
+		return nil, fmt.Errorf("failed to reset quota period: %w", err)
+	}
+
+	return r.mapToDomainQuota(&result), nil
+}
+
+func (r *subscriptionRepository) ListQuotasDueForRollover(ctx context.Context, now time.Time, afterOrganizationID int32, limit int32) ([]domain.QuotaTracking, error) {
+	results, err := r.store.ListQuotasDueForRollover(ctx, sqlc.ListQuotasDueForRolloverParams{
+		Now:                 toPgTimestamp(now),
+		AfterOrganizationID: afterOrganizationID,
+		BatchSize:           limit,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to decrement invoice count: %w", err)
+		return nil, fmt.Errorf("failed to list quotas due for rollover: %w", err)
+	}
+
+	quotas := make([]domain.QuotaTracking, 0, len(results))
+	for i := range results {
+		quotas = append(quotas, *r.mapToDomainQuota(&results[i]))
+	}
+	return quotas, nil
+}
+
+func (r *subscriptionRepository) SetSeatGrace(ctx context.Context, organizationID int32, endsAt *time.Time) (*domain.QuotaTracking, error) {
+	result, err := r.store.SetQuotaSeatGrace(ctx, sqlc.SetQuotaSeatGraceParams{
+		OrganizationID:  organizationID,
+		SeatGraceEndsAt: toPgTimestampPtr(endsAt),
+	})
+	if err != nil {
+		if errors.Is(err, sqlc.ErrRecordNotFound) {
+			return nil, domain.ErrQuotaNotFound
+		}
+		return nil, fmt.Errorf("failed to set seat grace: %w", err)
 	}
 
 	return r.mapToDomainQuota(&result), nil
@@ -124,6 +209,315 @@ func (r *subscriptionRepository) GetQuotaStatus(ctx context.Context, organizatio
 	return r.mapToDomainQuotaStatus(&result), nil
 }
 
+func (r *subscriptionRepository) GetMeterQuota(ctx context.Context, organizationID int32, meterSlug string) (*domain.MeterQuota, error) {
+	result, err := r.store.GetMeterQuota(ctx, sqlc.GetMeterQuotaParams{
+		OrganizationID: organizationID,
+		MeterSlug:      meterSlug,
+	})
+	if err != nil {
+		if errors.Is(err, sqlc.ErrRecordNotFound) {
+			return nil, domain.ErrMeterQuotaNotFound
+		}
+		return nil, fmt.Errorf("failed to get meter quota: %w", err)
+	}
+
+	return r.mapToDomainMeterQuota(&result), nil
+}
+
+func (r *subscriptionRepository) ListMeterQuotas(ctx context.Context, organizationID int32) ([]domain.MeterQuota, error) {
+	results, err := r.store.ListMeterQuotasByOrgID(ctx, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list meter quotas: %w", err)
+	}
+
+	quotas := make([]domain.MeterQuota, 0, len(results))
+	for i := range results {
+		quotas = append(quotas, *r.mapToDomainMeterQuota(&results[i]))
+	}
+	return quotas, nil
+}
+
+func (r *subscriptionRepository) UpsertMeterAllowance(ctx context.Context, organizationID int32, meterSlug string, allowance int64, periodStart, periodEnd time.Time) (*domain.MeterQuota, error) {
+	result, err := r.store.UpsertMeterQuotaAllowance(ctx, sqlc.UpsertMeterQuotaAllowanceParams{
+		OrganizationID: organizationID,
+		MeterSlug:      meterSlug,
+		Allowance:      allowance,
+		PeriodStart:    toPgTimestamp(periodStart),
+		PeriodEnd:      toPgTimestamp(periodEnd),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert meter allowance: %w", err)
+	}
+
+	return r.mapToDomainMeterQuota(&result), nil
+}
+
+func (r *subscriptionRepository) SetMeterRemaining(ctx context.Context, organizationID int32, meterSlug string, remaining int64, periodStart, periodEnd time.Time) (*domain.MeterQuota, error) {
+	result, err := r.store.SetMeterQuotaRemaining(ctx, sqlc.SetMeterQuotaRemainingParams{
+		OrganizationID: organizationID,
+		MeterSlug:      meterSlug,
+		Allowance:      remaining,
+		PeriodStart:    toPgTimestamp(periodStart),
+		PeriodEnd:      toPgTimestamp(periodEnd),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set meter remaining: %w", err)
+	}
+
+	return r.mapToDomainMeterQuota(&result), nil
+}
+
+func (r *subscriptionRepository) ClearMeterAllowancesExcept(ctx context.Context, organizationID int32, meterSlugs []string) error {
+	if meterSlugs == nil {
+		meterSlugs = []string{}
+	}
+	err := r.store.ClearMeterQuotaAllowancesExcept(ctx, sqlc.ClearMeterQuotaAllowancesExceptParams{
+		OrganizationID: organizationID,
+		MeterSlugs:     meterSlugs,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to clear meter allowances: %w", err)
+	}
+	return nil
+}
+
+func (r *subscriptionRepository) ResetMeterPeriods(ctx context.Context, organizationID int32, periodStart, periodEnd time.Time) ([]domain.MeterQuota, error) {
+	results, err := r.store.ResetMeterQuotasForPeriod(ctx, sqlc.ResetMeterQuotasForPeriodParams{
+		OrganizationID: organizationID,
+		PeriodStart:    toPgTimestamp(periodStart),
+		PeriodEnd:      toPgTimestamp(periodEnd),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to reset meter periods: %w", err)
+	}
+
+	quotas := make([]domain.MeterQuota, 0, len(results))
+	for i := range results {
+		quotas = append(quotas, *r.mapToDomainMeterQuota(&results[i]))
+	}
+	return quotas, nil
+}
+
+func (r *subscriptionRepository) ConsumeMeterQuota(ctx context.Context, usage *domain.UsageRecord) (*domain.MeterQuota, error) {
+	metadata := usage.Metadata
+	if metadata == nil {
+		metadata = map[string]any{}
+	}
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal usage metadata: %w", err)
+	}
+
+	accountID := pgtype.Int4{}
+	if usage.AccountID != 0 {
+		accountID = helpers.ToPgInt4(usage.AccountID)
+	}
+
+	result, err := r.store.ConsumeMeterQuota(ctx, sqlc.ConsumeMeterQuotaParams{
+		Amount:         usage.Amount,
+		OrganizationID: usage.OrganizationID,
+		MeterSlug:      usage.MeterSlug,
+		AccountID:      accountID,
+		Reference:      helpers.ToPgText(usage.Reference),
+		Metadata:       metadataJSON,
+	})
+	if err != nil {
+		if sqlc.ErrorCode(err) == sqlc.UniqueViolation {
+			return nil, domain.ErrUsageAlreadyRecorded
+		}
+		if errors.Is(err, sqlc.ErrRecordNotFound) {
+			// Nothing was consumed: tell a missing meter from an exhausted one
+			if _, getErr := r.GetMeterQuota(ctx, usage.OrganizationID, usage.MeterSlug); errors.Is(getErr, domain.ErrMeterQuotaNotFound) {
+				return nil, domain.ErrMeterQuotaNotFound
+			}
+			return nil, domain.ErrQuotaExceeded
+		}
+		return nil, fmt.Errorf("failed to consume meter quota: %w", err)
+	}
+
+	quota := &domain.MeterQuota{
+		ID:             result.ID,
+		OrganizationID: result.OrganizationID,
+		MeterSlug:      result.MeterSlug,
+		Allowance:      result.Allowance,
+		Consumed:       result.Consumed,
+		Reserved:       result.Reserved,
+		PeriodStart:    result.PeriodStart.Time,
+		PeriodEnd:      result.PeriodEnd.Time,
+		CreatedAt:      result.CreatedAt.Time,
+		UpdatedAt:      result.UpdatedAt.Time,
+	}
+	if result.LastSyncedAt.Valid {
+		quota.LastSyncedAt = &result.LastSyncedAt.Time
+	}
+	return quota, nil
+}
+
+func (r *subscriptionRepository) ReserveMeterQuota(ctx context.Context, usage *domain.UsageRecord, expiresAt time.Time) (*domain.QuotaReservation, error) {
+	accountID := pgtype.Int4{}
+	if usage.AccountID != 0 {
+		accountID = helpers.ToPgInt4(usage.AccountID)
+	}
+
+	result, err := r.store.ReserveMeterQuota(ctx, sqlc.ReserveMeterQuotaParams{
+		Amount:         usage.Amount,
+		OrganizationID: usage.OrganizationID,
+		MeterSlug:      usage.MeterSlug,
+		AccountID:      accountID,
+		Reference:      helpers.ToPgText(usage.Reference),
+		ExpiresAt:      toPgTimestamp(expiresAt),
+	})
+	if err != nil {
+		if errors.Is(err, sqlc.ErrRecordNotFound) {
+			// Nothing was reserved: tell a missing meter from an exhausted one
+			if _, getErr := r.GetMeterQuota(ctx, usage.OrganizationID, usage.MeterSlug); errors.Is(getErr, domain.ErrMeterQuotaNotFound) {
+				return nil, domain.ErrMeterQuotaNotFound
+			}
+			return nil, domain.ErrQuotaExceeded
+		}
+		return nil, fmt.Errorf("failed to reserve meter quota: %w", err)
+	}
+
+	quota := domain.MeterQuota{Allowance: result.Allowance, Consumed: result.Consumed, Reserved: result.Reserved}
+	return &domain.QuotaReservation{
+		ID:             result.ID,
+		OrganizationID: result.OrganizationID,
+		MeterSlug:      result.MeterSlug,
+		Amount:         result.Amount,
+		AccountID:      helpers.FromPgInt4(result.AccountID),
+		Reference:      helpers.FromPgText(result.Reference),
+		Metadata:       usage.Metadata,
+		Remaining:      quota.Remaining(),
+		PeriodEnd:      result.PeriodEnd.Time,
+		ExpiresAt:      result.ExpiresAt.Time,
+		CreatedAt:      result.CreatedAt.Time,
+	}, nil
+}
+
+func (r *subscriptionRepository) CommitQuotaReservation(ctx context.Context, reservation *domain.QuotaReservation) (*domain.MeterQuota, error) {
+	metadata := reservation.Metadata
+	if metadata == nil {
+		metadata = map[string]any{}
+	}
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal usage metadata: %w", err)
+	}
+
+	result, err := r.store.CommitQuotaReservation(ctx, sqlc.CommitQuotaReservationParams{
+		ID:        reservation.ID,
+		Reference: helpers.ToPgText(reservation.Reference),
+		Metadata:  metadataJSON,
+	})
+	if err != nil {
+		if sqlc.ErrorCode(err) == sqlc.UniqueViolation {
+			return nil, domain.ErrUsageAlreadyRecorded
+		}
+		if errors.Is(err, sqlc.ErrRecordNotFound) {
+			return nil, domain.ErrReservationNotFound
+		}
+		return nil, fmt.Errorf("failed to commit quota reservation: %w", err)
+	}
+
+	quota := &domain.MeterQuota{
+		ID:             result.ID,
+		OrganizationID: result.OrganizationID,
+		MeterSlug:      result.MeterSlug,
+		Allowance:      result.Allowance,
+		Consumed:       result.Consumed,
+		Reserved:       result.Reserved,
+		PeriodStart:    result.PeriodStart.Time,
+		PeriodEnd:      result.PeriodEnd.Time,
+		CreatedAt:      result.CreatedAt.Time,
+		UpdatedAt:      result.UpdatedAt.Time,
+	}
+	if result.LastSyncedAt.Valid {
+		quota.LastSyncedAt = &result.LastSyncedAt.Time
+	}
+	return quota, nil
+}
+
+func (r *subscriptionRepository) ReleaseQuotaReservation(ctx context.Context, reservationID int64) error {
+	released, err := r.store.ReleaseQuotaReservation(ctx, reservationID)
+	if err != nil {
+		return fmt.Errorf("failed to release quota reservation: %w", err)
+	}
+	if released == 0 {
+		return domain.ErrReservationNotFound
+	}
+	return nil
+}
+
+func (r *subscriptionRepository) ReleaseExpiredReservations(ctx context.Context, organizationID int32, meterSlug string, now time.Time) (int64, error) {
+	released, err := r.store.ReleaseExpiredQuotaReservations(ctx, sqlc.ReleaseExpiredQuotaReservationsParams{
+		OrganizationID: organizationID,
+		MeterSlug:      meterSlug,
+		Now:            toPgTimestamp(now),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to release expired quota reservations: %w", err)
+	}
+	return released, nil
+}
+
+func (r *subscriptionRepository) SummarizeUsage(ctx context.Context, query *domain.UsageQuery) ([]domain.UsageTotal, error) {
+	results, err := r.store.SummarizeUsageLedgerByDay(ctx, sqlc.SummarizeUsageLedgerByDayParams{
+		OrganizationID: query.OrganizationID,
+		FromTime:       toPgTimestamp(query.From),
+		ToTime:         toPgTimestamp(query.To),
+		MeterSlug:      helpers.ToPgText(query.MeterSlug),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize usage: %w", err)
+	}
+
+	totals := make([]domain.UsageTotal, 0, len(results))
+	for _, result := range results {
+		day := result.UsageDay.Time
+		totals = append(totals, domain.UsageTotal{
+			Day:          &day,
+			MeterSlug:    result.MeterSlug,
+			AccountID:    fromPgInt4Ptr(result.AccountID),
+			AccountEmail: helpers.FromPgText(result.AccountEmail),
+			Amount:       result.Amount,
+			Entries:      result.Entries,
+		})
+	}
+	return totals, nil
+}
+
+func (r *subscriptionRepository) ListUsageEntriesAfter(ctx context.Context, query *domain.UsageQuery, afterID int64, limit int32) ([]domain.UsageLedgerEntry, error) {
+	results, err := r.store.ListUsageLedgerEntriesAfterID(ctx, sqlc.ListUsageLedgerEntriesAfterIDParams{
+		OrganizationID: query.OrganizationID,
+		FromTime:       toPgTimestamp(query.From),
+		ToTime:         toPgTimestamp(query.To),
+		MeterSlug:      helpers.ToPgText(query.MeterSlug),
+		AfterID:        afterID,
+		BatchSize:      limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list usage ledger entries: %w", err)
+	}
+
+	entries := make([]domain.UsageLedgerEntry, 0, len(results))
+	for _, result := range results {
+		entries = append(entries, domain.UsageLedgerEntry{
+			ID:             result.ID,
+			OrganizationID: result.OrganizationID,
+			MeterSlug:      result.MeterSlug,
+			Amount:         result.Amount,
+			AccountID:      fromPgInt4Ptr(result.AccountID),
+			AccountEmail:   helpers.FromPgText(result.AccountEmail),
+			Reference:      helpers.FromPgText(result.Reference),
+			PeriodStart:    result.PeriodStart.Time,
+			PeriodEnd:      result.PeriodEnd.Time,
+			Metadata:       helpers.FromJSONB(result.Metadata),
+			CreatedAt:      result.CreatedAt.Time,
+		})
+	}
+	return entries, nil
+}
+
 // Mapping functions
 
 func (r *subscriptionRepository) mapToDomainSubscription(s *sqlc.SubscriptionBillingSubscription) *domain.Subscription {
@@ -155,6 +549,10 @@ func (r *subscriptionRepository) mapToDomainSubscription(s *sqlc.SubscriptionBil
 	if s.CanceledAt.Valid {
 		subscription.CanceledAt = &s.CanceledAt.Time
 	}
+	if s.PastDueSince.Valid {
+		subscription.PastDueSince = &s.PastDueSince.Time
+	}
+	subscription.DunningPhase = helpers.FromPgText(s.DunningPhase)
 
 	return subscription
 }
@@ -163,7 +561,6 @@ func (r *subscriptionRepository) mapToDomainQuota(q *sqlc.SubscriptionBillingQuo
 	quota := &domain.QuotaTracking{
 		ID:             q.ID,
 		OrganizationID: q.OrganizationID,
-		InvoiceCount:   q.InvoiceCount,
 		MaxSeats:       helpers.FromPgInt4(q.MaxSeats),
 		PeriodStart:    q.PeriodStart.Time,
 		PeriodEnd:      q.PeriodEnd.Time,
@@ -175,6 +572,31 @@ func (r *subscriptionRepository) mapToDomainQuota(q *sqlc.SubscriptionBillingQuo
 	if q.LastSyncedAt.Valid {
 		quota.LastSyncedAt = &q.LastSyncedAt.Time
 	}
+	if q.SeatGraceEndsAt.Valid {
+		quota.SeatGraceEndsAt = &q.SeatGraceEndsAt.Time
+	}
+
+	return quota
+}
+
+func (r *subscriptionRepository) mapToDomainMeterQuota(m *sqlc.SubscriptionBillingMeterQuota) *domain.MeterQuota {
+	quota := &domain.MeterQuota{
+		ID:             m.ID,
+		OrganizationID: m.OrganizationID,
+		MeterSlug:      m.MeterSlug,
+		Allowance:      m.Allowance,
+		Consumed:       m.Consumed,
+		Reserved:       m.Reserved,
+		PeriodStart:    m.PeriodStart.Time,
+		PeriodEnd:      m.PeriodEnd.Time,
+		CreatedAt:      m.CreatedAt.Time,
+		UpdatedAt:      m.UpdatedAt.Time,
+	}
+
+	// Handle nullable LastSyncedAt
+	if m.LastSyncedAt.Valid {
+		quota.LastSyncedAt = &m.LastSyncedAt.Time
+	}
 
 	return quota
 }
@@ -182,16 +604,18 @@ func (r *subscriptionRepository) mapToDomainQuota(q *sqlc.SubscriptionBillingQuo
 func (r *subscriptionRepository) mapToDomainQuotaStatus(qs *sqlc.GetQuotaStatusRow) *domain.QuotaStatus {
 	status := &domain.QuotaStatus{
 		SubscriptionStatus: qs.SubscriptionStatus,
+		ProductID:          qs.ProductID,
 		CurrentPeriodStart: qs.CurrentPeriodStart.Time,
 		CurrentPeriodEnd:   qs.CurrentPeriodEnd.Time,
-		InvoiceCount:       qs.InvoiceCount,
-		CanProcessInvoice:  qs.CanProcessInvoice,
 	}
 
 	// Handle nullable fields
 	if qs.CancelAtPeriodEnd.Valid {
 		status.CancelAtPeriodEnd = qs.CancelAtPeriodEnd.Bool
 	}
+	if qs.PastDueSince.Valid {
+		status.PastDueSince = &qs.PastDueSince.Time
+	}
 	if qs.MaxSeats.Valid {
 		status.MaxSeats = qs.MaxSeats.Int32
 	}
@@ -214,3 +638,10 @@ func toPgTimestampPtr(t *time.Time) pgtype.Timestamp {
 	}
 	return pgtype.Timestamp{Time: *t, Valid: true}
 }
+
+func fromPgInt4Ptr(i pgtype.Int4) *int32 {
+	if !i.Valid {
+		return nil
+	}
+	return &i.Int32
+}
@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/moasq/go-b2b-starter/internal/db/helpers"
+	sqlc "github.com/moasq/go-b2b-starter/internal/db/postgres/sqlc/gen"
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+)
+
+// webhookRepository implements domain.WebhookRepository using SQLC internally.
+type webhookRepository struct {
+	store sqlc.Store
+}
+
+// NewWebhookRepository creates a new WebhookRepository implementation.
+func NewWebhookRepository(store sqlc.Store) domain.WebhookRepository {
+	return &webhookRepository{store: store}
+}
+
+func (r *webhookRepository) Claim(ctx context.Context, provider, webhookID, eventType string, payload []byte) (*domain.ReceivedWebhook, bool, error) {
+	result, err := r.store.ClaimBillingWebhookEvent(ctx, sqlc.ClaimBillingWebhookEventParams{
+		Provider:  provider,
+		WebhookID: webhookID,
+		EventType: eventType,
+		Payload:   payload,
+	})
+	if err != nil {
+		// The conflict update was skipped: already processed or in progress
+		if errors.Is(err, sqlc.ErrRecordNotFound) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to claim webhook: %w", err)
+	}
+
+	return mapToDomainWebhook(&result), true, nil
+}
+
+func (r *webhookRepository) GetByID(ctx context.Context, id int64) (*domain.ReceivedWebhook, error) {
+	result, err := r.store.GetBillingWebhookEventByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sqlc.ErrRecordNotFound) {
+			return nil, domain.ErrWebhookNotFound
+		}
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return mapToDomainWebhook(&result), nil
+}
+
+func (r *webhookRepository) MarkProcessed(ctx context.Context, id int64) error {
+	if err := r.store.MarkBillingWebhookEventProcessed(ctx, id); err != nil {
+		return fmt.Errorf("failed to mark webhook processed: %w", err)
+	}
+	return nil
+}
+
+func (r *webhookRepository) MarkFailed(ctx context.Context, id int64, reason string) error {
+	err := r.store.MarkBillingWebhookEventFailed(ctx, sqlc.MarkBillingWebhookEventFailedParams{
+		ID:        id,
+		LastError: helpers.ToPgText(reason),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to mark webhook failed: %w", err)
+	}
+	return nil
+}
+
+func mapToDomainWebhook(w *sqlc.SubscriptionBillingWebhookEvent) *domain.ReceivedWebhook {
+	webhook := &domain.ReceivedWebhook{
+		ID:         w.ID,
+		Provider:   w.Provider,
+		WebhookID:  w.WebhookID,
+		EventType:  w.EventType,
+		Payload:    w.Payload,
+		Status:     w.Status,
+		Attempts:   w.Attempts,
+		ReceivedAt: w.ReceivedAt.Time,
+	}
+	if w.LastError.Valid {
+		webhook.LastError = w.LastError.String
+	}
+	if w.ProcessedAt.Valid {
+		processedAt := w.ProcessedAt.Time
+		webhook.ProcessedAt = &processedAt
+	}
+	return webhook
+}
@@ -0,0 +1,155 @@
+package stripe
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+)
+
+// Metadata keys the integration sets on Stripe objects. Customers and
+// subscriptions carry the external customer ID (the Stytch organization ID),
+// which is how Stripe objects are mapped back to organizations.
+const (
+	MetadataExternalCustomerID = "external_customer_id"
+	metadataOrganizationID     = "organization_id"
+)
+
+// expandable is a Stripe field that holds either an object ID or, when
+// expanded, the object itself
+type expandable[T any] struct {
+	ID     string
+	Object *T
+}
+
+func (e *expandable[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		return json.Unmarshal(data, &e.ID)
+	}
+
+	var ref struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(data, &ref); err != nil {
+		return err
+	}
+	var object T
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	e.ID = ref.ID
+	e.Object = &object
+	return nil
+}
+
+type stripeCustomer struct {
+	ID       string            `json:"id"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+type stripeProduct struct {
+	ID       string            `json:"id"`
+	Name     string            `json:"name"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+type stripePrice struct {
+	ID       string                    `json:"id"`
+	Nickname string                    `json:"nickname"`
+	Product  expandable[stripeProduct] `json:"product"`
+	Metadata map[string]string         `json:"metadata"`
+}
+
+type stripeSubscriptionItem struct {
+	ID    string      `json:"id"`
+	Price stripePrice `json:"price"`
+	// Newer API versions report the billing period per item
+	CurrentPeriodStart int64 `json:"current_period_start"`
+	CurrentPeriodEnd   int64 `json:"current_period_end"`
+}
+
+type stripeSubscription struct {
+	ID                 string                     `json:"id"`
+	Status             string                     `json:"status"`
+	Customer           expandable[stripeCustomer] `json:"customer"`
+	CurrentPeriodStart int64                      `json:"current_period_start"`
+	CurrentPeriodEnd   int64                      `json:"current_period_end"`
+	CancelAtPeriodEnd  bool                       `json:"cancel_at_period_end"`
+	CanceledAt         *int64                     `json:"canceled_at"`
+	Metadata           map[string]string          `json:"metadata"`
+	Items              struct {
+		Data []stripeSubscriptionItem `json:"data"`
+	} `json:"items"`
+}
+
+// toEventData maps a Stripe subscription into provider-agnostic event data.
+// Quota limits (meter allowances, max_seats) are read from the product metadata,
+// overridden by the price metadata, like Polar product metadata.
+func (s *stripeSubscription) toEventData() *domain.SubscriptionEventData {
+	data := &domain.SubscriptionEventData{
+		SubscriptionID:     s.ID,
+		Status:             s.Status,
+		CurrentPeriodStart: unixTime(s.CurrentPeriodStart),
+		CurrentPeriodEnd:   unixTime(s.CurrentPeriodEnd),
+		CancelAtPeriodEnd:  s.CancelAtPeriodEnd,
+		ProductMetadata:    map[string]string{},
+	}
+
+	if s.CanceledAt != nil && *s.CanceledAt > 0 {
+		canceledAt := unixTime(*s.CanceledAt)
+		data.CanceledAt = &canceledAt
+	}
+
+	if len(s.Items.Data) > 0 {
+		item := s.Items.Data[0]
+		if data.CurrentPeriodStart.IsZero() {
+			data.CurrentPeriodStart = unixTime(item.CurrentPeriodStart)
+		}
+		if data.CurrentPeriodEnd.IsZero() {
+			data.CurrentPeriodEnd = unixTime(item.CurrentPeriodEnd)
+		}
+
+		data.ProductID = item.Price.Product.ID
+		data.ProductName = item.Price.Nickname
+		if product := item.Price.Product.Object; product != nil {
+			data.ProductName = product.Name
+			for key, value := range product.Metadata {
+				data.ProductMetadata[key] = value
+			}
+		}
+		for key, value := range item.Price.Metadata {
+			data.ProductMetadata[key] = value
+		}
+	}
+
+	if customer := s.Customer.Object; customer != nil && len(customer.Metadata) > 0 {
+		data.CustomerMetadata = customer.Metadata
+	} else {
+		data.CustomerMetadata = s.Metadata
+	}
+
+	data.ExternalCustomerID = metadataExternalID(s.Metadata)
+	if data.ExternalCustomerID == "" {
+		data.ExternalCustomerID = metadataExternalID(data.CustomerMetadata)
+	}
+
+	return data
+}
+
+// metadataExternalID reads the external customer ID from Stripe metadata
+func metadataExternalID(metadata map[string]string) string {
+	if id := metadata[MetadataExternalCustomerID]; id != "" {
+		return id
+	}
+	return metadata[metadataOrganizationID]
+}
+
+func unixTime(seconds int64) time.Time {
+	if seconds <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0)
+}
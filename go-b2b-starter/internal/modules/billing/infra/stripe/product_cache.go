@@ -0,0 +1,93 @@
+package stripe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+	stripepkg "github.com/moasq/go-b2b-starter/internal/platform/stripe"
+)
+
+// productCacheTTL is how long a fetched product is reused. Product metadata
+// carries the plan's quotas, so edits in Stripe apply within this delay.
+const productCacheTTL = 5 * time.Minute
+
+// ProductCache fetches Stripe products by ID and keeps them for
+// productCacheTTL.
+//
+// Stripe expands at most four levels, so a subscription listing can expand
+// its prices but not their products; the products, whose metadata carries
+// the plan's quotas, are fetched through this cache instead.
+type ProductCache struct {
+	client *stripepkg.Client
+
+	mu       sync.Mutex
+	products map[string]cachedProduct
+}
+
+type cachedProduct struct {
+	product   stripeProduct
+	fetchedAt time.Time
+}
+
+func NewProductCache(client *stripepkg.Client) *ProductCache {
+	return &ProductCache{
+		client:   client,
+		products: make(map[string]cachedProduct),
+	}
+}
+
+// expandProducts fills in the product of every subscription item whose price
+// only refers to it by ID
+func (c *ProductCache) expandProducts(ctx context.Context, subscription *stripeSubscription) error {
+	for i := range subscription.Items.Data {
+		product := &subscription.Items.Data[i].Price.Product
+		if product.Object != nil || product.ID == "" {
+			continue
+		}
+
+		fetched, err := c.get(ctx, product.ID)
+		if err != nil {
+			return err
+		}
+		product.Object = fetched
+	}
+	return nil
+}
+
+// get returns a product, fetching it from Stripe when it is not cached or
+// the cached copy expired
+func (c *ProductCache) get(ctx context.Context, productID string) (*stripeProduct, error) {
+	c.mu.Lock()
+	cached, ok := c.products[productID]
+	c.mu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < productCacheTTL {
+		product := cached.product
+		return &product, nil
+	}
+
+	resp, err := c.client.Get(ctx, "/v1/products/"+url.PathEscape(productID), nil)
+	if err != nil {
+		var apiErr *stripepkg.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("%w: %s", domain.ErrProductNotFound, productID)
+		}
+		return nil, fmt.Errorf("failed to call Stripe products API: %w", rateLimitError(err))
+	}
+
+	var product stripeProduct
+	if err := stripepkg.DecodeJSON(resp, &product); err != nil {
+		return nil, fmt.Errorf("failed to decode product response: %w", err)
+	}
+
+	c.mu.Lock()
+	c.products[productID] = cachedProduct{product: product, fetchedAt: time.Now()}
+	c.mu.Unlock()
+
+	return &product, nil
+}
@@ -0,0 +1,550 @@
+package stripe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+	"github.com/moasq/go-b2b-starter/internal/platform/logger"
+	loggerdomain "github.com/moasq/go-b2b-starter/internal/platform/logger/domain"
+	stripepkg "github.com/moasq/go-b2b-starter/internal/platform/stripe"
+)
+
+// Ensure stripeAdapter implements domain.BillingProvider at compile time
+var _ domain.BillingProvider = (*stripeAdapter)(nil)
+
+type stripeAdapter struct {
+	client   *stripepkg.Client
+	products *ProductCache
+	logger   logger.Logger
+
+	// customers caches external customer ID → Stripe customer ID; the
+	// mapping never changes and customer search is rate limited
+	customers sync.Map
+}
+
+func NewStripeAdapter(client *stripepkg.Client, products *ProductCache, log logger.Logger) domain.BillingProvider {
+	return &stripeAdapter{
+		client:   client,
+		products: products,
+		logger:   log,
+	}
+}
+
+// GetSubscription retrieves the current subscription of a customer from Stripe
+func (a *stripeAdapter) GetSubscription(ctx context.Context, externalCustomerID string) (*domain.Subscription, error) {
+	customerID, err := a.findCustomerID(ctx, externalCustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("customer", customerID)
+	params.Set("status", "all")
+	params.Set("limit", "10")
+	params.Add("expand[]", "data.customer")
+	// Products are one level beyond what Stripe expands; see ProductCache
+	params.Add("expand[]", "data.items.data.price")
+
+	resp, err := a.client.Get(ctx, "/v1/subscriptions", params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Stripe subscriptions API: %w", rateLimitError(err))
+	}
+
+	var result struct {
+		Data []stripeSubscription `json:"data"`
+	}
+	if err := stripepkg.DecodeJSON(resp, &result); err != nil {
+		return nil, err
+	}
+
+	if len(result.Data) == 0 {
+		return nil, domain.ErrSubscriptionNotFound
+	}
+
+	// Subscriptions are listed newest first; prefer one that is still live
+	stripeSub := &result.Data[0]
+	for i := range result.Data {
+		if status := result.Data[i].Status; status != "canceled" && status != "incomplete_expired" {
+			stripeSub = &result.Data[i]
+			break
+		}
+	}
+
+	if err := a.products.expandProducts(ctx, stripeSub); err != nil {
+		return nil, err
+	}
+	eventData := stripeSub.toEventData()
+
+	a.logger.Info("stripe subscription sync completed", loggerdomain.Fields{
+		"customer_id":     externalCustomerID,
+		"subscription_id": stripeSub.ID,
+		"status":          stripeSub.Status,
+		"product_name":    eventData.ProductName,
+	})
+
+	// Create domain subscription (organizationID will be set by caller)
+	return &domain.Subscription{
+		ExternalCustomerID: externalCustomerID,
+		SubscriptionID:     stripeSub.ID,
+		SubscriptionStatus: stripeSub.Status,
+		ProductID:          eventData.ProductID,
+		ProductName:        eventData.ProductName,
+		CurrentPeriodStart: eventData.CurrentPeriodStart,
+		CurrentPeriodEnd:   eventData.CurrentPeriodEnd,
+		CancelAtPeriodEnd:  eventData.CancelAtPeriodEnd,
+		CanceledAt:         eventData.CanceledAt,
+		Metadata: map[string]any{
+			"product_metadata":  eventData.ProductMetadata,
+			"customer_metadata": eventData.CustomerMetadata,
+		},
+	}, nil
+}
+
+// GetCheckoutSession retrieves checkout session details from Stripe
+func (a *stripeAdapter) GetCheckoutSession(ctx context.Context, sessionID string) (*domain.CheckoutSessionResponse, error) {
+	params := url.Values{}
+	params.Add("expand[]", "line_items")
+
+	resp, err := a.client.Get(ctx, "/v1/checkout/sessions/"+url.PathEscape(sessionID), params)
+	if err != nil {
+		var apiErr *stripepkg.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("%w: %s", domain.ErrCheckoutSessionNotFound, sessionID)
+		}
+		return nil, fmt.Errorf("failed to call Stripe checkout API: %w", err)
+	}
+
+	var result struct {
+		ID                string            `json:"id"`
+		Status            string            `json:"status"`         // open, complete, expired
+		PaymentStatus     string            `json:"payment_status"` // paid, unpaid, no_payment_required
+		AmountTotal       int64             `json:"amount_total"`
+		ClientReferenceID string            `json:"client_reference_id"`
+		Metadata          map[string]string `json:"metadata"`
+		Subscription      expandable[struct {
+			ID string `json:"id"`
+		}] `json:"subscription"`
+		LineItems struct {
+			Data []struct {
+				Price stripePrice `json:"price"`
+			} `json:"data"`
+		} `json:"line_items"`
+		Created int64 `json:"created"`
+	}
+	if err := stripepkg.DecodeJSON(resp, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode checkout response: %w", err)
+	}
+
+	// Checkouts created by this service carry the external customer ID
+	// (Stytch org ID) as client reference
+	externalCustomerID := result.ClientReferenceID
+	if externalCustomerID == "" {
+		externalCustomerID = metadataExternalID(result.Metadata)
+	}
+
+	productID := ""
+	if len(result.LineItems.Data) > 0 {
+		productID = result.LineItems.Data[0].Price.Product.ID
+	}
+
+	a.logger.Info("stripe checkout session retrieved", loggerdomain.Fields{
+		"session_id":           result.ID,
+		"status":               result.Status,
+		"payment_status":       result.PaymentStatus,
+		"external_customer_id": externalCustomerID,
+		"subscription_id":      result.Subscription.ID,
+	})
+
+	return &domain.CheckoutSessionResponse{
+		ID:             result.ID,
+		Status:         checkoutStatus(result.Status, result.PaymentStatus),
+		CustomerID:     externalCustomerID,
+		SubscriptionID: result.Subscription.ID,
+		ProductID:      productID,
+		Amount:         result.AmountTotal,
+		CreatedAt:      unixTime(result.Created),
+	}, nil
+}
+
+// checkoutStatus maps a Stripe session status onto the provider-agnostic
+// values of CheckoutSessionResponse.Status
+func checkoutStatus(status, paymentStatus string) string {
+	switch status {
+	case "complete":
+		if paymentStatus == "unpaid" {
+			// Completed, but an asynchronous payment method is still settling
+			return "pending"
+		}
+		return "succeeded"
+	case "expired":
+		return "expired"
+	default:
+		return "pending"
+	}
+}
+
+// GetCheckoutSessionWithPolling retrieves checkout session with polling and retry logic
+// Polls every 2 seconds for up to 10 seconds, until the session succeeded,
+// expired, or a non-retryable error occurs
+func (a *stripeAdapter) GetCheckoutSessionWithPolling(ctx context.Context, sessionID string) (*domain.CheckoutSessionResponse, error) {
+	const (
+		pollInterval = 2 * time.Second
+		maxDuration  = 10 * time.Second
+	)
+
+	deadline := time.Now().Add(maxDuration)
+	lastStatus := "unknown"
+
+	for attempt := 1; ; attempt++ {
+		session, err := a.GetCheckoutSession(ctx, sessionID)
+		switch {
+		case err == nil:
+			lastStatus = session.Status
+			if session.Status == "succeeded" || session.Status == "expired" {
+				return session, nil
+			}
+		case !isRetryableError(err):
+			return nil, err
+		default:
+			a.logger.Debug("stripe checkout polling attempt failed, retrying", loggerdomain.Fields{
+				"session_id": sessionID,
+				"attempt":    attempt,
+				"error":      err.Error(),
+			})
+		}
+
+		if time.Now().Add(pollInterval).After(deadline) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+
+	a.logger.Warn("stripe checkout polling timeout", loggerdomain.Fields{
+		"session_id":  sessionID,
+		"last_status": lastStatus,
+	})
+	return nil, fmt.Errorf("checkout verification timed out after 10 seconds (last status: %s)", lastStatus)
+}
+
+// isRetryableError reports whether a Stripe call may succeed when retried:
+// network errors, rate limiting and server errors
+func isRetryableError(err error) bool {
+	if errors.Is(err, domain.ErrCheckoutSessionNotFound) {
+		return false
+	}
+
+	var apiErr *stripepkg.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+	}
+
+	return true
+}
+
+// IngestMeterEvent reports usage to a Stripe billing meter.
+// The meter's event name is the meter slug; Stripe aggregates the events
+// into the usage billed on the customer's metered price.
+func (a *stripeAdapter) IngestMeterEvent(ctx context.Context, externalCustomerID string, meterSlug string, amount int64) error {
+	customerID, err := a.findCustomerID(ctx, externalCustomerID)
+	if err != nil {
+		return err
+	}
+
+	params := url.Values{}
+	params.Set("event_name", meterSlug)
+	params.Set("payload[stripe_customer_id]", customerID)
+	params.Set("payload[value]", strconv.FormatInt(amount, 10))
+
+	resp, err := a.client.Post(ctx, "/v1/billing/meter_events", params)
+	if err != nil {
+		return fmt.Errorf("failed to call Stripe meter events API: %w", err)
+	}
+	resp.Body.Close()
+
+	a.logger.Info("meter event ingested successfully", loggerdomain.Fields{
+		"customer_id": externalCustomerID,
+		"meter_slug":  meterSlug,
+		"amount":      amount,
+	})
+
+	return nil
+}
+
+// CreateCheckoutSession creates a Stripe Checkout session for a subscription
+// to a product. The organization's Stripe customer is created first when it
+// does not exist, so it carries the external customer ID metadata that
+// webhooks are mapped by.
+func (a *stripeAdapter) CreateCheckoutSession(ctx context.Context, req *domain.CheckoutRequest) (*domain.CheckoutSession, error) {
+	priceID, err := a.resolvePriceID(ctx, req.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	customerID, err := a.findOrCreateCustomerID(ctx, req.ExternalCustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("mode", "subscription")
+	params.Set("customer", customerID)
+	params.Set("line_items[0][price]", priceID)
+	params.Set("line_items[0][quantity]", "1")
+	params.Set("client_reference_id", req.ExternalCustomerID)
+	params.Set("metadata["+MetadataExternalCustomerID+"]", req.ExternalCustomerID)
+	params.Set("subscription_data[metadata]["+MetadataExternalCustomerID+"]", req.ExternalCustomerID)
+	params.Set("success_url", withCheckoutSessionID(req.SuccessURL))
+	if req.CancelURL != "" {
+		params.Set("cancel_url", req.CancelURL)
+	}
+
+	resp, err := a.client.Post(ctx, "/v1/checkout/sessions", params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Stripe checkout API: %w", rateLimitError(err))
+	}
+
+	var result struct {
+		ID        string `json:"id"`
+		URL       string `json:"url"`
+		ExpiresAt int64  `json:"expires_at"`
+	}
+	if err := stripepkg.DecodeJSON(resp, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode checkout response: %w", err)
+	}
+
+	a.logger.Info("stripe checkout session created", loggerdomain.Fields{
+		"session_id":           result.ID,
+		"price_id":             priceID,
+		"external_customer_id": req.ExternalCustomerID,
+	})
+
+	session := &domain.CheckoutSession{
+		ID:  result.ID,
+		URL: result.URL,
+	}
+	if result.ExpiresAt > 0 {
+		expiresAt := unixTime(result.ExpiresAt)
+		session.ExpiresAt = &expiresAt
+	}
+
+	return session, nil
+}
+
+// CreateCustomerPortalSession creates a Stripe billing portal session
+func (a *stripeAdapter) CreateCustomerPortalSession(ctx context.Context, externalCustomerID string, returnURL string) (*domain.CustomerPortalSession, error) {
+	customerID, err := a.findCustomerID(ctx, externalCustomerID)
+	if errors.Is(err, domain.ErrSubscriptionNotFound) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrCustomerNotFound, externalCustomerID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("customer", customerID)
+	if returnURL != "" {
+		params.Set("return_url", returnURL)
+	}
+
+	resp, err := a.client.Post(ctx, "/v1/billing_portal/sessions", params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Stripe billing portal API: %w", rateLimitError(err))
+	}
+
+	var result struct {
+		URL string `json:"url"`
+	}
+	if err := stripepkg.DecodeJSON(resp, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode billing portal response: %w", err)
+	}
+
+	a.logger.Info("stripe billing portal session created", loggerdomain.Fields{
+		"external_customer_id": externalCustomerID,
+	})
+
+	return &domain.CustomerPortalSession{URL: result.URL}, nil
+}
+
+// ListProducts lists the active Stripe products for the plan catalog. The
+// metadata of a product's default price overrides the product metadata, like
+// in subscription webhooks.
+func (a *stripeAdapter) ListProducts(ctx context.Context) ([]domain.CatalogProduct, error) {
+	var products []domain.CatalogProduct
+
+	params := url.Values{}
+	params.Set("active", "true")
+	params.Set("limit", "100")
+	params.Add("expand[]", "data.default_price")
+
+	for {
+		resp, err := a.client.Get(ctx, "/v1/products", params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to call Stripe products API: %w", rateLimitError(err))
+		}
+
+		var result struct {
+			Data []struct {
+				stripeProduct
+				DefaultPrice expandable[stripePrice] `json:"default_price"`
+			} `json:"data"`
+			HasMore bool `json:"has_more"`
+		}
+		if err := stripepkg.DecodeJSON(resp, &result); err != nil {
+			return nil, fmt.Errorf("failed to decode products response: %w", err)
+		}
+
+		for _, product := range result.Data {
+			metadata := make(map[string]string, len(product.Metadata))
+			for key, value := range product.Metadata {
+				metadata[key] = value
+			}
+			if price := product.DefaultPrice.Object; price != nil {
+				for key, value := range price.Metadata {
+					metadata[key] = value
+				}
+			}
+			products = append(products, domain.CatalogProduct{ID: product.ID, Name: product.Name, Metadata: metadata})
+		}
+
+		if !result.HasMore || len(result.Data) == 0 {
+			break
+		}
+		params.Set("starting_after", result.Data[len(result.Data)-1].ID)
+	}
+
+	return products, nil
+}
+
+// resolvePriceID maps the product a checkout is for onto the price it is sold
+// at, the product's default price. Price IDs are refused, so a checkout cannot
+// pick a price the plans do not offer.
+func (a *stripeAdapter) resolvePriceID(ctx context.Context, productID string) (string, error) {
+	if !strings.HasPrefix(productID, "prod_") {
+		return "", fmt.Errorf("%w: %s is not a Stripe product ID", domain.ErrProductNotFound, productID)
+	}
+
+	resp, err := a.client.Get(ctx, "/v1/products/"+url.PathEscape(productID), nil)
+	if err != nil {
+		var apiErr *stripepkg.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return "", fmt.Errorf("%w: %s", domain.ErrProductNotFound, productID)
+		}
+		return "", fmt.Errorf("failed to call Stripe products API: %w", rateLimitError(err))
+	}
+
+	var product struct {
+		Active       bool                    `json:"active"`
+		DefaultPrice expandable[stripePrice] `json:"default_price"`
+	}
+	if err := stripepkg.DecodeJSON(resp, &product); err != nil {
+		return "", fmt.Errorf("failed to decode product response: %w", err)
+	}
+
+	if !product.Active || product.DefaultPrice.ID == "" {
+		return "", fmt.Errorf("%w: %s is inactive or has no default price", domain.ErrProductNotFound, productID)
+	}
+
+	return product.DefaultPrice.ID, nil
+}
+
+// findOrCreateCustomerID resolves the organization's Stripe customer, creating
+// it with the external customer ID metadata on its first checkout
+func (a *stripeAdapter) findOrCreateCustomerID(ctx context.Context, externalCustomerID string) (string, error) {
+	customerID, err := a.findCustomerID(ctx, externalCustomerID)
+	if !errors.Is(err, domain.ErrSubscriptionNotFound) {
+		return customerID, err
+	}
+
+	params := url.Values{}
+	params.Set("metadata["+MetadataExternalCustomerID+"]", externalCustomerID)
+
+	// Customer search lags behind creation, so concurrent first checkouts
+	// are deduplicated by the idempotency key instead
+	resp, err := a.client.PostIdempotent(ctx, "/v1/customers", params, "customer-"+externalCustomerID)
+	if err != nil {
+		return "", fmt.Errorf("failed to call Stripe customers API: %w", rateLimitError(err))
+	}
+
+	var customer stripeCustomer
+	if err := stripepkg.DecodeJSON(resp, &customer); err != nil {
+		return "", fmt.Errorf("failed to decode customer response: %w", err)
+	}
+
+	a.customers.Store(externalCustomerID, customer.ID)
+	a.logger.Info("stripe customer created", loggerdomain.Fields{
+		"customer_id":          customer.ID,
+		"external_customer_id": externalCustomerID,
+	})
+
+	return customer.ID, nil
+}
+
+// withCheckoutSessionID appends the checkout session ID to a success URL as
+// session_id, for the "Verification on Redirect" call; Stripe fills in the
+// {CHECKOUT_SESSION_ID} placeholder
+func withCheckoutSessionID(successURL string) string {
+	const placeholder = "{CHECKOUT_SESSION_ID}"
+	if successURL == "" || strings.Contains(successURL, placeholder) {
+		return successURL
+	}
+	separator := "?"
+	if strings.Contains(successURL, "?") {
+		separator = "&"
+	}
+	return successURL + separator + "session_id=" + placeholder
+}
+
+// findCustomerID resolves the Stripe customer created for an external
+// customer ID, by the external_customer_id metadata set on creation
+func (a *stripeAdapter) findCustomerID(ctx context.Context, externalCustomerID string) (string, error) {
+	if cached, ok := a.customers.Load(externalCustomerID); ok {
+		return cached.(string), nil
+	}
+
+	escaped := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(externalCustomerID)
+	params := url.Values{}
+	params.Set("query", fmt.Sprintf("metadata['%s']:'%s'", MetadataExternalCustomerID, escaped))
+	params.Set("limit", "1")
+
+	resp, err := a.client.Get(ctx, "/v1/customers/search", params)
+	if err != nil {
+		return "", fmt.Errorf("failed to call Stripe customer search API: %w", rateLimitError(err))
+	}
+
+	var result struct {
+		Data []stripeCustomer `json:"data"`
+	}
+	if err := stripepkg.DecodeJSON(resp, &result); err != nil {
+		return "", err
+	}
+
+	if len(result.Data) == 0 {
+		return "", fmt.Errorf("%w: no Stripe customer for %s", domain.ErrSubscriptionNotFound, externalCustomerID)
+	}
+
+	customerID := result.Data[0].ID
+	a.customers.Store(externalCustomerID, customerID)
+	return customerID, nil
+}
+
+// rateLimitError marks an API error caused by Stripe's rate limit as
+// domain.ErrProviderRateLimited, so callers can back off
+func rateLimitError(err error) error {
+	if errors.Is(err, stripepkg.ErrRateLimited) {
+		return fmt.Errorf("%w: %w", domain.ErrProviderRateLimited, err)
+	}
+	return err
+}
@@ -0,0 +1,165 @@
+package stripe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+	"github.com/moasq/go-b2b-starter/internal/platform/logger"
+)
+
+// Ensure webhookParser implements domain.WebhookParser at compile time
+var _ domain.WebhookParser = (*webhookParser)(nil)
+
+// webhookParser maps Stripe event payloads into provider-agnostic event data.
+//
+// The payload is the event's "data" member, whose "object" is the Stripe
+// object the event is about. Organizations are resolved through the
+// external_customer_id metadata that checkout sets on the subscription and
+// customer. Webhook objects are never expanded, so the products of
+// subscription prices are fetched to read the plan's quotas.
+type webhookParser struct {
+	products *ProductCache
+	logger   logger.Logger
+}
+
+func NewWebhookParser(products *ProductCache, log logger.Logger) domain.WebhookParser {
+	return &webhookParser{products: products, logger: log}
+}
+
+func (p *webhookParser) Parse(ctx context.Context, eventType string, payload map[string]any) (*domain.ParsedWebhookEvent, error) {
+	event := &domain.ParsedWebhookEvent{Type: eventType, Kind: domain.WebhookIgnored}
+
+	switch eventType {
+	case "customer.subscription.created",
+		"customer.subscription.updated",
+		"customer.subscription.paused",
+		"customer.subscription.resumed":
+		data, err := p.parseSubscription(ctx, payload)
+		if err != nil {
+			return nil, err
+		}
+		event.Kind = domain.WebhookSubscriptionUpserted
+		event.Subscription = data
+	case "customer.subscription.deleted":
+		data, err := p.parseSubscription(ctx, payload)
+		if err != nil {
+			return nil, err
+		}
+		event.Kind = domain.WebhookSubscriptionCanceled
+		event.Subscription = data
+	case "customer.updated":
+		data, err := p.parseCustomer(payload)
+		if err != nil {
+			return nil, err
+		}
+		event.Kind = domain.WebhookCustomerUpdated
+		event.Subscription = data
+	case "billing.credit_grant.created", "billing.credit_grant.updated":
+		data, err := p.parseCreditGrant(payload)
+		if err != nil {
+			return nil, err
+		}
+		event.Kind = domain.WebhookMeterGrant
+		event.MeterGrant = data
+	}
+
+	return event, nil
+}
+
+func (p *webhookParser) parseSubscription(ctx context.Context, payload map[string]any) (*domain.SubscriptionEventData, error) {
+	var subscription stripeSubscription
+	if err := decodeObject(payload, &subscription); err != nil {
+		return nil, err
+	}
+
+	// Without its product the quotas of the plan are unknown; failing lets
+	// Stripe redeliver the webhook instead of applying empty quotas
+	if err := p.products.expandProducts(ctx, &subscription); err != nil {
+		return nil, fmt.Errorf("failed to fetch product of subscription %s: %w", subscription.ID, err)
+	}
+
+	data := subscription.toEventData()
+	if data.ExternalCustomerID == "" {
+		p.logger.Warn("Stripe subscription missing external customer identifier", map[string]any{
+			"subscription_id": subscription.ID,
+			"customer_id":     subscription.Customer.ID,
+		})
+		return nil, fmt.Errorf("subscription %s has no %s metadata", subscription.ID, MetadataExternalCustomerID)
+	}
+
+	p.logger.Info("Parsed Stripe subscription webhook payload", map[string]any{
+		"subscription_id":       data.SubscriptionID,
+		"external_customer_id":  data.ExternalCustomerID,
+		"status":                data.Status,
+		"product_id":            data.ProductID,
+		"product_metadata_keys": len(data.ProductMetadata),
+	})
+
+	return data, nil
+}
+
+func (p *webhookParser) parseCustomer(payload map[string]any) (*domain.SubscriptionEventData, error) {
+	var customer stripeCustomer
+	if err := decodeObject(payload, &customer); err != nil {
+		return nil, err
+	}
+
+	externalID := metadataExternalID(customer.Metadata)
+	if externalID == "" {
+		return nil, fmt.Errorf("customer %s has no %s metadata", customer.ID, MetadataExternalCustomerID)
+	}
+
+	return &domain.SubscriptionEventData{
+		ExternalCustomerID: externalID,
+		CustomerMetadata:   customer.Metadata,
+	}, nil
+}
+
+// parseCreditGrant maps a billing credit grant onto a meter grant. Credit
+// grants are monetary, so the meter and the granted units are taken from the
+// grant metadata: meter_slug, units and external_customer_id.
+func (p *webhookParser) parseCreditGrant(payload map[string]any) (*domain.MeterGrantEventData, error) {
+	var grant struct {
+		ID       string            `json:"id"`
+		Metadata map[string]string `json:"metadata"`
+	}
+	if err := decodeObject(payload, &grant); err != nil {
+		return nil, err
+	}
+
+	data := &domain.MeterGrantEventData{
+		MeterSlug:          grant.Metadata["meter_slug"],
+		ExternalCustomerID: metadataExternalID(grant.Metadata),
+	}
+	if data.MeterSlug == "" || data.ExternalCustomerID == "" {
+		return nil, fmt.Errorf("credit grant %s missing meter_slug or %s metadata", grant.ID, MetadataExternalCustomerID)
+	}
+
+	units, err := strconv.ParseInt(grant.Metadata["units"], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("credit grant %s has invalid units metadata: %w", grant.ID, err)
+	}
+	data.AvailableCredits = int32(units)
+
+	return data, nil
+}
+
+// decodeObject decodes the event's data.object into a typed Stripe object
+func decodeObject(payload map[string]any, v any) error {
+	object, ok := payload["object"].(map[string]any)
+	if !ok {
+		return fmt.Errorf("webhook payload missing object")
+	}
+
+	raw, err := json.Marshal(object)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook object: %w", err)
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("failed to decode webhook object: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package stripe
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+	stripepkg "github.com/moasq/go-b2b-starter/internal/platform/stripe"
+)
+
+// ProviderName identifies Stripe as the source of recorded webhooks
+const ProviderName = "stripe"
+
+// Ensure webhookVerifier implements domain.WebhookVerifier at compile time
+var _ domain.WebhookVerifier = (*webhookVerifier)(nil)
+
+type webhookVerifier struct {
+	secret    string
+	tolerance time.Duration
+	now       func() time.Time
+}
+
+// NewWebhookVerifier verifies Stripe webhooks with the endpoint signing secret
+func NewWebhookVerifier(config *stripepkg.Config) domain.WebhookVerifier {
+	return &webhookVerifier{
+		secret:    config.WebhookSecret,
+		tolerance: config.WebhookTolerance,
+		now:       time.Now,
+	}
+}
+
+func (v *webhookVerifier) Provider() string {
+	return ProviderName
+}
+
+// Verify checks the Stripe-Signature header. Stripe has no webhook ID
+// header; the event ID (evt_...) identifies the webhook across retries.
+func (v *webhookVerifier) Verify(headers http.Header, payload []byte) (string, error) {
+	err := stripepkg.VerifyWebhookSignature(v.secret, headers.Get(stripepkg.SignatureHeader), payload, v.tolerance, v.now())
+	if err != nil {
+		if errors.Is(err, stripepkg.ErrTimestampOutsideTolerance) {
+			return "", fmt.Errorf("%w: %v", domain.ErrWebhookTimestampStale, err)
+		}
+		return "", fmt.Errorf("%w: %v", domain.ErrWebhookSignatureInvalid, err)
+	}
+
+	var event struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(payload, &event); err != nil || event.ID == "" {
+		return "", fmt.Errorf("%w: missing event id", domain.ErrInvalidWebhookPayload)
+	}
+
+	return event.ID, nil
+}
@@ -20,6 +20,36 @@ func (h *Handler) Routes(router *gin.RouterGroup, resolver serverDomain.Middlewa
 		subscriptions.GET("/status",
 			auth.RequirePermissionFunc("resource", "view"),
 			h.GetBillingStatus)
+
+		// Get seat usage - requires resource:view permission
+		subscriptions.GET("/seats",
+			auth.RequirePermissionFunc("resource", "view"),
+			h.GetSeatUsage)
+
+		// Get plan entitlements - requires resource:view permission
+		subscriptions.GET("/entitlements",
+			auth.RequirePermissionFunc("resource", "view"),
+			h.GetEntitlements)
+
+		// Get usage report - requires org:view permission
+		subscriptions.GET("/usage",
+			auth.RequirePermissionFunc("org", "view"),
+			h.GetUsageReport)
+
+		// Export usage ledger as CSV - requires org:manage permission
+		subscriptions.GET("/usage/export",
+			auth.RequirePermissionFunc("org", "manage"),
+			h.ExportUsage)
+
+		// Create a checkout session - requires org:manage permission
+		subscriptions.POST("/checkout",
+			auth.RequirePermissionFunc("org", "manage"),
+			h.CreateCheckoutSession)
+
+		// Create a customer portal session - requires org:manage permission
+		subscriptions.POST("/portal",
+			auth.RequirePermissionFunc("org", "manage"),
+			h.CreateCustomerPortalSession)
 	}
 
 	// Verify payment endpoint - auth only (session_id identifies org)
@@ -28,4 +58,8 @@ func (h *Handler) Routes(router *gin.RouterGroup, resolver serverDomain.Middlewa
 	router.POST("/subscriptions/verify-payment",
 		resolver.Get("auth"),
 		h.VerifyPayment)
+
+	// Billing provider webhooks (/webhooks/polar or /webhooks/stripe) - no auth,
+	// requests are authenticated by their signature
+	router.POST("/webhooks/"+h.webhookService.Provider(), h.HandleProviderWebhook)
 }
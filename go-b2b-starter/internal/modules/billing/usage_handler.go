@@ -0,0 +1,242 @@
+package billing
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/auth"
+	"github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+	"github.com/moasq/go-b2b-starter/pkg/httperr"
+)
+
+// usageCSVHeader is the header row of a usage export
+var usageCSVHeader = []string{
+	"id", "created_at", "meter_slug", "amount", "account_id", "account_email",
+	"reference", "period_start", "period_end", "metadata",
+}
+
+// GetUsageReport godoc
+// @Summary Get a usage report
+// @Description Sums the quota the organization consumed, from the usage ledger. Group the usage by day (UTC), meter and/or account with group_by, e.g. group_by=day,meter; without grouping all usage is one total. The range defaults to the current quota period and may span up to 366 days.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param from query string false "Start of the range, inclusive (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "End of the range, exclusive (RFC3339); a date (YYYY-MM-DD) includes that day"
+// @Param meter query string false "Only this meter, e.g. invoice.processed"
+// @Param group_by query string false "Comma-separated groupings: day, meter, account"
+// @Success 200 {object} domain.UsageReport "Usage totals"
+// @Failure 400 {object} httperr.HTTPError "Invalid range or grouping, or missing organization context"
+// @Failure 500 {object} httperr.HTTPError "Internal server error"
+// @Router /api/subscriptions/usage [get]
+func (h *Handler) GetUsageReport(c *gin.Context) {
+	reqCtx := auth.GetRequestContext(c)
+	if reqCtx == nil {
+		c.JSON(http.StatusBadRequest, httperr.NewHTTPError(
+			http.StatusBadRequest,
+			"missing_context",
+			"Organization context is required",
+		))
+		return
+	}
+
+	query, err := usageQueryFrom(c, reqCtx.OrganizationID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, httperr.NewHTTPError(
+			http.StatusBadRequest,
+			"invalid_usage_query",
+			err.Error(),
+		))
+		return
+	}
+
+	report, err := h.billingService.GetUsageReport(c.Request.Context(), query)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidUsageQuery) {
+			c.JSON(http.StatusBadRequest, httperr.NewHTTPError(
+				http.StatusBadRequest,
+				"invalid_usage_query",
+				err.Error(),
+			))
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, httperr.NewHTTPError(
+			http.StatusInternalServerError,
+			"usage_report_failed",
+			fmt.Sprintf("Failed to retrieve usage report: %v", err),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ExportUsage godoc
+// @Summary Export usage as CSV
+// @Description Downloads every usage ledger entry of the organization in the range as CSV, one row per quota consumption with the member, the source entity (reference) and the quota period it was counted against. Takes the same range and meter filters as GET /api/subscriptions/usage.
+// @Tags subscriptions
+// @Produce text/csv
+// @Param from query string false "Start of the range, inclusive (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "End of the range, exclusive (RFC3339); a date (YYYY-MM-DD) includes that day"
+// @Param meter query string false "Only this meter, e.g. invoice.processed"
+// @Success 200 {file} file "CSV with the columns id, created_at, meter_slug, amount, account_id, account_email, reference, period_start, period_end, metadata"
+// @Failure 400 {object} httperr.HTTPError "Invalid range, or missing organization context"
+// @Failure 500 {object} httperr.HTTPError "Internal server error"
+// @Router /api/subscriptions/usage/export [get]
+func (h *Handler) ExportUsage(c *gin.Context) {
+	reqCtx := auth.GetRequestContext(c)
+	if reqCtx == nil {
+		c.JSON(http.StatusBadRequest, httperr.NewHTTPError(
+			http.StatusBadRequest,
+			"missing_context",
+			"Organization context is required",
+		))
+		return
+	}
+
+	query, err := usageQueryFrom(c, reqCtx.OrganizationID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, httperr.NewHTTPError(
+			http.StatusBadRequest,
+			"invalid_usage_query",
+			err.Error(),
+		))
+		return
+	}
+	query.GroupBy = nil
+
+	// The response starts with the first page, so errors before it can still
+	// be answered with a status code
+	writer := csv.NewWriter(c.Writer)
+	started := false
+	start := func() {
+		started = true
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="usage-%s.csv"`, time.Now().UTC().Format("20060102-150405")))
+		c.Status(http.StatusOK)
+		_ = writer.Write(usageCSVHeader)
+	}
+
+	err = h.billingService.ExportUsage(c.Request.Context(), query, func(entries []domain.UsageLedgerEntry) error {
+		if !started {
+			start()
+		}
+		for _, entry := range entries {
+			if err := writer.Write(usageCSVRecord(entry)); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	})
+
+	switch {
+	case err == nil:
+		if !started {
+			start()
+		}
+		writer.Flush()
+	case started:
+		// Too late for an error status; the truncated file ends here
+		h.logger.Error("Usage export failed after it started", map[string]any{
+			"organization_id": reqCtx.OrganizationID,
+			"error":           err.Error(),
+		})
+		c.Abort()
+	case errors.Is(err, domain.ErrInvalidUsageQuery):
+		c.JSON(http.StatusBadRequest, httperr.NewHTTPError(
+			http.StatusBadRequest,
+			"invalid_usage_query",
+			err.Error(),
+		))
+	default:
+		c.JSON(http.StatusInternalServerError, httperr.NewHTTPError(
+			http.StatusInternalServerError,
+			"usage_export_failed",
+			fmt.Sprintf("Failed to export usage: %v", err),
+		))
+	}
+}
+
+// usageQueryFrom reads the range, meter and grouping query parameters of the
+// usage endpoints
+func usageQueryFrom(c *gin.Context, organizationID int32) (*domain.UsageQuery, error) {
+	query := &domain.UsageQuery{
+		OrganizationID: organizationID,
+		MeterSlug:      strings.TrimSpace(c.Query("meter")),
+	}
+
+	var err error
+	if query.From, _, err = parseUsageTime(c.Query("from")); err != nil {
+		return nil, fmt.Errorf("invalid from: %w", err)
+	}
+	var dateOnly bool
+	if query.To, dateOnly, err = parseUsageTime(c.Query("to")); err != nil {
+		return nil, fmt.Errorf("invalid to: %w", err)
+	}
+	if dateOnly {
+		// A date as the end of the range includes that day
+		query.To = query.To.AddDate(0, 0, 1)
+	}
+
+	for _, group := range strings.Split(c.Query("group_by"), ",") {
+		if group = strings.TrimSpace(group); group != "" {
+			query.GroupBy = append(query.GroupBy, group)
+		}
+	}
+
+	return query, nil
+}
+
+// parseUsageTime parses an RFC3339 time or a YYYY-MM-DD date (UTC); an empty
+// value is the zero time
+func parseUsageTime(value string) (t time.Time, dateOnly bool, err error) {
+	if value == "" {
+		return time.Time{}, false, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, false, nil
+	}
+	t, err = time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("expected RFC3339 or YYYY-MM-DD, got %q", value)
+	}
+	return t, true, nil
+}
+
+// usageCSVRecord formats a ledger entry as a row of the usage export
+func usageCSVRecord(entry domain.UsageLedgerEntry) []string {
+	accountID := ""
+	if entry.AccountID != nil {
+		accountID = strconv.FormatInt(int64(*entry.AccountID), 10)
+	}
+
+	metadata := ""
+	if len(entry.Metadata) > 0 {
+		if data, err := json.Marshal(entry.Metadata); err == nil {
+			metadata = string(data)
+		}
+	}
+
+	return []string{
+		strconv.FormatInt(entry.ID, 10),
+		entry.CreatedAt.UTC().Format(time.RFC3339),
+		entry.MeterSlug,
+		strconv.FormatInt(entry.Amount, 10),
+		accountID,
+		entry.AccountEmail,
+		entry.Reference,
+		entry.PeriodStart.UTC().Format(time.RFC3339),
+		entry.PeriodEnd.UTC().Format(time.RFC3339),
+		metadata,
+	}
+}
@@ -3,17 +3,23 @@ package services
 import (
 	"context"
 	"fmt"
+
+	docEvents "github.com/moasq/go-b2b-starter/internal/modules/documents/domain/events"
+	"github.com/moasq/go-b2b-starter/internal/platform/eventbus"
 )
 
 type documentListener struct {
 	embeddingService EmbeddingService
+	eventBus         eventbus.EventBus
 }
 
 func NewDocumentListener(
 	embeddingService EmbeddingService,
+	eventBus eventbus.EventBus,
 ) DocumentListener {
 	return &documentListener{
 		embeddingService: embeddingService,
+		eventBus:         eventBus,
 	}
 }
 
@@ -24,10 +30,15 @@ func (l *documentListener) HandleDocumentUploaded(ctx context.Context, documentI
 	}
 
 	// Create embedding for the document
-	_, err := l.embeddingService.EmbedDocument(ctx, orgID, documentID, text)
+	embedding, err := l.embeddingService.EmbedDocument(ctx, orgID, documentID, text)
 	if err != nil {
 		return fmt.Errorf("failed to embed document: %w", err)
 	}
 
+	// Announce that the document is ready for search and chat. The embedding
+	// is already stored, so a publish failure must not make the handler retry.
+	event := docEvents.NewDocumentProcessed(documentID, orgID, embedding.ID)
+	_ = l.eventBus.Publish(ctx, event)
+
 	return nil
 }
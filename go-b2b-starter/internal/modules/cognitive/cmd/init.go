@@ -20,20 +20,14 @@ func Init(container *dig.Container) error {
 
 	// Wire up event listener for document uploads
 	if err := container.Invoke(func(
-		bus eventbus.EventBus,
+		dlq *eventbus.DeadLetterQueue,
 		listener services.DocumentListener,
 	) error {
-		// Subscribe to DocumentUploaded events
-		return bus.Subscribe(docEvents.DocumentUploadedEventType, func(ctx context.Context, event eventbus.Event) error {
-			// Type assert to get the specific event
-			docEvent, ok := event.(*docEvents.DocumentUploaded)
-			if !ok {
-				return fmt.Errorf("unexpected event type: %T", event)
-			}
-
-			// Handle the event
-			return listener.HandleDocumentUploaded(ctx, docEvent.DocumentID, docEvent.OrganizationID, docEvent.ExtractedText)
-		})
+		// Subscribe to DocumentUploaded events (retried, then dead-lettered on failure)
+		return eventbus.SubscribeTypedWithRetry(dlq, "cognitive.embed_document",
+			func(ctx context.Context, event *docEvents.DocumentUploaded) error {
+				return listener.HandleDocumentUploaded(ctx, event.DocumentID, event.OrganizationID, event.ExtractedText)
+			})
 	}); err != nil {
 		return fmt.Errorf("failed to wire document event listener: %w", err)
 	}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+)
+
+// Config holds configuration for the cognitive module
+type Config struct {
+	// ChatPaywallEnabled requires the ai_chat plan feature for chat and charges
+	// every message against the llm.token meter. Every plan that should keep
+	// chat must grant both first (feature:ai_chat and llm_tokens product
+	// metadata, or a plans file entry).
+	ChatPaywallEnabled bool `mapstructure:"COGNITIVE_CHAT_PAYWALL_ENABLED"`
+}
+
+// LoadConfig reads configuration from file or environment variables.
+func LoadConfig() (Config, error) {
+	var cfg Config
+
+	viper.SetConfigName("app")
+	viper.SetConfigType("env")
+	viper.AddConfigPath(".")
+	viper.AutomaticEnv()
+
+	// Set default values
+	viper.SetDefault("COGNITIVE_CHAT_PAYWALL_ENABLED", false)
+
+	// Best-effort: ignore missing file, allow env-only usage
+	if err := viper.ReadInConfig(); err == nil {
+		_ = err
+	}
+
+	if err := viper.Unmarshal(&cfg); err != nil {
+		return cfg, fmt.Errorf("unable to decode cognitive config: %w", err)
+	}
+
+	return cfg, nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/moasq/go-b2b-starter/internal/modules/cognitive/app/services"
 	"github.com/moasq/go-b2b-starter/internal/modules/cognitive/domain"
 	"github.com/moasq/go-b2b-starter/internal/modules/auth"
+	"github.com/moasq/go-b2b-starter/internal/modules/paywall"
 	"github.com/moasq/go-b2b-starter/pkg/httperr"
 )
 
@@ -42,6 +43,7 @@ type ChatRequest struct {
 // @Param request body ChatRequest true "Chat request"
 // @Success 200 {object} domain.ChatResponse
 // @Failure 400 {object} httperr.HTTPError
+// @Failure 402 {object} paywall.ErrorResponse "Plan does not include AI chat, or the token quota is exhausted"
 // @Failure 500 {object} httperr.HTTPError
 // @Router /example_cognitive/chat [post]
 func (h *Handler) Chat(c *gin.Context) {
@@ -84,6 +86,11 @@ func (h *Handler) Chat(c *gin.Context) {
 		return
 	}
 
+	// Record the tokens reserved for this chat against the reply
+	if response.Message != nil {
+		paywall.SetUsageReference(c, fmt.Sprintf("chat_message:%d", response.Message.ID))
+	}
+
 	c.JSON(http.StatusOK, response)
 }
 
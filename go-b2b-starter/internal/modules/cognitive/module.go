@@ -4,8 +4,10 @@ import (
 	"go.uber.org/dig"
 
 	"github.com/moasq/go-b2b-starter/internal/modules/cognitive/app/services"
+	"github.com/moasq/go-b2b-starter/internal/modules/cognitive/config"
 	"github.com/moasq/go-b2b-starter/internal/modules/cognitive/domain"
 	"github.com/moasq/go-b2b-starter/internal/modules/cognitive/infra/ai"
+	"github.com/moasq/go-b2b-starter/internal/platform/eventbus"
 	llmdomain "github.com/moasq/go-b2b-starter/internal/platform/llm/domain"
 )
 
@@ -23,6 +25,10 @@ func NewModule(container *dig.Container) *Module {
 // RegisterDependencies registers all cognitive module dependencies
 // Note: Repository implementations are registered in internal/db/inject.go
 func (m *Module) RegisterDependencies() error {
+	if err := m.container.Provide(config.LoadConfig); err != nil {
+		return err
+	}
+
 	// Register AI adapters (infra layer)
 	if err := m.container.Provide(func(
 		llmClient llmdomain.LLMClient,
@@ -65,8 +71,9 @@ func (m *Module) RegisterDependencies() error {
 	// Register document listener
 	if err := m.container.Provide(func(
 		embeddingService services.EmbeddingService,
+		eventBus eventbus.EventBus,
 	) services.DocumentListener {
-		return services.NewDocumentListener(embeddingService)
+		return services.NewDocumentListener(embeddingService, eventBus)
 	}); err != nil {
 		return err
 	}
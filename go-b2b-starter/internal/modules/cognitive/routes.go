@@ -4,16 +4,31 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/moasq/go-b2b-starter/internal/modules/auth"
+	billingDomain "github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+	"github.com/moasq/go-b2b-starter/internal/modules/cognitive/config"
+	"github.com/moasq/go-b2b-starter/internal/modules/paywall"
 	serverDomain "github.com/moasq/go-b2b-starter/internal/platform/server/domain"
 )
 
+const (
+	// featureAIChat is the plan feature that unlocks the chat endpoint
+	featureAIChat = "ai_chat"
+
+	// chatTokenCost is the token budget reserved for one chat message
+	chatTokenCost = 2000
+)
+
 type Routes struct {
 	handler *Handler
+	paywall *paywall.Middleware
+	config  config.Config
 }
 
-func NewRoutes(handler *Handler) *Routes {
+func NewRoutes(handler *Handler, paywallMiddleware *paywall.Middleware, cfg config.Config) *Routes {
 	return &Routes{
 		handler: handler,
+		paywall: paywallMiddleware,
+		config:  cfg,
 	}
 }
 
@@ -22,13 +37,19 @@ func (r *Routes) RegisterRoutes(router *gin.RouterGroup, resolver serverDomain.M
 	cognitiveGroup.Use(
 		resolver.Get("auth"),
 		resolver.Get("org_context"),
-		resolver.Get("subscription"),
+		// Chat history stays readable when the subscription lapses
+		resolver.Get("paywall_read_only"),
 	)
 	{
-		// Chat endpoint
-		cognitiveGroup.POST("/chat",
-			auth.RequirePermissionFunc("resource", "create"),
-			r.handler.Chat)
+		// Chat endpoint; gated on the ai_chat feature and the llm.token meter
+		// when COGNITIVE_CHAT_PAYWALL_ENABLED is on
+		chat := []gin.HandlerFunc{auth.RequirePermissionFunc("resource", "create")}
+		if r.config.ChatPaywallEnabled {
+			chat = append(chat,
+				r.paywall.RequireEntitlement(featureAIChat),
+				r.paywall.RequireQuota(billingDomain.MeterLLMToken, chatTokenCost))
+		}
+		cognitiveGroup.POST("/chat", append(chat, r.handler.Chat)...)
 
 		// Chat sessions
 		sessionsGroup := cognitiveGroup.Group("/sessions")
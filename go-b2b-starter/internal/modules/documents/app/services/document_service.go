@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/moasq/go-b2b-starter/internal/db/core"
 	"github.com/moasq/go-b2b-starter/internal/modules/documents/domain"
 	"github.com/moasq/go-b2b-starter/internal/modules/documents/domain/events"
 	"github.com/moasq/go-b2b-starter/internal/platform/eventbus"
@@ -23,6 +24,7 @@ type documentService struct {
 	fileService filedomain.FileService
 	ocrService  ocrdomain.OCRService
 	eventBus    eventbus.EventBus
+	pool        core.Pool
 	logger      logger.Logger
 }
 
@@ -31,6 +33,7 @@ func NewDocumentService(
 	fileService filedomain.FileService,
 	ocrService ocrdomain.OCRService,
 	eventBus eventbus.EventBus,
+	pool core.Pool,
 	logger logger.Logger,
 ) DocumentService {
 	return &documentService{
@@ -38,6 +41,7 @@ func NewDocumentService(
 		fileService: fileService,
 		ocrService:  ocrService,
 		eventBus:    eventBus,
+		pool:        pool,
 		logger:      logger,
 	}
 }
@@ -232,17 +236,25 @@ func (s *documentService) ProcessDocument(ctx context.Context, orgID, docID int3
 		return nil, fmt.Errorf("%w: %v", domain.ErrTextExtractionFailed, err)
 	}
 
-	// Update document with extracted text
-	doc, err = s.docRepo.UpdateExtractedText(ctx, orgID, docID, extractedText)
+	// Store the extracted text and publish the event for the cognitive module
+	// in one transaction, so the outbox never misses or invents an upload.
+	// The other backends publish the event once the transaction commits.
+	// Embedding runs in the background so the upload does not wait for it
+	err = core.WithTransaction(ctx, s.pool, func(ctx context.Context, tx core.Transaction) error {
+		doc, err = s.docRepo.UpdateExtractedText(ctx, orgID, docID, extractedText)
+		if err != nil {
+			return fmt.Errorf("failed to update extracted text: %w", err)
+		}
+
+		event := events.NewDocumentUploaded(docID, orgID, doc.FileAssetID, doc.Title, extractedText)
+		if err := s.eventBus.PublishAsync(ctx, event); err != nil {
+			return fmt.Errorf("failed to publish document uploaded event: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
 		s.markDocumentFailed(ctx, orgID, docID, err.Error())
-		return nil, fmt.Errorf("failed to update extracted text: %w", err)
-	}
-
-	// Publish event for cognitive module to pick up
-	event := events.NewDocumentUploaded(docID, orgID, doc.FileAssetID, doc.Title, extractedText)
-	if err := s.eventBus.Publish(ctx, event); err != nil {
-		// Don't fail the operation just because event publishing failed
+		return nil, err
 	}
 
 	return doc, nil
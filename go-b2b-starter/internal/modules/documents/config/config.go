@@ -0,0 +1,40 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+)
+
+// Config holds configuration for the documents module
+type Config struct {
+	// UploadQuotaEnabled charges every upload against the document.processed
+	// meter. Every plan must grant the meter first (the document_count product
+	// metadata key or a plans file entry), as organizations without an
+	// allowance can no longer upload.
+	UploadQuotaEnabled bool `mapstructure:"DOCUMENTS_UPLOAD_QUOTA_ENABLED"`
+}
+
+// LoadConfig reads configuration from file or environment variables.
+func LoadConfig() (Config, error) {
+	var cfg Config
+
+	viper.SetConfigName("app")
+	viper.SetConfigType("env")
+	viper.AddConfigPath(".")
+	viper.AutomaticEnv()
+
+	// Set default values
+	viper.SetDefault("DOCUMENTS_UPLOAD_QUOTA_ENABLED", false)
+
+	// Best-effort: ignore missing file, allow env-only usage
+	if err := viper.ReadInConfig(); err == nil {
+		_ = err
+	}
+
+	if err := viper.Unmarshal(&cfg); err != nil {
+		return cfg, fmt.Errorf("unable to decode documents config: %w", err)
+	}
+
+	return cfg, nil
+}
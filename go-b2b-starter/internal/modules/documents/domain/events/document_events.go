@@ -1,6 +1,7 @@
 package events
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -30,6 +31,7 @@ func NewDocumentUploaded(documentID, organizationID, fileAssetID int32, title, e
 			Name:      DocumentUploadedEventType,
 			CreatedAt: time.Now(),
 			Meta:      make(map[string]interface{}),
+			Partition: documentPartition(documentID),
 		},
 		DocumentID:     documentID,
 		OrganizationID: organizationID,
@@ -54,6 +56,7 @@ func NewDocumentProcessed(documentID, organizationID, embeddingID int32) *Docume
 			Name:      DocumentProcessedEventType,
 			CreatedAt: time.Now(),
 			Meta:      make(map[string]interface{}),
+			Partition: documentPartition(documentID),
 		},
 		DocumentID:     documentID,
 		OrganizationID: organizationID,
@@ -76,9 +79,15 @@ func NewDocumentFailed(documentID, organizationID int32, err string) *DocumentFa
 			Name:      DocumentFailedEventType,
 			CreatedAt: time.Now(),
 			Meta:      make(map[string]interface{}),
+			Partition: documentPartition(documentID),
 		},
 		DocumentID:     documentID,
 		OrganizationID: organizationID,
 		Error:          err,
 	}
 }
+
+// documentPartition keeps the events of one document in order
+func documentPartition(documentID int32) string {
+	return fmt.Sprintf("document:%d", documentID)
+}
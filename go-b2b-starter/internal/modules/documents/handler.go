@@ -10,6 +10,7 @@ import (
 	"github.com/moasq/go-b2b-starter/internal/modules/auth"
 	"github.com/moasq/go-b2b-starter/internal/modules/documents/app/services"
 	_ "github.com/moasq/go-b2b-starter/internal/modules/documents/domain" // for swagger
+	"github.com/moasq/go-b2b-starter/internal/modules/paywall"
 	"github.com/moasq/go-b2b-starter/pkg/httperr"
 )
 
@@ -31,6 +32,7 @@ func NewHandler(service services.DocumentService) *Handler {
 // @Param title formData string true "Document title"
 // @Success 201 {object} domain.Document
 // @Failure 400 {object} httperr.HTTPError
+// @Failure 402 {object} paywall.ErrorResponse "Document quota exhausted"
 // @Failure 500 {object} httperr.HTTPError
 // @Router /example_documents/upload [post]
 func (h *Handler) UploadDocument(c *gin.Context) {
@@ -81,6 +83,9 @@ func (h *Handler) UploadDocument(c *gin.Context) {
 		return
 	}
 
+	// Record the quota reserved for this upload against the document
+	paywall.SetUsageReference(c, fmt.Sprintf("document:%d", document.ID))
+
 	c.JSON(http.StatusCreated, document)
 }
 
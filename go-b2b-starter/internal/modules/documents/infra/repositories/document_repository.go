@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/moasq/go-b2b-starter/internal/db/core"
 	"github.com/moasq/go-b2b-starter/internal/db/helpers"
+	"github.com/moasq/go-b2b-starter/internal/db/postgres"
 	sqlc "github.com/moasq/go-b2b-starter/internal/db/postgres/sqlc/gen"
 	"github.com/moasq/go-b2b-starter/internal/modules/documents/domain"
 )
@@ -20,6 +22,18 @@ func NewDocumentRepository(store sqlc.Store) domain.DocumentRepository {
 	return &documentRepository{store: store}
 }
 
+// queries returns the store joined to the transaction carried by ctx (see
+// core.WithTransaction), so document writes commit together with the events
+// written to the outbox in the same transaction.
+func (r *documentRepository) queries(ctx context.Context) sqlc.Store {
+	if tx, ok := core.TransactionFromContext(ctx); ok {
+		if pgxTx, ok := postgres.PgxTx(tx); ok {
+			return r.store.WithTx(pgxTx)
+		}
+	}
+	return r.store
+}
+
 func (r *documentRepository) Create(ctx context.Context, doc *domain.Document) (*domain.Document, error) {
 	params := sqlc.CreateDocumentParams{
 		OrganizationID: doc.OrganizationID,
@@ -33,7 +47,7 @@ func (r *documentRepository) Create(ctx context.Context, doc *domain.Document) (
 		Metadata:       helpers.ToJSONB(doc.Metadata),
 	}
 
-	result, err := r.store.CreateDocument(ctx, params)
+	result, err := r.queries(ctx).CreateDocument(ctx, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create document: %w", err)
 	}
@@ -47,7 +61,7 @@ func (r *documentRepository) GetByID(ctx context.Context, orgID, docID int32) (*
 		OrganizationID: orgID,
 	}
 
-	result, err := r.store.GetDocumentByID(ctx, params)
+	result, err := r.queries(ctx).GetDocumentByID(ctx, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get document: %w", err)
 	}
@@ -61,7 +75,7 @@ func (r *documentRepository) GetByFileAssetID(ctx context.Context, orgID, fileAs
 		OrganizationID: orgID,
 	}
 
-	result, err := r.store.GetDocumentByFileAssetID(ctx, params)
+	result, err := r.queries(ctx).GetDocumentByFileAssetID(ctx, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get document by file asset: %w", err)
 	}
@@ -76,7 +90,7 @@ func (r *documentRepository) List(ctx context.Context, orgID int32, limit, offse
 		Offset:         offset,
 	}
 
-	results, err := r.store.ListDocumentsByOrganization(ctx, params)
+	results, err := r.queries(ctx).ListDocumentsByOrganization(ctx, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list documents: %w", err)
 	}
@@ -97,7 +111,7 @@ func (r *documentRepository) ListByStatus(ctx context.Context, orgID int32, stat
 		Offset:         offset,
 	}
 
-	results, err := r.store.ListDocumentsByStatus(ctx, params)
+	results, err := r.queries(ctx).ListDocumentsByStatus(ctx, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list documents by status: %w", err)
 	}
@@ -117,7 +131,7 @@ func (r *documentRepository) UpdateStatus(ctx context.Context, orgID, docID int3
 		Status:         string(status),
 	}
 
-	result, err := r.store.UpdateDocumentStatus(ctx, params)
+	result, err := r.queries(ctx).UpdateDocumentStatus(ctx, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update document status: %w", err)
 	}
@@ -132,7 +146,7 @@ func (r *documentRepository) UpdateExtractedText(ctx context.Context, orgID, doc
 		ExtractedText:  helpers.ToPgText(text),
 	}
 
-	result, err := r.store.UpdateDocumentExtractedText(ctx, params)
+	result, err := r.queries(ctx).UpdateDocumentExtractedText(ctx, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update extracted text: %w", err)
 	}
@@ -148,7 +162,7 @@ func (r *documentRepository) Update(ctx context.Context, doc *domain.Document) (
 		Metadata:       helpers.ToJSONB(doc.Metadata),
 	}
 
-	result, err := r.store.UpdateDocument(ctx, params)
+	result, err := r.queries(ctx).UpdateDocument(ctx, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update document: %w", err)
 	}
@@ -162,7 +176,7 @@ func (r *documentRepository) Delete(ctx context.Context, orgID, docID int32) err
 		OrganizationID: orgID,
 	}
 
-	if err := r.store.DeleteDocument(ctx, params); err != nil {
+	if err := r.queries(ctx).DeleteDocument(ctx, params); err != nil {
 		return fmt.Errorf("failed to delete document: %w", err)
 	}
 
@@ -170,7 +184,7 @@ func (r *documentRepository) Delete(ctx context.Context, orgID, docID int32) err
 }
 
 func (r *documentRepository) Count(ctx context.Context, orgID int32) (int64, error) {
-	count, err := r.store.CountDocumentsByOrganization(ctx, orgID)
+	count, err := r.queries(ctx).CountDocumentsByOrganization(ctx, orgID)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count documents: %w", err)
 	}
@@ -184,7 +198,7 @@ func (r *documentRepository) CountByStatus(ctx context.Context, orgID int32, sta
 		Status:         string(status),
 	}
 
-	count, err := r.store.CountDocumentsByStatus(ctx, params)
+	count, err := r.queries(ctx).CountDocumentsByStatus(ctx, params)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count documents by status: %w", err)
 	}
@@ -3,8 +3,11 @@ package documents
 import (
 	"go.uber.org/dig"
 
+	"github.com/moasq/go-b2b-starter/internal/db/core"
 	"github.com/moasq/go-b2b-starter/internal/modules/documents/app/services"
+	"github.com/moasq/go-b2b-starter/internal/modules/documents/config"
 	"github.com/moasq/go-b2b-starter/internal/modules/documents/domain"
+	"github.com/moasq/go-b2b-starter/internal/modules/documents/domain/events"
 	"github.com/moasq/go-b2b-starter/internal/platform/eventbus"
 	filedomain "github.com/moasq/go-b2b-starter/internal/modules/files/domain"
 	"github.com/moasq/go-b2b-starter/internal/platform/logger"
@@ -25,15 +28,31 @@ func NewModule(container *dig.Container) *Module {
 // RegisterDependencies registers all documents module dependencies
 // Note: Repository implementations are registered in internal/db/inject.go
 func (m *Module) RegisterDependencies() error {
+	if err := m.container.Provide(config.LoadConfig); err != nil {
+		return err
+	}
+
+	// Register event types; the event bus rejects unregistered events
+	if err := eventbus.Register[*events.DocumentUploaded](events.DocumentUploadedEventType); err != nil {
+		return err
+	}
+	if err := eventbus.Register[*events.DocumentProcessed](events.DocumentProcessedEventType); err != nil {
+		return err
+	}
+	if err := eventbus.Register[*events.DocumentFailed](events.DocumentFailedEventType); err != nil {
+		return err
+	}
+
 	// Register document service
 	if err := m.container.Provide(func(
 		docRepo domain.DocumentRepository,
 		fileService filedomain.FileService,
 		ocrService ocrdomain.OCRService,
 		eventBus eventbus.EventBus,
+		pool core.Pool,
 		logger logger.Logger,
 	) services.DocumentService {
-		return services.NewDocumentService(docRepo, fileService, ocrService, eventBus, logger)
+		return services.NewDocumentService(docRepo, fileService, ocrService, eventBus, pool, logger)
 	}); err != nil {
 		return err
 	}
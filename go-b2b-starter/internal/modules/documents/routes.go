@@ -4,16 +4,23 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/moasq/go-b2b-starter/internal/modules/auth"
+	billingDomain "github.com/moasq/go-b2b-starter/internal/modules/billing/domain"
+	"github.com/moasq/go-b2b-starter/internal/modules/documents/config"
+	"github.com/moasq/go-b2b-starter/internal/modules/paywall"
 	serverDomain "github.com/moasq/go-b2b-starter/internal/platform/server/domain"
 )
 
 type Routes struct {
 	handler *Handler
+	paywall *paywall.Middleware
+	config  config.Config
 }
 
-func NewRoutes(handler *Handler) *Routes {
+func NewRoutes(handler *Handler, paywallMiddleware *paywall.Middleware, cfg config.Config) *Routes {
 	return &Routes{
 		handler: handler,
+		paywall: paywallMiddleware,
+		config:  cfg,
 	}
 }
 
@@ -22,13 +29,17 @@ func (r *Routes) RegisterRoutes(router *gin.RouterGroup, resolver serverDomain.M
 	docsGroup.Use(
 		resolver.Get("auth"),
 		resolver.Get("org_context"),
-		resolver.Get("subscription"),
+		// Documents stay readable when the subscription lapses
+		resolver.Get("paywall_read_only"),
 	)
 	{
-		// Upload document
-		docsGroup.POST("/upload",
-			auth.RequirePermissionFunc("resource", "create"),
-			r.handler.UploadDocument)
+		// Upload document; each upload is counted against the
+		// document.processed meter when DOCUMENTS_UPLOAD_QUOTA_ENABLED is on
+		upload := []gin.HandlerFunc{auth.RequirePermissionFunc("resource", "create")}
+		if r.config.UploadQuotaEnabled {
+			upload = append(upload, r.paywall.RequireQuota(billingDomain.MeterDocumentProcessed, 1))
+		}
+		docsGroup.POST("/upload", append(upload, r.handler.UploadDocument)...)
 
 		// List documents
 		docsGroup.GET("",
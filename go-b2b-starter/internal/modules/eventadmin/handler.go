@@ -0,0 +1,204 @@
+package eventadmin
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/moasq/go-b2b-starter/internal/platform/eventbus"
+	"github.com/moasq/go-b2b-starter/internal/platform/logger"
+	"github.com/moasq/go-b2b-starter/pkg/httperr"
+)
+
+// Handler exposes operator endpoints for the event bus
+type Handler struct {
+	dlq    *eventbus.DeadLetterQueue
+	logger logger.Logger
+}
+
+func NewHandler(dlq *eventbus.DeadLetterQueue, log logger.Logger) *Handler {
+	return &Handler{
+		dlq:    dlq,
+		logger: log,
+	}
+}
+
+// ListDeadLettersResponse is the response for listing dead letters
+type ListDeadLettersResponse struct {
+	DeadLetters []*eventbus.DeadLetter `json:"dead_letters"`
+	Limit       int                    `json:"limit"`
+	Offset      int                    `json:"offset"`
+}
+
+// ListDeadLetters godoc
+// @Summary List dead-lettered events
+// @Description Lists events that exhausted a handler retry policy, newest first
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Event bus admin token"
+// @Param event_name query string false "Filter by event name"
+// @Param handler_name query string false "Filter by handler name"
+// @Param limit query int false "Limit" default(50)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} ListDeadLettersResponse
+// @Failure 401 {object} httperr.HTTPError
+// @Failure 500 {object} httperr.HTTPError
+// @Router /api/admin/eventbus/dead-letters [get]
+func (h *Handler) ListDeadLetters(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	letters, err := h.dlq.List(c.Request.Context(), eventbus.DeadLetterFilter{
+		EventName:   c.Query("event_name"),
+		HandlerName: c.Query("handler_name"),
+		Limit:       limit,
+		Offset:      offset,
+	})
+	if err != nil {
+		h.respondError(c, err, "list_failed", "Failed to list dead letters")
+		return
+	}
+
+	c.JSON(http.StatusOK, ListDeadLettersResponse{
+		DeadLetters: letters,
+		Limit:       limit,
+		Offset:      offset,
+	})
+}
+
+// GetDeadLetter godoc
+// @Summary Inspect a dead-lettered event
+// @Description Returns the serialized event, handler name, attempts and last error
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Event bus admin token"
+// @Param id path int true "Dead letter ID"
+// @Success 200 {object} eventbus.DeadLetter
+// @Failure 400 {object} httperr.HTTPError
+// @Failure 404 {object} httperr.HTTPError
+// @Failure 500 {object} httperr.HTTPError
+// @Router /api/admin/eventbus/dead-letters/{id} [get]
+func (h *Handler) GetDeadLetter(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	letter, err := h.dlq.Get(c.Request.Context(), id)
+	if err != nil {
+		h.respondError(c, err, "get_failed", "Failed to get dead letter")
+		return
+	}
+
+	c.JSON(http.StatusOK, letter)
+}
+
+// ReplayDeadLetter godoc
+// @Summary Replay a dead-lettered event
+// @Description Runs the event through the handler that failed; removes the dead letter on success
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Event bus admin token"
+// @Param id path int true "Dead letter ID"
+// @Success 204
+// @Failure 400 {object} httperr.HTTPError
+// @Failure 404 {object} httperr.HTTPError
+// @Failure 409 {object} httperr.HTTPError "Handler not subscribed in this instance"
+// @Failure 422 {object} httperr.HTTPError "Handler failed again"
+// @Failure 500 {object} httperr.HTTPError
+// @Router /api/admin/eventbus/dead-letters/{id}/replay [post]
+func (h *Handler) ReplayDeadLetter(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.dlq.Replay(c.Request.Context(), id); err != nil {
+		switch {
+		case errors.Is(err, eventbus.ErrReplayFailed):
+			h.logger.Warn("Dead letter replay failed", map[string]any{
+				"dead_letter_id": id,
+				"error":          err.Error(),
+			})
+			c.JSON(http.StatusUnprocessableEntity, httperr.NewHTTPError(
+				http.StatusUnprocessableEntity,
+				"replay_failed",
+				"Handler failed again; the dead letter keeps the last error",
+			))
+		case errors.Is(err, eventbus.ErrHandlerNotSubscribed):
+			c.JSON(http.StatusConflict, httperr.NewHTTPError(
+				http.StatusConflict,
+				"handler_not_subscribed",
+				"The dead letter's handler is not subscribed to its event in this instance",
+			))
+		default:
+			// Not found, or the replay could not be run or recorded
+			h.respondError(c, err, "replay_failed", "Failed to replay dead letter")
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DiscardDeadLetter godoc
+// @Summary Discard a dead-lettered event
+// @Description Deletes the dead letter without processing it
+// @Tags admin
+// @Param X-Admin-Token header string true "Event bus admin token"
+// @Param id path int true "Dead letter ID"
+// @Success 204
+// @Failure 400 {object} httperr.HTTPError
+// @Failure 404 {object} httperr.HTTPError
+// @Failure 500 {object} httperr.HTTPError
+// @Router /api/admin/eventbus/dead-letters/{id} [delete]
+func (h *Handler) DiscardDeadLetter(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.dlq.Discard(c.Request.Context(), id); err != nil {
+		h.respondError(c, err, "discard_failed", "Failed to discard dead letter")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func parseID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, httperr.NewHTTPError(
+			http.StatusBadRequest,
+			"invalid_id",
+			"Dead letter ID must be a valid number",
+		))
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *Handler) respondError(c *gin.Context, err error, code, message string) {
+	if errors.Is(err, eventbus.ErrDeadLetterNotFound) {
+		c.JSON(http.StatusNotFound, httperr.NewHTTPError(
+			http.StatusNotFound,
+			"dead_letter_not_found",
+			"Dead letter not found",
+		))
+		return
+	}
+
+	// The error may carry SQL or handler details, so it is logged, not returned
+	h.logger.Error(message, map[string]any{
+		"path":  c.FullPath(),
+		"error": err.Error(),
+	})
+	c.JSON(http.StatusInternalServerError, httperr.NewHTTPError(
+		http.StatusInternalServerError,
+		code,
+		message,
+	))
+}
@@ -0,0 +1,73 @@
+package eventadmin
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/moasq/go-b2b-starter/internal/platform/eventbus"
+	serverDomain "github.com/moasq/go-b2b-starter/internal/platform/server/domain"
+	"github.com/moasq/go-b2b-starter/pkg/httperr"
+)
+
+// adminTokenHeader carries the operator token for event bus admin endpoints
+const adminTokenHeader = "X-Admin-Token"
+
+type Routes struct {
+	handler *Handler
+	config  eventbus.Config
+}
+
+func NewRoutes(handler *Handler, config eventbus.Config) *Routes {
+	return &Routes{
+		handler: handler,
+		config:  config,
+	}
+}
+
+// RegisterRoutes registers event bus admin routes.
+// Dead letters span all organizations, so these endpoints are guarded by an
+// operator token (EVENTBUS_ADMIN_TOKEN) rather than org-scoped RBAC.
+func (r *Routes) RegisterRoutes(router *gin.RouterGroup, resolver serverDomain.MiddlewareResolver) {
+	if r.config.AdminToken == "" {
+		return
+	}
+
+	admin := router.Group("/admin/eventbus")
+	admin.Use(requireAdminToken(r.config.AdminToken))
+	{
+		// GET /api/admin/eventbus/dead-letters
+		admin.GET("/dead-letters", r.handler.ListDeadLetters)
+
+		// GET /api/admin/eventbus/dead-letters/{id}
+		admin.GET("/dead-letters/:id", r.handler.GetDeadLetter)
+
+		// POST /api/admin/eventbus/dead-letters/{id}/replay
+		admin.POST("/dead-letters/:id/replay", r.handler.ReplayDeadLetter)
+
+		// DELETE /api/admin/eventbus/dead-letters/{id}
+		admin.DELETE("/dead-letters/:id", r.handler.DiscardDeadLetter)
+	}
+}
+
+// Routes returns a RouteRegistrar function compatible with the server interface
+func (r *Routes) Routes(router *gin.RouterGroup, resolver serverDomain.MiddlewareResolver) {
+	r.RegisterRoutes(router, resolver)
+}
+
+func requireAdminToken(token string) gin.HandlerFunc {
+	expected := []byte(token)
+	return func(c *gin.Context) {
+		provided := []byte(c.GetHeader(adminTokenHeader))
+		if subtle.ConstantTimeCompare(provided, expected) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, httperr.NewHTTPError(
+				http.StatusUnauthorized,
+				"invalid_admin_token",
+				"A valid admin token is required",
+			))
+			return
+		}
+		c.Next()
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"strings"
 
 	"github.com/moasq/go-b2b-starter/internal/modules/organizations/domain"
+	"github.com/moasq/go-b2b-starter/internal/modules/organizations/domain/events"
+	"github.com/moasq/go-b2b-starter/internal/platform/eventbus"
 	loggerDomain "github.com/moasq/go-b2b-starter/internal/platform/logger"
 )
 
@@ -41,6 +43,8 @@ type memberService struct {
 	authRoleRepo     domain.AuthRoleRepository
 	localOrgRepo     domain.OrganizationRepository
 	localAccountRepo domain.AccountRepository
+	seatLimiter      domain.SeatLimiter
+	eventBus         eventbus.EventBus
 	logger           loggerDomain.Logger
 }
 
@@ -50,6 +54,8 @@ func NewMemberService(
 	authRoleRepo domain.AuthRoleRepository,
 	localOrgRepo domain.OrganizationRepository,
 	localAccountRepo domain.AccountRepository,
+	seatLimiter domain.SeatLimiter,
+	eventBus eventbus.EventBus,
 	logger loggerDomain.Logger,
 ) MemberService {
 	return &memberService{
@@ -58,6 +64,8 @@ func NewMemberService(
 		authRoleRepo:     authRoleRepo,
 		localOrgRepo:     localOrgRepo,
 		localAccountRepo: localAccountRepo,
+		seatLimiter:      seatLimiter,
+		eventBus:         eventBus,
 		logger:           logger,
 	}
 }
@@ -167,7 +175,16 @@ func (s *memberService) BootstrapOrganizationWithOwner(
 		return nil, fmt.Errorf("failed to map auth organization: %w", err)
 	}
 
-	// Step 3: Create owner member (no invite).
+	// Step 3: Check the owner fits within the seat limit.
+	if err := s.seatLimiter.CheckSeatAvailable(ctx, localOrg.ID); err != nil {
+		s.logger.Warn("no seat available for organization owner", loggerDomain.Fields{
+			"local_org_id": localOrg.ID,
+			"error":        err.Error(),
+		})
+		return nil, err
+	}
+
+	// Step 4: Create owner member (no invite).
 	createMemberReq := &domain.CreateAuthMemberRequest{
 		OrganizationID: authOrg.OrganizationID,
 		Email:          req.OwnerEmail,
@@ -192,7 +209,7 @@ func (s *memberService) BootstrapOrganizationWithOwner(
 		})
 	})
 
-	// Step 4: Assign admin role in auth provider.
+	// Step 5: Assign admin role in auth provider.
 	if err := s.authMemberRepo.AssignRoles(ctx, &domain.AssignAuthRolesRequest{
 		OrganizationID: authOrg.OrganizationID,
 		MemberID:       member.MemberID,
@@ -206,7 +223,7 @@ func (s *memberService) BootstrapOrganizationWithOwner(
 		return nil, fmt.Errorf("failed to fetch admin role metadata: %w", err)
 	}
 
-	// Step 5: Create local account record.
+	// Step 6: Create local account record.
 	localAccount, err := s.localAccountRepo.Create(ctx, &domain.Account{
 		OrganizationID: localOrg.ID,
 		Email:          member.Email,
@@ -294,6 +311,16 @@ func (s *memberService) AddMemberDirect(
 		return nil, fmt.Errorf("failed to check existing account: %w", err)
 	}
 
+	// Every active account takes a seat of the organization's plan
+	if err := s.seatLimiter.CheckSeatAvailable(ctx, localOrgID); err != nil {
+		s.logger.Warn("no seat available for new member", loggerDomain.Fields{
+			"org_id": localOrgID,
+			"email":  req.Email,
+			"error":  err.Error(),
+		})
+		return nil, err
+	}
+
 	createReq := &domain.CreateAuthMemberRequest{
 		OrganizationID: orgID,
 		Email:          req.Email,
@@ -348,6 +375,15 @@ func (s *memberService) AddMemberDirect(
 		"invite_sent": true,
 	})
 
+	// The member exists at this point; a lost notification must not fail the request
+	event := events.NewMemberJoined(localOrgID, localAccount.ID, member.Email, member.Name, roleSlug)
+	if err := s.eventBus.Publish(ctx, event); err != nil {
+		s.logger.Warn("failed to publish member joined event", loggerDomain.Fields{
+			"org_id": localOrgID,
+			"error":  err.Error(),
+		})
+	}
+
 	return &AddMemberResponse{
 		MemberID:   member.MemberID,
 		Email:      member.Email,
@@ -522,6 +558,16 @@ func (s *memberService) DeleteOrganizationMember(
 		return fmt.Errorf("failed to remove member: %w", err)
 	}
 
+	// Deactivate the local account so the member no longer takes a seat.
+	// The member is already gone from the auth provider, so this only warns.
+	if err := s.deactivateLocalAccount(ctx, orgID, memberID); err != nil {
+		s.logger.Warn("failed to deactivate local account of removed member", loggerDomain.Fields{
+			"org_id":    orgID,
+			"member_id": memberID,
+			"error":     err.Error(),
+		})
+	}
+
 	s.logger.Info("member successfully deleted from organization", map[string]interface{}{
 		"org_id":    orgID,
 		"member_id": memberID,
@@ -568,6 +614,26 @@ func (s *memberService) resolveLocalOrganizationID(ctx context.Context, authOrgI
 	return org.ID, nil
 }
 
+// deactivateLocalAccount marks the local account mapped to an auth member inactive
+func (s *memberService) deactivateLocalAccount(ctx context.Context, authOrgID, memberID string) error {
+	localOrgID, err := s.resolveLocalOrganizationID(ctx, authOrgID)
+	if err != nil {
+		return err
+	}
+
+	accounts, err := s.localAccountRepo.ListByOrganization(ctx, localOrgID)
+	if err != nil {
+		return fmt.Errorf("failed to list accounts: %w", err)
+	}
+	for _, account := range accounts {
+		if account.StytchMemberID == memberID {
+			return s.localAccountRepo.Delete(ctx, localOrgID, account.ID)
+		}
+	}
+
+	return domain.ErrAccountNotFound
+}
+
 func mapRoleSlugToAccountRole(slug string) string {
 	switch strings.ToLower(strings.TrimSpace(slug)) {
 	case "owner":
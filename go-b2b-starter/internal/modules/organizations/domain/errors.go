@@ -1,6 +1,9 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // Organization errors
 var (
@@ -29,6 +32,11 @@ var (
 	ErrInvalidRole      = errors.New("invalid role")
 )
 
+// Seat errors
+var (
+	ErrSeatLimitReached = errors.New("seat limit reached")
+)
+
 // Auth provider member-related errors
 var (
 	ErrAuthMemberNotFound      = errors.New("auth member not found")
@@ -90,6 +98,23 @@ func NewOrganizationError(errorType, message string, orgID *int32, cause error)
 	}
 }
 
+// SeatLimitError is returned when adding a member would exceed the seat
+// limit of the organization's plan. It matches ErrSeatLimitReached.
+type SeatLimitError struct {
+	OrganizationID int32  `json:"organization_id"`
+	MaxSeats       int32  `json:"max_seats"`
+	UsedSeats      int64  `json:"used_seats"`
+	State          string `json:"state"`
+}
+
+func (e *SeatLimitError) Error() string {
+	return fmt.Sprintf("seat limit reached: %d of %d seats used", e.UsedSeats, e.MaxSeats)
+}
+
+func (e *SeatLimitError) Unwrap() error {
+	return ErrSeatLimitReached
+}
+
 // AccountError represents a domain-specific account error
 type AccountError struct {
 	Type           string `json:"type"`
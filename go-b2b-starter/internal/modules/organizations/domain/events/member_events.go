@@ -0,0 +1,45 @@
+package events
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moasq/go-b2b-starter/internal/platform/eventbus"
+)
+
+const (
+	MemberJoinedEventType = "member.joined"
+)
+
+// MemberJoined is published when a member has been added to an organization
+type MemberJoined struct {
+	eventbus.BaseEvent
+	OrganizationID int32  `json:"organization_id"`
+	AccountID      int32  `json:"account_id"`
+	Email          string `json:"email"`
+	Name           string `json:"name"`
+	Role           string `json:"role"`
+}
+
+func NewMemberJoined(organizationID, accountID int32, email, name, role string) *MemberJoined {
+	return &MemberJoined{
+		BaseEvent: eventbus.BaseEvent{
+			ID:        uuid.New().String(),
+			Name:      MemberJoinedEventType,
+			CreatedAt: time.Now(),
+			Meta:      make(map[string]interface{}),
+			Partition: organizationPartition(organizationID),
+		},
+		OrganizationID: organizationID,
+		AccountID:      accountID,
+		Email:          email,
+		Name:           name,
+		Role:           role,
+	}
+}
+
+// organizationPartition keeps the membership events of one organization in order
+func organizationPartition(organizationID int32) string {
+	return fmt.Sprintf("organization:%d", organizationID)
+}
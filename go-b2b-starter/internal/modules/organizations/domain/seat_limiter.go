@@ -0,0 +1,11 @@
+package domain
+
+import "context"
+
+// SeatLimiter enforces the seat limit of an organization's plan.
+// The billing module implements it; every active account takes a seat.
+type SeatLimiter interface {
+	// CheckSeatAvailable returns a *SeatLimitError when the organization
+	// cannot add another member
+	CheckSeatAvailable(ctx context.Context, organizationID int32) error
+}
@@ -1,12 +1,14 @@
 package organizations
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/moasq/go-b2b-starter/internal/modules/organizations/app/services"
+	"github.com/moasq/go-b2b-starter/internal/modules/organizations/domain"
 	"github.com/moasq/go-b2b-starter/pkg/response"
 	"github.com/moasq/go-b2b-starter/internal/modules/auth"
 	"github.com/moasq/go-b2b-starter/internal/platform/logger"
@@ -36,6 +38,7 @@ func NewMemberHandler(
 // @Param request body services.BootstrapOrganizationRequest true "Organization bootstrap request (passwordless - no password required)"
 // @Success 201 {object} services.BootstrapOrganizationResponse
 // @Failure 400 {object} map[string]any "Invalid request payload"
+// @Failure 402 {object} map[string]any "Seat limit reached"
 // @Failure 500 {object} map[string]any "Failed to bootstrap organization"
 // @Router /auth/signup [post]
 func (h *MemberHandler) BootstrapOrganization(c *gin.Context) {
@@ -55,6 +58,10 @@ func (h *MemberHandler) BootstrapOrganization(c *gin.Context) {
 			"org_name": req.OrgDisplayName,
 			"error":    err.Error(),
 		})
+		if errors.Is(err, domain.ErrSeatLimitReached) {
+			response.Error(c, http.StatusPaymentRequired, err.Error(), err)
+			return
+		}
 		response.Error(c, http.StatusInternalServerError, "failed to bootstrap organization", err)
 		return
 	}
@@ -80,6 +87,7 @@ func (h *MemberHandler) BootstrapOrganization(c *gin.Context) {
 // @Param role_slug body string false "Role slug (defaults to 'member')"
 // @Success 201 {object} services.AddMemberResponse
 // @Failure 400 {object} map[string]any "Invalid request payload or missing organization context"
+// @Failure 402 {object} map[string]any "Seat limit reached; upgrade the plan or remove a member"
 // @Failure 500 {object} map[string]any "Failed to add member"
 // @Router /auth/members [post]
 func (h *MemberHandler) AddMember(c *gin.Context) {
@@ -111,6 +119,10 @@ func (h *MemberHandler) AddMember(c *gin.Context) {
 			"email":  req.Email,
 			"error":  err.Error(),
 		})
+		if errors.Is(err, domain.ErrSeatLimitReached) {
+			response.Error(c, http.StatusPaymentRequired, err.Error(), err)
+			return
+		}
 		response.Error(c, http.StatusInternalServerError, "failed to add member", err)
 		return
 	}
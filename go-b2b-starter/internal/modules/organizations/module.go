@@ -5,7 +5,9 @@ import (
 
 	"github.com/moasq/go-b2b-starter/internal/modules/organizations/app/services"
 	"github.com/moasq/go-b2b-starter/internal/modules/organizations/domain"
+	"github.com/moasq/go-b2b-starter/internal/modules/organizations/domain/events"
 	"github.com/moasq/go-b2b-starter/internal/modules/organizations/infra/repositories"
+	"github.com/moasq/go-b2b-starter/internal/platform/eventbus"
 	loggerDomain "github.com/moasq/go-b2b-starter/internal/platform/logger/domain"
 	stytchcfg "github.com/moasq/go-b2b-starter/internal/platform/stytch"
 )
@@ -24,6 +26,11 @@ func NewModule(container *dig.Container) *Module {
 // RegisterDependencies registers all organization module dependencies
 // Note: Repository implementations are registered in internal/db/inject.go
 func (m *Module) RegisterDependencies() error {
+	// Register event types; the event bus rejects unregistered events
+	if err := eventbus.Register[*events.MemberJoined](events.MemberJoinedEventType); err != nil {
+		return err
+	}
+
 	// Register auth provider repositories (Stytch implementation)
 	if err := m.container.Provide(func(
 		client *stytchcfg.Client,
@@ -71,6 +78,8 @@ func (m *Module) RegisterDependencies() error {
 		authRoleRepo domain.AuthRoleRepository,
 		localOrgRepo domain.OrganizationRepository,
 		localAccountRepo domain.AccountRepository,
+		seatLimiter domain.SeatLimiter,
+		eventBus eventbus.EventBus,
 		logger loggerDomain.Logger,
 	) services.MemberService {
 		return services.NewMemberService(
@@ -79,6 +88,8 @@ func (m *Module) RegisterDependencies() error {
 			authRoleRepo,
 			localOrgRepo,
 			localAccountRepo,
+			seatLimiter,
+			eventBus,
 			logger,
 		)
 	}); err != nil {
@@ -0,0 +1,250 @@
+package paywall
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/moasq/go-b2b-starter/internal/platform/logger"
+	"github.com/moasq/go-b2b-starter/internal/platform/redis"
+)
+
+// Redis keys of the subscription status cache
+const (
+	statusCacheKeyPattern     = "paywall:status:%d"     // Status read from the provider
+	refreshCacheKeyPattern    = "paywall:refresh:%d"    // Result of the last lazy-guard refresh
+	generationCacheKeyPattern = "paywall:generation:%d" // Changed by every invalidation
+)
+
+// generationTTL is how long an invalidation generation is kept. It only has to
+// outlive the provider calls running during the invalidation; an expired
+// generation makes the calls still running skip their write.
+const generationTTL = time.Hour
+
+// StatusCacheConfig configures how long subscription statuses are cached.
+type StatusCacheConfig struct {
+	// ActiveTTL is how long a status that grants access is cached.
+	ActiveTTL time.Duration
+
+	// InactiveTTL is how long a status that refuses access is cached, and how
+	// long the result of a refresh is reused. It is kept short, as a customer
+	// who just paid should not wait for it.
+	InactiveTTL time.Duration
+}
+
+// DefaultStatusCacheConfig returns the default cache configuration.
+func DefaultStatusCacheConfig() StatusCacheConfig {
+	return StatusCacheConfig{
+		ActiveTTL:   30 * time.Second,
+		InactiveTTL: 5 * time.Second,
+	}
+}
+
+// StatusCache is a SubscriptionStatusProvider that caches the statuses of
+// another provider in Redis, so protected requests do not hit the database
+// every time.
+//
+// Concurrent lookups and lazy-guard refreshes of the same organization are
+// collapsed into one call to the wrapped provider, and a refresh result is
+// reused for InactiveTTL, so an inactive organization does not call the
+// billing provider on every request.
+//
+// Statuses are cached briefly; Invalidate drops an organization's cached
+// status as soon as its subscription changes. A status read from the provider
+// before the invalidation is neither written back nor shared with later
+// requests. Redis failures are not fatal: they are logged and the wrapped
+// provider is used directly.
+type StatusCache struct {
+	provider SubscriptionStatusProvider
+	redis    redis.Client
+	logger   logger.Logger
+	config   StatusCacheConfig
+	group    singleflight.Group
+}
+
+// NewStatusCache wraps provider with a Redis-backed cache.
+func NewStatusCache(provider SubscriptionStatusProvider, redisClient redis.Client, log logger.Logger, config StatusCacheConfig) *StatusCache {
+	return &StatusCache{
+		provider: provider,
+		redis:    redisClient,
+		logger:   log,
+		config:   config,
+	}
+}
+
+// GetSubscriptionStatus implements SubscriptionStatusProvider.
+func (c *StatusCache) GetSubscriptionStatus(ctx context.Context, organizationID int32) (*SubscriptionStatus, error) {
+	key := fmt.Sprintf(statusCacheKeyPattern, organizationID)
+	if status := c.get(ctx, key); status != nil {
+		return status, nil
+	}
+
+	generation := c.generation(ctx, organizationID)
+	return c.load(ctx, flightKey("status", organizationID, generation), func(ctx context.Context) (*SubscriptionStatus, error) {
+		status, err := c.provider.GetSubscriptionStatus(ctx, organizationID)
+		if err != nil {
+			return nil, err
+		}
+		c.storeUnlessInvalidated(ctx, organizationID, generation, func() {
+			c.set(ctx, key, status)
+		})
+		return status, nil
+	})
+}
+
+// RefreshSubscriptionStatus implements SubscriptionStatusProvider.
+//
+// A refresh reaching the billing provider is shared by every request of the
+// organization that asks for one within InactiveTTL. A status that turned
+// active replaces the cached status.
+func (c *StatusCache) RefreshSubscriptionStatus(ctx context.Context, organizationID int32) (*SubscriptionStatus, error) {
+	key := fmt.Sprintf(refreshCacheKeyPattern, organizationID)
+	if status := c.get(ctx, key); status != nil {
+		return status, nil
+	}
+
+	generation := c.generation(ctx, organizationID)
+	return c.load(ctx, flightKey("refresh", organizationID, generation), func(ctx context.Context) (*SubscriptionStatus, error) {
+		status, err := c.provider.RefreshSubscriptionStatus(ctx, organizationID)
+		if err != nil {
+			return nil, err
+		}
+		c.storeUnlessInvalidated(ctx, organizationID, generation, func() {
+			c.setWithTTL(ctx, key, status, c.config.InactiveTTL)
+			c.set(ctx, fmt.Sprintf(statusCacheKeyPattern, organizationID), status)
+		})
+		return status, nil
+	})
+}
+
+// Invalidate drops the cached status of an organization, so the next request
+// reads it from the wrapped provider. Provider calls already running when it
+// is called do not cache their result.
+func (c *StatusCache) Invalidate(ctx context.Context, organizationID int32) error {
+	// The generation changes first, so a call that misses it still finds its
+	// write deleted below
+	generation := strconv.FormatInt(time.Now().UnixNano(), 10)
+	if err := c.redis.Set(ctx, fmt.Sprintf(generationCacheKeyPattern, organizationID), generation, generationTTL); err != nil {
+		return fmt.Errorf("failed to record invalidation of org %d: %w", organizationID, err)
+	}
+	return c.drop(ctx, organizationID)
+}
+
+// drop deletes the cached status and refresh result of an organization
+func (c *StatusCache) drop(ctx context.Context, organizationID int32) error {
+	if err := c.redis.Delete(ctx, fmt.Sprintf(statusCacheKeyPattern, organizationID)); err != nil {
+		return fmt.Errorf("failed to invalidate cached subscription status of org %d: %w", organizationID, err)
+	}
+	if err := c.redis.Delete(ctx, fmt.Sprintf(refreshCacheKeyPattern, organizationID)); err != nil {
+		return fmt.Errorf("failed to invalidate cached subscription refresh of org %d: %w", organizationID, err)
+	}
+	return nil
+}
+
+// generation returns the organization's invalidation generation, empty when
+// it was not invalidated recently or Redis cannot be read
+func (c *StatusCache) generation(ctx context.Context, organizationID int32) string {
+	generation, err := c.redis.Get(ctx, fmt.Sprintf(generationCacheKeyPattern, organizationID))
+	if err != nil {
+		return ""
+	}
+	return generation
+}
+
+// storeUnlessInvalidated runs store when the organization was not invalidated
+// since generation was read. An invalidation racing with store is caught by
+// checking again afterwards and dropping what store wrote.
+func (c *StatusCache) storeUnlessInvalidated(ctx context.Context, organizationID int32, generation string, store func()) {
+	if c.generation(ctx, organizationID) != generation {
+		return
+	}
+	store()
+	if c.generation(ctx, organizationID) != generation {
+		if err := c.drop(ctx, organizationID); err != nil {
+			c.logger.Warn("Failed to drop subscription status cached during an invalidation", map[string]any{
+				"organization_id": organizationID,
+				"error":           err.Error(),
+			})
+		}
+	}
+}
+
+// flightKey names the shared provider call of an organization. It includes the
+// invalidation generation, so requests after an invalidation do not join a
+// call that started before it.
+func flightKey(kind string, organizationID int32, generation string) string {
+	return kind + ":" + strconv.Itoa(int(organizationID)) + ":" + generation
+}
+
+// load runs fetch once for all concurrent callers with the same key. The call
+// outlives a caller that gives up, since the others still wait for it.
+func (c *StatusCache) load(ctx context.Context, key string, fetch func(ctx context.Context) (*SubscriptionStatus, error)) (*SubscriptionStatus, error) {
+	shared := context.WithoutCancel(ctx)
+	result := c.group.DoChan(key, func() (any, error) {
+		return fetch(shared)
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-result:
+		if r.Err != nil {
+			return nil, r.Err
+		}
+		// Callers may modify the status, so each gets its own copy
+		status := *r.Val.(*SubscriptionStatus)
+		return &status, nil
+	}
+}
+
+// get returns the cached status under key, nil on a miss or a Redis failure.
+func (c *StatusCache) get(ctx context.Context, key string) *SubscriptionStatus {
+	cached, err := c.redis.Get(ctx, key)
+	if err != nil || cached == "" {
+		return nil
+	}
+
+	var status SubscriptionStatus
+	if err := json.Unmarshal([]byte(cached), &status); err != nil {
+		c.logger.Warn("Discarding unreadable cached subscription status", map[string]any{
+			"key":   key,
+			"error": err.Error(),
+		})
+		return nil
+	}
+	return &status
+}
+
+// set caches a status for the TTL matching whether it grants access.
+func (c *StatusCache) set(ctx context.Context, key string, status *SubscriptionStatus) {
+	ttl := c.config.InactiveTTL
+	if status.IsActive {
+		ttl = c.config.ActiveTTL
+	}
+	c.setWithTTL(ctx, key, status, ttl)
+}
+
+func (c *StatusCache) setWithTTL(ctx context.Context, key string, status *SubscriptionStatus, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	data, err := json.Marshal(status)
+	if err != nil {
+		c.logger.Warn("Failed to encode subscription status for caching", map[string]any{
+			"key":   key,
+			"error": err.Error(),
+		})
+		return
+	}
+	if err := c.redis.Set(ctx, key, string(data), ttl); err != nil {
+		c.logger.Warn("Failed to cache subscription status", map[string]any{
+			"key":   key,
+			"error": err.Error(),
+		})
+	}
+}
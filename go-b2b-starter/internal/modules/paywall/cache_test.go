@@ -0,0 +1,156 @@
+package paywall
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/platform/logger"
+)
+
+// TestStatusCacheInvalidateDuringFetch checks that a status read from the
+// provider before an invalidation is not cached, and that a request after the
+// invalidation does not get it either.
+func TestStatusCacheInvalidateDuringFetch(t *testing.T) {
+	ctx := context.Background()
+	provider := &blockingStatusProvider{started: make(chan string, 2), release: make(chan struct{})}
+	cache := NewStatusCache(provider, newMemoryRedis(), logger.New(logger.WithLevel(logger.ErrorLevel)), DefaultStatusCacheConfig())
+
+	provider.setStatus(StatusPastDue)
+	before := make(chan *SubscriptionStatus)
+	go func() {
+		status, _ := cache.GetSubscriptionStatus(ctx, 1)
+		before <- status
+	}()
+	<-provider.started
+
+	// The subscription is paid while the status is being read
+	provider.setStatus(StatusActive)
+	if err := cache.Invalidate(ctx, 1); err != nil {
+		t.Fatalf("Invalidate: %v", err)
+	}
+
+	after := make(chan *SubscriptionStatus)
+	go func() {
+		status, _ := cache.GetSubscriptionStatus(ctx, 1)
+		after <- status
+	}()
+	select {
+	case status := <-provider.started:
+		if status != StatusActive {
+			t.Fatalf("request after the invalidation read %q, want %s", status, StatusActive)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("request after the invalidation joined the provider call started before it")
+	}
+
+	close(provider.release)
+	<-before
+	if status := <-after; status == nil || status.Status != StatusActive {
+		t.Fatalf("request after the invalidation got %+v, want %s", status, StatusActive)
+	}
+
+	cached := cache.get(ctx, "paywall:status:1")
+	if cached == nil || cached.Status != StatusActive {
+		t.Fatalf("cached status = %+v, want %s", cached, StatusActive)
+	}
+}
+
+// TestStatusCacheStaleWriteBack checks that a provider call running during an
+// invalidation leaves nothing cached.
+func TestStatusCacheStaleWriteBack(t *testing.T) {
+	ctx := context.Background()
+	provider := &blockingStatusProvider{started: make(chan string, 1), release: make(chan struct{})}
+	cache := NewStatusCache(provider, newMemoryRedis(), logger.New(logger.WithLevel(logger.ErrorLevel)), DefaultStatusCacheConfig())
+
+	provider.setStatus(StatusPastDue)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = cache.GetSubscriptionStatus(ctx, 1)
+	}()
+	<-provider.started
+
+	if err := cache.Invalidate(ctx, 1); err != nil {
+		t.Fatalf("Invalidate: %v", err)
+	}
+	close(provider.release)
+	<-done
+
+	if cached := cache.get(ctx, "paywall:status:1"); cached != nil {
+		t.Fatalf("status read before the invalidation was cached: %+v", cached)
+	}
+}
+
+// blockingStatusProvider reports each call on started with the status it
+// returns, and returns once release is closed
+type blockingStatusProvider struct {
+	started chan string
+	release chan struct{}
+
+	mu     sync.Mutex
+	status string
+}
+
+func (p *blockingStatusProvider) setStatus(status string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.status = status
+}
+
+func (p *blockingStatusProvider) GetSubscriptionStatus(_ context.Context, organizationID int32) (*SubscriptionStatus, error) {
+	p.mu.Lock()
+	status := p.status
+	p.mu.Unlock()
+
+	p.started <- status
+	<-p.release
+	return &SubscriptionStatus{OrganizationID: organizationID, Status: status, IsActive: status == StatusActive}, nil
+}
+
+func (p *blockingStatusProvider) RefreshSubscriptionStatus(ctx context.Context, organizationID int32) (*SubscriptionStatus, error) {
+	return p.GetSubscriptionStatus(ctx, organizationID)
+}
+
+// memoryRedis is an in-memory redis.Client; entries do not expire
+type memoryRedis struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func newMemoryRedis() *memoryRedis {
+	return &memoryRedis{values: make(map[string]string)}
+}
+
+func (r *memoryRedis) Set(_ context.Context, key string, value interface{}, _ time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.values[key] = value.(string)
+	return nil
+}
+
+func (r *memoryRedis) Get(_ context.Context, key string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	value, ok := r.values[key]
+	if !ok {
+		return "", errors.New("redis: nil")
+	}
+	return value, nil
+}
+
+func (r *memoryRedis) Delete(_ context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.values, key)
+	return nil
+}
+
+func (r *memoryRedis) Exists(_ context.Context, key string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.values[key]
+	return ok, nil
+}
@@ -13,6 +13,15 @@ type contextKey string
 const (
 	// subscriptionStatusKey is the context key for storing the SubscriptionStatus.
 	subscriptionStatusKey contextKey = "subscription_status"
+
+	// entitlementsKey is the context key for storing the Entitlements.
+	entitlementsKey contextKey = "entitlements"
+
+	// quotaReservationsKey is the context key for storing the QuotaReservations by meter.
+	quotaReservationsKey contextKey = "quota_reservations"
+
+	// usageReferenceKey is the context key for storing the usage reference.
+	usageReferenceKey contextKey = "usage_reference"
 )
 
 // SetSubscriptionStatus stores the SubscriptionStatus in the Gin context.
@@ -91,3 +100,76 @@ func SubscriptionStatusFromContext(ctx context.Context) *SubscriptionStatus {
 	}
 	return nil
 }
+
+// SetEntitlements stores the Entitlements in the Gin context.
+//
+// This is called by the RequireEntitlement middleware, so several
+// RequireEntitlement checks on one route resolve the plan once.
+func SetEntitlements(c *gin.Context, entitlements *Entitlements) {
+	c.Set(string(entitlementsKey), entitlements)
+}
+
+// GetEntitlements retrieves the Entitlements from the Gin context.
+//
+// Returns nil if no entitlements are set (RequireEntitlement not applied).
+func GetEntitlements(c *gin.Context) *Entitlements {
+	if val, exists := c.Get(string(entitlementsKey)); exists {
+		if entitlements, ok := val.(*Entitlements); ok {
+			return entitlements
+		}
+	}
+	return nil
+}
+
+// setQuotaReservation stores a QuotaReservation in the Gin context, by meter.
+func setQuotaReservation(c *gin.Context, reservation *QuotaReservation) {
+	reservations := getQuotaReservations(c)
+	if reservations == nil {
+		reservations = map[string]*QuotaReservation{}
+		c.Set(string(quotaReservationsKey), reservations)
+	}
+	reservations[reservation.Meter] = reservation
+}
+
+// GetQuotaReservation retrieves the reservation RequireQuota made for a meter.
+//
+// Returns nil if RequireQuota was not applied for the meter.
+//
+// Example:
+//
+//	if reservation := paywall.GetQuotaReservation(c, "document.processed"); reservation != nil {
+//	    remaining := reservation.Remaining
+//	}
+func GetQuotaReservation(c *gin.Context, meter string) *QuotaReservation {
+	return getQuotaReservations(c)[meter]
+}
+
+func getQuotaReservations(c *gin.Context) map[string]*QuotaReservation {
+	if val, exists := c.Get(string(quotaReservationsKey)); exists {
+		if reservations, ok := val.(map[string]*QuotaReservation); ok {
+			return reservations
+		}
+	}
+	return nil
+}
+
+// SetUsageReference names the entity a request's quota paid for, e.g. the ID
+// of an uploaded document.
+//
+// RequireQuota records it with the usage when committing, which makes the
+// commit idempotent and lets admins trace usage back to its source.
+//
+// Example:
+//
+//	doc, err := h.service.UploadDocument(ctx, orgID, file)
+//	if err == nil {
+//	    paywall.SetUsageReference(c, fmt.Sprintf("document:%d", doc.ID))
+//	}
+func SetUsageReference(c *gin.Context, reference string) {
+	c.Set(string(usageReferenceKey), reference)
+}
+
+// getUsageReference retrieves the usage reference set by the handler.
+func getUsageReference(c *gin.Context) string {
+	return c.GetString(string(usageReferenceKey))
+}
@@ -0,0 +1,79 @@
+package paywall
+
+import (
+	"context"
+	"time"
+)
+
+// EntitlementProvider abstracts how an organization's plan features are resolved.
+//
+// The billing module implements this interface through its plan catalog.
+// Like SubscriptionStatusProvider, implementations should read from the
+// local database only.
+type EntitlementProvider interface {
+	// GetEntitlements returns the features the organization can use now.
+	// An organization without an active subscription has no features (not an error).
+	GetEntitlements(ctx context.Context, organizationID int32) (*Entitlements, error)
+}
+
+// Entitlements are the plan features an organization can use now.
+type Entitlements struct {
+	// OrganizationID is the database primary key for the organization.
+	OrganizationID int32 `json:"organization_id"`
+
+	// Plan is the key of the organization's plan, empty without a known plan.
+	Plan string `json:"plan,omitempty"`
+
+	// Features are the boolean features of the plan, e.g. "ai_chat".
+	Features map[string]bool `json:"features"`
+}
+
+// HasFeature returns true if the plan enables the feature.
+func (e *Entitlements) HasFeature(feature string) bool {
+	return e != nil && e.Features[feature]
+}
+
+// QuotaProvider abstracts how metered usage is reserved and consumed.
+//
+// The billing module implements this interface with its meter quotas and
+// usage ledger. A reservation holds units while a request is in progress, so
+// concurrent requests cannot overdraw an allowance; it is then committed,
+// which consumes the units, or released.
+type QuotaProvider interface {
+	// ReserveQuota holds units of a meter before the request runs.
+	// Returns ErrQuotaExceeded when the allowance left is insufficient.
+	ReserveQuota(ctx context.Context, request *QuotaRequest) (*QuotaReservation, error)
+
+	// CommitQuota consumes a reservation after the request succeeded.
+	CommitQuota(ctx context.Context, reservation *QuotaReservation) error
+
+	// ReleaseQuota returns a reservation's units after the request failed.
+	ReleaseQuota(ctx context.Context, reservation *QuotaReservation) error
+}
+
+// QuotaRequest describes units of a meter a request needs.
+type QuotaRequest struct {
+	OrganizationID int32
+	AccountID      int32  // Optional: member whose request consumes the quota
+	Meter          string // Meter slug, e.g. "document.processed"
+	Amount         int64
+	Reference      string         // Optional: idempotency key, e.g. the document ID
+	Metadata       map[string]any // Optional: recorded with the usage
+}
+
+// QuotaReservation is units of a meter held for a request in progress.
+type QuotaReservation struct {
+	ID             int64
+	OrganizationID int32
+	AccountID      int32
+	Meter          string
+	Amount         int64
+	Reference      string
+	Metadata       map[string]any
+
+	// Remaining is the units left on the meter after this reservation.
+	Remaining int64
+
+	// ResetsAt is when the meter's allowance resets.
+	ResetsAt time.Time
+}
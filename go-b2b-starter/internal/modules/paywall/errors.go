@@ -1,6 +1,9 @@
 package paywall
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 // Subscription errors.
 //
@@ -28,6 +31,14 @@ var (
 	// HTTP status: 402 Payment Required
 	ErrPaymentFailed = errors.New("subscription payment failed")
 
+	// ErrFeatureNotEntitled is returned when the organization's plan does not include a feature.
+	// HTTP status: 402 Payment Required
+	ErrFeatureNotEntitled = errors.New("plan does not include feature")
+
+	// ErrQuotaExceeded is returned when a meter's allowance for the period is used up.
+	// HTTP status: 402 Payment Required
+	ErrQuotaExceeded = errors.New("quota exceeded")
+
 	// ErrMissingOrganization is returned when organization ID is not in context.
 	// This means RequireOrganization middleware hasn't run.
 	// HTTP status: 500 Internal Server Error (misconfigured middleware)
@@ -40,7 +51,9 @@ func IsPaymentRequiredError(err error) bool {
 		errors.Is(err, ErrSubscriptionInactive) ||
 		errors.Is(err, ErrSubscriptionExpired) ||
 		errors.Is(err, ErrSubscriptionCanceled) ||
-		errors.Is(err, ErrPaymentFailed)
+		errors.Is(err, ErrPaymentFailed) ||
+		errors.Is(err, ErrFeatureNotEntitled) ||
+		errors.Is(err, ErrQuotaExceeded)
 }
 
 // HTTPStatusCode returns the appropriate HTTP status code for a subscription error.
@@ -76,4 +89,24 @@ type ErrorResponse struct {
 	// Status is the subscription status that caused the error.
 	// Optional - helps the client understand the specific issue.
 	Status string `json:"status,omitempty"`
+
+	// Feature is the plan feature the request needs.
+	// Optional - only set when the plan does not include it.
+	Feature string `json:"feature,omitempty"`
+
+	// Meter is the meter whose quota the request needs.
+	// Optional - only set when the quota is exceeded.
+	Meter string `json:"meter,omitempty"`
+
+	// ReadOnly is set when the organization can still read, and only
+	// writes are refused until the subscription is active again.
+	ReadOnly bool `json:"read_only,omitempty"`
+
+	// DunningPhase is the dunning phase of a past due subscription
+	// ("read_only" or "blocked"). Optional - only set for past due payments.
+	DunningPhase string `json:"dunning_phase,omitempty"`
+
+	// DunningPhaseEndsAt is when a read-only organization will be blocked.
+	// Optional - only set in the read-only phase.
+	DunningPhaseEndsAt *time.Time `json:"dunning_phase_ends_at,omitempty"`
 }
@@ -0,0 +1,100 @@
+package paywall
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Response headers describing the plan and quota usage of the organization
+const (
+	HeaderPlan               = "X-Plan"
+	HeaderSubscriptionStatus = "X-Subscription-Status"
+
+	// HeaderQuotaRemaining lists the units left per meter,
+	// e.g. "document.processed=42, llm.token=150000"
+	HeaderQuotaRemaining = "X-Quota-Remaining"
+
+	// HeaderQuotaReset is when the first of the quotas resets (RFC3339)
+	HeaderQuotaReset = "X-Quota-Reset"
+
+	// HeaderQuotaWarning lists the meters past the warning threshold with the
+	// share of their allowance that is used, e.g. "document.processed=92%"
+	HeaderQuotaWarning = "X-Quota-Warning"
+)
+
+// setStatusHeaders describes the subscription status on the response. It
+// runs before the handler, as headers cannot be added once the body is written.
+func (m *Middleware) setStatusHeaders(c *gin.Context, status *SubscriptionStatus) {
+	c.Header(HeaderSubscriptionStatus, status.Status)
+	if status.Plan != "" {
+		c.Header(HeaderPlan, status.Plan)
+	}
+	m.setQuotaHeaders(c, status.Quotas)
+}
+
+// setReservationHeaders updates the quota headers with the remaining units of
+// a reservation, which are more recent than those of the subscription status.
+func (m *Middleware) setReservationHeaders(c *gin.Context, reservation *QuotaReservation) {
+	var quotas []QuotaUsage
+	if status := GetSubscriptionStatus(c); status != nil {
+		quotas = append(quotas, status.Quotas...)
+	}
+
+	found := false
+	for i := range quotas {
+		if quotas[i].Meter == reservation.Meter {
+			quotas[i].Remaining = reservation.Remaining
+			if !reservation.ResetsAt.IsZero() {
+				quotas[i].ResetsAt = reservation.ResetsAt
+			}
+			found = true
+		}
+	}
+	if !found {
+		quotas = append(quotas, QuotaUsage{
+			Meter:     reservation.Meter,
+			Remaining: reservation.Remaining,
+			ResetsAt:  reservation.ResetsAt,
+		})
+	}
+
+	m.setQuotaHeaders(c, quotas)
+}
+
+// setQuotaHeaders sets the quota headers, replacing earlier values. Headers
+// of quotas that are absent are left unset.
+func (m *Middleware) setQuotaHeaders(c *gin.Context, quotas []QuotaUsage) {
+	if len(quotas) == 0 {
+		return
+	}
+
+	sorted := append([]QuotaUsage(nil), quotas...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Meter < sorted[j].Meter })
+
+	var remaining, warnings []string
+	var resetsAt time.Time
+	for _, quota := range sorted {
+		remaining = append(remaining, fmt.Sprintf("%s=%d", quota.Meter, max(quota.Remaining, 0)))
+
+		if !quota.ResetsAt.IsZero() && (resetsAt.IsZero() || quota.ResetsAt.Before(resetsAt)) {
+			resetsAt = quota.ResetsAt
+		}
+
+		threshold := m.config.QuotaWarningThreshold
+		if used := quota.UsedFraction(); threshold > 0 && used >= threshold {
+			warnings = append(warnings, fmt.Sprintf("%s=%d%%", quota.Meter, int(math.Min(used, 1)*100)))
+		}
+	}
+
+	c.Header(HeaderQuotaRemaining, strings.Join(remaining, ", "))
+	if !resetsAt.IsZero() {
+		c.Header(HeaderQuotaReset, resetsAt.UTC().Format(time.RFC3339))
+	}
+	// An empty value removes a warning set earlier in the chain
+	c.Header(HeaderQuotaWarning, strings.Join(warnings, ", "))
+}
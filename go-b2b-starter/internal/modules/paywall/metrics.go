@@ -0,0 +1,15 @@
+package paywall
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Prometheus series of the paywall middleware, exported on /metrics
+var (
+	quotaSettlementFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "paywall",
+		Name:      "quota_settlement_failures_total",
+		Help:      "Quota reservations that could not be committed or released after a request, by action and meter.",
+	}, []string{"action", "meter"})
+)
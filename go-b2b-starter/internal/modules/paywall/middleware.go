@@ -1,11 +1,15 @@
 package paywall
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/moasq/go-b2b-starter/internal/modules/auth"
+	"github.com/moasq/go-b2b-starter/internal/platform/logger"
 )
 
 // MiddlewareConfig configures the subscription middleware behavior.
@@ -21,14 +25,20 @@ type MiddlewareConfig struct {
 	// AllowTrialing determines if trialing subscriptions are allowed.
 	// Default: true (trialing is allowed)
 	AllowTrialing bool
+
+	// QuotaWarningThreshold is the share of a quota (0-1) from which
+	// responses carry an X-Quota-Warning header. Zero disables the warning.
+	// Default: 0.8
+	QuotaWarningThreshold float64
 }
 
 // DefaultMiddlewareConfig returns the default middleware configuration.
 func DefaultMiddlewareConfig() *MiddlewareConfig {
 	return &MiddlewareConfig{
-		ErrorHandler:  defaultErrorHandler,
-		UpgradeURL:    "/billing",
-		AllowTrialing: true,
+		ErrorHandler:          defaultErrorHandler,
+		UpgradeURL:            "/billing",
+		AllowTrialing:         true,
+		QuotaWarningThreshold: 0.8,
 	}
 }
 
@@ -41,14 +51,29 @@ func defaultErrorHandler(c *gin.Context, statusCode int, response *ErrorResponse
 //
 // Use NewMiddleware to create an instance with proper dependencies.
 type Middleware struct {
-	provider SubscriptionStatusProvider
-	config   *MiddlewareConfig
+	provider     SubscriptionStatusProvider
+	entitlements EntitlementProvider
+	quotas       QuotaProvider
+	logger       logger.Logger
+	config       *MiddlewareConfig
 }
 
 // Parameters:
 //   - provider: The subscription status provider (implements SubscriptionStatusProvider)
+//   - entitlements: The plan feature provider for RequireEntitlement (optional)
+//   - quotas: The metered usage provider for RequireQuota (optional)
+//   - log: Logger for failures that cannot be reported in the response (optional, logs to the console if nil)
 //   - config: Middleware configuration (optional, uses defaults if nil)
-func NewMiddleware(provider SubscriptionStatusProvider, config *MiddlewareConfig) *Middleware {
+func NewMiddleware(
+	provider SubscriptionStatusProvider,
+	entitlements EntitlementProvider,
+	quotas QuotaProvider,
+	log logger.Logger,
+	config *MiddlewareConfig,
+) *Middleware {
+	if log == nil {
+		log = logger.New()
+	}
 	if config == nil {
 		config = DefaultMiddlewareConfig()
 	}
@@ -56,8 +81,11 @@ func NewMiddleware(provider SubscriptionStatusProvider, config *MiddlewareConfig
 		config.ErrorHandler = defaultErrorHandler
 	}
 	return &Middleware{
-		provider: provider,
-		config:   config,
+		provider:     provider,
+		entitlements: entitlements,
+		quotas:       quotas,
+		logger:       log,
+		config:       config,
 	}
 }
 
@@ -69,6 +97,14 @@ func NewMiddleware(provider SubscriptionStatusProvider, config *MiddlewareConfig
 //  3. Sets SubscriptionStatus in Gin context if active
 //  4. Returns 402 Payment Required if subscription is not active
 //
+// Responses that pass carry the plan, subscription status and quota usage in
+// X-Plan, X-Subscription-Status, X-Quota-Remaining and X-Quota-Reset headers,
+// plus X-Quota-Warning once a quota passes QuotaWarningThreshold.
+//
+// A past due subscription follows the dunning policy: during the grace phase
+// requests pass with X-Dunning-Phase warning headers, during the read-only
+// phase only GET and HEAD pass, and once blocked every request gets 402.
+//
 // Must be called AFTER auth.RequireOrganization middleware.
 //
 // Usage:
@@ -77,6 +113,28 @@ func NewMiddleware(provider SubscriptionStatusProvider, config *MiddlewareConfig
 //	router.Use(authMiddleware.RequireOrganization())
 //	router.Use(subscriptionMiddleware.RequireActiveSubscription())
 func (m *Middleware) RequireActiveSubscription() gin.HandlerFunc {
+	return m.requireSubscription(false)
+}
+
+// ReadOnlyWhenInactive returns middleware that degrades an organization
+// without an active subscription to read-only access instead of blocking it.
+//
+// It behaves like RequireActiveSubscription, except that GET and HEAD requests
+// of an inactive organization (canceled, unpaid, past due in any dunning
+// phase, or without a subscription) still pass, so customers keep access to
+// their data while a payment is sorted out. Writes get 402 Payment Required
+// with read_only set and an error code ending in "_read_only".
+//
+// Usage:
+//
+//	docsGroup.Use(resolver.Get("paywall_read_only"))
+func (m *Middleware) ReadOnlyWhenInactive() gin.HandlerFunc {
+	return m.requireSubscription(true)
+}
+
+// requireSubscription checks the subscription status of the organization.
+// With readOnlyWhenInactive, an inactive organization may still read.
+func (m *Middleware) requireSubscription(readOnlyWhenInactive bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Skip OPTIONS requests (CORS preflight)
 		if c.Request.Method == "OPTIONS" {
@@ -109,9 +167,12 @@ func (m *Middleware) RequireActiveSubscription() gin.HandlerFunc {
 			return
 		}
 
+		// A read-only organization can still read
+		readOnlyAccess := isReadOnly(status, readOnlyWhenInactive) && isReadMethod(c.Request.Method)
+
 		// Lazy Guarding: If DB says inactive BUT subscription exists (not "none"),
 		// double-check with payment provider in case we missed a webhook
-		if !status.IsActive && status.Status != StatusNone {
+		if !status.IsActive && status.Status != StatusNone && !readOnlyAccess {
 			// Attempt to refresh subscription status from provider
 			freshStatus, refreshErr := m.provider.RefreshSubscriptionStatus(c.Request.Context(), orgID)
 
@@ -127,22 +188,35 @@ func (m *Middleware) RequireActiveSubscription() gin.HandlerFunc {
 		}
 
 		// Check if subscription is active (after potential refresh)
-		if !status.IsActive {
-			response := m.buildErrorResponse(status)
+		if !status.IsActive && !readOnlyAccess {
+			response := m.buildErrorResponse(status, isReadOnly(status, readOnlyWhenInactive))
 			m.config.ErrorHandler(c, http.StatusPaymentRequired, response)
 			c.Abort()
 			return
 		}
 
+		// Warn the client while a past due payment is being retried
+		if status.DunningPhase != "" {
+			setDunningHeaders(c, status)
+		}
+
 		// Set subscription status in context for downstream handlers
 		SetSubscriptionStatus(c, status)
+		m.setStatusHeaders(c, status)
 
 		c.Next()
 	}
 }
 
+// isReadOnly reports whether an inactive organization may still read: in the
+// read-only dunning phase, and in any inactive state with readOnlyWhenInactive.
+func isReadOnly(status *SubscriptionStatus, readOnlyWhenInactive bool) bool {
+	return status.IsReadOnly() || (readOnlyWhenInactive && !status.IsActive)
+}
+
 // buildErrorResponse creates an appropriate error response based on subscription status.
-func (m *Middleware) buildErrorResponse(status *SubscriptionStatus) *ErrorResponse {
+// A read-only organization is told that only its writes are refused.
+func (m *Middleware) buildErrorResponse(status *SubscriptionStatus, readOnly bool) *ErrorResponse {
 	response := &ErrorResponse{
 		UpgradeURL: m.config.UpgradeURL,
 		Status:     status.Status,
@@ -152,23 +226,249 @@ func (m *Middleware) buildErrorResponse(status *SubscriptionStatus) *ErrorRespon
 	case StatusPastDue:
 		response.Error = "payment_failed"
 		response.Message = "Your subscription payment has failed. Please update your payment method."
+		response.DunningPhase = status.DunningPhase
+		if status.IsReadOnly() {
+			response.DunningPhaseEndsAt = status.DunningPhaseEndsAt
+		}
+		if readOnly {
+			response.Message = "Your subscription payment has failed. Your organization is read-only until you update your payment method."
+		}
 	case StatusCanceled:
 		response.Error = "subscription_canceled"
 		response.Message = "Your subscription has been canceled. Please resubscribe to continue."
+		if readOnly {
+			response.Message = "Your subscription has been canceled. Your organization is read-only until you resubscribe."
+		}
 	case StatusUnpaid:
 		response.Error = "payment_required"
 		response.Message = "Your subscription is unpaid. Please update your payment method."
+		if readOnly {
+			response.Message = "Your subscription is unpaid. Your organization is read-only until you update your payment method."
+		}
 	default:
 		response.Error = "subscription_inactive"
 		response.Message = "An active subscription is required to access this feature"
 		if status.Reason != "" {
 			response.Message = status.Reason
 		}
+		if readOnly {
+			response.Message = "An active subscription is required to make changes. Your organization is read-only until you subscribe."
+		}
+	}
+
+	// Reads still pass, only the write was refused
+	if readOnly {
+		response.Error += "_read_only"
+		response.ReadOnly = true
 	}
 
 	return response
 }
 
+// Response headers warning of a past due payment
+const (
+	HeaderDunningPhase       = "X-Dunning-Phase"
+	HeaderDunningPhaseEndsAt = "X-Dunning-Phase-Ends-At"
+)
+
+// setDunningHeaders tells the client the dunning phase and when it ends, so it
+// can warn the user before access is restricted.
+func setDunningHeaders(c *gin.Context, status *SubscriptionStatus) {
+	c.Header(HeaderDunningPhase, status.DunningPhase)
+	if status.DunningPhaseEndsAt != nil {
+		c.Header(HeaderDunningPhaseEndsAt, status.DunningPhaseEndsAt.UTC().Format(time.RFC3339))
+	}
+}
+
+// isReadMethod reports whether an HTTP method only reads.
+func isReadMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+// RequireEntitlement returns middleware that requires a plan feature.
+//
+// This middleware:
+//  1. Gets OrganizationID from auth context (requires RequireOrganization to run first)
+//  2. Resolves the organization's entitlements from the EntitlementProvider
+//  3. Sets Entitlements in Gin context
+//  4. Returns 402 Payment Required if the plan does not include the feature
+//
+// Usage:
+//
+//	router.POST("/chat",
+//	    paywallMiddleware.RequireEntitlement("ai_chat"),
+//	    handler)
+func (m *Middleware) RequireEntitlement(feature string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Skip OPTIONS requests (CORS preflight)
+		if c.Request.Method == "OPTIONS" {
+			c.Next()
+			return
+		}
+
+		orgID := auth.GetOrganizationID(c)
+		if orgID == 0 || m.entitlements == nil {
+			m.config.ErrorHandler(c, http.StatusInternalServerError, &ErrorResponse{
+				Error:   "configuration_error",
+				Message: "Organization context and an entitlement provider are required - ensure RequireOrganization middleware is applied",
+			})
+			c.Abort()
+			return
+		}
+
+		// Several entitlement checks on one route resolve the plan once
+		entitlements := GetEntitlements(c)
+		if entitlements == nil || entitlements.OrganizationID != orgID {
+			var err error
+			entitlements, err = m.entitlements.GetEntitlements(c.Request.Context(), orgID)
+			if err != nil {
+				m.config.ErrorHandler(c, http.StatusInternalServerError, &ErrorResponse{
+					Error:   "entitlements_unavailable",
+					Message: "Failed to resolve the features of your plan",
+				})
+				c.Abort()
+				return
+			}
+			SetEntitlements(c, entitlements)
+		}
+
+		if !entitlements.HasFeature(feature) {
+			m.config.ErrorHandler(c, http.StatusPaymentRequired, &ErrorResponse{
+				Error:      "feature_not_available",
+				Message:    fmt.Sprintf("Your plan does not include %s. Please upgrade to use this feature.", feature),
+				UpgradeURL: m.config.UpgradeURL,
+				Feature:    feature,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireQuota returns middleware that charges cost units of a meter for a
+// request.
+//
+// This middleware:
+//  1. Gets OrganizationID from auth context (requires RequireOrganization to run first)
+//  2. Reserves cost units from the QuotaProvider before the handler runs
+//  3. Returns 402 Payment Required if the quota is exceeded
+//  4. Commits the reservation when the handler responds with 2xx, and
+//     releases it otherwise, so failed requests are not charged
+//
+// Handlers can name what the quota paid for with SetUsageReference.
+//
+// Usage:
+//
+//	router.POST("/documents/upload",
+//	    paywallMiddleware.RequireQuota("document.processed", 1),
+//	    handler)
+func (m *Middleware) RequireQuota(meter string, cost int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Skip OPTIONS requests (CORS preflight)
+		if c.Request.Method == "OPTIONS" {
+			c.Next()
+			return
+		}
+
+		orgID := auth.GetOrganizationID(c)
+		if orgID == 0 || m.quotas == nil {
+			m.config.ErrorHandler(c, http.StatusInternalServerError, &ErrorResponse{
+				Error:   "configuration_error",
+				Message: "Organization context and a quota provider are required - ensure RequireOrganization middleware is applied",
+			})
+			c.Abort()
+			return
+		}
+
+		reservation, err := m.quotas.ReserveQuota(c.Request.Context(), &QuotaRequest{
+			OrganizationID: orgID,
+			AccountID:      auth.GetAccountID(c),
+			Meter:          meter,
+			Amount:         cost,
+			Metadata: map[string]any{
+				"method": c.Request.Method,
+				"route":  c.FullPath(),
+			},
+		})
+		if err != nil {
+			m.handleQuotaError(c, meter, err)
+			c.Abort()
+			return
+		}
+		setQuotaReservation(c, reservation)
+		m.setReservationHeaders(c, reservation)
+
+		// The reservation is settled even if the client disconnects; a
+		// panicking handler releases it
+		ctx := context.WithoutCancel(c.Request.Context())
+		settled := false
+		defer func() {
+			if !settled {
+				m.settleQuota(ctx, reservation, false)
+			}
+		}()
+
+		c.Next()
+
+		settled = true
+		status := c.Writer.Status()
+		if reference := getUsageReference(c); reference != "" {
+			reservation.Reference = reference
+		}
+		m.settleQuota(ctx, reservation, status >= 200 && status < 300)
+	}
+}
+
+// handleQuotaError responds to a failed quota reservation.
+func (m *Middleware) handleQuotaError(c *gin.Context, meter string, err error) {
+	switch {
+	case errors.Is(err, ErrQuotaExceeded):
+		m.config.ErrorHandler(c, http.StatusPaymentRequired, &ErrorResponse{
+			Error:      "quota_exceeded",
+			Message:    fmt.Sprintf("Your plan's %s quota for this billing period is used up. Please upgrade to continue.", meter),
+			UpgradeURL: m.config.UpgradeURL,
+			Meter:      meter,
+		})
+	case IsPaymentRequiredError(err):
+		m.config.ErrorHandler(c, http.StatusPaymentRequired, &ErrorResponse{
+			Error:      "subscription_inactive",
+			Message:    "An active subscription is required to access this feature",
+			UpgradeURL: m.config.UpgradeURL,
+		})
+	default:
+		m.config.ErrorHandler(c, http.StatusInternalServerError, &ErrorResponse{
+			Error:   "quota_unavailable",
+			Message: "Failed to check the quota of your plan",
+		})
+	}
+}
+
+// settleQuota commits a reservation after a successful request and releases
+// it otherwise. The response is already written, so failures are logged and
+// counted in paywall_quota_settlement_failures_total: a lost commit leaves the
+// request unbilled, and an unreleased reservation holds its units until it
+// expires.
+func (m *Middleware) settleQuota(ctx context.Context, reservation *QuotaReservation, commit bool) {
+	action, settle := "release", m.quotas.ReleaseQuota
+	if commit {
+		action, settle = "commit", m.quotas.CommitQuota
+	}
+
+	if err := settle(ctx, reservation); err != nil {
+		quotaSettlementFailures.WithLabelValues(action, reservation.Meter).Inc()
+		m.logger.Error("Failed to settle quota reservation", map[string]any{
+			"action":          action,
+			"reservation_id":  reservation.ID,
+			"organization_id": reservation.OrganizationID,
+			"meter":           reservation.Meter,
+			"amount":          reservation.Amount,
+			"error":           err.Error(),
+		})
+	}
+}
+
 // RequireActiveSubscriptionFunc is a standalone middleware function.
 //
 // This is a convenience function that doesn't require a Middleware instance.
@@ -180,7 +480,7 @@ func (m *Middleware) buildErrorResponse(status *SubscriptionStatus) *ErrorRespon
 //	    subscription.RequireActiveSubscriptionFunc(provider),
 //	    handler)
 func RequireActiveSubscriptionFunc(provider SubscriptionStatusProvider) gin.HandlerFunc {
-	m := NewMiddleware(provider, nil)
+	m := NewMiddleware(provider, nil, nil, nil, nil)
 	return m.RequireActiveSubscription()
 }
 
@@ -217,6 +517,7 @@ func (m *Middleware) OptionalSubscriptionStatus() gin.HandlerFunc {
 		status, err := m.provider.GetSubscriptionStatus(c.Request.Context(), orgID)
 		if err == nil && status != nil {
 			SetSubscriptionStatus(c, status)
+			m.setStatusHeaders(c, status)
 		}
 
 		c.Next()
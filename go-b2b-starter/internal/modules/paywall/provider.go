@@ -5,6 +5,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/dig"
+
+	"github.com/moasq/go-b2b-starter/internal/platform/logger"
 )
 
 // ServerMiddlewareRegistrar is the interface for registering named middleware.
@@ -21,6 +23,9 @@ type ServerMiddlewareRegistrar interface {
 //
 // The following must be available in the container:
 //   - subscription.SubscriptionStatusProvider
+//   - paywall.EntitlementProvider (for RequireEntitlement)
+//   - paywall.QuotaProvider (for RequireQuota)
+//   - logger.Logger
 //
 // # Usage
 //
@@ -30,8 +35,11 @@ type ServerMiddlewareRegistrar interface {
 func SetupMiddleware(container *dig.Container) error {
 	if err := container.Provide(func(
 		provider SubscriptionStatusProvider,
+		entitlements EntitlementProvider,
+		quotas QuotaProvider,
+		log logger.Logger,
 	) *Middleware {
-		return NewMiddleware(provider, nil)
+		return NewMiddleware(provider, entitlements, quotas, log, nil)
 	}); err != nil {
 		return fmt.Errorf("failed to provide subscription middleware: %w", err)
 	}
@@ -52,8 +60,11 @@ func SetupMiddleware(container *dig.Container) error {
 func SetupMiddlewareWithConfig(container *dig.Container, config *MiddlewareConfig) error {
 	if err := container.Provide(func(
 		provider SubscriptionStatusProvider,
+		entitlements EntitlementProvider,
+		quotas QuotaProvider,
+		log logger.Logger,
 	) *Middleware {
-		return NewMiddleware(provider, config)
+		return NewMiddleware(provider, entitlements, quotas, log, config)
 	}); err != nil {
 		return fmt.Errorf("failed to provide subscription middleware: %w", err)
 	}
@@ -87,6 +98,12 @@ func RegisterNamedMiddlewares(container *dig.Container) error {
 			return middleware.RequireActiveSubscription()
 		})
 
+		// Register read-only paywall middleware (inactive organizations can
+		// still read, writes get 402)
+		server.RegisterNamedMiddleware("paywall_read_only", func() gin.HandlerFunc {
+			return middleware.ReadOnlyWhenInactive()
+		})
+
 		// Register optional paywall middleware (sets status but doesn't block)
 		server.RegisterNamedMiddleware("paywall_optional", func() gin.HandlerFunc {
 			return middleware.OptionalSubscriptionStatus()
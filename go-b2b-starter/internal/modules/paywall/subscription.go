@@ -43,7 +43,7 @@
 //
 // In routes:
 //
-//	paywallMiddleware := paywall.NewMiddleware(provider, nil)
+//	paywallMiddleware := paywall.NewMiddleware(provider, entitlements, quotas, log, nil)
 //	router.Use(
 //	    auth.RequireAuth(authProvider),
 //	    auth.RequireOrganization(orgRepo, accountRepo),
@@ -118,6 +118,44 @@ type SubscriptionStatus struct {
 	// Reason provides a human-readable explanation when IsActive is false.
 	// Examples: "subscription expired", "payment failed", "no subscription found"
 	Reason string `json:"reason,omitempty"`
+
+	// DunningPhase is set while a payment is past due: "grace" (IsActive stays
+	// true), "read_only" (only reads are allowed) or "blocked".
+	DunningPhase string `json:"dunning_phase,omitempty"`
+
+	// DunningPhaseEndsAt is when the next dunning phase starts; nil once blocked.
+	DunningPhaseEndsAt *time.Time `json:"dunning_phase_ends_at,omitempty"`
+
+	// Plan is the key of the organization's plan, e.g. "pro".
+	// Empty when the plan is unknown.
+	Plan string `json:"plan,omitempty"`
+
+	// Quotas is the usage of each metered quota in the current period.
+	Quotas []QuotaUsage `json:"quotas,omitempty"`
+}
+
+// QuotaUsage is the usage of a metered quota in the current billing period.
+type QuotaUsage struct {
+	// Meter is the meter slug, e.g. "document.processed".
+	Meter string `json:"meter"`
+
+	// Allowance is the number of units the plan grants for the period.
+	Allowance int64 `json:"allowance"`
+
+	// Remaining is the number of units left, not counting reserved units.
+	Remaining int64 `json:"remaining"`
+
+	// ResetsAt is when the period ends and the allowance is granted again.
+	ResetsAt time.Time `json:"resets_at"`
+}
+
+// UsedFraction returns the share of the allowance that is used or reserved,
+// 0 for a meter without allowance.
+func (q QuotaUsage) UsedFraction() float64 {
+	if q.Allowance <= 0 {
+		return 0
+	}
+	return float64(q.Allowance-q.Remaining) / float64(q.Allowance)
 }
 
 // IsTrialing returns true if the subscription is in a trial period.
@@ -130,6 +168,11 @@ func (s *SubscriptionStatus) IsPastDue() bool {
 	return s.Status == StatusPastDue
 }
 
+// IsReadOnly returns true if a past due payment limits the organization to reads.
+func (s *SubscriptionStatus) IsReadOnly() bool {
+	return s.DunningPhase == DunningPhaseReadOnly
+}
+
 // IsCanceled returns true if the subscription has been canceled.
 func (s *SubscriptionStatus) IsCanceled() bool {
 	return s.Status == StatusCanceled
@@ -146,6 +189,14 @@ const (
 	StatusNone     = "none" // No subscription exists
 )
 
+// Dunning phase constants.
+// A past due subscription moves through these phases while the payment is retried.
+const (
+	DunningPhaseGrace    = "grace"     // Full access, with a warning
+	DunningPhaseReadOnly = "read_only" // Safe methods only, writes get 402
+	DunningPhaseBlocked  = "blocked"   // No access until the payment succeeds
+)
+
 // IsActiveStatus returns true if the given status represents an active subscription.
 // Active statuses allow access to protected features.
 func IsActiveStatus(status string) bool {
@@ -0,0 +1,338 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/webhooks/config"
+	"github.com/moasq/go-b2b-starter/internal/modules/webhooks/domain"
+	logger "github.com/moasq/go-b2b-starter/internal/platform/logger/domain"
+)
+
+// Standard Webhooks request headers (https://www.standardwebhooks.com)
+const (
+	HeaderWebhookID        = "webhook-id"
+	HeaderWebhookTimestamp = "webhook-timestamp"
+	HeaderWebhookSignature = "webhook-signature"
+)
+
+// maxDrainedResponseBytes caps how much of a response is read so the
+// connection can be reused. Bodies are not kept: endpoints answer with the
+// status code only, so a delivery cannot be used to read internal responses.
+const maxDrainedResponseBytes = 64 << 10
+
+// errAddressNotAllowed is returned when an endpoint resolves to an address
+// refused by domain.IsPublicAddress
+var errAddressNotAllowed = errors.New("endpoint resolves to a non-public address")
+
+// dispatcher claims due deliveries and POSTs them to customer endpoints.
+//
+// Deliveries are leased with SKIP LOCKED, so several API instances can run a
+// dispatcher side by side. A delivery is retried with exponential backoff
+// until it is acknowledged with a 2xx response or MaxAttempts is reached.
+type dispatcher struct {
+	endpointRepo domain.EndpointRepository
+	deliveryRepo domain.DeliveryRepository
+	client       *http.Client
+	config       config.Config
+	logger       logger.Logger
+
+	mu      sync.Mutex
+	started bool
+	closed  bool
+	wake    chan struct{}
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+func NewDispatcher(
+	endpointRepo domain.EndpointRepository,
+	deliveryRepo domain.DeliveryRepository,
+	cfg config.Config,
+	logger logger.Logger,
+) Dispatcher {
+	return &dispatcher{
+		endpointRepo: endpointRepo,
+		deliveryRepo: deliveryRepo,
+		client:       newEndpointClient(cfg.RequestTimeout),
+		config:       cfg,
+		logger:       logger,
+		wake:         make(chan struct{}, 1),
+		done:         make(chan struct{}),
+	}
+}
+
+// Start launches the delivery worker
+func (d *dispatcher) Start() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return fmt.Errorf("webhook dispatcher is closed")
+	}
+	if d.started {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	d.started = true
+
+	go d.run(ctx)
+
+	d.logger.Info("Webhook dispatcher started", map[string]interface{}{
+		"poll_interval": d.config.PollInterval.String(),
+		"batch_size":    d.config.BatchSize,
+	})
+
+	return nil
+}
+
+// Close stops the worker, waiting for the in-flight batch to finish.
+// Pending deliveries stay queued and are sent after restart.
+func (d *dispatcher) Close() error {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return nil
+	}
+	d.closed = true
+	started := d.started
+	cancel := d.cancel
+	d.mu.Unlock()
+
+	if started {
+		cancel()
+		<-d.done
+	}
+	return nil
+}
+
+// Wake nudges the worker; deliveries queued in an uncommitted transaction
+// are picked up by the next poll at the latest
+func (d *dispatcher) Wake() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run is the delivery loop
+func (d *dispatcher) run(ctx context.Context) {
+	defer close(d.done)
+
+	ticker := time.NewTicker(d.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+
+		// Keep draining while there is work
+		for {
+			claimed, err := d.dispatchBatch(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					d.logger.Error("Webhook dispatch batch failed", map[string]interface{}{
+						"error": err.Error(),
+					})
+				}
+				break
+			}
+			if claimed == 0 || ctx.Err() != nil {
+				break
+			}
+		}
+	}
+}
+
+// dispatchBatch claims up to BatchSize due deliveries and sends them concurrently
+func (d *dispatcher) dispatchBatch(ctx context.Context) (int, error) {
+	deliveries, err := d.deliveryRepo.ClaimDue(ctx, d.config.BatchSize, d.config.LeaseDuration)
+	if err != nil {
+		return 0, err
+	}
+
+	// Requests get their own deadline; shutdown waits for them instead of
+	// cancelling mid-flight, the lease covers a crash
+	sendCtx := context.WithoutCancel(ctx)
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func(delivery *domain.Delivery) {
+			defer wg.Done()
+			d.deliver(sendCtx, delivery)
+		}(delivery)
+	}
+	wg.Wait()
+
+	return len(deliveries), nil
+}
+
+// deliver makes one attempt for a delivery and records the outcome
+func (d *dispatcher) deliver(ctx context.Context, delivery *domain.Delivery) {
+	attempt := &domain.DeliveryAttempt{
+		DeliveryID: delivery.ID,
+		Attempt:    delivery.Attempts + 1,
+	}
+
+	endpoint, err := d.endpointRepo.GetByID(ctx, delivery.OrganizationID, delivery.EndpointID)
+	if err != nil {
+		attempt.Error = err.Error()
+	} else if !endpoint.Enabled {
+		attempt.Error = "endpoint is disabled"
+	} else if err := endpoint.Validate(); err != nil {
+		// Endpoints registered before the URL rules were tightened
+		attempt.Error = err.Error()
+	} else {
+		d.send(ctx, endpoint, delivery, attempt)
+	}
+
+	if err := d.deliveryRepo.RecordAttempt(ctx, attempt); err != nil {
+		d.logger.Error("Failed to record webhook delivery attempt", map[string]interface{}{
+			"delivery_id": delivery.ID,
+			"error":       err.Error(),
+		})
+	}
+
+	if attempt.Error == "" && attempt.StatusCode != nil && *attempt.StatusCode/100 == 2 {
+		if err := d.deliveryRepo.MarkSucceeded(ctx, delivery.ID, *attempt.StatusCode); err != nil {
+			d.logger.Error("Failed to mark webhook delivery succeeded", map[string]interface{}{
+				"delivery_id": delivery.ID,
+				"error":       err.Error(),
+			})
+		}
+		return
+	}
+
+	lastError := attempt.Error
+	if lastError == "" && attempt.StatusCode != nil {
+		lastError = fmt.Sprintf("endpoint responded with status %d", *attempt.StatusCode)
+	}
+
+	status := domain.DeliveryStatusPending
+	retryIn := d.backoff(attempt.Attempt)
+	if attempt.Attempt >= d.config.MaxAttempts {
+		status = domain.DeliveryStatusFailed
+		retryIn = 0
+	}
+
+	d.logger.Warn("Webhook delivery failed", map[string]interface{}{
+		"delivery_id":     delivery.ID,
+		"endpoint_id":     delivery.EndpointID,
+		"organization_id": delivery.OrganizationID,
+		"event_type":      delivery.EventType,
+		"attempt":         attempt.Attempt,
+		"status":          string(status),
+		"retry_in":        retryIn.String(),
+		"error":           lastError,
+	})
+
+	if err := d.deliveryRepo.MarkFailed(ctx, delivery.ID, status, attempt.StatusCode, lastError, retryIn); err != nil {
+		d.logger.Error("Failed to record webhook delivery failure", map[string]interface{}{
+			"delivery_id": delivery.ID,
+			"error":       err.Error(),
+		})
+	}
+}
+
+// send POSTs the signed payload and fills in the attempt outcome
+func (d *dispatcher) send(ctx context.Context, endpoint *domain.Endpoint, delivery *domain.Delivery, attempt *domain.DeliveryAttempt) {
+	start := time.Now()
+	defer func() {
+		attempt.DurationMs = int32(time.Since(start).Milliseconds())
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		attempt.Error = fmt.Sprintf("failed to build request: %v", err)
+		return
+	}
+
+	// The event ID stays the same across retries so receivers can deduplicate
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature, err := signPayload(endpoint.Secret, delivery.EventID, timestamp, delivery.Payload)
+	if err != nil {
+		attempt.Error = err.Error()
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go-b2b-starter-webhooks/1.0")
+	req.Header.Set(HeaderWebhookID, delivery.EventID)
+	req.Header.Set(HeaderWebhookTimestamp, timestamp)
+	req.Header.Set(HeaderWebhookSignature, "v1,"+signature)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		attempt.Error = err.Error()
+		return
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainedResponseBytes))
+
+	statusCode := int32(resp.StatusCode)
+	attempt.StatusCode = &statusCode
+}
+
+// newEndpointClient returns the HTTP client used to call customer endpoints.
+//
+// Every connection is checked against domain.IsPublicAddress once the host
+// is resolved, so a hostname that passed validation cannot later be pointed
+// at an internal service (DNS rebinding). Proxies are not used, as the check
+// would only see the proxy's address.
+func newEndpointClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			addr, err := netip.ParseAddr(host)
+			if err != nil || !domain.IsPublicAddress(addr) {
+				return fmt.Errorf("%w: %s", errAddressNotAllowed, host)
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		// Endpoints must answer themselves rather than hand the event on
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// backoff returns the delay after a failed attempt: InitialBackoff doubled per attempt, capped
+func (d *dispatcher) backoff(attempt int32) time.Duration {
+	delay := time.Duration(float64(d.config.InitialBackoff) * math.Pow(2, float64(attempt-1)))
+	if delay <= 0 || delay > d.config.MaxBackoff {
+		return d.config.MaxBackoff
+	}
+	return delay
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/webhooks/domain"
+	"github.com/moasq/go-b2b-starter/internal/platform/eventbus"
+	logger "github.com/moasq/go-b2b-starter/internal/platform/logger/domain"
+)
+
+// envelopeFields are BaseEvent fields that describe the internal event rather
+// than the business data; they are left out of the customer payload
+var envelopeFields = []string{"id", "name", "created_at", "metadata", "partition_key"}
+
+type eventFanout struct {
+	endpointRepo domain.EndpointRepository
+	deliveryRepo domain.DeliveryRepository
+	dispatcher   Dispatcher
+	logger       logger.Logger
+}
+
+func NewEventFanout(
+	endpointRepo domain.EndpointRepository,
+	deliveryRepo domain.DeliveryRepository,
+	dispatcher Dispatcher,
+	logger logger.Logger,
+) EventFanout {
+	return &eventFanout{
+		endpointRepo: endpointRepo,
+		deliveryRepo: deliveryRepo,
+		dispatcher:   dispatcher,
+		logger:       logger,
+	}
+}
+
+func (f *eventFanout) HandleEvent(ctx context.Context, event eventbus.Event) error {
+	data, err := eventData(event)
+	if err != nil {
+		return err
+	}
+
+	orgID := organizationID(data)
+	if orgID == 0 {
+		orgID = eventbus.CorrelationFromEvent(event).OrganizationID
+	}
+	if orgID == 0 {
+		// Not tied to an organization, nobody to notify
+		return nil
+	}
+
+	endpoints, err := f.endpointRepo.ListForEvent(ctx, orgID, event.EventName())
+	if err != nil {
+		return err
+	}
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	timestamp := event.Timestamp()
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	payload, err := json.Marshal(domain.Message{
+		Type:      event.EventName(),
+		Timestamp: timestamp.UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	// Redelivered events are queued only once per endpoint, so retrying the
+	// whole fan-out after a partial failure is safe
+	for _, endpoint := range endpoints {
+		if err := f.deliveryRepo.Create(ctx, &domain.Delivery{
+			EndpointID:     endpoint.ID,
+			OrganizationID: orgID,
+			EventID:        event.EventID(),
+			EventType:      event.EventName(),
+			Payload:        payload,
+		}); err != nil {
+			return err
+		}
+	}
+
+	f.logger.Debug("Queued webhook deliveries", map[string]interface{}{
+		"event_id":        event.EventID(),
+		"event_type":      event.EventName(),
+		"organization_id": orgID,
+		"endpoints":       len(endpoints),
+	})
+
+	f.dispatcher.Wake()
+	return nil
+}
+
+// eventData returns the event's own fields as a JSON object
+func eventData(event eventbus.Event) (map[string]any, error) {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event %s: %w", event.EventName(), err)
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("failed to decode event %s: %w", event.EventName(), err)
+	}
+
+	for _, field := range envelopeFields {
+		delete(data, field)
+	}
+	return data, nil
+}
+
+// organizationID reads the organization_id field carried by organization events
+func organizationID(data map[string]any) int32 {
+	if id, ok := data["organization_id"].(float64); ok {
+		return int32(id)
+	}
+	return 0
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/webhooks/domain"
+	"github.com/moasq/go-b2b-starter/internal/platform/eventbus"
+)
+
+// WebhookService manages the webhook endpoints of an organization and their
+// delivery log
+type WebhookService interface {
+	// CreateEndpoint registers an endpoint; the response carries its signing secret
+	CreateEndpoint(ctx context.Context, orgID int32, req *CreateEndpointRequest) (*domain.Endpoint, error)
+
+	// ListEndpoints lists the endpoints of an organization
+	ListEndpoints(ctx context.Context, orgID int32) ([]*domain.Endpoint, error)
+
+	// GetEndpoint retrieves an endpoint by ID
+	GetEndpoint(ctx context.Context, orgID, endpointID int32) (*domain.Endpoint, error)
+
+	// UpdateEndpoint updates the URL, description, event filter or enabled flag
+	UpdateEndpoint(ctx context.Context, orgID, endpointID int32, req *UpdateEndpointRequest) (*domain.Endpoint, error)
+
+	// DeleteEndpoint removes an endpoint together with its delivery log
+	DeleteEndpoint(ctx context.Context, orgID, endpointID int32) error
+
+	// RotateSecret replaces the signing secret; the response carries the new secret
+	RotateSecret(ctx context.Context, orgID, endpointID int32) (*domain.Endpoint, error)
+
+	// ListDeliveries lists the deliveries of an endpoint, newest first
+	ListDeliveries(ctx context.Context, orgID, endpointID int32, req *ListDeliveriesRequest) (*ListDeliveriesResponse, error)
+
+	// GetDelivery retrieves a delivery with its attempt log
+	GetDelivery(ctx context.Context, orgID int32, deliveryID int64) (*domain.Delivery, error)
+
+	// Redeliver queues a delivery for an immediate new attempt
+	Redeliver(ctx context.Context, orgID int32, deliveryID int64) (*domain.Delivery, error)
+
+	// EventTypes returns the event types endpoints can subscribe to
+	EventTypes() []string
+}
+
+// EventFanout turns internal events into deliveries for the endpoints of
+// the event's organization
+type EventFanout interface {
+	// HandleEvent queues the event for every enabled endpoint that accepts it
+	HandleEvent(ctx context.Context, event eventbus.Event) error
+}
+
+// Dispatcher sends queued deliveries to customer endpoints in the background
+type Dispatcher interface {
+	// Start launches the delivery worker
+	Start() error
+
+	// Close stops the worker, waiting for in-flight requests
+	Close() error
+
+	// Wake asks the worker to look for due deliveries now
+	Wake()
+}
+
+// CreateEndpointRequest represents a request to register a webhook endpoint
+type CreateEndpointRequest struct {
+	URL         string   `json:"url" binding:"required"`
+	Description string   `json:"description,omitempty"`
+	EventTypes  []string `json:"event_types,omitempty"`
+}
+
+// UpdateEndpointRequest represents a request to update a webhook endpoint;
+// omitted fields keep their value
+type UpdateEndpointRequest struct {
+	URL         *string  `json:"url,omitempty"`
+	Description *string  `json:"description,omitempty"`
+	EventTypes  []string `json:"event_types,omitempty"`
+	Enabled     *bool    `json:"enabled,omitempty"`
+}
+
+// ListDeliveriesRequest represents a request to list deliveries
+type ListDeliveriesRequest struct {
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+// ListDeliveriesResponse represents the response for listing deliveries
+type ListDeliveriesResponse struct {
+	Deliveries []*domain.Delivery `json:"deliveries"`
+	Limit      int32              `json:"limit"`
+	Offset     int32              `json:"offset"`
+}
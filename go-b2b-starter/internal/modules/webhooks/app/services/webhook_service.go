@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/webhooks/config"
+	"github.com/moasq/go-b2b-starter/internal/modules/webhooks/domain"
+)
+
+// secretPrefix marks webhook signing secrets, as in the Standard Webhooks
+// spec: a secret is the prefix followed by the base64-encoded signing key
+const secretPrefix = "whsec_"
+
+type webhookService struct {
+	endpointRepo domain.EndpointRepository
+	deliveryRepo domain.DeliveryRepository
+	dispatcher   Dispatcher
+	config       config.Config
+}
+
+func NewWebhookService(
+	endpointRepo domain.EndpointRepository,
+	deliveryRepo domain.DeliveryRepository,
+	dispatcher Dispatcher,
+	cfg config.Config,
+) WebhookService {
+	return &webhookService{
+		endpointRepo: endpointRepo,
+		deliveryRepo: deliveryRepo,
+		dispatcher:   dispatcher,
+		config:       cfg,
+	}
+}
+
+func (s *webhookService) CreateEndpoint(ctx context.Context, orgID int32, req *CreateEndpointRequest) (*domain.Endpoint, error) {
+	if err := s.validateEventTypes(req.EventTypes); err != nil {
+		return nil, err
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := &domain.Endpoint{
+		OrganizationID: orgID,
+		URL:            req.URL,
+		Description:    req.Description,
+		EventTypes:     req.EventTypes,
+		Secret:         secret,
+		Enabled:        true,
+	}
+	if err := endpoint.Validate(); err != nil {
+		return nil, err
+	}
+
+	// The secret is returned once, on creation
+	return s.endpointRepo.Create(ctx, endpoint)
+}
+
+func (s *webhookService) ListEndpoints(ctx context.Context, orgID int32) ([]*domain.Endpoint, error) {
+	endpoints, err := s.endpointRepo.List(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	for _, endpoint := range endpoints {
+		endpoint.Secret = ""
+	}
+	return endpoints, nil
+}
+
+func (s *webhookService) GetEndpoint(ctx context.Context, orgID, endpointID int32) (*domain.Endpoint, error) {
+	endpoint, err := s.endpointRepo.GetByID(ctx, orgID, endpointID)
+	if err != nil {
+		return nil, err
+	}
+	endpoint.Secret = ""
+	return endpoint, nil
+}
+
+func (s *webhookService) UpdateEndpoint(ctx context.Context, orgID, endpointID int32, req *UpdateEndpointRequest) (*domain.Endpoint, error) {
+	endpoint, err := s.endpointRepo.GetByID(ctx, orgID, endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		endpoint.URL = *req.URL
+	}
+	if req.Description != nil {
+		endpoint.Description = *req.Description
+	}
+	if req.EventTypes != nil {
+		if err := s.validateEventTypes(req.EventTypes); err != nil {
+			return nil, err
+		}
+		endpoint.EventTypes = req.EventTypes
+	}
+	if req.Enabled != nil {
+		endpoint.Enabled = *req.Enabled
+	}
+	if err := endpoint.Validate(); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.endpointRepo.Update(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	updated.Secret = ""
+	return updated, nil
+}
+
+func (s *webhookService) DeleteEndpoint(ctx context.Context, orgID, endpointID int32) error {
+	if _, err := s.endpointRepo.GetByID(ctx, orgID, endpointID); err != nil {
+		return err
+	}
+	return s.endpointRepo.Delete(ctx, orgID, endpointID)
+}
+
+func (s *webhookService) RotateSecret(ctx context.Context, orgID, endpointID int32) (*domain.Endpoint, error) {
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, err
+	}
+	return s.endpointRepo.UpdateSecret(ctx, orgID, endpointID, secret)
+}
+
+func (s *webhookService) ListDeliveries(ctx context.Context, orgID, endpointID int32, req *ListDeliveriesRequest) (*ListDeliveriesResponse, error) {
+	if _, err := s.endpointRepo.GetByID(ctx, orgID, endpointID); err != nil {
+		return nil, err
+	}
+
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 20
+	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
+
+	deliveries, err := s.deliveryRepo.ListByEndpoint(ctx, orgID, endpointID, req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListDeliveriesResponse{
+		Deliveries: deliveries,
+		Limit:      req.Limit,
+		Offset:     req.Offset,
+	}, nil
+}
+
+func (s *webhookService) GetDelivery(ctx context.Context, orgID int32, deliveryID int64) (*domain.Delivery, error) {
+	delivery, err := s.deliveryRepo.GetByID(ctx, orgID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts, err := s.deliveryRepo.ListAttempts(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	delivery.AttemptLog = attempts
+
+	return delivery, nil
+}
+
+func (s *webhookService) Redeliver(ctx context.Context, orgID int32, deliveryID int64) (*domain.Delivery, error) {
+	delivery, err := s.deliveryRepo.Requeue(ctx, orgID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	s.dispatcher.Wake()
+	return delivery, nil
+}
+
+func (s *webhookService) EventTypes() []string {
+	return s.config.EventTypeList()
+}
+
+func (s *webhookService) validateEventTypes(eventTypes []string) error {
+	for _, eventType := range eventTypes {
+		if !s.config.Supports(eventType) {
+			return fmt.Errorf("%w: %s", domain.ErrUnsupportedEventType, eventType)
+		}
+	}
+	return nil
+}
+
+// generateSecret returns a new random signing secret
+func generateSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return secretPrefix + base64.StdEncoding.EncodeToString(b), nil
+}
+
+// signPayload returns the Standard Webhooks signature of a message: the
+// base64 HMAC-SHA256 of "{id}.{timestamp}.{payload}", keyed with the decoded
+// bytes of the secret, so receivers can verify it with any Standard Webhooks
+// library.
+func signPayload(secret, webhookID, timestamp string, payload []byte) (string, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(secret, secretPrefix))
+	if err != nil {
+		return "", fmt.Errorf("invalid webhook signing secret, rotate it: %w", err)
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(webhookID + "." + timestamp + "."))
+	mac.Write(payload)
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
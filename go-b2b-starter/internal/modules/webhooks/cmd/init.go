@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+
+	"go.uber.org/dig"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/webhooks"
+	"github.com/moasq/go-b2b-starter/internal/modules/webhooks/app/services"
+	"github.com/moasq/go-b2b-starter/internal/modules/webhooks/config"
+	"github.com/moasq/go-b2b-starter/internal/platform/eventbus"
+)
+
+// fanoutHandlerName names the fan-out subscription in dead letters
+const fanoutHandlerName = "webhooks.fanout"
+
+func Init(container *dig.Container) error {
+	module := webhooks.NewModule(container)
+	if err := module.RegisterDependencies(); err != nil {
+		return fmt.Errorf("failed to register webhooks dependencies: %w", err)
+	}
+
+	// Queue a delivery per endpoint for every event offered to customers
+	if err := container.Invoke(func(
+		dlq *eventbus.DeadLetterQueue,
+		fanout services.EventFanout,
+		cfg config.Config,
+	) error {
+		for _, eventType := range cfg.EventTypeList() {
+			if err := dlq.Subscribe(eventType, fanoutHandlerName, fanout.HandleEvent); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to wire webhook event fan-out: %w", err)
+	}
+
+	return nil
+}
+
+// Start launches the webhook delivery worker
+func Start(container *dig.Container) error {
+	return container.Invoke(func(dispatcher services.Dispatcher) error {
+		if err := dispatcher.Start(); err != nil {
+			return fmt.Errorf("failed to start webhook dispatcher: %w", err)
+		}
+		return nil
+	})
+}
+
+// Close stops the webhook delivery worker, waiting for in-flight requests
+func Close(container *dig.Container) error {
+	return container.Invoke(func(dispatcher services.Dispatcher) error {
+		return dispatcher.Close()
+	})
+}
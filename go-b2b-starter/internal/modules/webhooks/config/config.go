@@ -0,0 +1,116 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Config holds configuration for outbound webhook delivery
+type Config struct {
+	// EventTypes are the internal events offered to customer endpoints,
+	// comma separated
+	EventTypes string `mapstructure:"WEBHOOKS_EVENT_TYPES"`
+
+	// PollInterval is how often the dispatcher looks for due deliveries
+	PollInterval time.Duration `mapstructure:"WEBHOOKS_POLL_INTERVAL"`
+
+	// BatchSize is the number of deliveries claimed per poll
+	BatchSize int32 `mapstructure:"WEBHOOKS_BATCH_SIZE"`
+
+	// LeaseDuration is how long a claimed delivery is hidden from other
+	// dispatchers; it must cover the request timeout
+	LeaseDuration time.Duration `mapstructure:"WEBHOOKS_LEASE_DURATION"`
+
+	// RequestTimeout bounds a single POST to a customer endpoint
+	RequestTimeout time.Duration `mapstructure:"WEBHOOKS_REQUEST_TIMEOUT"`
+
+	// MaxAttempts is the number of attempts before a delivery is marked failed
+	MaxAttempts int32 `mapstructure:"WEBHOOKS_MAX_ATTEMPTS"`
+
+	// InitialBackoff is the delay after the first failed attempt; it doubles
+	// per attempt up to MaxBackoff
+	InitialBackoff time.Duration `mapstructure:"WEBHOOKS_INITIAL_BACKOFF"`
+	MaxBackoff     time.Duration `mapstructure:"WEBHOOKS_MAX_BACKOFF"`
+}
+
+// LoadConfig reads configuration from file or environment variables.
+func LoadConfig() (Config, error) {
+	var cfg Config
+
+	viper.SetConfigName("app")
+	viper.SetConfigType("env")
+	viper.AddConfigPath(".")
+	viper.AutomaticEnv()
+
+	// Set default values
+	viper.SetDefault("WEBHOOKS_EVENT_TYPES", "document.processed,document.failed,member.joined,subscription.changed")
+	viper.SetDefault("WEBHOOKS_POLL_INTERVAL", "2s")
+	viper.SetDefault("WEBHOOKS_BATCH_SIZE", 20)
+	viper.SetDefault("WEBHOOKS_LEASE_DURATION", "1m")
+	viper.SetDefault("WEBHOOKS_REQUEST_TIMEOUT", "10s")
+	viper.SetDefault("WEBHOOKS_MAX_ATTEMPTS", 8)
+	viper.SetDefault("WEBHOOKS_INITIAL_BACKOFF", "30s")
+	viper.SetDefault("WEBHOOKS_MAX_BACKOFF", "6h")
+
+	// Best-effort: ignore missing file, allow env-only usage
+	if err := viper.ReadInConfig(); err == nil {
+		_ = err
+	}
+
+	if err := viper.Unmarshal(&cfg); err != nil {
+		return cfg, fmt.Errorf("unable to decode webhooks config: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	if c.PollInterval <= 0 {
+		return fmt.Errorf("webhook poll interval must be positive (WEBHOOKS_POLL_INTERVAL)")
+	}
+	if c.BatchSize <= 0 {
+		return fmt.Errorf("webhook batch size must be positive (WEBHOOKS_BATCH_SIZE)")
+	}
+	if c.RequestTimeout <= 0 {
+		return fmt.Errorf("webhook request timeout must be positive (WEBHOOKS_REQUEST_TIMEOUT)")
+	}
+	if c.LeaseDuration < c.RequestTimeout {
+		return fmt.Errorf("webhook lease duration must be at least the request timeout (WEBHOOKS_LEASE_DURATION)")
+	}
+	if c.MaxAttempts < 1 {
+		return fmt.Errorf("webhook max attempts must be at least 1 (WEBHOOKS_MAX_ATTEMPTS)")
+	}
+	if c.InitialBackoff <= 0 || c.MaxBackoff < c.InitialBackoff {
+		return fmt.Errorf("webhook backoff must be positive and not exceed the maximum (WEBHOOKS_INITIAL_BACKOFF, WEBHOOKS_MAX_BACKOFF)")
+	}
+	return nil
+}
+
+// EventTypeList returns the configured event types
+func (c Config) EventTypeList() []string {
+	var types []string
+	for _, t := range strings.Split(c.EventTypes, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// Supports reports whether an event type is offered to endpoints
+func (c Config) Supports(eventType string) bool {
+	for _, t := range c.EventTypeList() {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
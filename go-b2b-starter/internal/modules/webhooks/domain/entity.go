@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DeliveryStatus represents the state of a webhook delivery
+type DeliveryStatus string
+
+const (
+	// DeliveryStatusPending is waiting for its first or next attempt
+	DeliveryStatusPending DeliveryStatus = "pending"
+	// DeliveryStatusSucceeded was acknowledged with a 2xx response
+	DeliveryStatusSucceeded DeliveryStatus = "succeeded"
+	// DeliveryStatusFailed exhausted its attempts; it can be redelivered manually
+	DeliveryStatusFailed DeliveryStatus = "failed"
+)
+
+// Endpoint is a URL registered by an organization to receive webhooks
+type Endpoint struct {
+	ID             int32  `json:"id"`
+	OrganizationID int32  `json:"organization_id"`
+	URL            string `json:"url"`
+	Description    string `json:"description"`
+	// EventTypes filters the events sent to the endpoint; empty means all
+	EventTypes []string `json:"event_types"`
+	// Secret signs the payloads; only returned when created or rotated
+	Secret    string    `json:"secret,omitempty"`
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate validates the endpoint entity. The URL must use https and may not
+// name a local or private address; hostnames are checked again on every
+// connection, once resolved (see IsPublicAddress).
+func (e *Endpoint) Validate() error {
+	if e.OrganizationID == 0 {
+		return ErrEndpointOrganizationRequired
+	}
+	if e.URL == "" {
+		return ErrEndpointURLRequired
+	}
+	parsed, err := url.Parse(e.URL)
+	if err != nil || parsed.Host == "" || parsed.Scheme != "https" {
+		return ErrEndpointURLInvalid
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrEndpointURLNotPublic
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !IsPublicAddress(addr) {
+		return ErrEndpointURLNotPublic
+	}
+	return nil
+}
+
+// nonPublicPrefixes are ranges not covered by the netip predicates that must
+// not be reached either: "this network" and carrier-grade NAT (RFC 6598)
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+}
+
+// IsPublicAddress reports whether webhooks may be sent to addr. Loopback,
+// private, link-local, multicast and unspecified addresses are refused, so
+// an endpoint cannot be used to reach the internal network or cloud metadata.
+func IsPublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() ||
+		addr.IsUnspecified() ||
+		addr.IsLoopback() ||
+		addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// Accepts reports whether the endpoint subscribes to an event type
+func (e *Endpoint) Accepts(eventType string) bool {
+	if !e.Enabled {
+		return false
+	}
+	if len(e.EventTypes) == 0 {
+		return true
+	}
+	for _, t := range e.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Delivery is one event queued for one endpoint, together with its outcome
+type Delivery struct {
+	ID             int64              `json:"id"`
+	EndpointID     int32              `json:"endpoint_id"`
+	OrganizationID int32              `json:"organization_id"`
+	EventID        string             `json:"event_id"`
+	EventType      string             `json:"event_type"`
+	Payload        []byte             `json:"-"`
+	Status         DeliveryStatus     `json:"status"`
+	Attempts       int32              `json:"attempts"`
+	NextAttemptAt  time.Time          `json:"next_attempt_at"`
+	LastStatusCode *int32             `json:"last_status_code,omitempty"`
+	LastError      string             `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time         `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+	AttemptLog     []*DeliveryAttempt `json:"attempt_log,omitempty"`
+}
+
+// DeliveryAttempt records a single HTTP request made for a delivery
+type DeliveryAttempt struct {
+	ID         int64     `json:"id"`
+	DeliveryID int64     `json:"delivery_id"`
+	Attempt    int32     `json:"attempt"`
+	StatusCode *int32    `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int32     `json:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Message is the JSON body POSTed to endpoints, following the Standard
+// Webhooks payload structure
+type Message struct {
+	Type      string         `json:"type"`
+	Timestamp time.Time      `json:"timestamp"`
+	Data      map[string]any `json:"data"`
+}
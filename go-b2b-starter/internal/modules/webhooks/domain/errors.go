@@ -0,0 +1,17 @@
+package domain
+
+import "errors"
+
+// Domain errors for webhooks
+var (
+	// Validation errors
+	ErrEndpointOrganizationRequired = errors.New("webhook endpoint organization ID is required")
+	ErrEndpointURLRequired          = errors.New("webhook endpoint URL is required")
+	ErrEndpointURLInvalid           = errors.New("webhook endpoint URL must be an absolute https URL")
+	ErrEndpointURLNotPublic         = errors.New("webhook endpoint URL must not point to a local or private address")
+	ErrUnsupportedEventType         = errors.New("unsupported webhook event type")
+
+	// Not found errors
+	ErrEndpointNotFound = errors.New("webhook endpoint not found")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+)
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// EndpointRepository defines the interface for webhook endpoint data operations
+type EndpointRepository interface {
+	// Create creates a new endpoint
+	Create(ctx context.Context, endpoint *Endpoint) (*Endpoint, error)
+
+	// GetByID retrieves an endpoint by ID
+	GetByID(ctx context.Context, orgID, endpointID int32) (*Endpoint, error)
+
+	// List retrieves all endpoints of an organization
+	List(ctx context.Context, orgID int32) ([]*Endpoint, error)
+
+	// ListForEvent retrieves the enabled endpoints of an organization that accept an event type
+	ListForEvent(ctx context.Context, orgID int32, eventType string) ([]*Endpoint, error)
+
+	// Update updates the URL, description, event filter and enabled flag
+	Update(ctx context.Context, endpoint *Endpoint) (*Endpoint, error)
+
+	// UpdateSecret replaces the signing secret
+	UpdateSecret(ctx context.Context, orgID, endpointID int32, secret string) (*Endpoint, error)
+
+	// Delete removes an endpoint and its delivery log
+	Delete(ctx context.Context, orgID, endpointID int32) error
+}
+
+// DeliveryRepository defines the interface for webhook delivery data operations
+type DeliveryRepository interface {
+	// Create queues an event for an endpoint; queuing the same event twice is a no-op
+	Create(ctx context.Context, delivery *Delivery) error
+
+	// ClaimDue leases up to batchSize due deliveries for the given duration
+	ClaimDue(ctx context.Context, batchSize int32, lease time.Duration) ([]*Delivery, error)
+
+	// MarkSucceeded records a successful attempt
+	MarkSucceeded(ctx context.Context, deliveryID int64, statusCode int32) error
+
+	// MarkFailed records a failed attempt; the delivery is retried after retryIn
+	// while status is pending
+	MarkFailed(ctx context.Context, deliveryID int64, status DeliveryStatus, statusCode *int32, lastError string, retryIn time.Duration) error
+
+	// GetByID retrieves a delivery by ID
+	GetByID(ctx context.Context, orgID int32, deliveryID int64) (*Delivery, error)
+
+	// ListByEndpoint retrieves the deliveries of an endpoint, newest first
+	ListByEndpoint(ctx context.Context, orgID, endpointID int32, limit, offset int32) ([]*Delivery, error)
+
+	// Requeue schedules a delivery for an immediate attempt
+	Requeue(ctx context.Context, orgID int32, deliveryID int64) (*Delivery, error)
+
+	// RecordAttempt appends an entry to the delivery log
+	RecordAttempt(ctx context.Context, attempt *DeliveryAttempt) error
+
+	// ListAttempts retrieves the delivery log of a delivery
+	ListAttempts(ctx context.Context, deliveryID int64) ([]*DeliveryAttempt, error)
+}
@@ -0,0 +1,382 @@
+package webhooks
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/auth"
+	"github.com/moasq/go-b2b-starter/internal/modules/webhooks/app/services"
+	"github.com/moasq/go-b2b-starter/internal/modules/webhooks/domain"
+	"github.com/moasq/go-b2b-starter/pkg/httperr"
+)
+
+type Handler struct {
+	service services.WebhookService
+}
+
+func NewHandler(service services.WebhookService) *Handler {
+	return &Handler{service: service}
+}
+
+// ListEndpointsResponse represents the response for listing endpoints
+type ListEndpointsResponse struct {
+	Endpoints []*domain.Endpoint `json:"endpoints"`
+	// EventTypes are the event types endpoints can subscribe to
+	EventTypes []string `json:"event_types"`
+}
+
+// CreateEndpoint registers a webhook endpoint
+// @Summary Create webhook endpoint
+// @Description Registers a URL to receive signed webhooks for the organization. The signing secret (whsec_ followed by the base64-encoded HMAC key, per Standard Webhooks) is only returned in this response and when rotated. An empty event_types list subscribes to every event type.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param request body services.CreateEndpointRequest true "Endpoint to register"
+// @Success 201 {object} domain.Endpoint
+// @Failure 400 {object} httperr.HTTPError
+// @Failure 500 {object} httperr.HTTPError
+// @Router /webhooks/endpoints [post]
+func (h *Handler) CreateEndpoint(c *gin.Context) {
+	reqCtx, ok := requestContext(c)
+	if !ok {
+		return
+	}
+
+	var req services.CreateEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, httperr.NewHTTPError(
+			http.StatusBadRequest,
+			"invalid_request",
+			"Invalid request payload: "+err.Error(),
+		))
+		return
+	}
+
+	endpoint, err := h.service.CreateEndpoint(c.Request.Context(), reqCtx.OrganizationID, &req)
+	if err != nil {
+		respondError(c, err, "create_failed", "Failed to create webhook endpoint")
+		return
+	}
+
+	c.JSON(http.StatusCreated, endpoint)
+}
+
+// ListEndpoints lists the webhook endpoints of the organization
+// @Summary List webhook endpoints
+// @Description Lists the organization's webhook endpoints and the event types they can subscribe to
+// @Tags Webhooks
+// @Produce json
+// @Success 200 {object} ListEndpointsResponse
+// @Failure 500 {object} httperr.HTTPError
+// @Router /webhooks/endpoints [get]
+func (h *Handler) ListEndpoints(c *gin.Context) {
+	reqCtx, ok := requestContext(c)
+	if !ok {
+		return
+	}
+
+	endpoints, err := h.service.ListEndpoints(c.Request.Context(), reqCtx.OrganizationID)
+	if err != nil {
+		respondError(c, err, "list_failed", "Failed to list webhook endpoints")
+		return
+	}
+
+	c.JSON(http.StatusOK, ListEndpointsResponse{
+		Endpoints:  endpoints,
+		EventTypes: h.service.EventTypes(),
+	})
+}
+
+// GetEndpoint returns a webhook endpoint
+// @Summary Get webhook endpoint
+// @Tags Webhooks
+// @Produce json
+// @Param id path int true "Endpoint ID"
+// @Success 200 {object} domain.Endpoint
+// @Failure 400 {object} httperr.HTTPError
+// @Failure 404 {object} httperr.HTTPError
+// @Router /webhooks/endpoints/{id} [get]
+func (h *Handler) GetEndpoint(c *gin.Context) {
+	reqCtx, ok := requestContext(c)
+	if !ok {
+		return
+	}
+	endpointID, ok := parseEndpointID(c)
+	if !ok {
+		return
+	}
+
+	endpoint, err := h.service.GetEndpoint(c.Request.Context(), reqCtx.OrganizationID, endpointID)
+	if err != nil {
+		respondError(c, err, "get_failed", "Failed to get webhook endpoint")
+		return
+	}
+
+	c.JSON(http.StatusOK, endpoint)
+}
+
+// UpdateEndpoint updates a webhook endpoint
+// @Summary Update webhook endpoint
+// @Description Updates the URL, description, event filter or enabled flag; omitted fields keep their value
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Endpoint ID"
+// @Param request body services.UpdateEndpointRequest true "Fields to update"
+// @Success 200 {object} domain.Endpoint
+// @Failure 400 {object} httperr.HTTPError
+// @Failure 404 {object} httperr.HTTPError
+// @Router /webhooks/endpoints/{id} [patch]
+func (h *Handler) UpdateEndpoint(c *gin.Context) {
+	reqCtx, ok := requestContext(c)
+	if !ok {
+		return
+	}
+	endpointID, ok := parseEndpointID(c)
+	if !ok {
+		return
+	}
+
+	var req services.UpdateEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, httperr.NewHTTPError(
+			http.StatusBadRequest,
+			"invalid_request",
+			"Invalid request payload: "+err.Error(),
+		))
+		return
+	}
+
+	endpoint, err := h.service.UpdateEndpoint(c.Request.Context(), reqCtx.OrganizationID, endpointID, &req)
+	if err != nil {
+		respondError(c, err, "update_failed", "Failed to update webhook endpoint")
+		return
+	}
+
+	c.JSON(http.StatusOK, endpoint)
+}
+
+// DeleteEndpoint removes a webhook endpoint
+// @Summary Delete webhook endpoint
+// @Description Deletes the endpoint together with its delivery log
+// @Tags Webhooks
+// @Param id path int true "Endpoint ID"
+// @Success 204
+// @Failure 400 {object} httperr.HTTPError
+// @Failure 404 {object} httperr.HTTPError
+// @Router /webhooks/endpoints/{id} [delete]
+func (h *Handler) DeleteEndpoint(c *gin.Context) {
+	reqCtx, ok := requestContext(c)
+	if !ok {
+		return
+	}
+	endpointID, ok := parseEndpointID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteEndpoint(c.Request.Context(), reqCtx.OrganizationID, endpointID); err != nil {
+		respondError(c, err, "delete_failed", "Failed to delete webhook endpoint")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RotateSecret replaces the signing secret of a webhook endpoint
+// @Summary Rotate webhook signing secret
+// @Description Generates a new signing secret; the old one stops working immediately
+// @Tags Webhooks
+// @Produce json
+// @Param id path int true "Endpoint ID"
+// @Success 200 {object} domain.Endpoint
+// @Failure 400 {object} httperr.HTTPError
+// @Failure 404 {object} httperr.HTTPError
+// @Router /webhooks/endpoints/{id}/rotate-secret [post]
+func (h *Handler) RotateSecret(c *gin.Context) {
+	reqCtx, ok := requestContext(c)
+	if !ok {
+		return
+	}
+	endpointID, ok := parseEndpointID(c)
+	if !ok {
+		return
+	}
+
+	endpoint, err := h.service.RotateSecret(c.Request.Context(), reqCtx.OrganizationID, endpointID)
+	if err != nil {
+		respondError(c, err, "rotate_failed", "Failed to rotate webhook secret")
+		return
+	}
+
+	c.JSON(http.StatusOK, endpoint)
+}
+
+// ListDeliveries lists the deliveries of a webhook endpoint
+// @Summary List webhook deliveries
+// @Description Lists the delivery log of an endpoint, newest first
+// @Tags Webhooks
+// @Produce json
+// @Param id path int true "Endpoint ID"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} services.ListDeliveriesResponse
+// @Failure 400 {object} httperr.HTTPError
+// @Failure 404 {object} httperr.HTTPError
+// @Router /webhooks/endpoints/{id}/deliveries [get]
+func (h *Handler) ListDeliveries(c *gin.Context) {
+	reqCtx, ok := requestContext(c)
+	if !ok {
+		return
+	}
+	endpointID, ok := parseEndpointID(c)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	response, err := h.service.ListDeliveries(c.Request.Context(), reqCtx.OrganizationID, endpointID, &services.ListDeliveriesRequest{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		respondError(c, err, "list_failed", "Failed to list webhook deliveries")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetDelivery returns a webhook delivery with its attempts
+// @Summary Get webhook delivery
+// @Description Returns a delivery together with every attempt made, including response status
+// @Tags Webhooks
+// @Produce json
+// @Param id path int true "Delivery ID"
+// @Success 200 {object} domain.Delivery
+// @Failure 400 {object} httperr.HTTPError
+// @Failure 404 {object} httperr.HTTPError
+// @Router /webhooks/deliveries/{id} [get]
+func (h *Handler) GetDelivery(c *gin.Context) {
+	reqCtx, ok := requestContext(c)
+	if !ok {
+		return
+	}
+	deliveryID, ok := parseDeliveryID(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.service.GetDelivery(c.Request.Context(), reqCtx.OrganizationID, deliveryID)
+	if err != nil {
+		respondError(c, err, "get_failed", "Failed to get webhook delivery")
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
+
+// Redeliver queues a webhook delivery again
+// @Summary Redeliver webhook
+// @Description Queues the delivery for an immediate new attempt with the original payload and webhook-id
+// @Tags Webhooks
+// @Produce json
+// @Param id path int true "Delivery ID"
+// @Success 202 {object} domain.Delivery
+// @Failure 400 {object} httperr.HTTPError
+// @Failure 404 {object} httperr.HTTPError
+// @Router /webhooks/deliveries/{id}/redeliver [post]
+func (h *Handler) Redeliver(c *gin.Context) {
+	reqCtx, ok := requestContext(c)
+	if !ok {
+		return
+	}
+	deliveryID, ok := parseDeliveryID(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.service.Redeliver(c.Request.Context(), reqCtx.OrganizationID, deliveryID)
+	if err != nil {
+		respondError(c, err, "redeliver_failed", "Failed to redeliver webhook")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+func requestContext(c *gin.Context) (*auth.RequestContext, bool) {
+	reqCtx := auth.GetRequestContext(c)
+	if reqCtx == nil {
+		c.JSON(http.StatusBadRequest, httperr.NewHTTPError(
+			http.StatusBadRequest,
+			"missing_context",
+			"Organization context is required",
+		))
+		return nil, false
+	}
+	return reqCtx, true
+}
+
+func parseEndpointID(c *gin.Context) (int32, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, httperr.NewHTTPError(
+			http.StatusBadRequest,
+			"invalid_id",
+			"Endpoint ID must be a valid number",
+		))
+		return 0, false
+	}
+	return int32(id), true
+}
+
+func parseDeliveryID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, httperr.NewHTTPError(
+			http.StatusBadRequest,
+			"invalid_id",
+			"Delivery ID must be a valid number",
+		))
+		return 0, false
+	}
+	return id, true
+}
+
+func respondError(c *gin.Context, err error, code, message string) {
+	switch {
+	case errors.Is(err, domain.ErrEndpointNotFound):
+		c.JSON(http.StatusNotFound, httperr.NewHTTPError(
+			http.StatusNotFound,
+			"endpoint_not_found",
+			"Webhook endpoint not found",
+		))
+	case errors.Is(err, domain.ErrDeliveryNotFound):
+		c.JSON(http.StatusNotFound, httperr.NewHTTPError(
+			http.StatusNotFound,
+			"delivery_not_found",
+			"Webhook delivery not found",
+		))
+	case errors.Is(err, domain.ErrEndpointURLRequired),
+		errors.Is(err, domain.ErrEndpointURLInvalid),
+		errors.Is(err, domain.ErrEndpointURLNotPublic),
+		errors.Is(err, domain.ErrUnsupportedEventType):
+		c.JSON(http.StatusBadRequest, httperr.NewHTTPError(
+			http.StatusBadRequest,
+			"invalid_endpoint",
+			err.Error(),
+		))
+	default:
+		c.JSON(http.StatusInternalServerError, httperr.NewHTTPError(
+			http.StatusInternalServerError,
+			code,
+			message+": "+err.Error(),
+		))
+	}
+}
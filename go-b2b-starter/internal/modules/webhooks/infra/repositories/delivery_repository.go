@@ -0,0 +1,193 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/moasq/go-b2b-starter/internal/db/helpers"
+	sqlc "github.com/moasq/go-b2b-starter/internal/db/postgres/sqlc/gen"
+	"github.com/moasq/go-b2b-starter/internal/modules/webhooks/domain"
+)
+
+// deliveryRepository implements domain.DeliveryRepository using SQLC internally.
+// SQLC types are never exposed outside this package.
+type deliveryRepository struct {
+	store sqlc.Store
+}
+
+// NewDeliveryRepository creates a new DeliveryRepository implementation.
+func NewDeliveryRepository(store sqlc.Store) domain.DeliveryRepository {
+	return &deliveryRepository{store: store}
+}
+
+func (r *deliveryRepository) Create(ctx context.Context, delivery *domain.Delivery) error {
+	params := sqlc.CreateWebhookDeliveryParams{
+		EndpointID:     delivery.EndpointID,
+		OrganizationID: delivery.OrganizationID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Payload:        delivery.Payload,
+	}
+
+	if err := r.store.CreateWebhookDelivery(ctx, params); err != nil {
+		return fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+	return nil
+}
+
+func (r *deliveryRepository) ClaimDue(ctx context.Context, batchSize int32, lease time.Duration) ([]*domain.Delivery, error) {
+	results, err := r.store.ClaimDueWebhookDeliveries(ctx, sqlc.ClaimDueWebhookDeliveriesParams{
+		LeaseSeconds: lease.Seconds(),
+		BatchSize:    batchSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+
+	return mapDeliveriesToDomain(results), nil
+}
+
+func (r *deliveryRepository) MarkSucceeded(ctx context.Context, deliveryID int64, statusCode int32) error {
+	if err := r.store.MarkWebhookDeliverySucceeded(ctx, sqlc.MarkWebhookDeliverySucceededParams{
+		ID:             deliveryID,
+		LastStatusCode: helpers.ToPgInt4(statusCode),
+	}); err != nil {
+		return fmt.Errorf("failed to mark webhook delivery succeeded: %w", err)
+	}
+	return nil
+}
+
+func (r *deliveryRepository) MarkFailed(ctx context.Context, deliveryID int64, status domain.DeliveryStatus, statusCode *int32, lastError string, retryIn time.Duration) error {
+	if err := r.store.MarkWebhookDeliveryFailed(ctx, sqlc.MarkWebhookDeliveryFailedParams{
+		Status:         string(status),
+		LastStatusCode: helpers.ToPgInt4Ptr(statusCode),
+		LastError:      helpers.ToPgText(lastError),
+		RetryInSeconds: retryIn.Seconds(),
+		ID:             deliveryID,
+	}); err != nil {
+		return fmt.Errorf("failed to mark webhook delivery failed: %w", err)
+	}
+	return nil
+}
+
+func (r *deliveryRepository) GetByID(ctx context.Context, orgID int32, deliveryID int64) (*domain.Delivery, error) {
+	result, err := r.store.GetWebhookDeliveryByID(ctx, sqlc.GetWebhookDeliveryByIDParams{
+		ID:             deliveryID,
+		OrganizationID: orgID,
+	})
+	if err != nil {
+		if errors.Is(err, sqlc.ErrRecordNotFound) {
+			return nil, domain.ErrDeliveryNotFound
+		}
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+
+	return mapDeliveryToDomain(&result), nil
+}
+
+func (r *deliveryRepository) ListByEndpoint(ctx context.Context, orgID, endpointID int32, limit, offset int32) ([]*domain.Delivery, error) {
+	results, err := r.store.ListWebhookDeliveriesByEndpoint(ctx, sqlc.ListWebhookDeliveriesByEndpointParams{
+		EndpointID:     endpointID,
+		OrganizationID: orgID,
+		Limit:          limit,
+		Offset:         offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+
+	return mapDeliveriesToDomain(results), nil
+}
+
+func (r *deliveryRepository) Requeue(ctx context.Context, orgID int32, deliveryID int64) (*domain.Delivery, error) {
+	result, err := r.store.RequeueWebhookDelivery(ctx, sqlc.RequeueWebhookDeliveryParams{
+		ID:             deliveryID,
+		OrganizationID: orgID,
+	})
+	if err != nil {
+		if errors.Is(err, sqlc.ErrRecordNotFound) {
+			return nil, domain.ErrDeliveryNotFound
+		}
+		return nil, fmt.Errorf("failed to requeue webhook delivery: %w", err)
+	}
+
+	return mapDeliveryToDomain(&result), nil
+}
+
+func (r *deliveryRepository) RecordAttempt(ctx context.Context, attempt *domain.DeliveryAttempt) error {
+	params := sqlc.CreateWebhookDeliveryAttemptParams{
+		DeliveryID: attempt.DeliveryID,
+		Attempt:    attempt.Attempt,
+		StatusCode: helpers.ToPgInt4Ptr(attempt.StatusCode),
+		Error:      helpers.ToPgText(attempt.Error),
+		DurationMs: attempt.DurationMs,
+	}
+
+	if err := r.store.CreateWebhookDeliveryAttempt(ctx, params); err != nil {
+		return fmt.Errorf("failed to record webhook delivery attempt: %w", err)
+	}
+	return nil
+}
+
+func (r *deliveryRepository) ListAttempts(ctx context.Context, deliveryID int64) ([]*domain.DeliveryAttempt, error) {
+	results, err := r.store.ListWebhookDeliveryAttempts(ctx, deliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook delivery attempts: %w", err)
+	}
+
+	attempts := make([]*domain.DeliveryAttempt, len(results))
+	for i, a := range results {
+		attempts[i] = &domain.DeliveryAttempt{
+			ID:         a.ID,
+			DeliveryID: a.DeliveryID,
+			Attempt:    a.Attempt,
+			StatusCode: fromPgInt4Ptr(a.StatusCode),
+			Error:      helpers.FromPgText(a.Error),
+			DurationMs: a.DurationMs,
+			CreatedAt:  a.CreatedAt.Time,
+		}
+	}
+	return attempts, nil
+}
+
+func mapDeliveryToDomain(d *sqlc.WebhooksDelivery) *domain.Delivery {
+	delivery := &domain.Delivery{
+		ID:             d.ID,
+		EndpointID:     d.EndpointID,
+		OrganizationID: d.OrganizationID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Payload:        d.Payload,
+		Status:         domain.DeliveryStatus(d.Status),
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt.Time,
+		LastStatusCode: fromPgInt4Ptr(d.LastStatusCode),
+		LastError:      helpers.FromPgText(d.LastError),
+		CreatedAt:      d.CreatedAt.Time,
+		UpdatedAt:      d.UpdatedAt.Time,
+	}
+	if d.DeliveredAt.Valid {
+		deliveredAt := d.DeliveredAt.Time
+		delivery.DeliveredAt = &deliveredAt
+	}
+	return delivery
+}
+
+func mapDeliveriesToDomain(results []sqlc.WebhooksDelivery) []*domain.Delivery {
+	deliveries := make([]*domain.Delivery, len(results))
+	for i := range results {
+		deliveries[i] = mapDeliveryToDomain(&results[i])
+	}
+	return deliveries
+}
+
+func fromPgInt4Ptr(i pgtype.Int4) *int32 {
+	if !i.Valid {
+		return nil
+	}
+	value := i.Int32
+	return &value
+}
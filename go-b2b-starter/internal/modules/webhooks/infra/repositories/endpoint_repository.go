@@ -0,0 +1,152 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sqlc "github.com/moasq/go-b2b-starter/internal/db/postgres/sqlc/gen"
+	"github.com/moasq/go-b2b-starter/internal/modules/webhooks/domain"
+)
+
+// endpointRepository implements domain.EndpointRepository using SQLC internally.
+// SQLC types are never exposed outside this package.
+type endpointRepository struct {
+	store sqlc.Store
+}
+
+// NewEndpointRepository creates a new EndpointRepository implementation.
+func NewEndpointRepository(store sqlc.Store) domain.EndpointRepository {
+	return &endpointRepository{store: store}
+}
+
+func (r *endpointRepository) Create(ctx context.Context, endpoint *domain.Endpoint) (*domain.Endpoint, error) {
+	params := sqlc.CreateWebhookEndpointParams{
+		OrganizationID: endpoint.OrganizationID,
+		Url:            endpoint.URL,
+		Description:    endpoint.Description,
+		EventTypes:     nonNilStrings(endpoint.EventTypes),
+		Secret:         endpoint.Secret,
+		Enabled:        endpoint.Enabled,
+	}
+
+	result, err := r.store.CreateWebhookEndpoint(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook endpoint: %w", err)
+	}
+
+	return mapEndpointToDomain(&result), nil
+}
+
+func (r *endpointRepository) GetByID(ctx context.Context, orgID, endpointID int32) (*domain.Endpoint, error) {
+	result, err := r.store.GetWebhookEndpointByID(ctx, sqlc.GetWebhookEndpointByIDParams{
+		ID:             endpointID,
+		OrganizationID: orgID,
+	})
+	if err != nil {
+		if errors.Is(err, sqlc.ErrRecordNotFound) {
+			return nil, domain.ErrEndpointNotFound
+		}
+		return nil, fmt.Errorf("failed to get webhook endpoint: %w", err)
+	}
+
+	return mapEndpointToDomain(&result), nil
+}
+
+func (r *endpointRepository) List(ctx context.Context, orgID int32) ([]*domain.Endpoint, error) {
+	results, err := r.store.ListWebhookEndpointsByOrganization(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook endpoints: %w", err)
+	}
+
+	return mapEndpointsToDomain(results), nil
+}
+
+func (r *endpointRepository) ListForEvent(ctx context.Context, orgID int32, eventType string) ([]*domain.Endpoint, error) {
+	results, err := r.store.ListWebhookEndpointsForEvent(ctx, sqlc.ListWebhookEndpointsForEventParams{
+		OrganizationID: orgID,
+		EventType:      eventType,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook endpoints for event: %w", err)
+	}
+
+	return mapEndpointsToDomain(results), nil
+}
+
+func (r *endpointRepository) Update(ctx context.Context, endpoint *domain.Endpoint) (*domain.Endpoint, error) {
+	params := sqlc.UpdateWebhookEndpointParams{
+		ID:             endpoint.ID,
+		OrganizationID: endpoint.OrganizationID,
+		Url:            endpoint.URL,
+		Description:    endpoint.Description,
+		EventTypes:     nonNilStrings(endpoint.EventTypes),
+		Enabled:        endpoint.Enabled,
+	}
+
+	result, err := r.store.UpdateWebhookEndpoint(ctx, params)
+	if err != nil {
+		if errors.Is(err, sqlc.ErrRecordNotFound) {
+			return nil, domain.ErrEndpointNotFound
+		}
+		return nil, fmt.Errorf("failed to update webhook endpoint: %w", err)
+	}
+
+	return mapEndpointToDomain(&result), nil
+}
+
+func (r *endpointRepository) UpdateSecret(ctx context.Context, orgID, endpointID int32, secret string) (*domain.Endpoint, error) {
+	result, err := r.store.UpdateWebhookEndpointSecret(ctx, sqlc.UpdateWebhookEndpointSecretParams{
+		ID:             endpointID,
+		OrganizationID: orgID,
+		Secret:         secret,
+	})
+	if err != nil {
+		if errors.Is(err, sqlc.ErrRecordNotFound) {
+			return nil, domain.ErrEndpointNotFound
+		}
+		return nil, fmt.Errorf("failed to update webhook endpoint secret: %w", err)
+	}
+
+	return mapEndpointToDomain(&result), nil
+}
+
+func (r *endpointRepository) Delete(ctx context.Context, orgID, endpointID int32) error {
+	if err := r.store.DeleteWebhookEndpoint(ctx, sqlc.DeleteWebhookEndpointParams{
+		ID:             endpointID,
+		OrganizationID: orgID,
+	}); err != nil {
+		return fmt.Errorf("failed to delete webhook endpoint: %w", err)
+	}
+	return nil
+}
+
+func mapEndpointToDomain(e *sqlc.WebhooksEndpoint) *domain.Endpoint {
+	return &domain.Endpoint{
+		ID:             e.ID,
+		OrganizationID: e.OrganizationID,
+		URL:            e.Url,
+		Description:    e.Description,
+		EventTypes:     nonNilStrings(e.EventTypes),
+		Secret:         e.Secret,
+		Enabled:        e.Enabled,
+		CreatedAt:      e.CreatedAt.Time,
+		UpdatedAt:      e.UpdatedAt.Time,
+	}
+}
+
+func mapEndpointsToDomain(results []sqlc.WebhooksEndpoint) []*domain.Endpoint {
+	endpoints := make([]*domain.Endpoint, len(results))
+	for i := range results {
+		endpoints[i] = mapEndpointToDomain(&results[i])
+	}
+	return endpoints
+}
+
+// nonNilStrings keeps TEXT[] columns from being written as NULL
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
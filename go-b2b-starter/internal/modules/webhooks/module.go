@@ -0,0 +1,68 @@
+package webhooks
+
+import (
+	"go.uber.org/dig"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/webhooks/app/services"
+	"github.com/moasq/go-b2b-starter/internal/modules/webhooks/config"
+	"github.com/moasq/go-b2b-starter/internal/modules/webhooks/domain"
+	"github.com/moasq/go-b2b-starter/internal/platform/logger"
+)
+
+// Module provides webhooks module dependencies
+type Module struct {
+	container *dig.Container
+}
+
+func NewModule(container *dig.Container) *Module {
+	return &Module{
+		container: container,
+	}
+}
+
+// RegisterDependencies registers all webhooks module dependencies
+// Note: Repository implementations are registered in internal/db/inject.go
+func (m *Module) RegisterDependencies() error {
+	// Register configuration
+	if err := m.container.Provide(config.LoadConfig); err != nil {
+		return err
+	}
+
+	// Register delivery dispatcher
+	if err := m.container.Provide(func(
+		endpointRepo domain.EndpointRepository,
+		deliveryRepo domain.DeliveryRepository,
+		cfg config.Config,
+		logger logger.Logger,
+	) services.Dispatcher {
+		return services.NewDispatcher(endpointRepo, deliveryRepo, cfg, logger)
+	}); err != nil {
+		return err
+	}
+
+	// Register event fan-out
+	if err := m.container.Provide(func(
+		endpointRepo domain.EndpointRepository,
+		deliveryRepo domain.DeliveryRepository,
+		dispatcher services.Dispatcher,
+		logger logger.Logger,
+	) services.EventFanout {
+		return services.NewEventFanout(endpointRepo, deliveryRepo, dispatcher, logger)
+	}); err != nil {
+		return err
+	}
+
+	// Register webhook service
+	if err := m.container.Provide(func(
+		endpointRepo domain.EndpointRepository,
+		deliveryRepo domain.DeliveryRepository,
+		dispatcher services.Dispatcher,
+		cfg config.Config,
+	) services.WebhookService {
+		return services.NewWebhookService(endpointRepo, deliveryRepo, dispatcher, cfg)
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
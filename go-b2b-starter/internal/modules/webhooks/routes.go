@@ -0,0 +1,46 @@
+package webhooks
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/moasq/go-b2b-starter/internal/modules/auth"
+	serverDomain "github.com/moasq/go-b2b-starter/internal/platform/server/domain"
+)
+
+type Routes struct {
+	handler *Handler
+}
+
+func NewRoutes(handler *Handler) *Routes {
+	return &Routes{
+		handler: handler,
+	}
+}
+
+func (r *Routes) RegisterRoutes(router *gin.RouterGroup, resolver serverDomain.MiddlewareResolver) {
+	webhooksGroup := router.Group("/webhooks")
+	webhooksGroup.Use(
+		resolver.Get("auth"),
+		resolver.Get("org_context"),
+		auth.RequirePermissionFunc("org", "manage"),
+	)
+	{
+		// Endpoints
+		webhooksGroup.POST("/endpoints", r.handler.CreateEndpoint)
+		webhooksGroup.GET("/endpoints", r.handler.ListEndpoints)
+		webhooksGroup.GET("/endpoints/:id", r.handler.GetEndpoint)
+		webhooksGroup.PATCH("/endpoints/:id", r.handler.UpdateEndpoint)
+		webhooksGroup.DELETE("/endpoints/:id", r.handler.DeleteEndpoint)
+		webhooksGroup.POST("/endpoints/:id/rotate-secret", r.handler.RotateSecret)
+
+		// Delivery log
+		webhooksGroup.GET("/endpoints/:id/deliveries", r.handler.ListDeliveries)
+		webhooksGroup.GET("/deliveries/:id", r.handler.GetDelivery)
+		webhooksGroup.POST("/deliveries/:id/redeliver", r.handler.Redeliver)
+	}
+}
+
+// Routes returns a RouteRegistrar function compatible with the server interface
+func (r *Routes) Routes(router *gin.RouterGroup, resolver serverDomain.MiddlewareResolver) {
+	r.RegisterRoutes(router, resolver)
+}
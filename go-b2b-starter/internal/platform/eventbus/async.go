@@ -0,0 +1,353 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/db/core"
+)
+
+// ErrQueueFull is returned by PublishAsync when an event type's queue stays
+// full for longer than the enqueue timeout
+var ErrQueueFull = errors.New("event queue is full")
+
+// PoolConfig sizes the worker pool of one event type
+type PoolConfig struct {
+	// Workers is the number of handlers running concurrently for the event type
+	Workers int
+	// QueueSize is the number of events buffered before PublishAsync blocks
+	QueueSize int
+}
+
+// AsyncConfig configures PublishAsync worker pools
+type AsyncConfig struct {
+	// Default is used for event types without an entry in Pools
+	Default PoolConfig
+	// Pools overrides pool sizing per event name
+	Pools map[string]PoolConfig
+	// EnqueueTimeout is how long PublishAsync waits for queue space before
+	// returning ErrQueueFull; zero waits until the context is done
+	EnqueueTimeout time.Duration
+	// DrainTimeout bounds how long Close waits for queued events to be handled
+	DrainTimeout time.Duration
+	// PartitionLanes is the number of serial workers shared by all events
+	// that carry a partition key; events with the same key always use the
+	// same lane, so they are handled one at a time and in publish order
+	PartitionLanes int
+	// LaneQueueSize is the queue depth of each partition lane
+	LaneQueueSize int
+	// OnError is called when an asynchronously handled event fails; optional
+	OnError func(event Event, err error)
+}
+
+// DefaultAsyncConfig returns small pools suitable for a single instance
+func DefaultAsyncConfig() AsyncConfig {
+	return AsyncConfig{
+		Default:        PoolConfig{Workers: 4, QueueSize: 100},
+		PartitionLanes: 8,
+		LaneQueueSize:  100,
+		EnqueueTimeout: 5 * time.Second,
+		DrainTimeout:   30 * time.Second,
+	}
+}
+
+// poolFor returns the sizing for an event type
+func (c AsyncConfig) poolFor(eventName string) PoolConfig {
+	pool, ok := c.Pools[eventName]
+	if !ok {
+		pool = c.Default
+	}
+	if pool.Workers < 1 {
+		pool.Workers = 1
+	}
+	if pool.QueueSize < 0 {
+		pool.QueueSize = 0
+	}
+	return pool
+}
+
+// ParsePoolOverrides parses "event.name=workers:queue" pairs separated by
+// commas, e.g. "document.uploaded=2:50,document.failed=1:10".
+func ParsePoolOverrides(spec string) (map[string]PoolConfig, error) {
+	pools := make(map[string]PoolConfig)
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return pools, nil
+	}
+
+	for _, entry := range strings.Split(spec, ",") {
+		name, sizing, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid pool override %q, expected event.name=workers:queue", entry)
+		}
+
+		workersStr, queueStr, ok := strings.Cut(sizing, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid pool override %q, expected event.name=workers:queue", entry)
+		}
+
+		workers, err := strconv.Atoi(workersStr)
+		if err != nil || workers < 1 {
+			return nil, fmt.Errorf("invalid worker count in pool override %q", entry)
+		}
+		queue, err := strconv.Atoi(queueStr)
+		if err != nil || queue < 0 {
+			return nil, fmt.Errorf("invalid queue size in pool override %q", entry)
+		}
+
+		pools[name] = PoolConfig{Workers: workers, QueueSize: queue}
+	}
+
+	return pools, nil
+}
+
+// asyncJob is a queued event together with the publisher's context values
+type asyncJob struct {
+	ctx   context.Context
+	event Event
+}
+
+// workerPool runs the handlers of one event type
+type workerPool struct {
+	queue chan asyncJob
+	wg    sync.WaitGroup
+
+	// done is closed on shutdown; the queue itself is never closed, so a
+	// sender blocked on a full queue cannot panic and is released instead
+	done chan struct{}
+	// senders counts enqueues past the closed check; workers wait for them
+	// before the final drain so no accepted event is left behind
+	senders sync.WaitGroup
+}
+
+func newWorkerPool(queueSize int) *workerPool {
+	return &workerPool{
+		queue: make(chan asyncJob, queueSize),
+		done:  make(chan struct{}),
+	}
+}
+
+// asyncDispatcher owns the per-event-type worker pools and the partition
+// lanes of a bus
+type asyncDispatcher struct {
+	config   AsyncConfig
+	dispatch func(ctx context.Context, event Event) error
+
+	// mu guards pools, lanes and closed. It is never held while blocking on
+	// a queue, so a full queue cannot stall other publishers or Close
+	mu     sync.RWMutex
+	pools  map[string]*workerPool
+	lanes  []*workerPool
+	closed bool
+}
+
+func newAsyncDispatcher(config AsyncConfig, dispatch func(ctx context.Context, event Event) error) *asyncDispatcher {
+	return &asyncDispatcher{
+		config:   config,
+		dispatch: dispatch,
+		pools:    make(map[string]*workerPool),
+	}
+}
+
+// enqueue hands the event to its worker pool, or to its partition lane when
+// it carries a partition key, blocking while the queue is full
+func (d *asyncDispatcher) enqueue(ctx context.Context, event Event) error {
+	var (
+		pool *workerPool
+		err  error
+	)
+	if key := PartitionKeyOf(event); key != "" {
+		pool, err = d.lane(key)
+	} else {
+		pool, err = d.pool(event.EventName())
+	}
+	if err != nil {
+		return err
+	}
+
+	d.mu.RLock()
+	if d.closed {
+		d.mu.RUnlock()
+		return fmt.Errorf("event bus is closed")
+	}
+	pool.senders.Add(1)
+	d.mu.RUnlock()
+	defer pool.senders.Done()
+
+	// Handlers outlive the request that published the event, and its
+	// transaction, which may be committed or still in use by then
+	job := asyncJob{ctx: core.ContextWithTransaction(context.WithoutCancel(ctx), nil), event: event}
+
+	// Fast path: queue has room
+	select {
+	case pool.queue <- job:
+		asyncQueueDepth.WithLabelValues(event.EventName()).Inc()
+		return nil
+	default:
+	}
+
+	var timeout <-chan time.Time
+	if d.config.EnqueueTimeout > 0 {
+		timer := time.NewTimer(d.config.EnqueueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case pool.queue <- job:
+		asyncQueueDepth.WithLabelValues(event.EventName()).Inc()
+		return nil
+	case <-pool.done:
+		return fmt.Errorf("event bus is closed")
+	case <-timeout:
+		return fmt.Errorf("%w: %s", ErrQueueFull, event.EventName())
+	case <-ctx.Done():
+		return fmt.Errorf("failed to enqueue event %s: %w", event.EventName(), ctx.Err())
+	}
+}
+
+// pool returns the worker pool of an event type, starting it on first use
+func (d *asyncDispatcher) pool(eventName string) (*workerPool, error) {
+	d.mu.RLock()
+	pool, ok := d.pools[eventName]
+	d.mu.RUnlock()
+	if ok {
+		return pool, nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return nil, fmt.Errorf("event bus is closed")
+	}
+	if pool, ok := d.pools[eventName]; ok {
+		return pool, nil
+	}
+
+	sizing := d.config.poolFor(eventName)
+	pool = newWorkerPool(sizing.QueueSize)
+	d.pools[eventName] = pool
+
+	for i := 0; i < sizing.Workers; i++ {
+		pool.wg.Add(1)
+		go d.work(pool)
+	}
+
+	return pool, nil
+}
+
+// lane returns the serial worker responsible for a partition key, starting
+// the lanes on first use
+func (d *asyncDispatcher) lane(key string) (*workerPool, error) {
+	d.mu.RLock()
+	lanes := d.lanes
+	d.mu.RUnlock()
+
+	if lanes == nil {
+		d.mu.Lock()
+		if d.closed {
+			d.mu.Unlock()
+			return nil, fmt.Errorf("event bus is closed")
+		}
+		if d.lanes == nil {
+			count := d.config.PartitionLanes
+			if count < 1 {
+				count = 1
+			}
+			queueSize := d.config.LaneQueueSize
+			if queueSize < 0 {
+				queueSize = 0
+			}
+
+			d.lanes = make([]*workerPool, count)
+			for i := range d.lanes {
+				// One worker per lane keeps events of a key strictly ordered
+				lane := newWorkerPool(queueSize)
+				lane.wg.Add(1)
+				go d.work(lane)
+				d.lanes[i] = lane
+			}
+		}
+		lanes = d.lanes
+		d.mu.Unlock()
+	}
+
+	return lanes[laneFor(key, len(lanes))], nil
+}
+
+func (d *asyncDispatcher) work(pool *workerPool) {
+	defer pool.wg.Done()
+
+	for {
+		select {
+		case job := <-pool.queue:
+			d.handle(job)
+		case <-pool.done:
+			// Let in-flight senders finish, then drain what was accepted
+			pool.senders.Wait()
+			for {
+				select {
+				case job := <-pool.queue:
+					d.handle(job)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (d *asyncDispatcher) handle(job asyncJob) {
+	asyncQueueDepth.WithLabelValues(job.event.EventName()).Dec()
+	if err := d.dispatch(job.ctx, job.event); err != nil && d.config.OnError != nil {
+		d.config.OnError(job.event, err)
+	}
+}
+
+// close stops accepting events and waits up to DrainTimeout for queued
+// events to be handled
+func (d *asyncDispatcher) close() error {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return nil
+	}
+	d.closed = true
+
+	pools := make([]*workerPool, 0, len(d.pools)+len(d.lanes))
+	for _, pool := range d.pools {
+		close(pool.done)
+		pools = append(pools, pool)
+	}
+	for _, lane := range d.lanes {
+		close(lane.done)
+		pools = append(pools, lane)
+	}
+	d.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		for _, pool := range pools {
+			pool.wg.Wait()
+		}
+		close(drained)
+	}()
+
+	if d.config.DrainTimeout <= 0 {
+		<-drained
+		return nil
+	}
+
+	select {
+	case <-drained:
+		return nil
+	case <-time.After(d.config.DrainTimeout):
+		return fmt.Errorf("timed out after %s draining async event queues", d.config.DrainTimeout)
+	}
+}
@@ -5,12 +5,17 @@ import (
 	"fmt"
 	"reflect"
 	"sync"
+
+	"github.com/moasq/go-b2b-starter/internal/db/core"
 )
 
 // EventBus handles publishing and subscribing to events
 type EventBus interface {
 	// Publish publishes an event to all subscribers
 	Publish(ctx context.Context, event Event) error
+	// PublishAsync enqueues an event and returns without waiting for handlers.
+	// It blocks (backpressure) while the event type's queue is full.
+	PublishAsync(ctx context.Context, event Event) error
 	// Subscribe registers a handler for a specific event type
 	Subscribe(eventName string, handler EventHandler[Event]) error
 	// Unsubscribe removes a handler for a specific event type
@@ -19,31 +24,144 @@ type EventBus interface {
 	Close() error
 }
 
+// namedSubscriber is implemented by buses that label handlers by name in
+// logs and metrics
+type namedSubscriber interface {
+	SubscribeNamed(eventName, handlerName string, handler EventHandler[Event]) error
+}
+
+// SubscribeNamed subscribes a handler under an explicit name, reported by
+// HandlerNameFromContext. Buses without named subscriptions fall back to
+// Subscribe.
+func SubscribeNamed(bus EventBus, eventName, handlerName string, handler EventHandler[Event]) error {
+	if named, ok := bus.(namedSubscriber); ok {
+		return named.SubscribeNamed(eventName, handlerName, handler)
+	}
+	return bus.Subscribe(eventName, handler)
+}
+
+// subscription is a handler together with its name
+type subscription struct {
+	name    string
+	handler EventHandler[Event]
+}
+
 // InMemoryEventBus is an in-memory implementation of EventBus
 type InMemoryEventBus struct {
 	mu          sync.RWMutex
-	subscribers map[string][]EventHandler[Event]
+	subscribers map[string][]subscription
 	middleware  []EventMiddleware
 	closed      bool
+	async       *asyncDispatcher
+	keys        *keyedMutex
 }
 
 func NewInMemoryEventBus(middleware ...EventMiddleware) EventBus {
-	return &InMemoryEventBus{
-		subscribers: make(map[string][]EventHandler[Event]),
+	return NewInMemoryEventBusWithConfig(DefaultAsyncConfig(), middleware...)
+}
+
+// NewInMemoryEventBusWithConfig creates an in-memory bus whose PublishAsync
+// worker pools are sized by asyncConfig
+func NewInMemoryEventBusWithConfig(asyncConfig AsyncConfig, middleware ...EventMiddleware) *InMemoryEventBus {
+	bus := &InMemoryEventBus{
+		subscribers: make(map[string][]subscription),
 		middleware:  middleware,
 		closed:      false,
+		keys:        newKeyedMutex(),
 	}
+	bus.async = newAsyncDispatcher(asyncConfig, bus.dispatch)
+	return bus
 }
 
 // Publish publishes an event to all registered handlers
 func (bus *InMemoryEventBus) Publish(ctx context.Context, event Event) error {
 	bus.mu.RLock()
-	if bus.closed {
-		bus.mu.RUnlock()
+	closed := bus.closed
+	bus.mu.RUnlock()
+	if closed {
 		return fmt.Errorf("event bus is closed")
 	}
 
-	handlers := make([]EventHandler[Event], len(bus.subscribers[event.EventName()]))
+	if err := defaultRegistry.Validate(event); err != nil {
+		return err
+	}
+
+	stampCorrelation(ctx, event)
+
+	// Inside a transaction the handlers run once it commits, so they never
+	// act on data that is not visible yet or is rolled back
+	if _, ok := core.TransactionFromContext(ctx); ok {
+		core.AfterCommit(ctx, func() {
+			recordPublished(event)
+			if err := bus.dispatch(ctx, event); err != nil {
+				bus.reportError(event, err)
+			}
+		})
+		return nil
+	}
+
+	recordPublished(event)
+	return bus.dispatch(ctx, event)
+}
+
+// PublishAsync enqueues the event on its event type's worker pool.
+// Handler errors are reported through AsyncConfig.OnError.
+//
+// Inside a transaction the event is enqueued once it commits; enqueue
+// failures are then reported through AsyncConfig.OnError as well.
+func (bus *InMemoryEventBus) PublishAsync(ctx context.Context, event Event) error {
+	if err := defaultRegistry.Validate(event); err != nil {
+		return err
+	}
+
+	stampCorrelation(ctx, event)
+
+	if _, ok := core.TransactionFromContext(ctx); ok {
+		core.AfterCommit(ctx, func() {
+			if err := bus.enqueue(ctx, event); err != nil {
+				bus.reportError(event, err)
+			}
+		})
+		return nil
+	}
+
+	return bus.enqueue(ctx, event)
+}
+
+// enqueue hands the event to the async dispatcher and counts it as published
+func (bus *InMemoryEventBus) enqueue(ctx context.Context, event Event) error {
+	if err := bus.async.enqueue(ctx, event); err != nil {
+		return err
+	}
+	recordPublished(event)
+	return nil
+}
+
+// reportError passes the error of an event published after a commit to
+// AsyncConfig.OnError, as there is no caller left to return it to
+func (bus *InMemoryEventBus) reportError(event Event, err error) {
+	if bus.async.config.OnError != nil {
+		bus.async.config.OnError(event, err)
+	}
+}
+
+// dispatch runs all handlers of the event and waits for them. Events with a
+// partition key are dispatched one at a time per key. Handlers run with the
+// event's correlation (see Correlation) and their name in the context.
+//
+// Handlers run outside the publisher's transaction: they run concurrently and
+// may outlive it, and a pgx transaction is not safe for concurrent use.
+func (bus *InMemoryEventBus) dispatch(ctx context.Context, event Event) error {
+	ctx = core.ContextWithTransaction(ctx, nil)
+
+	if key := PartitionKeyOf(event); key != "" {
+		var unlock func()
+		ctx, unlock = bus.keys.lockPartition(ctx, key)
+		defer unlock()
+	}
+
+	bus.mu.RLock()
+	handlers := make([]subscription, len(bus.subscribers[event.EventName()]))
 	copy(handlers, bus.subscribers[event.EventName()])
 	bus.mu.RUnlock()
 
@@ -51,22 +169,24 @@ func (bus *InMemoryEventBus) Publish(ctx context.Context, event Event) error {
 		return nil
 	}
 
+	ctx = handlerContext(ctx, event)
+
 	// Execute handlers concurrently
 	var wg sync.WaitGroup
 	errCh := make(chan error, len(handlers))
 
 	for i, handler := range handlers {
 		wg.Add(1)
-		go func(handlerIndex int, h EventHandler[Event]) {
+		go func(handlerIndex int, sub subscription) {
 			defer wg.Done()
 
 			// Apply middleware chain
-			finalHandler := h
+			finalHandler := sub.handler
 			for i := len(bus.middleware) - 1; i >= 0; i-- {
 				finalHandler = bus.middleware[i](finalHandler)
 			}
 
-			if err := finalHandler(ctx, event); err != nil {
+			if err := finalHandler(withHandlerName(ctx, sub.name), event); err != nil {
 				errCh <- fmt.Errorf("handler error for event %s: %w", event.EventName(), err)
 			}
 		}(i, handler)
@@ -89,8 +209,18 @@ func (bus *InMemoryEventBus) Publish(ctx context.Context, event Event) error {
 	return nil
 }
 
-// Subscribe registers a handler for a specific event type
+// Subscribe registers a handler for a specific event type, named after its
+// function. The event name must be registered (see Register).
 func (bus *InMemoryEventBus) Subscribe(eventName string, handler EventHandler[Event]) error {
+	return bus.SubscribeNamed(eventName, funcName(handler), handler)
+}
+
+// SubscribeNamed registers a handler under an explicit name
+func (bus *InMemoryEventBus) SubscribeNamed(eventName, handlerName string, handler EventHandler[Event]) error {
+	if !defaultRegistry.IsRegistered(eventName) {
+		return fmt.Errorf("cannot subscribe to %s: %w", eventName, ErrUnregisteredEvent)
+	}
+
 	bus.mu.Lock()
 	defer bus.mu.Unlock()
 
@@ -98,7 +228,7 @@ func (bus *InMemoryEventBus) Subscribe(eventName string, handler EventHandler[Ev
 		return fmt.Errorf("event bus is closed")
 	}
 
-	bus.subscribers[eventName] = append(bus.subscribers[eventName], handler)
+	bus.subscribers[eventName] = append(bus.subscribers[eventName], subscription{name: handlerName, handler: handler})
 
 	return nil
 }
@@ -109,9 +239,9 @@ func (bus *InMemoryEventBus) Unsubscribe(eventName string, handler EventHandler[
 	defer bus.mu.Unlock()
 
 	handlers := bus.subscribers[eventName]
-	for i, h := range handlers {
+	for i, sub := range handlers {
 		// Compare function pointers
-		if reflect.ValueOf(h).Pointer() == reflect.ValueOf(handler).Pointer() {
+		if reflect.ValueOf(sub.handler).Pointer() == reflect.ValueOf(handler).Pointer() {
 			bus.subscribers[eventName] = append(handlers[:i], handlers[i+1:]...)
 			break
 		}
@@ -120,14 +250,16 @@ func (bus *InMemoryEventBus) Unsubscribe(eventName string, handler EventHandler[
 	return nil
 }
 
-// Close gracefully shuts down the event bus
+// Close gracefully shuts down the event bus, draining queued async events first
 func (bus *InMemoryEventBus) Close() error {
+	drainErr := bus.async.close()
+
 	bus.mu.Lock()
 	defer bus.mu.Unlock()
 
 	bus.closed = true
-	bus.subscribers = make(map[string][]EventHandler[Event])
-	return nil
+	bus.subscribers = make(map[string][]subscription)
+	return drainErr
 }
 
 // GetSubscriberCount returns the number of subscribers for an event (for testing/debugging)
@@ -1,11 +1,52 @@
 package cmd
 
-import "go.uber.org/dig"
+import (
+	"fmt"
+
+	"go.uber.org/dig"
+
+	"github.com/moasq/go-b2b-starter/internal/platform/eventbus"
+)
 
 func Init(container *dig.Container) error {
 	if err := ProvideEventBus(container); err != nil {
 		return err
 	}
+
+	if err := ProvideDeadLetterQueue(container); err != nil {
+		return err
+	}
 	
 	return nil
-}
\ No newline at end of file
+}
+
+// starter is implemented by event bus backends that run background workers
+type starter interface {
+	Start() error
+}
+
+// Start launches background delivery for backends that need it (e.g. the
+// outbox relay). Call it after all modules have registered their subscribers.
+func Start(container *dig.Container) error {
+	return container.Invoke(func(bus eventbus.EventBus) error {
+		s, ok := bus.(starter)
+		if !ok {
+			return nil
+		}
+		if err := s.Start(); err != nil {
+			return fmt.Errorf("failed to start event bus: %w", err)
+		}
+		return nil
+	})
+}
+
+// Close shuts the event bus down, draining queued events. Call it after the
+// HTTP server has stopped accepting requests.
+func Close(container *dig.Container) error {
+	return container.Invoke(func(bus eventbus.EventBus) error {
+		if err := bus.Close(); err != nil {
+			return fmt.Errorf("failed to close event bus: %w", err)
+		}
+		return nil
+	})
+}
@@ -1,21 +1,81 @@
 package cmd
 
 import (
+	"fmt"
+
 	"go.uber.org/dig"
-	
+
+	"github.com/moasq/go-b2b-starter/internal/db/core"
 	"github.com/moasq/go-b2b-starter/internal/platform/eventbus"
 	"github.com/moasq/go-b2b-starter/internal/platform/logger/domain"
+	"github.com/moasq/go-b2b-starter/internal/platform/redis"
 )
 
-// ProvideEventBus creates and configures the event bus with middleware
+// ProvideEventBus creates and configures the event bus with middleware.
+// The backend is selected with EVENTBUS_BACKEND ("memory", "outbox" or "redis").
 func ProvideEventBus(container *dig.Container) error {
-	return container.Provide(func(logger domain.Logger) eventbus.EventBus {
-		middleware := []eventbus.EventMiddleware{
-			eventbus.RecoveryMiddleware(logger),
-			eventbus.LoggingMiddleware(logger),
-			eventbus.MetricsMiddleware(),
+	if err := container.Provide(eventbus.LoadConfig); err != nil {
+		return fmt.Errorf("failed to provide eventbus config: %w", err)
+	}
+
+	if err := container.Provide(func(pool core.Pool) eventbus.EventStore {
+		return eventbus.NewPostgresEventStore(pool)
+	}); err != nil {
+		return fmt.Errorf("failed to provide event store: %w", err)
+	}
+
+	if err := container.Provide(eventbus.NewReplayer); err != nil {
+		return fmt.Errorf("failed to provide event replayer: %w", err)
+	}
+
+	return container.Provide(func(
+		cfg eventbus.Config,
+		pool core.Pool,
+		streams redis.StreamClient,
+		store eventbus.EventStore,
+		logger domain.Logger,
+	) eventbus.EventBus {
+		bus := newBackend(cfg, pool, streams, logger)
+		if cfg.StoreEnabled {
+			return eventbus.NewRecordingEventBus(bus, store)
 		}
-		
-		return eventbus.NewInMemoryEventBus(middleware...)
+		return bus
+	})
+}
+
+// newBackend builds the EventBus implementation selected in configuration
+func newBackend(cfg eventbus.Config, pool core.Pool, streams redis.StreamClient, logger domain.Logger) eventbus.EventBus {
+	middleware := []eventbus.EventMiddleware{
+		eventbus.RecoveryMiddleware(logger),
+		eventbus.LoggingMiddleware(logger),
+		eventbus.MetricsMiddleware(),
+	}
+
+	switch cfg.Backend {
+	case eventbus.BackendOutbox:
+		return eventbus.NewOutboxEventBus(pool, cfg, logger, middleware...)
+	case eventbus.BackendRedis:
+		return eventbus.NewRedisStreamEventBus(streams, cfg, logger, middleware...)
+	}
+
+	return eventbus.NewInMemoryEventBusWithConfig(cfg.AsyncConfig(logger), middleware...)
+}
+
+// ProvideDeadLetterQueue registers the dead-letter store and the queue used to
+// subscribe handlers with retry policies
+func ProvideDeadLetterQueue(container *dig.Container) error {
+	if err := container.Provide(func(pool core.Pool) eventbus.DeadLetterStore {
+		return eventbus.NewPostgresDeadLetterStore(pool)
+	}); err != nil {
+		return fmt.Errorf("failed to provide dead letter store: %w", err)
+	}
+
+	return container.Provide(func(
+		cfg eventbus.Config,
+		bus eventbus.EventBus,
+		store eventbus.DeadLetterStore,
+		logger domain.Logger,
+	) *eventbus.DeadLetterQueue {
+		return eventbus.NewDeadLetterQueue(bus, store, logger, cfg.RetryPolicy())
 	})
-}
\ No newline at end of file
+}
@@ -0,0 +1,227 @@
+package eventbus
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/moasq/go-b2b-starter/internal/platform/logger/domain"
+)
+
+// Supported event bus backends
+const (
+	// BackendMemory dispatches events in-process; events are lost on crash/restart
+	BackendMemory = "memory"
+	// BackendOutbox persists events to Postgres and dispatches them from a relay worker
+	BackendOutbox = "outbox"
+	// BackendRedis delivers events across instances through a Redis stream consumer group
+	BackendRedis = "redis"
+)
+
+type Config struct {
+	// Backend selects the EventBus implementation ("memory", "outbox" or "redis")
+	Backend string `mapstructure:"EVENTBUS_BACKEND"`
+
+	// StoreEnabled records every published event in the append-only event store
+	StoreEnabled bool `mapstructure:"EVENTBUS_STORE_ENABLED"`
+
+	// OutboxPollInterval is how often the relay polls for pending events
+	OutboxPollInterval time.Duration `mapstructure:"EVENTBUS_OUTBOX_POLL_INTERVAL"`
+	// OutboxBatchSize is the maximum number of events claimed per poll
+	OutboxBatchSize int `mapstructure:"EVENTBUS_OUTBOX_BATCH_SIZE"`
+	// OutboxLeaseDuration is how long a claimed event is hidden from other relays
+	OutboxLeaseDuration time.Duration `mapstructure:"EVENTBUS_OUTBOX_LEASE_DURATION"`
+	// OutboxHandlerTimeout bounds the time handlers get to process one event
+	OutboxHandlerTimeout time.Duration `mapstructure:"EVENTBUS_OUTBOX_HANDLER_TIMEOUT"`
+	// OutboxMaxBackoff caps the delay between delivery attempts of a failing event
+	OutboxMaxBackoff time.Duration `mapstructure:"EVENTBUS_OUTBOX_MAX_BACKOFF"`
+
+	// RedisStream is the stream key events are appended to
+	RedisStream string `mapstructure:"EVENTBUS_REDIS_STREAM"`
+	// RedisGroup is the consumer group shared by all replicas
+	RedisGroup string `mapstructure:"EVENTBUS_REDIS_GROUP"`
+	// RedisConsumer names this replica within the group; defaults to hostname-pid
+	RedisConsumer string `mapstructure:"EVENTBUS_REDIS_CONSUMER"`
+	// RedisBatchSize is the maximum number of entries read or reclaimed at once
+	RedisBatchSize int `mapstructure:"EVENTBUS_REDIS_BATCH_SIZE"`
+	// RedisBlock is how long a read waits for new entries
+	RedisBlock time.Duration `mapstructure:"EVENTBUS_REDIS_BLOCK"`
+	// RedisHandlerTimeout bounds the time handlers get to process one event
+	RedisHandlerTimeout time.Duration `mapstructure:"EVENTBUS_REDIS_HANDLER_TIMEOUT"`
+	// RedisClaimMinIdle is how long an entry stays pending before another consumer reclaims it
+	RedisClaimMinIdle time.Duration `mapstructure:"EVENTBUS_REDIS_CLAIM_MIN_IDLE"`
+	// RedisClaimInterval is how often pending entries are checked for reclaiming
+	RedisClaimInterval time.Duration `mapstructure:"EVENTBUS_REDIS_CLAIM_INTERVAL"`
+	// RedisMaxDeliveries drops entries delivered more often than this (0 = never)
+	RedisMaxDeliveries int `mapstructure:"EVENTBUS_REDIS_MAX_DELIVERIES"`
+	// RedisMaxLen trims the stream to roughly this many entries (0 = unbounded)
+	RedisMaxLen int64 `mapstructure:"EVENTBUS_REDIS_MAX_LEN"`
+
+	// RetryMaxAttempts is the default number of handler invocations before dead-lettering
+	RetryMaxAttempts int `mapstructure:"EVENTBUS_RETRY_MAX_ATTEMPTS"`
+	// RetryInitialBackoff is the default delay before the first handler retry
+	RetryInitialBackoff time.Duration `mapstructure:"EVENTBUS_RETRY_INITIAL_BACKOFF"`
+	// RetryMaxBackoff caps the default delay between handler retries
+	RetryMaxBackoff time.Duration `mapstructure:"EVENTBUS_RETRY_MAX_BACKOFF"`
+
+	// AsyncWorkers is the default number of PublishAsync workers per event type
+	AsyncWorkers int `mapstructure:"EVENTBUS_ASYNC_WORKERS"`
+	// AsyncQueueSize is the default PublishAsync queue depth per event type
+	AsyncQueueSize int `mapstructure:"EVENTBUS_ASYNC_QUEUE_SIZE"`
+	// AsyncPools overrides pool sizing per event type ("event.name=workers:queue,...")
+	AsyncPools string `mapstructure:"EVENTBUS_ASYNC_POOLS"`
+	// PartitionLanes is the number of serial workers for events with a partition key
+	PartitionLanes int `mapstructure:"EVENTBUS_PARTITION_LANES"`
+	// PartitionQueueSize is the queue depth of each partition lane
+	PartitionQueueSize int `mapstructure:"EVENTBUS_PARTITION_QUEUE_SIZE"`
+	// AsyncEnqueueTimeout is how long PublishAsync blocks on a full queue before failing
+	AsyncEnqueueTimeout time.Duration `mapstructure:"EVENTBUS_ASYNC_ENQUEUE_TIMEOUT"`
+	// AsyncDrainTimeout bounds how long Close waits for queued events on shutdown
+	AsyncDrainTimeout time.Duration `mapstructure:"EVENTBUS_ASYNC_DRAIN_TIMEOUT"`
+
+	// AdminToken guards the event bus admin endpoints; empty disables them
+	AdminToken string `mapstructure:"EVENTBUS_ADMIN_TOKEN"`
+}
+
+// LoadConfig reads configuration from file or environment variables.
+func LoadConfig() (Config, error) {
+	var cfg Config
+
+	viper.SetConfigName("app")
+	viper.SetConfigType("env")
+	viper.AddConfigPath(".")
+	viper.AutomaticEnv()
+
+	// Set default values
+	viper.SetDefault("EVENTBUS_BACKEND", BackendMemory)
+	viper.SetDefault("EVENTBUS_STORE_ENABLED", true)
+	viper.SetDefault("EVENTBUS_OUTBOX_POLL_INTERVAL", "1s")
+	viper.SetDefault("EVENTBUS_OUTBOX_BATCH_SIZE", 50)
+	viper.SetDefault("EVENTBUS_OUTBOX_LEASE_DURATION", "10m")
+	viper.SetDefault("EVENTBUS_OUTBOX_HANDLER_TIMEOUT", "5m")
+	viper.SetDefault("EVENTBUS_OUTBOX_MAX_BACKOFF", "15m")
+	viper.SetDefault("EVENTBUS_REDIS_STREAM", "eventbus:events")
+	viper.SetDefault("EVENTBUS_REDIS_GROUP", "api")
+	viper.SetDefault("EVENTBUS_REDIS_CONSUMER", "")
+	viper.SetDefault("EVENTBUS_REDIS_BATCH_SIZE", 50)
+	viper.SetDefault("EVENTBUS_REDIS_BLOCK", "5s")
+	viper.SetDefault("EVENTBUS_REDIS_HANDLER_TIMEOUT", "5m")
+	viper.SetDefault("EVENTBUS_REDIS_CLAIM_MIN_IDLE", "10m")
+	viper.SetDefault("EVENTBUS_REDIS_CLAIM_INTERVAL", "30s")
+	viper.SetDefault("EVENTBUS_REDIS_MAX_DELIVERIES", 10)
+	viper.SetDefault("EVENTBUS_REDIS_MAX_LEN", 100000)
+	viper.SetDefault("EVENTBUS_RETRY_MAX_ATTEMPTS", 3)
+	viper.SetDefault("EVENTBUS_RETRY_INITIAL_BACKOFF", "200ms")
+	viper.SetDefault("EVENTBUS_RETRY_MAX_BACKOFF", "10s")
+	viper.SetDefault("EVENTBUS_ASYNC_WORKERS", 4)
+	viper.SetDefault("EVENTBUS_ASYNC_QUEUE_SIZE", 100)
+	viper.SetDefault("EVENTBUS_ASYNC_POOLS", "")
+	viper.SetDefault("EVENTBUS_PARTITION_LANES", 8)
+	viper.SetDefault("EVENTBUS_PARTITION_QUEUE_SIZE", 100)
+	viper.SetDefault("EVENTBUS_ASYNC_ENQUEUE_TIMEOUT", "5s")
+	viper.SetDefault("EVENTBUS_ASYNC_DRAIN_TIMEOUT", "30s")
+	viper.SetDefault("EVENTBUS_ADMIN_TOKEN", "")
+
+	// Best-effort: ignore missing file, allow env-only usage
+	if err := viper.ReadInConfig(); err == nil {
+		_ = err
+	}
+
+	if err := viper.Unmarshal(&cfg); err != nil {
+		return cfg, fmt.Errorf("unable to decode eventbus config: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	switch c.Backend {
+	case BackendMemory, BackendOutbox, BackendRedis:
+	default:
+		return fmt.Errorf("unsupported event bus backend %q (EVENTBUS_BACKEND)", c.Backend)
+	}
+
+	if c.RetryMaxAttempts < 1 {
+		return fmt.Errorf("retry max attempts must be at least 1 (EVENTBUS_RETRY_MAX_ATTEMPTS)")
+	}
+
+	if c.AsyncWorkers < 1 {
+		return fmt.Errorf("async workers must be at least 1 (EVENTBUS_ASYNC_WORKERS)")
+	}
+	if c.AsyncQueueSize < 0 {
+		return fmt.Errorf("async queue size must not be negative (EVENTBUS_ASYNC_QUEUE_SIZE)")
+	}
+	if c.PartitionLanes < 1 {
+		return fmt.Errorf("partition lanes must be at least 1 (EVENTBUS_PARTITION_LANES)")
+	}
+	if _, err := ParsePoolOverrides(c.AsyncPools); err != nil {
+		return fmt.Errorf("%w (EVENTBUS_ASYNC_POOLS)", err)
+	}
+
+	if c.Backend == BackendOutbox {
+		if c.OutboxPollInterval <= 0 {
+			return fmt.Errorf("outbox poll interval must be positive (EVENTBUS_OUTBOX_POLL_INTERVAL)")
+		}
+		if c.OutboxBatchSize <= 0 {
+			return fmt.Errorf("outbox batch size must be positive (EVENTBUS_OUTBOX_BATCH_SIZE)")
+		}
+		if c.OutboxLeaseDuration < c.OutboxHandlerTimeout {
+			return fmt.Errorf("outbox lease duration must be at least the handler timeout (EVENTBUS_OUTBOX_LEASE_DURATION)")
+		}
+	}
+
+	if c.Backend == BackendRedis {
+		if c.RedisStream == "" || c.RedisGroup == "" {
+			return fmt.Errorf("redis stream and group are required (EVENTBUS_REDIS_STREAM, EVENTBUS_REDIS_GROUP)")
+		}
+		if c.RedisBatchSize <= 0 {
+			return fmt.Errorf("redis batch size must be positive (EVENTBUS_REDIS_BATCH_SIZE)")
+		}
+		if c.RedisClaimInterval <= 0 {
+			return fmt.Errorf("redis claim interval must be positive (EVENTBUS_REDIS_CLAIM_INTERVAL)")
+		}
+		if c.RedisClaimMinIdle < c.RedisHandlerTimeout {
+			return fmt.Errorf("redis claim min idle must be at least the handler timeout (EVENTBUS_REDIS_CLAIM_MIN_IDLE)")
+		}
+	}
+
+	return nil
+}
+
+// RetryPolicy returns the default handler retry policy from configuration
+func (c Config) RetryPolicy() RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.MaxAttempts = c.RetryMaxAttempts
+	policy.InitialBackoff = c.RetryInitialBackoff
+	policy.MaxBackoff = c.RetryMaxBackoff
+	return policy
+}
+
+// AsyncConfig returns the PublishAsync pool configuration; handler failures
+// of asynchronously published events are logged
+func (c Config) AsyncConfig(logger domain.Logger) AsyncConfig {
+	// Overrides were checked by Validate
+	pools, _ := ParsePoolOverrides(c.AsyncPools)
+
+	return AsyncConfig{
+		Default:        PoolConfig{Workers: c.AsyncWorkers, QueueSize: c.AsyncQueueSize},
+		Pools:          pools,
+		PartitionLanes: c.PartitionLanes,
+		LaneQueueSize:  c.PartitionQueueSize,
+		EnqueueTimeout: c.AsyncEnqueueTimeout,
+		DrainTimeout:   c.AsyncDrainTimeout,
+		OnError: func(event Event, err error) {
+			logger.Error("Async event handling failed", map[string]interface{}{
+				"event_name": event.EventName(),
+				"event_id":   event.EventID(),
+				"error":      err.Error(),
+			})
+		},
+	}
+}
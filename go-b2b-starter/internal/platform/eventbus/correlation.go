@@ -0,0 +1,188 @@
+package eventbus
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// Metadata keys under which the originating request is recorded on events
+const (
+	MetaRequestID      = "request_id"
+	MetaOrganizationID = "organization_id"
+	MetaTraceParent    = "traceparent"
+	MetaTraceState     = "tracestate"
+)
+
+// Correlation identifies the request an event originates from. Publishers
+// copy it from the context into the event metadata, and dispatch restores it
+// into the handler context, so handler logs and spans line up with the API
+// call that caused them.
+type Correlation struct {
+	RequestID      string
+	OrganizationID int32
+	// TraceParent and TraceState are W3C trace context header values
+	TraceParent string
+	TraceState  string
+}
+
+// TraceID returns the trace ID part of TraceParent, or "" if it is not set
+func (c Correlation) TraceID() string {
+	parts := strings.Split(c.TraceParent, "-")
+	if len(parts) != 4 {
+		return ""
+	}
+	return parts[1]
+}
+
+// IsZero reports whether no correlation is set
+func (c Correlation) IsZero() bool {
+	return c == Correlation{}
+}
+
+type correlationKey struct{}
+
+// WithCorrelation returns a context carrying the correlation
+func WithCorrelation(ctx context.Context, correlation Correlation) context.Context {
+	return context.WithValue(ctx, correlationKey{}, correlation)
+}
+
+// WithOrganizationID adds the organization to the context's correlation
+func WithOrganizationID(ctx context.Context, organizationID int32) context.Context {
+	correlation := CorrelationFromContext(ctx)
+	correlation.OrganizationID = organizationID
+	return WithCorrelation(ctx, correlation)
+}
+
+// CorrelationFromContext returns the correlation carried by the context,
+// or the zero value if there is none
+func CorrelationFromContext(ctx context.Context) Correlation {
+	if correlation, ok := ctx.Value(correlationKey{}).(Correlation); ok {
+		return correlation
+	}
+	return Correlation{}
+}
+
+// CorrelationFromEvent reads the correlation recorded in the event metadata
+func CorrelationFromEvent(event Event) Correlation {
+	meta := event.Metadata()
+	if meta == nil {
+		return Correlation{}
+	}
+
+	correlation := Correlation{}
+	correlation.RequestID, _ = meta[MetaRequestID].(string)
+	correlation.TraceParent, _ = meta[MetaTraceParent].(string)
+	correlation.TraceState, _ = meta[MetaTraceState].(string)
+
+	// Numbers come back as float64 once the event went through JSON
+	switch id := meta[MetaOrganizationID].(type) {
+	case int32:
+		correlation.OrganizationID = id
+	case int:
+		correlation.OrganizationID = int32(id)
+	case int64:
+		correlation.OrganizationID = int32(id)
+	case float64:
+		correlation.OrganizationID = int32(id)
+	case string:
+		if parsed, err := strconv.ParseInt(id, 10, 32); err == nil {
+			correlation.OrganizationID = int32(parsed)
+		}
+	}
+
+	return correlation
+}
+
+// metadataSetter is implemented by events embedding BaseEvent by pointer
+type metadataSetter interface {
+	SetMetadata(key string, value interface{})
+}
+
+// stampCorrelation records the context's correlation in the event metadata.
+// Keys the publisher already set are left untouched.
+func stampCorrelation(ctx context.Context, event Event) {
+	correlation := CorrelationFromContext(ctx)
+	if correlation.IsZero() {
+		return
+	}
+
+	set := func(key string, value interface{}) {
+		if meta := event.Metadata(); meta != nil {
+			if _, exists := meta[key]; exists {
+				return
+			}
+			meta[key] = value
+			return
+		}
+		if setter, ok := event.(metadataSetter); ok {
+			setter.SetMetadata(key, value)
+		}
+	}
+
+	if correlation.RequestID != "" {
+		set(MetaRequestID, correlation.RequestID)
+	}
+	if correlation.OrganizationID != 0 {
+		set(MetaOrganizationID, correlation.OrganizationID)
+	}
+	if correlation.TraceParent != "" {
+		set(MetaTraceParent, correlation.TraceParent)
+	}
+	if correlation.TraceState != "" {
+		set(MetaTraceState, correlation.TraceState)
+	}
+}
+
+// handlerContext restores the event's correlation into the handler context.
+// The handler runs as a child span of the publisher: same trace, new span ID.
+func handlerContext(ctx context.Context, event Event) context.Context {
+	correlation := CorrelationFromEvent(event)
+	if correlation.IsZero() {
+		return ctx
+	}
+	correlation.TraceParent = ChildTraceParent(correlation.TraceParent)
+	return WithCorrelation(ctx, correlation)
+}
+
+// NewTraceParent starts a new sampled W3C trace
+func NewTraceParent() string {
+	return "00-" + randomHex(16) + "-" + randomHex(8) + "-01"
+}
+
+// ChildTraceParent returns a traceparent for a span whose parent is
+// traceParent. Invalid values are returned unchanged.
+func ChildTraceParent(traceParent string) string {
+	if !ValidTraceParent(traceParent) {
+		return traceParent
+	}
+	parts := strings.Split(traceParent, "-")
+	return parts[0] + "-" + parts[1] + "-" + randomHex(8) + "-" + parts[3]
+}
+
+// ValidTraceParent reports whether value is a well-formed version 00
+// traceparent header
+func ValidTraceParent(value string) bool {
+	parts := strings.Split(value, "-")
+	if len(parts) != 4 || parts[0] != "00" {
+		return false
+	}
+	if len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return false
+	}
+	for _, part := range parts[1:] {
+		if _, err := hex.DecodeString(part); err != nil {
+			return false
+		}
+	}
+	// All-zero trace and span IDs are invalid
+	return strings.Trim(parts[1], "0") != "" && strings.Trim(parts[2], "0") != ""
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
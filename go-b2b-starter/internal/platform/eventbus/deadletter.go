@@ -0,0 +1,277 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/platform/logger/domain"
+)
+
+var (
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+	// ErrHandlerNotSubscribed is returned by Replay when the dead letter's
+	// handler is not subscribed in this process, e.g. after it was renamed
+	ErrHandlerNotSubscribed = errors.New("handler is not subscribed")
+
+	// ErrReplayFailed is returned by Replay when the handler failed again
+	ErrReplayFailed = errors.New("dead letter replay failed")
+)
+
+// DeadLetter is an event a handler could not process within its retry policy
+type DeadLetter struct {
+	ID          int64           `json:"id"`
+	EventID     string          `json:"event_id"`
+	EventName   string          `json:"event_name"`
+	HandlerName string          `json:"handler_name"`
+	Payload     json.RawMessage `json:"payload"`
+	Metadata    json.RawMessage `json:"metadata"`
+	Attempts    int             `json:"attempts"`
+	LastError   string          `json:"last_error"`
+	FailedAt    time.Time       `json:"failed_at"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// DeadLetterFilter narrows a dead letter listing; empty fields match everything
+type DeadLetterFilter struct {
+	EventName   string
+	HandlerName string
+	Limit       int
+	Offset      int
+}
+
+// DeadLetterStore persists dead-lettered events
+type DeadLetterStore interface {
+	Save(ctx context.Context, letter *DeadLetter) error
+	Get(ctx context.Context, id int64) (*DeadLetter, error)
+	List(ctx context.Context, filter DeadLetterFilter) ([]*DeadLetter, error)
+	// RecordFailure updates attempts and last error after a failed replay
+	RecordFailure(ctx context.Context, id int64, attempts int, lastError string) error
+	Delete(ctx context.Context, id int64) error
+}
+
+// DeadLetterMiddleware parks events whose handler still fails (after
+// RetryMiddleware gave up) in the store instead of dropping them. Once the
+// event is stored the error is swallowed, so durable backends do not
+// redeliver it to the other handlers; if storing fails the original error
+// is returned.
+func DeadLetterMiddleware(store DeadLetterStore, handlerName string, logger domain.Logger) EventMiddleware {
+	return func(next EventHandler[Event]) EventHandler[Event] {
+		return func(ctx context.Context, event Event) error {
+			err := next(ctx, event)
+			if err == nil {
+				return nil
+			}
+
+			attempts := 1
+			var exhausted *RetryExhaustedError
+			if errors.As(err, &exhausted) {
+				attempts = exhausted.Attempts
+				err = exhausted.Err
+			}
+
+			payload, metadata, encodeErr := encodeEvent(event)
+			if encodeErr != nil {
+				logger.Error("Failed to encode event for dead-lettering", map[string]interface{}{
+					"event_name":   event.EventName(),
+					"event_id":     event.EventID(),
+					"handler_name": handlerName,
+					"error":        encodeErr.Error(),
+				})
+				return err
+			}
+
+			letter := &DeadLetter{
+				EventID:     event.EventID(),
+				EventName:   event.EventName(),
+				HandlerName: handlerName,
+				Payload:     payload,
+				Metadata:    metadata,
+				Attempts:    attempts,
+				LastError:   err.Error(),
+				FailedAt:    time.Now(),
+			}
+
+			// The handler context may already be cancelled; the record must still be written
+			if saveErr := store.Save(context.WithoutCancel(ctx), letter); saveErr != nil {
+				logger.Error("Failed to store dead letter", map[string]interface{}{
+					"event_name":   event.EventName(),
+					"event_id":     event.EventID(),
+					"handler_name": handlerName,
+					"error":        saveErr.Error(),
+				})
+				return err
+			}
+
+			eventsDeadLettered.With(metricLabels(ctx, event)).Inc()
+			markOutcome(ctx, func(o *handlerOutcome) { o.deadLettered = true })
+
+			logger.Warn("Event dead-lettered", map[string]interface{}{
+				"event_name":     event.EventName(),
+				"event_id":       event.EventID(),
+				"handler_name":   handlerName,
+				"attempts":       attempts,
+				"dead_letter_id": letter.ID,
+				"error":          err.Error(),
+			})
+
+			return nil
+		}
+	}
+}
+
+// SubscribeOption customizes a subscription made through DeadLetterQueue
+type SubscribeOption func(*subscribeOptions)
+
+type subscribeOptions struct {
+	policy RetryPolicy
+}
+
+// WithRetryPolicy overrides the default retry policy for one subscription
+func WithRetryPolicy(policy RetryPolicy) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.policy = policy
+	}
+}
+
+// WithMaxAttempts overrides only the attempt count of the default policy
+func WithMaxAttempts(attempts int) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.policy.MaxAttempts = attempts
+	}
+}
+
+// DeadLetterQueue registers named handlers with per-subscription retry
+// policies and manages the events they failed to process.
+//
+// Each subscription runs the chain
+// DeadLetterMiddleware -> RetryMiddleware -> RecoveryMiddleware ->
+// attemptMetricsMiddleware -> handler inside the bus-wide middleware.
+type DeadLetterQueue struct {
+	bus           EventBus
+	store         DeadLetterStore
+	logger        domain.Logger
+	defaultPolicy RetryPolicy
+
+	mu sync.RWMutex
+	// handlers holds the retrying handler per "event/handler" key, used for replay
+	handlers map[string]EventHandler[Event]
+}
+
+func NewDeadLetterQueue(bus EventBus, store DeadLetterStore, logger domain.Logger, defaultPolicy RetryPolicy) *DeadLetterQueue {
+	return &DeadLetterQueue{
+		bus:           bus,
+		store:         store,
+		logger:        logger,
+		defaultPolicy: defaultPolicy,
+		handlers:      make(map[string]EventHandler[Event]),
+	}
+}
+
+// Subscribe registers handler on the bus under handlerName. The name must be
+// unique per event and stable across deployments, since dead letters refer to it.
+func (q *DeadLetterQueue) Subscribe(eventName, handlerName string, handler EventHandler[Event], opts ...SubscribeOption) error {
+	if handlerName == "" {
+		return fmt.Errorf("handler name is required for event %s", eventName)
+	}
+
+	options := subscribeOptions{policy: q.defaultPolicy}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	key := handlerKey(eventName, handlerName)
+
+	q.mu.Lock()
+	if _, exists := q.handlers[key]; exists {
+		q.mu.Unlock()
+		return fmt.Errorf("handler %s is already subscribed to event %s", handlerName, eventName)
+	}
+
+	retrying := RetryMiddleware(options.policy)(RecoveryMiddleware(q.logger)(attemptMetricsMiddleware()(handler)))
+	q.handlers[key] = retrying
+	q.mu.Unlock()
+
+	return SubscribeNamed(q.bus, eventName, handlerName, DeadLetterMiddleware(q.store, handlerName, q.logger)(retrying))
+}
+
+// List returns dead letters, newest first
+func (q *DeadLetterQueue) List(ctx context.Context, filter DeadLetterFilter) ([]*DeadLetter, error) {
+	return q.store.List(ctx, filter)
+}
+
+// Get returns a single dead letter
+func (q *DeadLetterQueue) Get(ctx context.Context, id int64) (*DeadLetter, error) {
+	return q.store.Get(ctx, id)
+}
+
+// Replay runs the dead letter through the handler that originally failed,
+// using the handler's retry policy. On success the dead letter is removed;
+// on failure its attempts and last error are updated and ErrReplayFailed is
+// returned. Other errors mean the replay could not run or be recorded.
+func (q *DeadLetterQueue) Replay(ctx context.Context, id int64) error {
+	letter, err := q.store.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	q.mu.RLock()
+	handler, ok := q.handlers[handlerKey(letter.EventName, letter.HandlerName)]
+	q.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %s to event %s", ErrHandlerNotSubscribed, letter.HandlerName, letter.EventName)
+	}
+
+	event, err := decodeEvent(letter.EventName, letter.Payload)
+	if err != nil {
+		return err
+	}
+
+	if handleErr := handler(ctx, event); handleErr != nil {
+		attempts := 1
+		var exhausted *RetryExhaustedError
+		if errors.As(handleErr, &exhausted) {
+			attempts = exhausted.Attempts
+			handleErr = exhausted.Err
+		}
+
+		if err := q.store.RecordFailure(ctx, id, letter.Attempts+attempts, handleErr.Error()); err != nil {
+			return fmt.Errorf("failed to record replay failure: %w", err)
+		}
+		return fmt.Errorf("%w: dead letter %d: %w", ErrReplayFailed, id, handleErr)
+	}
+
+	if err := q.store.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to remove replayed dead letter: %w", err)
+	}
+
+	q.logger.Info("Dead letter replayed", map[string]interface{}{
+		"dead_letter_id": id,
+		"event_name":     letter.EventName,
+		"event_id":       letter.EventID,
+		"handler_name":   letter.HandlerName,
+	})
+
+	return nil
+}
+
+// Discard deletes a dead letter without processing it
+func (q *DeadLetterQueue) Discard(ctx context.Context, id int64) error {
+	if err := q.store.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	q.logger.Info("Dead letter discarded", map[string]interface{}{
+		"dead_letter_id": id,
+	})
+
+	return nil
+}
+
+func handlerKey(eventName, handlerName string) string {
+	return eventName + "/" + handlerName
+}
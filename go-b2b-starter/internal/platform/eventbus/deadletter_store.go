@@ -0,0 +1,178 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/moasq/go-b2b-starter/internal/db/core"
+)
+
+const (
+	upsertDeadLetter = `
+INSERT INTO eventbus.dead_letters (
+    event_id, event_name, handler_name, payload, metadata, attempts, last_error, failed_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (event_id, handler_name) DO UPDATE SET
+    payload = EXCLUDED.payload,
+    metadata = EXCLUDED.metadata,
+    attempts = eventbus.dead_letters.attempts + EXCLUDED.attempts,
+    last_error = EXCLUDED.last_error,
+    failed_at = EXCLUDED.failed_at
+RETURNING id, attempts, created_at`
+
+	selectDeadLetterColumns = `
+SELECT id, event_id, event_name, handler_name, payload, metadata,
+       attempts, last_error, failed_at, created_at
+FROM eventbus.dead_letters`
+
+	getDeadLetter = selectDeadLetterColumns + `
+WHERE id = $1`
+
+	listDeadLetters = selectDeadLetterColumns + `
+WHERE ($1 = '' OR event_name = $1)
+  AND ($2 = '' OR handler_name = $2)
+ORDER BY created_at DESC, id DESC
+LIMIT $3 OFFSET $4`
+
+	recordDeadLetterFailure = `
+UPDATE eventbus.dead_letters
+SET attempts = $2, last_error = $3, failed_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING id`
+
+	deleteDeadLetter = `
+DELETE FROM eventbus.dead_letters
+WHERE id = $1
+RETURNING id`
+)
+
+// defaultDeadLetterLimit is used when a listing does not specify a limit
+const defaultDeadLetterLimit = 50
+
+// PostgresDeadLetterStore stores dead letters in eventbus.dead_letters
+type PostgresDeadLetterStore struct {
+	pool core.Pool
+}
+
+func NewPostgresDeadLetterStore(pool core.Pool) *PostgresDeadLetterStore {
+	return &PostgresDeadLetterStore{pool: pool}
+}
+
+// Save inserts the dead letter, or folds it into the existing record when the
+// same event already failed for the same handler.
+func (s *PostgresDeadLetterStore) Save(ctx context.Context, letter *DeadLetter) error {
+	var attempts int32
+	err := s.pool.QueryRow(ctx, upsertDeadLetter,
+		letter.EventID,
+		letter.EventName,
+		letter.HandlerName,
+		[]byte(letter.Payload),
+		[]byte(letter.Metadata),
+		int32(letter.Attempts),
+		letter.LastError,
+		letter.FailedAt,
+	).Scan(&letter.ID, &attempts, &letter.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save dead letter: %w", err)
+	}
+
+	letter.Attempts = int(attempts)
+	return nil
+}
+
+func (s *PostgresDeadLetterStore) Get(ctx context.Context, id int64) (*DeadLetter, error) {
+	letter, err := scanDeadLetter(s.pool.QueryRow(ctx, getDeadLetter, id))
+	if err != nil {
+		if errors.Is(err, core.ErrNoRows) {
+			return nil, ErrDeadLetterNotFound
+		}
+		return nil, fmt.Errorf("failed to get dead letter: %w", err)
+	}
+	return letter, nil
+}
+
+func (s *PostgresDeadLetterStore) List(ctx context.Context, filter DeadLetterFilter) ([]*DeadLetter, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultDeadLetterLimit
+	}
+
+	rows, err := s.pool.Query(ctx, listDeadLetters,
+		filter.EventName, filter.HandlerName, int32(limit), int32(filter.Offset))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+	defer rows.Close()
+
+	letters := []*DeadLetter{}
+	for rows.Next() {
+		letter, err := scanDeadLetter(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan dead letter: %w", err)
+		}
+		letters = append(letters, letter)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+
+	return letters, nil
+}
+
+func (s *PostgresDeadLetterStore) RecordFailure(ctx context.Context, id int64, attempts int, lastError string) error {
+	var updated int64
+	if err := s.pool.QueryRow(ctx, recordDeadLetterFailure, id, int32(attempts), lastError).Scan(&updated); err != nil {
+		if errors.Is(err, core.ErrNoRows) {
+			return ErrDeadLetterNotFound
+		}
+		return fmt.Errorf("failed to update dead letter: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresDeadLetterStore) Delete(ctx context.Context, id int64) error {
+	var deleted int64
+	if err := s.pool.QueryRow(ctx, deleteDeadLetter, id).Scan(&deleted); err != nil {
+		if errors.Is(err, core.ErrNoRows) {
+			return ErrDeadLetterNotFound
+		}
+		return fmt.Errorf("failed to delete dead letter: %w", err)
+	}
+	return nil
+}
+
+// deadLetterScanner is satisfied by both core.Row and core.Rows
+type deadLetterScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanDeadLetter(row deadLetterScanner) (*DeadLetter, error) {
+	var (
+		letter   DeadLetter
+		payload  []byte
+		metadata []byte
+		attempts int32
+	)
+
+	err := row.Scan(
+		&letter.ID,
+		&letter.EventID,
+		&letter.EventName,
+		&letter.HandlerName,
+		&payload,
+		&metadata,
+		&attempts,
+		&letter.LastError,
+		&letter.FailedAt,
+		&letter.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	letter.Payload = payload
+	letter.Metadata = metadata
+	letter.Attempts = int(attempts)
+	return &letter, nil
+}
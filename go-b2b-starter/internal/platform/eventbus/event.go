@@ -23,6 +23,9 @@ type BaseEvent struct {
 	Name      string                 `json:"name"`
 	CreatedAt time.Time              `json:"created_at"`
 	Meta      map[string]interface{} `json:"metadata,omitempty"`
+	// Partition optionally orders this event with others sharing the key
+	// (see PartitionedEvent)
+	Partition string `json:"partition_key,omitempty"`
 }
 
 func (e BaseEvent) EventName() string {
@@ -41,6 +44,18 @@ func (e BaseEvent) Metadata() map[string]interface{} {
 	return e.Meta
 }
 
+func (e BaseEvent) PartitionKey() string {
+	return e.Partition
+}
+
+// SetMetadata sets a metadata entry, allocating the map if needed
+func (e *BaseEvent) SetMetadata(key string, value interface{}) {
+	if e.Meta == nil {
+		e.Meta = make(map[string]interface{})
+	}
+	e.Meta[key] = value
+}
+
 // EventHandler represents a function that handles an event
 type EventHandler[T Event] func(ctx context.Context, event T) error
 
@@ -286,4 +286,20 @@ func NewPaymentExecuted(invoiceID, paymentID, organizationID int32, transactionI
 		DiscountCaptured: discountCaptured,
 		ExecutedDate:     executedDate,
 	}
-}
\ No newline at end of file
+}
+
+// Register the common events so they can be published and subscribed to
+func init() {
+	MustRegister[*InvoiceUploaded]("invoice.uploaded")
+	MustRegister[*InvoiceValidated]("invoice.validated")
+	MustRegister[*OCRRequested]("ocr.requested")
+	MustRegister[*TextExtracted]("text.extracted")
+	MustRegister[*DuplicateCheckRequested]("duplicate.check_requested")
+	MustRegister[*DuplicateDetected]("duplicate.detected")
+	MustRegister[*UniqueConfirmed]("duplicate.unique_confirmed")
+	MustRegister[*ApprovalRequested]("approval.requested")
+	MustRegister[*ApprovalGranted]("approval.granted")
+	MustRegister[*ApprovalRejected]("approval.rejected")
+	MustRegister[*PaymentScheduled]("payment.scheduled")
+	MustRegister[*PaymentExecuted]("payment.executed")
+}
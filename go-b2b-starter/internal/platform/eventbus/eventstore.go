@@ -0,0 +1,222 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/db/core"
+)
+
+const (
+	appendEvent = `
+INSERT INTO eventbus.events (event_id, event_name, partition_key, payload, metadata, occurred_at)
+VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6)
+ON CONFLICT (event_id) DO NOTHING`
+
+	scanEvents = `
+SELECT id, event_id, event_name, COALESCE(partition_key, ''), payload, metadata, occurred_at, recorded_at
+FROM eventbus.events
+WHERE id > $1
+  AND ($2::timestamp IS NULL OR occurred_at >= $2)
+  AND ($3::timestamp IS NULL OR occurred_at < $3)
+  AND (cardinality($4::text[]) = 0 OR event_name = ANY($4::text[]))
+ORDER BY id
+LIMIT $5`
+)
+
+// defaultScanBatchSize is used when a scan does not specify a batch size
+const defaultScanBatchSize = 500
+
+// StoredEvent is an event as recorded in the event store
+type StoredEvent struct {
+	ID           int64           `json:"id"`
+	EventID      string          `json:"event_id"`
+	EventName    string          `json:"event_name"`
+	PartitionKey string          `json:"partition_key,omitempty"`
+	Payload      json.RawMessage `json:"payload"`
+	Metadata     json.RawMessage `json:"metadata"`
+	OccurredAt   time.Time       `json:"occurred_at"`
+	RecordedAt   time.Time       `json:"recorded_at"`
+}
+
+// EventStoreFilter selects stored events; zero values match everything
+type EventStoreFilter struct {
+	// From and To bound the event timestamp as [From, To)
+	From time.Time
+	To   time.Time
+	// EventNames restricts the scan to these event names
+	EventNames []string
+	// BatchSize is the number of rows fetched per query
+	BatchSize int
+}
+
+// EventStore is an append-only log of published events
+type EventStore interface {
+	// Append records the event; appending the same event ID twice is a no-op
+	Append(ctx context.Context, event Event) error
+	// Scan calls fn for every matching event in the order they were recorded
+	Scan(ctx context.Context, filter EventStoreFilter, fn func(*StoredEvent) error) error
+}
+
+// PostgresEventStore stores events in eventbus.events
+type PostgresEventStore struct {
+	pool core.Pool
+}
+
+func NewPostgresEventStore(pool core.Pool) *PostgresEventStore {
+	return &PostgresEventStore{pool: pool}
+}
+
+// Append joins the transaction carried by ctx, if any, so the event is only
+// recorded when the surrounding domain write commits
+func (s *PostgresEventStore) Append(ctx context.Context, event Event) error {
+	payload, metadata, err := encodeEvent(event)
+	if err != nil {
+		return err
+	}
+
+	occurredAt := event.Timestamp()
+	if occurredAt.IsZero() {
+		occurredAt = time.Now()
+	}
+
+	err = core.WithTransaction(ctx, s.pool, func(ctx context.Context, tx core.Transaction) error {
+		return tx.Execute(ctx, appendEvent,
+			event.EventID(), event.EventName(), PartitionKeyOf(event), payload, metadata, occurredAt)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to append event %s to store: %w", event.EventName(), err)
+	}
+	return nil
+}
+
+func (s *PostgresEventStore) Scan(ctx context.Context, filter EventStoreFilter, fn func(*StoredEvent) error) error {
+	batchSize := filter.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultScanBatchSize
+	}
+
+	var from, to *time.Time
+	if !filter.From.IsZero() {
+		from = &filter.From
+	}
+	if !filter.To.IsZero() {
+		to = &filter.To
+	}
+
+	names := filter.EventNames
+	if names == nil {
+		names = []string{}
+	}
+
+	var lastID int64
+	for {
+		batch, err := s.scanBatch(ctx, lastID, from, to, names, batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range batch {
+			if err := fn(event); err != nil {
+				return err
+			}
+			lastID = event.ID
+		}
+
+		if len(batch) < batchSize {
+			return nil
+		}
+	}
+}
+
+// scanBatch reads one page so no rows are held open while fn runs handlers
+func (s *PostgresEventStore) scanBatch(ctx context.Context, afterID int64, from, to *time.Time, names []string, limit int) ([]*StoredEvent, error) {
+	rows, err := s.pool.Query(ctx, scanEvents, afterID, from, to, names, int32(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan event store: %w", err)
+	}
+	defer rows.Close()
+
+	var batch []*StoredEvent
+	for rows.Next() {
+		var (
+			event    StoredEvent
+			payload  []byte
+			metadata []byte
+		)
+		if err := rows.Scan(
+			&event.ID,
+			&event.EventID,
+			&event.EventName,
+			&event.PartitionKey,
+			&payload,
+			&metadata,
+			&event.OccurredAt,
+			&event.RecordedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to read stored event: %w", err)
+		}
+		event.Payload = payload
+		event.Metadata = metadata
+		batch = append(batch, &event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan event store: %w", err)
+	}
+
+	return batch, nil
+}
+
+// RecordingEventBus records every published event in an EventStore before
+// handing it to the underlying bus
+type RecordingEventBus struct {
+	EventBus
+	store EventStore
+}
+
+func NewRecordingEventBus(bus EventBus, store EventStore) *RecordingEventBus {
+	return &RecordingEventBus{EventBus: bus, store: store}
+}
+
+// Publish records the event, then publishes it
+func (bus *RecordingEventBus) Publish(ctx context.Context, event Event) error {
+	// Record the correlation along with the event
+	stampCorrelation(ctx, event)
+	if err := bus.store.Append(ctx, event); err != nil {
+		return err
+	}
+	return bus.EventBus.Publish(ctx, event)
+}
+
+// PublishAsync records the event, then enqueues it
+func (bus *RecordingEventBus) PublishAsync(ctx context.Context, event Event) error {
+	// Record the correlation along with the event
+	stampCorrelation(ctx, event)
+	if err := bus.store.Append(ctx, event); err != nil {
+		return err
+	}
+	return bus.EventBus.PublishAsync(ctx, event)
+}
+
+// SubscribeNamed registers a handler under an explicit name
+func (bus *RecordingEventBus) SubscribeNamed(eventName, handlerName string, handler EventHandler[Event]) error {
+	return SubscribeNamed(bus.EventBus, eventName, handlerName, handler)
+}
+
+// Start starts the underlying bus if it runs background workers
+func (bus *RecordingEventBus) Start() error {
+	if s, ok := bus.EventBus.(interface{ Start() error }); ok {
+		return s.Start()
+	}
+	return nil
+}
+
+func (bus *RecordingEventBus) dispatch(ctx context.Context, event Event) error {
+	local, ok := bus.EventBus.(localDispatcher)
+	if !ok {
+		return fmt.Errorf("event bus %T cannot dispatch locally", bus.EventBus)
+	}
+	return local.dispatch(ctx, event)
+}
@@ -0,0 +1,197 @@
+package eventbus
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Prometheus series of the event bus, exported on /metrics
+var (
+	eventsPublished = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "eventbus",
+		Name:      "events_published_total",
+		Help:      "Events accepted by the event bus.",
+	}, []string{"event"})
+
+	eventsHandled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "eventbus",
+		Name:      "events_handled_total",
+		Help:      "Events handled successfully, per handler.",
+	}, []string{"event", "handler"})
+
+	eventsFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "eventbus",
+		Name:      "events_failed_total",
+		Help:      "Handler attempts that returned an error.",
+	}, []string{"event", "handler"})
+
+	eventsPanicked = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "eventbus",
+		Name:      "events_panicked_total",
+		Help:      "Handler attempts that panicked.",
+	}, []string{"event", "handler"})
+
+	eventsRetried = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "eventbus",
+		Name:      "events_retried_total",
+		Help:      "Handler attempts retried after a failure.",
+	}, []string{"event", "handler"})
+
+	eventsDeadLettered = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "eventbus",
+		Name:      "events_dead_lettered_total",
+		Help:      "Events parked in the dead-letter store.",
+	}, []string{"event", "handler"})
+
+	handlerDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "eventbus",
+		Name:      "handler_duration_seconds",
+		Help:      "Time spent in event handlers.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 4, 10), // 1ms .. ~4.4min
+	}, []string{"event", "handler"})
+
+	handlersInFlight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "eventbus",
+		Name:      "handlers_in_flight",
+		Help:      "Handlers currently running.",
+	}, []string{"event", "handler"})
+
+	asyncQueueDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "eventbus",
+		Name:      "async_queue_depth",
+		Help:      "Events waiting in PublishAsync queues.",
+	}, []string{"event"})
+)
+
+// MetricsMiddleware records handled, failed and panicked counts, duration
+// and in-flight handlers per event name and handler name.
+//
+// Handlers subscribed through DeadLetterQueue swallow their errors and
+// panics; their attempts are counted inside the dead-letter chain instead
+// (see attemptMetricsMiddleware), and dead-lettered events are not counted
+// as handled.
+func MetricsMiddleware() EventMiddleware {
+	return func(next EventHandler[Event]) EventHandler[Event] {
+		return func(ctx context.Context, event Event) (err error) {
+			labels := metricLabels(ctx, event)
+
+			inFlight := handlersInFlight.With(labels)
+			inFlight.Inc()
+			start := time.Now()
+
+			outcome := &handlerOutcome{}
+			ctx = context.WithValue(ctx, handlerOutcomeKey{}, outcome)
+
+			defer func() {
+				inFlight.Dec()
+				handlerDuration.With(labels).Observe(time.Since(start).Seconds())
+
+				if r := recover(); r != nil {
+					if !outcome.failureRecorded {
+						eventsPanicked.With(labels).Inc()
+					}
+					// Leave the panic to RecoveryMiddleware
+					panic(r)
+				}
+
+				switch {
+				case err != nil:
+					if !outcome.failureRecorded {
+						eventsFailed.With(labels).Inc()
+					}
+				case !outcome.deadLettered:
+					eventsHandled.With(labels).Inc()
+				}
+			}()
+
+			return next(ctx, event)
+		}
+	}
+}
+
+// attemptMetricsMiddleware counts every failed or panicking attempt of a
+// handler. DeadLetterQueue runs it innermost, below the retry and recovery
+// layers that would otherwise hide the failures from MetricsMiddleware.
+func attemptMetricsMiddleware() EventMiddleware {
+	return func(next EventHandler[Event]) EventHandler[Event] {
+		return func(ctx context.Context, event Event) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					eventsPanicked.With(metricLabels(ctx, event)).Inc()
+					markOutcome(ctx, func(o *handlerOutcome) { o.failureRecorded = true })
+					panic(r)
+				}
+
+				if err != nil {
+					eventsFailed.With(metricLabels(ctx, event)).Inc()
+					markOutcome(ctx, func(o *handlerOutcome) { o.failureRecorded = true })
+				}
+			}()
+
+			return next(ctx, event)
+		}
+	}
+}
+
+func metricLabels(ctx context.Context, event Event) prometheus.Labels {
+	return prometheus.Labels{
+		"event":   event.EventName(),
+		"handler": HandlerNameFromContext(ctx),
+	}
+}
+
+// handlerOutcome is what the dead-letter chain already recorded about an
+// event, shared with the MetricsMiddleware around it
+type handlerOutcome struct {
+	failureRecorded bool
+	deadLettered    bool
+}
+
+type handlerOutcomeKey struct{}
+
+// markOutcome updates the outcome of the enclosing MetricsMiddleware, if any
+func markOutcome(ctx context.Context, mark func(*handlerOutcome)) {
+	if outcome, ok := ctx.Value(handlerOutcomeKey{}).(*handlerOutcome); ok {
+		mark(outcome)
+	}
+}
+
+// recordPublished counts an event accepted by a bus
+func recordPublished(event Event) {
+	eventsPublished.WithLabelValues(event.EventName()).Inc()
+}
+
+type handlerNameKey struct{}
+
+// withHandlerName marks the context with the name of the handler being run
+func withHandlerName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, handlerNameKey{}, name)
+}
+
+// HandlerNameFromContext returns the name of the handler an event is being
+// dispatched to, for use in middleware. See SubscribeNamed.
+func HandlerNameFromContext(ctx context.Context) string {
+	if name, ok := ctx.Value(handlerNameKey{}).(string); ok {
+		return name
+	}
+	return "unknown"
+}
+
+// funcName derives a handler name from its function, e.g. "cmd.Init.func1"
+func funcName(fn interface{}) string {
+	f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	if f == nil {
+		return "unknown"
+	}
+	name := f.Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
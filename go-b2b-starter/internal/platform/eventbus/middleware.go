@@ -14,28 +14,22 @@ func LoggingMiddleware(logger domain.Logger) EventMiddleware {
 	return func(next EventHandler[Event]) EventHandler[Event] {
 		return func(ctx context.Context, event Event) error {
 			start := time.Now()
-			logger.Info("Processing event", map[string]interface{}{
-				"event_name": event.EventName(),
-				"event_id":   event.EventID(),
-				"timestamp":  event.Timestamp(),
-			})
+			logger.Info("Processing event", eventLogFields(ctx, event, map[string]interface{}{
+				"timestamp": event.Timestamp(),
+			}))
 
 			err := next(ctx, event)
 			duration := time.Since(start)
 
 			if err != nil {
-				logger.Error("Event processing failed", map[string]interface{}{
-					"event_name": event.EventName(),
-					"event_id":   event.EventID(),
-					"error":      err.Error(),
-					"duration":   duration,
-				})
+				logger.Error("Event processing failed", eventLogFields(ctx, event, map[string]interface{}{
+					"error":    err.Error(),
+					"duration": duration,
+				}))
 			} else {
-				logger.Info("Event processed successfully", map[string]interface{}{
-					"event_name": event.EventName(),
-					"event_id":   event.EventID(),
-					"duration":   duration,
-				})
+				logger.Info("Event processed successfully", eventLogFields(ctx, event, map[string]interface{}{
+					"duration": duration,
+				}))
 			}
 
 			return err
@@ -83,20 +77,22 @@ func getMapKeys(m map[string]interface{}) []string {
 	return keys
 }
 
-// MetricsMiddleware adds metrics collection to event handling
-func MetricsMiddleware() EventMiddleware {
-	return func(next EventHandler[Event]) EventHandler[Event] {
-		return func(ctx context.Context, event Event) error {
-			start := time.Now()
-
-			err := next(ctx, event)
-			duration := time.Since(start)
+// eventLogFields adds the event, handler and request correlation to fields
+func eventLogFields(ctx context.Context, event Event, fields map[string]interface{}) map[string]interface{} {
+	fields["event_name"] = event.EventName()
+	fields["event_id"] = event.EventID()
+	fields["handler"] = HandlerNameFromContext(ctx)
 
-			// Here you could send metrics to Prometheus, StatsD, etc.
-			// For now, we'll just log the metrics
-			_ = duration // Placeholder for actual metrics implementation
-
-			return err
-		}
+	correlation := CorrelationFromContext(ctx)
+	if correlation.RequestID != "" {
+		fields["request_id"] = correlation.RequestID
 	}
+	if correlation.OrganizationID != 0 {
+		fields["organization_id"] = correlation.OrganizationID
+	}
+	if traceID := correlation.TraceID(); traceID != "" {
+		fields["trace_id"] = traceID
+	}
+
+	return fields
 }
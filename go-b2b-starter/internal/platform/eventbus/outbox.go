@@ -0,0 +1,344 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/db/core"
+	"github.com/moasq/go-b2b-starter/internal/platform/logger/domain"
+)
+
+const (
+	insertOutboxEvent = `
+INSERT INTO eventbus.outbox (event_id, event_name, payload, metadata, occurred_at, partition_key)
+VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))
+ON CONFLICT (event_id) DO NOTHING`
+
+	claimOutboxEvents = `
+UPDATE eventbus.outbox
+SET
+    attempts = attempts + 1,
+    locked_until = CURRENT_TIMESTAMP + make_interval(secs => $2)
+WHERE id IN (
+    SELECT o.id FROM eventbus.outbox o
+    WHERE o.dispatched_at IS NULL
+      AND o.next_attempt_at <= CURRENT_TIMESTAMP
+      AND (o.locked_until IS NULL OR o.locked_until < CURRENT_TIMESTAMP)
+      -- Keep per-key order: skip events behind an undispatched one with the same key
+      AND (o.partition_key IS NULL OR NOT EXISTS (
+          SELECT 1 FROM eventbus.outbox earlier
+          WHERE earlier.partition_key = o.partition_key
+            AND earlier.dispatched_at IS NULL
+            AND earlier.id < o.id
+      ))
+    ORDER BY o.id
+    LIMIT $1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, event_name, payload, attempts`
+
+	markOutboxEventDispatched = `
+UPDATE eventbus.outbox
+SET
+    dispatched_at = CURRENT_TIMESTAMP,
+    locked_until = NULL,
+    last_error = NULL
+WHERE id = $1`
+
+	markOutboxEventFailed = `
+UPDATE eventbus.outbox
+SET
+    locked_until = NULL,
+    last_error = $2,
+    next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $3)
+WHERE id = $1`
+)
+
+// OutboxEventBus is a Postgres-backed implementation of EventBus using the
+// transactional outbox pattern.
+//
+// Publish writes the event into eventbus.outbox. If the context carries a
+// transaction (see core.WithTransaction), the insert joins it, so the event
+// is committed or rolled back together with the domain write. A relay worker
+// then claims pending rows and dispatches them to subscribers with
+// at-least-once delivery: an event is retried with exponential backoff until
+// every handler succeeds, so handlers must be idempotent. Events sharing a
+// partition key are relayed strictly in publish order, even across relays:
+// a failing event holds back the later events of its key.
+type OutboxEventBus struct {
+	pool   core.Pool
+	config Config
+	logger domain.Logger
+
+	// local holds subscriptions and runs the middleware chain for dispatch
+	local *InMemoryEventBus
+
+	mu      sync.Mutex
+	started bool
+	closed  bool
+	wake    chan struct{}
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+func NewOutboxEventBus(pool core.Pool, config Config, logger domain.Logger, middleware ...EventMiddleware) *OutboxEventBus {
+	return &OutboxEventBus{
+		pool:   pool,
+		config: config,
+		logger: logger,
+		local:  NewInMemoryEventBusWithConfig(config.AsyncConfig(logger), middleware...),
+		wake:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+}
+
+// Publish stores the event in the outbox; delivery happens asynchronously
+func (bus *OutboxEventBus) Publish(ctx context.Context, event Event) error {
+	bus.mu.Lock()
+	closed := bus.closed
+	bus.mu.Unlock()
+	if closed {
+		return fmt.Errorf("event bus is closed")
+	}
+
+	stampCorrelation(ctx, event)
+
+	payload, metadata, err := encodeEvent(event)
+	if err != nil {
+		return err
+	}
+
+	occurredAt := event.Timestamp()
+	if occurredAt.IsZero() {
+		occurredAt = time.Now()
+	}
+
+	err = core.WithTransaction(ctx, bus.pool, func(ctx context.Context, tx core.Transaction) error {
+		return tx.Execute(ctx, insertOutboxEvent,
+			event.EventID(), event.EventName(), payload, metadata, occurredAt, PartitionKeyOf(event))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write event %s to outbox: %w", event.EventName(), err)
+	}
+	recordPublished(event)
+
+	// Nudge the relay; if the insert joined an outer transaction the row
+	// becomes visible on commit and is picked up by the next poll at the latest
+	select {
+	case bus.wake <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// PublishAsync is equivalent to Publish: writing to the outbox never waits
+// for handlers, so there is nothing further to defer
+func (bus *OutboxEventBus) PublishAsync(ctx context.Context, event Event) error {
+	return bus.Publish(ctx, event)
+}
+
+// Subscribe registers a handler for a specific event type
+func (bus *OutboxEventBus) Subscribe(eventName string, handler EventHandler[Event]) error {
+	return bus.local.Subscribe(eventName, handler)
+}
+
+// SubscribeNamed registers a handler under an explicit name
+func (bus *OutboxEventBus) SubscribeNamed(eventName, handlerName string, handler EventHandler[Event]) error {
+	return bus.local.SubscribeNamed(eventName, handlerName, handler)
+}
+
+// Unsubscribe removes a handler for a specific event type
+func (bus *OutboxEventBus) Unsubscribe(eventName string, handler EventHandler[Event]) error {
+	return bus.local.Unsubscribe(eventName, handler)
+}
+
+// dispatch runs this process's subscribers directly (used by replay)
+func (bus *OutboxEventBus) dispatch(ctx context.Context, event Event) error {
+	return bus.local.dispatch(ctx, event)
+}
+
+// Start launches the relay worker.
+//
+// Call it once every module has subscribed; events relayed before a handler
+// is registered are not delivered to that handler.
+func (bus *OutboxEventBus) Start() error {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	if bus.closed {
+		return fmt.Errorf("event bus is closed")
+	}
+	if bus.started {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	bus.cancel = cancel
+	bus.started = true
+
+	go bus.run(ctx)
+
+	bus.logger.Info("Outbox relay started", map[string]interface{}{
+		"poll_interval": bus.config.OutboxPollInterval.String(),
+		"batch_size":    bus.config.OutboxBatchSize,
+	})
+
+	return nil
+}
+
+// Close stops the relay, waiting for the in-flight batch to finish.
+// Undelivered events stay in the outbox and are relayed after restart.
+func (bus *OutboxEventBus) Close() error {
+	bus.mu.Lock()
+	if bus.closed {
+		bus.mu.Unlock()
+		return nil
+	}
+	bus.closed = true
+	started := bus.started
+	cancel := bus.cancel
+	bus.mu.Unlock()
+
+	if started {
+		cancel()
+		<-bus.done
+	}
+
+	return bus.local.Close()
+}
+
+// run is the relay loop
+func (bus *OutboxEventBus) run(ctx context.Context) {
+	defer close(bus.done)
+
+	ticker := time.NewTicker(bus.config.OutboxPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-bus.wake:
+		}
+
+		// Keep draining while there is work; events held back behind an
+		// earlier event of their partition become claimable next round
+		for {
+			claimed, err := bus.relayBatch(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					bus.logger.Error("Outbox relay batch failed", map[string]interface{}{
+						"error": err.Error(),
+					})
+				}
+				break
+			}
+			if claimed == 0 || ctx.Err() != nil {
+				break
+			}
+		}
+	}
+}
+
+// outboxRow is a claimed outbox entry
+type outboxRow struct {
+	id        int64
+	eventName string
+	payload   []byte
+	attempts  int32
+}
+
+// relayBatch claims up to OutboxBatchSize pending events and dispatches them
+func (bus *OutboxEventBus) relayBatch(ctx context.Context) (int, error) {
+	rows, err := bus.pool.Query(ctx, claimOutboxEvents,
+		bus.config.OutboxBatchSize, bus.config.OutboxLeaseDuration.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+
+	var batch []outboxRow
+	for rows.Next() {
+		var row outboxRow
+		if err := rows.Scan(&row.id, &row.eventName, &row.payload, &row.attempts); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+		batch = append(batch, row)
+	}
+	if err := rows.Close(); err != nil {
+		return 0, fmt.Errorf("failed to read outbox events: %w", err)
+	}
+
+	// RETURNING does not preserve the subquery order
+	sort.Slice(batch, func(i, j int) bool { return batch[i].id < batch[j].id })
+
+	for _, row := range batch {
+		bus.deliver(ctx, row)
+	}
+
+	return len(batch), nil
+}
+
+// deliver dispatches one outbox event and records the outcome
+func (bus *OutboxEventBus) deliver(ctx context.Context, row outboxRow) {
+	// Handlers get their own deadline; shutdown waits for them instead of
+	// cancelling mid-flight, the lease covers a crash
+	handlerCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), bus.config.OutboxHandlerTimeout)
+	defer cancel()
+
+	dispatchErr := func() error {
+		event, err := decodeEvent(row.eventName, row.payload)
+		if err != nil {
+			return err
+		}
+		return bus.local.dispatch(handlerCtx, event)
+	}()
+
+	// Record the outcome even if shutdown was requested meanwhile
+	recordCtx := context.WithoutCancel(ctx)
+
+	if dispatchErr == nil {
+		if err := bus.pool.Execute(recordCtx, markOutboxEventDispatched, row.id); err != nil {
+			bus.logger.Error("Failed to mark outbox event dispatched", map[string]interface{}{
+				"outbox_id":  row.id,
+				"event_name": row.eventName,
+				"error":      err.Error(),
+			})
+		}
+		return
+	}
+
+	backoff := bus.backoff(row.attempts)
+	bus.logger.Warn("Outbox event delivery failed, will retry", map[string]interface{}{
+		"outbox_id":  row.id,
+		"event_name": row.eventName,
+		"attempts":   row.attempts,
+		"retry_in":   backoff.String(),
+		"error":      dispatchErr.Error(),
+	})
+
+	if err := bus.pool.Execute(recordCtx, markOutboxEventFailed,
+		row.id, dispatchErr.Error(), backoff.Seconds()); err != nil {
+		bus.logger.Error("Failed to record outbox delivery failure", map[string]interface{}{
+			"outbox_id":  row.id,
+			"event_name": row.eventName,
+			"error":      err.Error(),
+		})
+	}
+}
+
+// backoff returns the delay before the next attempt: poll interval doubled per attempt, capped
+func (bus *OutboxEventBus) backoff(attempts int32) time.Duration {
+	base := bus.config.OutboxPollInterval
+	delay := time.Duration(float64(base) * math.Pow(2, float64(attempts-1)))
+	if delay <= 0 || delay > bus.config.OutboxMaxBackoff {
+		return bus.config.OutboxMaxBackoff
+	}
+	return delay
+}
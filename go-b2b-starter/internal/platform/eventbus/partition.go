@@ -0,0 +1,87 @@
+package eventbus
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+)
+
+// PartitionedEvent is implemented by events that must be handled in order
+// relative to other events with the same partition key, e.g. all events of
+// one document or one organization's subscription. BaseEvent implements it
+// through its Partition field.
+type PartitionedEvent interface {
+	Event
+	PartitionKey() string
+}
+
+// PartitionKeyOf returns the event's partition key, or "" if it has none
+func PartitionKeyOf(event Event) string {
+	if partitioned, ok := event.(PartitionedEvent); ok {
+		return partitioned.PartitionKey()
+	}
+	return ""
+}
+
+// laneFor maps a partition key onto one of n lanes
+func laneFor(key string, n int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(n))
+}
+
+// keyedMutex serializes work per key while letting different keys proceed
+// in parallel. Idle keys are removed, so the map only holds keys in use.
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*keyLock
+}
+
+type keyLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+func newKeyedMutex() *keyedMutex {
+	return &keyedMutex{locks: make(map[string]*keyLock)}
+}
+
+// Lock blocks until key is free and returns the function releasing it
+func (k *keyedMutex) Lock(key string) func() {
+	k.mu.Lock()
+	lock, ok := k.locks[key]
+	if !ok {
+		lock = &keyLock{}
+		k.locks[key] = lock
+	}
+	lock.refs++
+	k.mu.Unlock()
+
+	lock.mu.Lock()
+
+	return func() {
+		lock.mu.Unlock()
+
+		k.mu.Lock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(k.locks, key)
+		}
+		k.mu.Unlock()
+	}
+}
+
+// heldPartitionKey marks the context of a handler that runs under a key lock
+type heldPartitionKey struct{ key string }
+
+// lockPartition serializes dispatch for key. A handler that synchronously
+// publishes a follow-up event with the same key already holds the lock, so
+// the nested dispatch runs inline instead of deadlocking.
+func (k *keyedMutex) lockPartition(ctx context.Context, key string) (context.Context, func()) {
+	marker := heldPartitionKey{key: key}
+	if ctx.Value(marker) != nil {
+		return ctx, func() {}
+	}
+	unlock := k.Lock(key)
+	return context.WithValue(ctx, marker, true), unlock
+}
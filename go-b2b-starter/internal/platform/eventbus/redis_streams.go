@@ -0,0 +1,350 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/db/core"
+	"github.com/moasq/go-b2b-starter/internal/platform/logger/domain"
+	"github.com/moasq/go-b2b-starter/internal/platform/redis"
+)
+
+// Stream entry fields
+const (
+	streamFieldEventName = "event_name"
+	streamFieldPayload   = "payload"
+)
+
+// RedisStreamEventBus is an EventBus that delivers events across instances
+// through a Redis stream read by a consumer group.
+//
+// Every replica joins the same group, so each event is handled by exactly one
+// replica's subscribers. Entries are acknowledged once all handlers succeed;
+// failed entries stay pending and, like entries of crashed consumers, are
+// reclaimed by a live consumer after ClaimMinIdle. Delivery is at-least-once,
+// so handlers must be idempotent.
+//
+// Each replica handles its entries one at a time, but the group spreads
+// entries across replicas, so partition keys are not ordered cluster-wide.
+// Use the outbox backend where strict per-key order matters.
+type RedisStreamEventBus struct {
+	streams  redis.StreamClient
+	config   Config
+	consumer string
+	logger   domain.Logger
+
+	// local holds subscriptions and runs the middleware chain for dispatch
+	local *InMemoryEventBus
+
+	mu      sync.Mutex
+	started bool
+	closed  bool
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func NewRedisStreamEventBus(streams redis.StreamClient, config Config, logger domain.Logger, middleware ...EventMiddleware) *RedisStreamEventBus {
+	consumer := config.RedisConsumer
+	if consumer == "" {
+		hostname, _ := os.Hostname()
+		consumer = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	}
+
+	return &RedisStreamEventBus{
+		streams:  streams,
+		config:   config,
+		consumer: consumer,
+		logger:   logger,
+		local:    NewInMemoryEventBusWithConfig(config.AsyncConfig(logger), middleware...),
+	}
+}
+
+// Publish appends the event to the stream; delivery happens asynchronously.
+// Inside a transaction the event is appended once it commits, and a failure
+// to append is logged.
+func (bus *RedisStreamEventBus) Publish(ctx context.Context, event Event) error {
+	bus.mu.Lock()
+	closed := bus.closed
+	bus.mu.Unlock()
+	if closed {
+		return fmt.Errorf("event bus is closed")
+	}
+
+	stampCorrelation(ctx, event)
+
+	if _, ok := core.TransactionFromContext(ctx); ok {
+		core.AfterCommit(ctx, func() {
+			if err := bus.append(ctx, event); err != nil {
+				bus.logger.Error("Failed to publish event after commit", eventLogFields(ctx, event, map[string]interface{}{
+					"error": err.Error(),
+				}))
+			}
+		})
+		return nil
+	}
+
+	return bus.append(ctx, event)
+}
+
+// append adds the event to the stream
+func (bus *RedisStreamEventBus) append(ctx context.Context, event Event) error {
+	payload, err := MarshalEvent(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = bus.streams.StreamAdd(ctx, bus.config.RedisStream, bus.config.RedisMaxLen, map[string]any{
+		streamFieldEventName: event.EventName(),
+		streamFieldPayload:   string(payload),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to append event %s to stream: %w", event.EventName(), err)
+	}
+	recordPublished(event)
+
+	return nil
+}
+
+// PublishAsync is equivalent to Publish: appending to the stream never waits
+// for handlers
+func (bus *RedisStreamEventBus) PublishAsync(ctx context.Context, event Event) error {
+	return bus.Publish(ctx, event)
+}
+
+// Subscribe registers a handler for a specific event type
+func (bus *RedisStreamEventBus) Subscribe(eventName string, handler EventHandler[Event]) error {
+	return bus.local.Subscribe(eventName, handler)
+}
+
+// SubscribeNamed registers a handler under an explicit name
+func (bus *RedisStreamEventBus) SubscribeNamed(eventName, handlerName string, handler EventHandler[Event]) error {
+	return bus.local.SubscribeNamed(eventName, handlerName, handler)
+}
+
+// Unsubscribe removes a handler for a specific event type
+func (bus *RedisStreamEventBus) Unsubscribe(eventName string, handler EventHandler[Event]) error {
+	return bus.local.Unsubscribe(eventName, handler)
+}
+
+// dispatch runs this process's subscribers directly (used by replay)
+func (bus *RedisStreamEventBus) dispatch(ctx context.Context, event Event) error {
+	return bus.local.dispatch(ctx, event)
+}
+
+// Start creates the consumer group if needed and launches the read and
+// reclaim loops. Call it once every module has subscribed.
+func (bus *RedisStreamEventBus) Start() error {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	if bus.closed {
+		return fmt.Errorf("event bus is closed")
+	}
+	if bus.started {
+		return nil
+	}
+
+	if err := bus.streams.StreamEnsureGroup(context.Background(), bus.config.RedisStream, bus.config.RedisGroup); err != nil {
+		return fmt.Errorf("failed to create consumer group %s: %w", bus.config.RedisGroup, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	bus.cancel = cancel
+	bus.started = true
+
+	bus.wg.Add(2)
+	go bus.readLoop(ctx)
+	go bus.reclaimLoop(ctx)
+
+	bus.logger.Info("Redis stream consumer started", map[string]interface{}{
+		"stream":   bus.config.RedisStream,
+		"group":    bus.config.RedisGroup,
+		"consumer": bus.consumer,
+	})
+
+	return nil
+}
+
+// Close stops consuming, waiting for the message being handled to finish.
+// Unacknowledged entries are reclaimed by another consumer.
+func (bus *RedisStreamEventBus) Close() error {
+	bus.mu.Lock()
+	if bus.closed {
+		bus.mu.Unlock()
+		return nil
+	}
+	bus.closed = true
+	started := bus.started
+	cancel := bus.cancel
+	bus.mu.Unlock()
+
+	if started {
+		cancel()
+		bus.wg.Wait()
+	}
+
+	return bus.local.Close()
+}
+
+// readLoop consumes new entries delivered to this consumer
+func (bus *RedisStreamEventBus) readLoop(ctx context.Context) {
+	defer bus.wg.Done()
+
+	for ctx.Err() == nil {
+		messages, err := bus.streams.StreamReadGroup(ctx, bus.config.RedisStream, bus.config.RedisGroup,
+			bus.consumer, int64(bus.config.RedisBatchSize), bus.config.RedisBlock)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			bus.logger.Error("Failed to read from event stream", map[string]interface{}{
+				"stream": bus.config.RedisStream,
+				"error":  err.Error(),
+			})
+			bus.sleep(ctx, time.Second)
+			continue
+		}
+
+		for _, message := range messages {
+			bus.handle(ctx, message)
+		}
+	}
+}
+
+// reclaimLoop periodically takes over entries left pending by failed
+// handlers or consumers that died before acknowledging
+func (bus *RedisStreamEventBus) reclaimLoop(ctx context.Context) {
+	defer bus.wg.Done()
+
+	ticker := time.NewTicker(bus.config.RedisClaimInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		start := "0-0"
+		for ctx.Err() == nil {
+			messages, next, err := bus.streams.StreamClaimIdle(ctx, bus.config.RedisStream, bus.config.RedisGroup,
+				bus.consumer, bus.config.RedisClaimMinIdle, start, int64(bus.config.RedisBatchSize))
+			if err != nil {
+				if ctx.Err() == nil {
+					bus.logger.Error("Failed to reclaim pending events", map[string]interface{}{
+						"stream": bus.config.RedisStream,
+						"error":  err.Error(),
+					})
+				}
+				break
+			}
+
+			for _, message := range messages {
+				if bus.exceededDeliveries(ctx, message) {
+					continue
+				}
+				bus.handle(ctx, message)
+			}
+
+			if next == "0-0" || len(messages) == 0 {
+				break
+			}
+			start = next
+		}
+	}
+}
+
+// exceededDeliveries acknowledges and drops an entry that was delivered more
+// than RedisMaxDeliveries times, so a poison message cannot loop forever
+func (bus *RedisStreamEventBus) exceededDeliveries(ctx context.Context, message redis.StreamMessage) bool {
+	if bus.config.RedisMaxDeliveries <= 0 {
+		return false
+	}
+
+	pending, err := bus.streams.StreamPending(ctx, bus.config.RedisStream, bus.config.RedisGroup, message.ID, message.ID, 1)
+	if err != nil || len(pending) == 0 {
+		return false
+	}
+	if pending[0].Deliveries <= int64(bus.config.RedisMaxDeliveries) {
+		return false
+	}
+
+	bus.logger.Error("Dropping event after too many deliveries", map[string]interface{}{
+		"stream":     bus.config.RedisStream,
+		"message_id": message.ID,
+		"event_name": message.Values[streamFieldEventName],
+		"deliveries": pending[0].Deliveries,
+	})
+	bus.ack(message.ID)
+	return true
+}
+
+// handle dispatches one stream entry and acknowledges it on success
+func (bus *RedisStreamEventBus) handle(ctx context.Context, message redis.StreamMessage) {
+	name, _ := message.Values[streamFieldEventName].(string)
+	payload, _ := message.Values[streamFieldPayload].(string)
+
+	if name == "" || payload == "" {
+		bus.logger.Error("Dropping malformed stream entry", map[string]interface{}{
+			"stream":     bus.config.RedisStream,
+			"message_id": message.ID,
+		})
+		bus.ack(message.ID)
+		return
+	}
+
+	event, err := UnmarshalEvent(name, []byte(payload))
+	if err != nil {
+		bus.logger.Error("Dropping undecodable stream entry", map[string]interface{}{
+			"stream":     bus.config.RedisStream,
+			"message_id": message.ID,
+			"event_name": name,
+			"error":      err.Error(),
+		})
+		bus.ack(message.ID)
+		return
+	}
+
+	// Handlers get their own deadline; shutdown waits for them instead of
+	// cancelling mid-flight
+	handlerCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), bus.config.RedisHandlerTimeout)
+	defer cancel()
+
+	if err := bus.local.dispatch(handlerCtx, event); err != nil {
+		// Leave pending; reclaimed after RedisClaimMinIdle
+		bus.logger.Warn("Stream event delivery failed, will retry", map[string]interface{}{
+			"stream":     bus.config.RedisStream,
+			"message_id": message.ID,
+			"event_name": name,
+			"event_id":   event.EventID(),
+			"error":      err.Error(),
+		})
+		return
+	}
+
+	bus.ack(message.ID)
+}
+
+func (bus *RedisStreamEventBus) ack(id string) {
+	// Acknowledge even when shutting down so the entry is not redelivered
+	if err := bus.streams.StreamAck(context.Background(), bus.config.RedisStream, bus.config.RedisGroup, id); err != nil {
+		bus.logger.Error("Failed to acknowledge stream entry", map[string]interface{}{
+			"stream":     bus.config.RedisStream,
+			"message_id": id,
+			"error":      err.Error(),
+		})
+	}
+}
+
+func (bus *RedisStreamEventBus) sleep(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
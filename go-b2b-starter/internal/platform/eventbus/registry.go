@@ -0,0 +1,218 @@
+package eventbus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var (
+	// ErrUnregisteredEvent is returned when publishing or subscribing to an
+	// event name that has no registered Go type
+	ErrUnregisteredEvent = errors.New("event is not registered")
+	// ErrEventTypeMismatch is returned when an event's Go type differs from
+	// the type registered for its name
+	ErrEventTypeMismatch = errors.New("event type does not match registration")
+)
+
+// RawEvent is an event read back from durable storage or a transport whose
+// name is not registered. Handlers can still inspect the common BaseEvent
+// fields and unmarshal Payload themselves.
+type RawEvent struct {
+	BaseEvent
+	Payload json.RawMessage `json:"-"`
+}
+
+// Registry maps event names to the Go types published under them.
+//
+// Every event must be registered before it is published or subscribed to;
+// the bus rejects anything else, so typos in event names and mismatched
+// handler types surface during startup instead of silently never matching.
+type Registry struct {
+	mu     sync.RWMutex
+	byName map[string]reflect.Type
+	byType map[reflect.Type]string
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		byName: make(map[string]reflect.Type),
+		byType: make(map[reflect.Type]string),
+	}
+}
+
+// defaultRegistry is used by the package-level helpers and all buses
+var defaultRegistry = NewRegistry()
+
+// DefaultRegistry returns the registry shared by the event buses
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// Register associates name with the Go type T (usually a pointer, e.g.
+// *events.DocumentUploaded). Registering the same pair twice is a no-op; a
+// name or type already bound to something else is an error.
+//
+// Modules register their events during initialization:
+//
+//	eventbus.Register[*events.DocumentUploaded](events.DocumentUploadedEventType)
+func Register[T Event](name string) error {
+	return defaultRegistry.register(name, reflect.TypeFor[T]())
+}
+
+// MustRegister is like Register but panics on conflicts
+func MustRegister[T Event](name string) {
+	if err := Register[T](name); err != nil {
+		panic(err)
+	}
+}
+
+func (r *Registry) register(name string, t reflect.Type) error {
+	if name == "" {
+		return fmt.Errorf("event name is required to register %s", t)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.byName[name]; ok {
+		if existing == t {
+			return nil
+		}
+		return fmt.Errorf("event %s is already registered as %s, cannot register %s", name, existing, t)
+	}
+	if existing, ok := r.byType[t]; ok {
+		return fmt.Errorf("type %s is already registered as event %s, cannot register it as %s", t, existing, name)
+	}
+
+	r.byName[name] = t
+	r.byType[t] = name
+	return nil
+}
+
+// IsRegistered reports whether an event name has a registered type
+func (r *Registry) IsRegistered(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.byName[name]
+	return ok
+}
+
+// NameOf returns the event name registered for type t
+func (r *Registry) NameOf(t reflect.Type) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	name, ok := r.byType[t]
+	return name, ok
+}
+
+// Names returns all registered event names
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.byName))
+	for name := range r.byName {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Validate checks that the event's name is registered with the event's Go type
+func (r *Registry) Validate(event Event) error {
+	r.mu.RLock()
+	t, ok := r.byName[event.EventName()]
+	r.mu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnregisteredEvent, event.EventName())
+	}
+	if actual := reflect.TypeOf(event); actual != t {
+		return fmt.Errorf("%w: %s is registered as %s, got %s", ErrEventTypeMismatch, event.EventName(), t, actual)
+	}
+	return nil
+}
+
+// Marshal serializes a registered event to JSON
+func (r *Registry) Marshal(event Event) ([]byte, error) {
+	if err := r.Validate(event); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event %s: %w", event.EventName(), err)
+	}
+	return data, nil
+}
+
+// Unmarshal rebuilds an event of the type registered for name.
+// Unregistered names are returned as *RawEvent.
+func (r *Registry) Unmarshal(name string, data []byte) (Event, error) {
+	r.mu.RLock()
+	t, ok := r.byName[name]
+	r.mu.RUnlock()
+
+	if !ok {
+		raw := &RawEvent{Payload: json.RawMessage(data)}
+		if err := json.Unmarshal(data, &raw.BaseEvent); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal event %s: %w", name, err)
+		}
+		return raw, nil
+	}
+
+	isPointer := t.Kind() == reflect.Ptr
+	elem := t
+	if isPointer {
+		elem = t.Elem()
+	}
+
+	value := reflect.New(elem)
+	if err := json.Unmarshal(data, value.Interface()); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event %s: %w", name, err)
+	}
+	if !isPointer {
+		value = value.Elem()
+	}
+
+	event, ok := value.Interface().(Event)
+	if !ok {
+		return nil, fmt.Errorf("registered type %s for event %s does not implement Event", t, name)
+	}
+	return event, nil
+}
+
+// MarshalEvent serializes a registered event using the default registry
+func MarshalEvent(event Event) ([]byte, error) {
+	return defaultRegistry.Marshal(event)
+}
+
+// UnmarshalEvent rebuilds an event using the default registry
+func UnmarshalEvent(name string, data []byte) (Event, error) {
+	return defaultRegistry.Unmarshal(name, data)
+}
+
+// encodeEvent serializes an event and its metadata for storage
+func encodeEvent(event Event) (payload []byte, metadata []byte, err error) {
+	payload, err = defaultRegistry.Marshal(event)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	meta := event.Metadata()
+	if meta == nil {
+		meta = map[string]interface{}{}
+	}
+	metadata, err = json.Marshal(meta)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal metadata for event %s: %w", event.EventName(), err)
+	}
+
+	return payload, metadata, nil
+}
+
+// decodeEvent rebuilds an event from its stored JSON payload
+func decodeEvent(name string, payload []byte) (Event, error) {
+	return defaultRegistry.Unmarshal(name, payload)
+}
@@ -0,0 +1,124 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/platform/logger/domain"
+)
+
+// localDispatcher is implemented by buses that can run this process's
+// subscribers directly, bypassing their transport
+type localDispatcher interface {
+	dispatch(ctx context.Context, event Event) error
+}
+
+// replayKey marks the context of handlers invoked by a replay
+type replayKey struct{}
+
+// IsReplay reports whether a handler is processing a replayed event, e.g. to
+// skip side effects such as emails that must not be repeated
+func IsReplay(ctx context.Context) bool {
+	replay, _ := ctx.Value(replayKey{}).(bool)
+	return replay
+}
+
+// ReplayOptions selects the stored events to replay
+type ReplayOptions struct {
+	From       time.Time
+	To         time.Time
+	EventNames []string
+	BatchSize  int
+	// DryRun counts matching events without invoking handlers
+	DryRun bool
+}
+
+// ReplayResult summarizes a replay run
+type ReplayResult struct {
+	Matched  int `json:"matched"`
+	Replayed int `json:"replayed"`
+	Failed   int `json:"failed"`
+	// Skipped counts events whose name is no longer registered
+	Skipped int `json:"skipped"`
+}
+
+// Replayer re-delivers stored events to the current subscribers of this
+// process, e.g. to backfill a new projection or rebuild one after a bug fix.
+// Replayed events are not re-recorded or re-sent through the transport.
+type Replayer struct {
+	store  EventStore
+	bus    EventBus
+	logger domain.Logger
+}
+
+func NewReplayer(store EventStore, bus EventBus, logger domain.Logger) *Replayer {
+	return &Replayer{store: store, bus: bus, logger: logger}
+}
+
+// Replay dispatches matching events in recorded order. Handler failures are
+// counted and logged; the replay continues with the next event.
+func (r *Replayer) Replay(ctx context.Context, opts ReplayOptions) (ReplayResult, error) {
+	var result ReplayResult
+
+	local, ok := r.bus.(localDispatcher)
+	if !ok {
+		return result, fmt.Errorf("event bus %T does not support replay", r.bus)
+	}
+
+	replayCtx := context.WithValue(ctx, replayKey{}, true)
+
+	err := r.store.Scan(ctx, EventStoreFilter{
+		From:       opts.From,
+		To:         opts.To,
+		EventNames: opts.EventNames,
+		BatchSize:  opts.BatchSize,
+	}, func(stored *StoredEvent) error {
+		result.Matched++
+
+		if !defaultRegistry.IsRegistered(stored.EventName) {
+			result.Skipped++
+			return nil
+		}
+		if opts.DryRun {
+			return nil
+		}
+
+		event, err := UnmarshalEvent(stored.EventName, stored.Payload)
+		if err != nil {
+			result.Failed++
+			r.logger.Error("Failed to decode stored event", map[string]interface{}{
+				"event_name": stored.EventName,
+				"event_id":   stored.EventID,
+				"error":      err.Error(),
+			})
+			return nil
+		}
+
+		if err := local.dispatch(replayCtx, event); err != nil {
+			result.Failed++
+			r.logger.Error("Replayed event failed", map[string]interface{}{
+				"event_name": stored.EventName,
+				"event_id":   stored.EventID,
+				"error":      err.Error(),
+			})
+			return nil
+		}
+
+		result.Replayed++
+		return ctx.Err()
+	})
+	if err != nil {
+		return result, fmt.Errorf("replay stopped: %w", err)
+	}
+
+	r.logger.Info("Event replay finished", map[string]interface{}{
+		"matched":  result.Matched,
+		"replayed": result.Replayed,
+		"failed":   result.Failed,
+		"skipped":  result.Skipped,
+		"dry_run":  opts.DryRun,
+	})
+
+	return result, nil
+}
@@ -0,0 +1,100 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// RetryPolicy controls how often a failing handler is re-invoked before the
+// event is given up on (and dead-lettered, see DeadLetterQueue).
+type RetryPolicy struct {
+	// MaxAttempts is the total number of invocations, including the first one
+	MaxAttempts int
+	// InitialBackoff is the delay before the second attempt
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts
+	MaxBackoff time.Duration
+	// Multiplier grows the delay after every failed attempt
+	Multiplier float64
+}
+
+// DefaultRetryPolicy returns a policy with a few quick in-process retries
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Multiplier:     2,
+	}
+}
+
+// NoRetry returns a policy that invokes the handler exactly once
+func NoRetry() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 1}
+}
+
+// Backoff returns the delay after the given failed attempt (1-based)
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := time.Duration(float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1)))
+	if p.MaxBackoff > 0 && (delay <= 0 || delay > p.MaxBackoff) {
+		return p.MaxBackoff
+	}
+	return delay
+}
+
+// RetryExhaustedError is returned by RetryMiddleware when every attempt failed
+type RetryExhaustedError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *RetryExhaustedError) Error() string {
+	return fmt.Sprintf("handler failed after %d attempt(s): %v", e.Attempts, e.Err)
+}
+
+func (e *RetryExhaustedError) Unwrap() error {
+	return e.Err
+}
+
+// RetryMiddleware re-invokes the handler with exponential backoff until it
+// succeeds or the policy's attempts are used up. Place RecoveryMiddleware
+// after it so panics are retried like errors.
+func RetryMiddleware(policy RetryPolicy) EventMiddleware {
+	maxAttempts := policy.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	return func(next EventHandler[Event]) EventHandler[Event] {
+		return func(ctx context.Context, event Event) error {
+			var err error
+			for attempt := 1; attempt <= maxAttempts; attempt++ {
+				if err = next(ctx, event); err == nil {
+					return nil
+				}
+
+				if attempt == maxAttempts {
+					break
+				}
+				eventsRetried.With(metricLabels(ctx, event)).Inc()
+
+				timer := time.NewTimer(policy.Backoff(attempt))
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return &RetryExhaustedError{Attempts: attempt, Err: err}
+				case <-timer.C:
+				}
+			}
+
+			return &RetryExhaustedError{Attempts: maxAttempts, Err: err}
+		}
+	}
+}
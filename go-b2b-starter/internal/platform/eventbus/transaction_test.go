@@ -0,0 +1,246 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/db/core"
+)
+
+const (
+	testUploadedEventType  = "test.uploaded"
+	testProcessedEventType = "test.processed"
+)
+
+type testUploaded struct {
+	BaseEvent
+}
+
+type testProcessed struct {
+	BaseEvent
+}
+
+func init() {
+	MustRegister[*testUploaded](testUploadedEventType)
+	MustRegister[*testProcessed](testProcessedEventType)
+}
+
+func newTestBaseEvent(name string) BaseEvent {
+	return BaseEvent{
+		ID:        name + "-" + time.Now().Format(time.RFC3339Nano),
+		Name:      name,
+		CreatedAt: time.Now(),
+		Meta:      make(map[string]interface{}),
+	}
+}
+
+func newTestUploaded() *testUploaded {
+	return &testUploaded{BaseEvent: newTestBaseEvent(testUploadedEventType)}
+}
+
+func newTestProcessed() *testProcessed {
+	return &testProcessed{BaseEvent: newTestBaseEvent(testProcessedEventType)}
+}
+
+// TestPublishAsyncInTransaction mirrors the default configuration (memory
+// backend with the event store): an event published in a transaction is
+// handled after the commit, outside the transaction, and the event the
+// handler publishes is recorded and delivered.
+func TestPublishAsyncInTransaction(t *testing.T) {
+	ctx := context.Background()
+	pool := &memoryPool{}
+	bus := NewRecordingEventBus(NewInMemoryEventBusWithConfig(DefaultAsyncConfig()), NewPostgresEventStore(pool))
+	defer bus.Close()
+
+	committed := make(chan struct{})
+	handlerErrs := make(chan error, 1)
+	processed := make(chan struct{}, 1)
+
+	err := bus.Subscribe(testUploadedEventType, func(ctx context.Context, event Event) error {
+		select {
+		case <-committed:
+		default:
+			handlerErrs <- errors.New("handler ran before the transaction committed")
+			return nil
+		}
+		if _, ok := core.TransactionFromContext(ctx); ok {
+			handlerErrs <- errors.New("handler context carries the publisher's transaction")
+			return nil
+		}
+		handlerErrs <- bus.Publish(ctx, newTestProcessed())
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if err := bus.Subscribe(testProcessedEventType, func(ctx context.Context, event Event) error {
+		processed <- struct{}{}
+		return nil
+	}); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	err = core.WithTransaction(ctx, pool, func(ctx context.Context, tx core.Transaction) error {
+		if err := bus.PublishAsync(ctx, newTestUploaded()); err != nil {
+			return err
+		}
+		// Give a handler running inside the transaction time to show up
+		time.Sleep(50 * time.Millisecond)
+		close(committed)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction: %v", err)
+	}
+
+	select {
+	case err := <-handlerErrs:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler did not run after the commit")
+	}
+	select {
+	case <-processed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("event published by the handler was not delivered")
+	}
+
+	if got := pool.committedEvents(); len(got) != 2 || got[0] != testUploadedEventType || got[1] != testProcessedEventType {
+		t.Fatalf("recorded events = %v, want [%s %s]", got, testUploadedEventType, testProcessedEventType)
+	}
+}
+
+// TestPublishInRolledBackTransaction checks that events published in a
+// transaction that rolls back are neither handled nor recorded.
+func TestPublishInRolledBackTransaction(t *testing.T) {
+	ctx := context.Background()
+	pool := &memoryPool{}
+	bus := NewRecordingEventBus(NewInMemoryEventBusWithConfig(DefaultAsyncConfig()), NewPostgresEventStore(pool))
+
+	var mu sync.Mutex
+	handled := 0
+	if err := bus.Subscribe(testUploadedEventType, func(ctx context.Context, event Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		handled++
+		return nil
+	}); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	rollback := errors.New("rollback")
+	err := core.WithTransaction(ctx, pool, func(ctx context.Context, tx core.Transaction) error {
+		if err := bus.Publish(ctx, newTestUploaded()); err != nil {
+			return err
+		}
+		if err := bus.PublishAsync(ctx, newTestUploaded()); err != nil {
+			return err
+		}
+		return rollback
+	})
+	if !errors.Is(err, rollback) {
+		t.Fatalf("WithTransaction = %v, want %v", err, rollback)
+	}
+
+	// Close drains the async queues
+	if err := bus.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if handled != 0 {
+		t.Fatalf("handler ran %d times for events of a rolled back transaction", handled)
+	}
+	if got := pool.committedEvents(); len(got) != 0 {
+		t.Fatalf("recorded events = %v, want none", got)
+	}
+}
+
+// memoryPool is a core.Pool whose transactions collect the event names
+// appended to the event store, keeping them once committed
+type memoryPool struct {
+	mu        sync.Mutex
+	committed []string
+}
+
+func (p *memoryPool) committedEvents() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.committed...)
+}
+
+func (p *memoryPool) BeginTx(context.Context) (core.Transaction, error) {
+	return &memoryTx{pool: p}, nil
+}
+
+func (p *memoryPool) Execute(context.Context, string, ...any) error {
+	return errors.New("memoryPool only runs queries in transactions")
+}
+
+func (p *memoryPool) Query(context.Context, string, ...any) (core.Rows, error) {
+	return nil, errors.New("memoryPool only runs queries in transactions")
+}
+
+func (p *memoryPool) QueryRow(context.Context, string, ...any) core.Row { return nil }
+func (p *memoryPool) Ping(context.Context) error                        { return nil }
+func (p *memoryPool) Close() error                                      { return nil }
+func (p *memoryPool) Stats() core.PoolStats                             { return core.PoolStats{} }
+func (p *memoryPool) SetMaxConnections(int)                             {}
+func (p *memoryPool) SetMaxConnectionLifetime(time.Duration)            {}
+func (p *memoryPool) SetMaxConnectionIdleTime(time.Duration)            {}
+
+// memoryTx fails like a pgx transaction once it is committed or rolled back
+type memoryTx struct {
+	pool *memoryPool
+
+	mu      sync.Mutex
+	pending []string
+	closed  bool
+}
+
+func (tx *memoryTx) Execute(_ context.Context, _ string, args ...any) error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.closed {
+		return errors.New("tx is closed")
+	}
+	// appendEvent takes the event name as its second argument
+	tx.pending = append(tx.pending, args[1].(string))
+	return nil
+}
+
+func (tx *memoryTx) Commit(context.Context) error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.closed {
+		return errors.New("tx is closed")
+	}
+	tx.closed = true
+
+	tx.pool.mu.Lock()
+	tx.pool.committed = append(tx.pool.committed, tx.pending...)
+	tx.pool.mu.Unlock()
+	return nil
+}
+
+func (tx *memoryTx) Rollback(context.Context) error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	tx.closed = true
+	return nil
+}
+
+func (tx *memoryTx) Query(context.Context, string, ...any) (core.Rows, error) {
+	return nil, errors.New("memoryTx does not run queries")
+}
+
+func (tx *memoryTx) QueryRow(context.Context, string, ...any) core.Row { return nil }
+func (tx *memoryTx) BeginTx(context.Context) (core.Transaction, error) { return tx, nil }
+func (tx *memoryTx) Ping(context.Context) error                        { return nil }
+func (tx *memoryTx) Close() error                                      { return nil }
@@ -0,0 +1,52 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// TypedHandler adapts a handler for a concrete event type to
+// EventHandler[Event] and returns the event name T is registered under.
+// It fails if T is not registered.
+func TypedHandler[T Event](handler func(ctx context.Context, event T) error) (string, EventHandler[Event], error) {
+	t := reflect.TypeFor[T]()
+	name, ok := defaultRegistry.NameOf(t)
+	if !ok {
+		return "", nil, fmt.Errorf("%w: no event name registered for type %s", ErrUnregisteredEvent, t)
+	}
+
+	adapted := func(ctx context.Context, event Event) error {
+		typed, ok := event.(T)
+		if !ok {
+			return fmt.Errorf("%w: %s handler expects %s, got %T", ErrEventTypeMismatch, name, t, event)
+		}
+		return handler(ctx, typed)
+	}
+
+	return name, adapted, nil
+}
+
+// SubscribeTyped subscribes a handler for the event registered as type T,
+// so handlers no longer type-assert or repeat event names:
+//
+//	eventbus.SubscribeTyped(bus, func(ctx context.Context, e *events.DocumentUploaded) error {
+//		return listener.Handle(ctx, e.DocumentID)
+//	})
+func SubscribeTyped[T Event](bus EventBus, handler func(ctx context.Context, event T) error) error {
+	name, adapted, err := TypedHandler(handler)
+	if err != nil {
+		return err
+	}
+	// Name the subscription after the typed handler, not the adapter
+	return SubscribeNamed(bus, name, funcName(handler), adapted)
+}
+
+// SubscribeTypedWithRetry is SubscribeTyped for DeadLetterQueue subscriptions
+func SubscribeTypedWithRetry[T Event](q *DeadLetterQueue, handlerName string, handler func(ctx context.Context, event T) error, opts ...SubscribeOption) error {
+	name, adapted, err := TypedHandler(handler)
+	if err != nil {
+		return err
+	}
+	return q.Subscribe(name, handlerName, adapted, opts...)
+}
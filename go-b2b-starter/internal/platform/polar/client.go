@@ -4,12 +4,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"time"
 )
 
+// ErrRateLimited is returned (wrapped) when Polar answers with HTTP 429
+var ErrRateLimited = errors.New("polar API rate limit exceeded")
+
 // Client provides a low-level HTTP client for Polar API
 // This is a generic HTTP wrapper - business logic should be in higher layers
 type Client struct {
@@ -85,6 +89,9 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 	if resp.StatusCode >= 400 {
 		defer resp.Body.Close()
 		bodyBytes, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return nil, fmt.Errorf("%w: %s", ErrRateLimited, string(bodyBytes))
+		}
 		return nil, fmt.Errorf("Polar API error (HTTP %d): %s", resp.StatusCode, string(bodyBytes))
 	}
 
@@ -2,6 +2,7 @@ package polar
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -21,6 +22,10 @@ type Config struct {
 	// Get this from Polar Dashboard → Settings → Webhooks
 	WebhookSecret string `mapstructure:"WEBHOOK_SECRET"`
 
+	// WebhookTolerance is the maximum age (and clock skew) accepted for the
+	// Webhook-Timestamp header; older deliveries are rejected as replays
+	WebhookTolerance time.Duration `mapstructure:"POLAR_WEBHOOK_TOLERANCE"`
+
 	// Debug enables debug logging
 	Debug bool `mapstructure:"POLAR_DEBUG"`
 }
@@ -37,6 +42,7 @@ func LoadConfig() (Config, error) {
 	// Set default values
 	viper.SetDefault("POLAR_BASE_URL", "https://api.polar.sh")
 	viper.SetDefault("POLAR_DEBUG", false)
+	viper.SetDefault("POLAR_WEBHOOK_TOLERANCE", "5m")
 
 	// Best-effort: ignore missing file, allow env-only usage
 	if err := viper.ReadInConfig(); err == nil {
@@ -65,6 +71,10 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("polar base URL is required (POLAR_BASE_URL)")
 	}
 
+	if c.WebhookTolerance < 0 {
+		return fmt.Errorf("polar webhook tolerance must not be negative (POLAR_WEBHOOK_TOLERANCE)")
+	}
+
 	// WebhookSecret is optional - only needed for webhook verification
 	// If not provided, webhook signature verification will be skipped (with warning)
 
@@ -74,15 +84,17 @@ func (c *Config) Validate() error {
 // DefaultConfig returns a configuration with sane defaults for production
 func DefaultConfig() *Config {
 	return &Config{
-		BaseURL: "https://api.polar.sh",
-		Debug:   false,
+		BaseURL:          "https://api.polar.sh",
+		WebhookTolerance: 5 * time.Minute,
+		Debug:            false,
 	}
 }
 
 // SandboxConfig returns a configuration with defaults for sandbox environment
 func SandboxConfig() *Config {
 	return &Config{
-		BaseURL: "https://sandbox-api.polar.sh",
-		Debug:   true,
+		BaseURL:          "https://sandbox-api.polar.sh",
+		WebhookTolerance: 5 * time.Minute,
+		Debug:            true,
 	}
 }
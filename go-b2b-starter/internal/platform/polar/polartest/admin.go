@@ -0,0 +1,162 @@
+package polartest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// AdminPrefix prefixes the routes that script the fake over HTTP, for use
+// when it runs as a standalone process (cmd/fakepolar). They need no token.
+const AdminPrefix = "/_polartest"
+
+// webhookRequest is the body of the admin webhook route. Without data, the
+// stored subscription of the external customer is sent.
+type webhookRequest struct {
+	URL                string         `json:"url"`
+	Type               string         `json:"type"`
+	ExternalCustomerID string         `json:"external_customer_id"`
+	Data               map[string]any `json:"data"`
+}
+
+// failureRequest is the body of the admin failures route
+type failureRequest struct {
+	Method     string `json:"method"`
+	PathPrefix string `json:"path_prefix"`
+	Status     int    `json:"status"`
+	Times      int    `json:"times"`
+}
+
+func (s *Server) registerAdminRoutes() {
+	s.mux.HandleFunc("PUT "+AdminPrefix+"/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		var sub Subscription
+		if !decodeAdmin(w, r, &sub) {
+			return
+		}
+		writeJSON(w, http.StatusOK, s.SetSubscription(sub))
+	})
+
+	s.mux.HandleFunc("PUT "+AdminPrefix+"/checkouts", func(w http.ResponseWriter, r *http.Request) {
+		var session CheckoutSession
+		if !decodeAdmin(w, r, &session) {
+			return
+		}
+		writeJSON(w, http.StatusOK, s.SetCheckoutSession(session))
+	})
+
+	s.mux.HandleFunc("POST "+AdminPrefix+"/checkouts/{id}/complete", func(w http.ResponseWriter, r *http.Request) {
+		sub, err := s.CompleteCheckout(r.PathValue("id"))
+		if err != nil {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, sub)
+	})
+
+	s.mux.HandleFunc("PUT "+AdminPrefix+"/products", func(w http.ResponseWriter, r *http.Request) {
+		var products []Product
+		if !decodeAdmin(w, r, &products) {
+			return
+		}
+		s.SetProducts(products...)
+		writeJSON(w, http.StatusOK, products)
+	})
+
+	s.mux.HandleFunc("GET "+AdminPrefix+"/meter-events", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, s.MeterEvents())
+	})
+
+	s.mux.HandleFunc("POST "+AdminPrefix+"/webhooks", func(w http.ResponseWriter, r *http.Request) {
+		var req webhookRequest
+		if !decodeAdmin(w, r, &req) {
+			return
+		}
+		if req.URL == "" {
+			req.URL = s.WebhookURL
+		}
+		if req.URL == "" || req.Type == "" {
+			writeError(w, http.StatusBadRequest, "url and type are required")
+			return
+		}
+
+		var err error
+		if req.Data != nil {
+			err = s.SendWebhook(r.Context(), req.URL, req.Type, req.Data)
+		} else {
+			err = s.SendSubscriptionWebhook(r.Context(), req.URL, req.Type, req.ExternalCustomerID)
+		}
+		if err != nil {
+			writeError(w, http.StatusBadGateway, err.Error())
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.mux.HandleFunc("POST "+AdminPrefix+"/failures", func(w http.ResponseWriter, r *http.Request) {
+		var req failureRequest
+		if !decodeAdmin(w, r, &req) {
+			return
+		}
+		if req.Status < 400 || req.Times <= 0 {
+			writeError(w, http.StatusBadRequest, "status must be an error status and times positive")
+			return
+		}
+		s.FailNext(strings.ToUpper(req.Method), req.PathPrefix, req.Status, req.Times)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.mux.HandleFunc("POST "+AdminPrefix+"/reset", func(w http.ResponseWriter, r *http.Request) {
+		s.Reset()
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// handleCheckoutPage stands in for Polar's hosted checkout: opening the
+// checkout URL pays it, announces the new subscription to WebhookURL and
+// redirects to the success URL
+func (s *Server) handleCheckoutPage(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	sub, err := s.CompleteCheckout(id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if s.WebhookURL != "" {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), 10*time.Second)
+		defer cancel()
+		if err := s.SendSubscriptionWebhook(ctx, s.WebhookURL, "subscription.created", sub.ExternalCustomerID); err != nil {
+			writeError(w, http.StatusBadGateway, err.Error())
+			return
+		}
+	}
+
+	session, _ := s.CheckoutSession(id)
+	if session.SuccessURL == "" {
+		writeJSON(w, http.StatusOK, session)
+		return
+	}
+	http.Redirect(w, r, strings.ReplaceAll(session.SuccessURL, "{CHECKOUT_ID}", id), http.StatusSeeOther)
+}
+
+// handlePortalPage stands in for Polar's customer portal and shows the
+// customer's subscription
+func (s *Server) handlePortalPage(w http.ResponseWriter, r *http.Request) {
+	sub, ok := s.Subscription(r.PathValue("customer"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "customer not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, sub)
+}
+
+func decodeAdmin(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,713 @@
+// Package polartest provides an in-process fake of the Polar API for tests
+// and offline development.
+//
+// The fake serves the endpoints the billing module calls (subscriptions,
+// checkouts, customer sessions, products and event ingestion) from scriptable
+// in-memory state, and sends webhooks signed like Polar's:
+//
+//	srv := polartest.NewServer()
+//	defer srv.Close()
+//
+//	srv.SetSubscription(polartest.Subscription{ExternalCustomerID: "org_1", ProductID: "prod_pro"})
+//	client, _ := polar.NewClient(srv.Config())
+package polartest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/platform/polar"
+)
+
+// Credentials accepted and used by the fake
+const (
+	AccessToken   = "polar_oat_polartest"
+	WebhookSecret = "polar_whs_polartest"
+)
+
+// Checkout statuses
+const (
+	CheckoutOpen      = "open"
+	CheckoutConfirmed = "confirmed"
+	CheckoutSucceeded = "succeeded"
+	CheckoutFailed    = "failed"
+	CheckoutExpired   = "expired"
+)
+
+// Subscription is a subscription served for an external customer ID
+type Subscription struct {
+	ID                 string            `json:"id"`
+	ExternalCustomerID string            `json:"external_customer_id"`
+	CustomerID         string            `json:"customer_id"`
+	CustomerMetadata   map[string]string `json:"customer_metadata"`
+	ProductID          string            `json:"product_id"`
+	ProductName        string            `json:"product_name"`
+	ProductMetadata    map[string]string `json:"product_metadata"`
+	Status             string            `json:"status"`
+	CurrentPeriodStart time.Time         `json:"current_period_start"`
+	CurrentPeriodEnd   time.Time         `json:"current_period_end"`
+	CancelAtPeriodEnd  bool              `json:"cancel_at_period_end"`
+	CanceledAt         *time.Time        `json:"canceled_at"`
+}
+
+// CheckoutSession is a checkout served by ID. While PendingPolls is positive,
+// reads report the checkout as confirmed and count PendingPolls down, so
+// polling callers see the payment complete after that many reads.
+type CheckoutSession struct {
+	ID                 string    `json:"id"`
+	Status             string    `json:"status"`
+	ExternalCustomerID string    `json:"external_customer_id"`
+	CustomerID         string    `json:"customer_id"`
+	ProductID          string    `json:"product_id"`
+	SubscriptionID     string    `json:"subscription_id"`
+	Amount             int64     `json:"amount"`
+	SuccessURL         string    `json:"success_url"`
+	ReturnURL          string    `json:"return_url"`
+	CreatedAt          time.Time `json:"created_at"`
+	ExpiresAt          time.Time `json:"expires_at"`
+	PendingPolls       int       `json:"pending_polls"`
+}
+
+// Product is a product listed by the products endpoint. MeterCredits adds a
+// meter_credit benefit with that many units.
+type Product struct {
+	ID           string            `json:"id"`
+	Name         string            `json:"name"`
+	Metadata     map[string]string `json:"metadata"`
+	MeterCredits int32             `json:"meter_credits"`
+	IsArchived   bool              `json:"is_archived"`
+}
+
+// MeterEvent is an event received by the ingestion endpoint
+type MeterEvent struct {
+	Name               string         `json:"name"`
+	ExternalCustomerID string         `json:"external_customer_id"`
+	Metadata           map[string]any `json:"metadata"`
+	ReceivedAt         time.Time      `json:"received_at"`
+}
+
+// Server is a fake Polar API. All methods are safe for concurrent use.
+type Server struct {
+	// URL is the base URL of the fake, e.g. http://127.0.0.1:51234
+	URL string
+
+	// WebhookURL, when set, receives the webhooks of checkouts completed on
+	// the fake's hosted checkout page
+	WebhookURL string
+
+	server *httptest.Server
+	mux    *http.ServeMux
+
+	mu            sync.Mutex
+	subscriptions map[string]*Subscription
+	checkouts     map[string]*CheckoutSession
+	products      []Product
+	events        []MeterEvent
+	failures      []*failure
+	sequence      int
+}
+
+// failure is a scripted error response
+type failure struct {
+	method     string
+	pathPrefix string
+	status     int
+	remaining  int
+}
+
+// NewServer starts a fake Polar API on a loopback port. Close it when done.
+func NewServer() *Server {
+	s := newServer()
+	s.server = httptest.NewServer(s)
+	s.URL = s.server.URL
+	return s
+}
+
+// NewServerAt starts a fake Polar API listening on addr, e.g. "localhost:8090"
+func NewServerAt(addr string) (*Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	s := newServer()
+	s.server = httptest.NewUnstartedServer(s)
+	s.server.Listener.Close()
+	s.server.Listener = listener
+	s.server.Start()
+	s.URL = s.server.URL
+	return s, nil
+}
+
+func newServer() *Server {
+	s := &Server{
+		mux:           http.NewServeMux(),
+		subscriptions: make(map[string]*Subscription),
+		checkouts:     make(map[string]*CheckoutSession),
+	}
+
+	s.mux.HandleFunc("GET /v1/subscriptions", s.authorized(s.handleListSubscriptions))
+	s.mux.HandleFunc("GET /v1/subscriptions/", s.authorized(s.handleListSubscriptions))
+	s.mux.HandleFunc("POST /v1/checkouts/", s.authorized(s.handleCreateCheckout))
+	s.mux.HandleFunc("GET /v1/checkouts/custom/{id}", s.authorized(s.handleGetCheckout))
+	s.mux.HandleFunc("GET /v1/checkouts/{id}", s.authorized(s.handleGetCheckout))
+	s.mux.HandleFunc("POST /v1/customer-sessions/", s.authorized(s.handleCreateCustomerSession))
+	s.mux.HandleFunc("GET /v1/products/", s.authorized(s.handleListProducts))
+	s.mux.HandleFunc("POST /v1/events/ingest", s.authorized(s.handleIngestEvents))
+
+	// Pages a browser is sent to during offline development
+	s.mux.HandleFunc("GET /checkout/{id}", s.handleCheckoutPage)
+	s.mux.HandleFunc("GET /portal/{customer}", s.handlePortalPage)
+
+	s.registerAdminRoutes()
+
+	return s
+}
+
+// Close shuts the fake down
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Config returns a Polar client configuration pointing at the fake
+func (s *Server) Config() *polar.Config {
+	return &polar.Config{
+		AccessToken:      AccessToken,
+		BaseURL:          s.URL,
+		WebhookSecret:    WebhookSecret,
+		WebhookTolerance: 5 * time.Minute,
+	}
+}
+
+// ServeHTTP serves the fake API, answering with a scripted failure first
+// when one matches the request
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if status, ok := s.takeFailure(r.Method, r.URL.Path); ok {
+		writeError(w, status, "scripted failure")
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// SetSubscription stores the subscription of an external customer, filling
+// in IDs, an active status and a current monthly period when left empty
+func (s *Server) SetSubscription(sub Subscription) Subscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sub.ID == "" {
+		sub.ID = s.nextID("sub")
+	}
+	if sub.CustomerID == "" {
+		sub.CustomerID = s.customerIDLocked(sub.ExternalCustomerID)
+	}
+	if sub.Status == "" {
+		sub.Status = "active"
+	}
+	if sub.CurrentPeriodStart.IsZero() {
+		sub.CurrentPeriodStart = time.Now().UTC().Truncate(time.Second)
+	}
+	if sub.CurrentPeriodEnd.IsZero() {
+		sub.CurrentPeriodEnd = sub.CurrentPeriodStart.AddDate(0, 1, 0)
+	}
+	if sub.ProductName == "" || sub.ProductMetadata == nil {
+		if product, ok := s.productLocked(sub.ProductID); ok {
+			if sub.ProductName == "" {
+				sub.ProductName = product.Name
+			}
+			if sub.ProductMetadata == nil {
+				sub.ProductMetadata = product.Metadata
+			}
+		}
+	}
+
+	s.subscriptions[sub.ExternalCustomerID] = &sub
+	return sub
+}
+
+// Subscription returns the subscription of an external customer
+func (s *Server) Subscription(externalCustomerID string) (Subscription, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub, ok := s.subscriptions[externalCustomerID]
+	if !ok {
+		return Subscription{}, false
+	}
+	return *sub, true
+}
+
+// UpdateSubscription changes the subscription of an external customer in place
+func (s *Server) UpdateSubscription(externalCustomerID string, update func(*Subscription)) (Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub, ok := s.subscriptions[externalCustomerID]
+	if !ok {
+		return Subscription{}, fmt.Errorf("no subscription for external customer %q", externalCustomerID)
+	}
+	update(sub)
+	return *sub, nil
+}
+
+// DeleteSubscription removes the subscription of an external customer
+func (s *Server) DeleteSubscription(externalCustomerID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.subscriptions, externalCustomerID)
+}
+
+// SetCheckoutSession stores a checkout, filling in its ID, status and
+// timestamps when left empty
+func (s *Server) SetCheckoutSession(session CheckoutSession) CheckoutSession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if session.ID == "" {
+		session.ID = s.nextID("checkout")
+	}
+	if session.Status == "" {
+		session.Status = CheckoutSucceeded
+	}
+	if session.CustomerID == "" && session.ExternalCustomerID != "" {
+		session.CustomerID = s.customerIDLocked(session.ExternalCustomerID)
+	}
+	if session.SubscriptionID == "" && session.Status == CheckoutSucceeded {
+		if sub, ok := s.subscriptions[session.ExternalCustomerID]; ok {
+			session.SubscriptionID = sub.ID
+		}
+	}
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	}
+	if session.ExpiresAt.IsZero() {
+		session.ExpiresAt = session.CreatedAt.Add(time.Hour)
+	}
+
+	s.checkouts[session.ID] = &session
+	return session
+}
+
+// CheckoutSession returns a checkout by ID
+func (s *Server) CheckoutSession(id string) (CheckoutSession, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.checkouts[id]
+	if !ok {
+		return CheckoutSession{}, false
+	}
+	return *session, true
+}
+
+// CompleteCheckout pays a checkout: its customer gets an active subscription
+// to the checkout's product and the checkout succeeds
+func (s *Server) CompleteCheckout(id string) (Subscription, error) {
+	session, ok := s.CheckoutSession(id)
+	if !ok {
+		return Subscription{}, fmt.Errorf("no checkout %q", id)
+	}
+	if session.ExternalCustomerID == "" {
+		return Subscription{}, fmt.Errorf("checkout %q has no external customer ID", id)
+	}
+
+	sub := s.SetSubscription(Subscription{
+		ExternalCustomerID: session.ExternalCustomerID,
+		ProductID:          session.ProductID,
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := s.checkouts[id]
+	stored.Status = CheckoutSucceeded
+	stored.SubscriptionID = sub.ID
+	stored.CustomerID = sub.CustomerID
+	return sub, nil
+}
+
+// SetProducts replaces the product catalog. Once it has products, checkouts
+// for unknown products are rejected like Polar does.
+func (s *Server) SetProducts(products ...Product) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.products = append([]Product(nil), products...)
+}
+
+// MeterEvents returns the ingested events in the order they were received
+func (s *Server) MeterEvents() []MeterEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]MeterEvent(nil), s.events...)
+}
+
+// MeterUsage sums the counts ingested for a meter and external customer
+func (s *Server) MeterUsage(externalCustomerID, meterSlug string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var total int64
+	for _, event := range s.events {
+		if event.ExternalCustomerID == externalCustomerID && event.Name == meterSlug {
+			total += eventCount(event)
+		}
+	}
+	return total
+}
+
+// FailNext answers the next times requests matching method and path prefix
+// with status instead of serving them, e.g. FailNext("GET",
+// "/v1/checkouts/", http.StatusServiceUnavailable, 2). Use 429 to exercise
+// rate limit handling. An empty method matches any method.
+func (s *Server) FailNext(method, pathPrefix string, status, times int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = append(s.failures, &failure{
+		method:     method,
+		pathPrefix: pathPrefix,
+		status:     status,
+		remaining:  times,
+	})
+}
+
+// Reset clears all state and scripted failures
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.subscriptions = make(map[string]*Subscription)
+	s.checkouts = make(map[string]*CheckoutSession)
+	s.products = nil
+	s.events = nil
+	s.failures = nil
+}
+
+func (s *Server) takeFailure(method, path string) (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, f := range s.failures {
+		if (f.method != "" && f.method != method) || !strings.HasPrefix(path, f.pathPrefix) {
+			continue
+		}
+		f.remaining--
+		if f.remaining <= 0 {
+			s.failures = append(s.failures[:i], s.failures[i+1:]...)
+		}
+		return f.status, true
+	}
+	return 0, false
+}
+
+// authorized rejects requests without the fake's access token, like Polar
+// rejects an invalid organization access token
+func (s *Server) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+AccessToken {
+			writeError(w, http.StatusUnauthorized, "invalid access token")
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (s *Server) handleListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	externalCustomerID := query.Get("customer_external_id")
+	if externalCustomerID == "" {
+		externalCustomerID = query.Get("external_customer_id")
+	}
+
+	s.mu.Lock()
+	items := []map[string]any{}
+	if sub, ok := s.subscriptions[externalCustomerID]; ok {
+		items = append(items, s.subscriptionObjectLocked(sub))
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, listResponse(items, len(items), 1))
+}
+
+func (s *Server) handleCreateCheckout(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Products           []string `json:"products"`
+		ExternalCustomerID string   `json:"external_customer_id"`
+		SuccessURL         string   `json:"success_url"`
+		ReturnURL          string   `json:"return_url"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "invalid request body")
+		return
+	}
+	if len(body.Products) == 0 {
+		writeError(w, http.StatusUnprocessableEntity, "products is required")
+		return
+	}
+
+	s.mu.Lock()
+	product, known := s.productLocked(body.Products[0])
+	if len(s.products) > 0 && (!known || product.IsArchived) {
+		s.mu.Unlock()
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("product %s does not exist", body.Products[0]))
+		return
+	}
+	s.mu.Unlock()
+
+	session := s.SetCheckoutSession(CheckoutSession{
+		Status:             CheckoutOpen,
+		ExternalCustomerID: body.ExternalCustomerID,
+		ProductID:          body.Products[0],
+		SuccessURL:         body.SuccessURL,
+		ReturnURL:          body.ReturnURL,
+	})
+
+	writeJSON(w, http.StatusCreated, map[string]any{
+		"id":         session.ID,
+		"status":     session.Status,
+		"url":        s.URL + "/checkout/" + session.ID,
+		"expires_at": session.ExpiresAt.Format(time.RFC3339),
+	})
+}
+
+func (s *Server) handleGetCheckout(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	session, ok := s.checkouts[r.PathValue("id")]
+	if !ok {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, "checkout not found")
+		return
+	}
+
+	status := session.Status
+	if session.PendingPolls > 0 {
+		session.PendingPolls--
+		status = CheckoutConfirmed
+	}
+	object := map[string]any{
+		"id":                   session.ID,
+		"status":               status,
+		"amount":               session.Amount,
+		"customer_external_id": session.ExternalCustomerID,
+		"customer_id":          session.CustomerID,
+		"product":              map[string]any{"id": session.ProductID},
+		"customer": map[string]any{
+			"id":          session.CustomerID,
+			"external_id": session.ExternalCustomerID,
+		},
+		"subscription": map[string]any{"id": session.SubscriptionID},
+		"created_at":   session.CreatedAt.Format(time.RFC3339),
+		"expires_at":   session.ExpiresAt.Format(time.RFC3339),
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, object)
+}
+
+func (s *Server) handleCreateCustomerSession(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		ExternalCustomerID string `json:"external_customer_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "invalid request body")
+		return
+	}
+
+	// Polar only knows customers that completed a checkout
+	if _, ok := s.Subscription(body.ExternalCustomerID); !ok {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("customer %s does not exist", body.ExternalCustomerID))
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]any{
+		"customer_portal_url": s.URL + "/portal/" + body.ExternalCustomerID,
+		"expires_at":          time.Now().UTC().Add(time.Hour).Format(time.RFC3339),
+	})
+}
+
+func (s *Server) handleListProducts(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 10
+	}
+	page, err := strconv.Atoi(query.Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+
+	s.mu.Lock()
+	var matching []Product
+	for _, product := range s.products {
+		if query.Get("is_archived") == "false" && product.IsArchived {
+			continue
+		}
+		matching = append(matching, product)
+	}
+	s.mu.Unlock()
+
+	maxPage := max(1, (len(matching)+limit-1)/limit)
+	items := []map[string]any{}
+	for i := (page - 1) * limit; i < min(page*limit, len(matching)); i++ {
+		items = append(items, productObject(matching[i]))
+	}
+
+	writeJSON(w, http.StatusOK, listResponse(items, len(matching), maxPage))
+}
+
+func (s *Server) handleIngestEvents(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Events []MeterEvent `json:"events"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "invalid request body")
+		return
+	}
+	for _, event := range body.Events {
+		if event.Name == "" || event.ExternalCustomerID == "" {
+			writeError(w, http.StatusUnprocessableEntity, "events need a name and external_customer_id")
+			return
+		}
+	}
+
+	now := time.Now().UTC()
+	s.mu.Lock()
+	for _, event := range body.Events {
+		event.ReceivedAt = now
+		s.events = append(s.events, event)
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]any{"inserted": len(body.Events)})
+}
+
+// subscriptionObjectLocked renders a subscription like Polar's API and
+// webhooks do
+func (s *Server) subscriptionObjectLocked(sub *Subscription) map[string]any {
+	product := map[string]any{
+		"id":       sub.ProductID,
+		"name":     sub.ProductName,
+		"metadata": orEmpty(sub.ProductMetadata),
+	}
+	if catalog, ok := s.productLocked(sub.ProductID); ok {
+		product = productObject(catalog)
+		if sub.ProductName != "" {
+			product["name"] = sub.ProductName
+		}
+		if sub.ProductMetadata != nil {
+			product["metadata"] = sub.ProductMetadata
+		}
+	}
+
+	var canceledAt any
+	if sub.CanceledAt != nil {
+		canceledAt = sub.CanceledAt.UTC().Format(time.RFC3339)
+	}
+
+	return map[string]any{
+		"id":                   sub.ID,
+		"status":               sub.Status,
+		"customer_id":          sub.CustomerID,
+		"product_id":           sub.ProductID,
+		"current_period_start": sub.CurrentPeriodStart.UTC().Format(time.RFC3339),
+		"current_period_end":   sub.CurrentPeriodEnd.UTC().Format(time.RFC3339),
+		"cancel_at_period_end": sub.CancelAtPeriodEnd,
+		"canceled_at":          canceledAt,
+		"customer": map[string]any{
+			"id":          sub.CustomerID,
+			"external_id": sub.ExternalCustomerID,
+			"metadata":    orEmpty(sub.CustomerMetadata),
+		},
+		"product": product,
+	}
+}
+
+func (s *Server) productLocked(id string) (Product, bool) {
+	for _, product := range s.products {
+		if product.ID == id {
+			return product, true
+		}
+	}
+	return Product{}, false
+}
+
+// customerIDLocked returns the Polar customer ID of an external customer,
+// keeping the one already assigned
+func (s *Server) customerIDLocked(externalCustomerID string) string {
+	if sub, ok := s.subscriptions[externalCustomerID]; ok && sub.CustomerID != "" {
+		return sub.CustomerID
+	}
+	return "cus_" + externalCustomerID
+}
+
+func (s *Server) nextID(prefix string) string {
+	s.sequence++
+	return fmt.Sprintf("%s_%06d", prefix, s.sequence)
+}
+
+func productObject(product Product) map[string]any {
+	benefits := []map[string]any{}
+	if product.MeterCredits > 0 {
+		benefits = append(benefits, map[string]any{
+			"type":       "meter_credit",
+			"properties": map[string]any{"units": product.MeterCredits},
+		})
+	}
+	return map[string]any{
+		"id":          product.ID,
+		"name":        product.Name,
+		"metadata":    orEmpty(product.Metadata),
+		"is_archived": product.IsArchived,
+		"benefits":    benefits,
+	}
+}
+
+func listResponse(items []map[string]any, totalCount, maxPage int) map[string]any {
+	return map[string]any{
+		"items": items,
+		"pagination": map[string]any{
+			"total_count": totalCount,
+			"max_page":    maxPage,
+		},
+	}
+}
+
+// eventCount is the usage an ingested event reports in its count metadata
+func eventCount(event MeterEvent) int64 {
+	switch count := event.Metadata["count"].(type) {
+	case float64:
+		return int64(count)
+	case int64:
+		return count
+	case int:
+		return int64(count)
+	}
+	return 0
+}
+
+func orEmpty(m map[string]string) map[string]string {
+	if m == nil {
+		return map[string]string{}
+	}
+	return m
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, detail string) {
+	writeJSON(w, status, map[string]any{"detail": detail})
+}
@@ -0,0 +1,104 @@
+package polartest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/moasq/go-b2b-starter/internal/platform/polar"
+)
+
+// Standard Webhooks headers sent by Polar
+const (
+	HeaderWebhookID        = "Webhook-Id"
+	HeaderWebhookTimestamp = "Webhook-Timestamp"
+	HeaderWebhookSignature = "Webhook-Signature"
+)
+
+// SignWebhook returns the headers Polar sends with a webhook payload, signed
+// with secret at timestamp. Tests that call a handler directly use it to sign
+// a body they built themselves.
+func SignWebhook(secret, webhookID string, timestamp time.Time, payload []byte) http.Header {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+
+	headers := make(http.Header)
+	headers.Set("Content-Type", "application/json")
+	headers.Set(HeaderWebhookID, webhookID)
+	headers.Set(HeaderWebhookTimestamp, unix)
+	headers.Set(HeaderWebhookSignature, "v1,"+polar.ComputeWebhookSignature(secret, webhookID, unix, payload))
+	return headers
+}
+
+// WebhookPayload returns the body of a Polar webhook
+func WebhookPayload(eventType string, data any) ([]byte, error) {
+	payload, err := json.Marshal(map[string]any{
+		"type": eventType,
+		"data": data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+	return payload, nil
+}
+
+// SendWebhook delivers a webhook to url, signed with WebhookSecret. It fails
+// unless the receiver answers with a 2xx status.
+func (s *Server) SendWebhook(ctx context.Context, url, eventType string, data any) error {
+	payload, err := WebhookPayload(eventType, data)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	webhookID := s.nextID("msg")
+	s.mu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header = SignWebhook(WebhookSecret, webhookID, time.Now(), payload)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to deliver webhook %s: %w", eventType, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("webhook %s rejected (HTTP %d): %s", eventType, resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// SendSubscriptionWebhook delivers a subscription event, e.g.
+// "subscription.updated", carrying the stored subscription of an external
+// customer
+func (s *Server) SendSubscriptionWebhook(ctx context.Context, url, eventType, externalCustomerID string) error {
+	s.mu.Lock()
+	sub, ok := s.subscriptions[externalCustomerID]
+	if !ok {
+		s.mu.Unlock()
+		return fmt.Errorf("no subscription for external customer %q", externalCustomerID)
+	}
+	data := s.subscriptionObjectLocked(sub)
+	s.mu.Unlock()
+
+	return s.SendWebhook(ctx, url, eventType, data)
+}
+
+// SendMeterGrantWebhook delivers a "meter.grant.updated" event reporting the
+// credits an external customer has left on a meter
+func (s *Server) SendMeterGrantWebhook(ctx context.Context, url, externalCustomerID, meterSlug string, available int32) error {
+	return s.SendWebhook(ctx, url, "meter.grant.updated", map[string]any{
+		"meter_slug":           meterSlug,
+		"external_customer_id": externalCustomerID,
+		"balance":              map[string]any{"available": available},
+	})
+}
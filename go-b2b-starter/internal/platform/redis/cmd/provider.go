@@ -11,6 +11,7 @@ func provideRedisDependencies(container *dig.Container) error {
 	providers := []any{
 		redis.LoadConfig,
 		provideRedisStore,
+		provideStreamClient,
 	}
 
 	for _, provider := range providers {
@@ -25,3 +26,12 @@ func provideRedisDependencies(container *dig.Container) error {
 func provideRedisStore() (redis.Client, error) {
 	return redis.InitRedis()
 }
+
+// provideStreamClient exposes the Streams commands of the shared connection
+func provideStreamClient(client redis.Client) (redis.StreamClient, error) {
+	streams, ok := client.(redis.StreamClient)
+	if !ok {
+		return nil, fmt.Errorf("redis client %T does not support streams", client)
+	}
+	return streams, nil
+}
@@ -0,0 +1,132 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// StreamMessage is an entry read from a Redis stream
+type StreamMessage struct {
+	ID     string
+	Values map[string]any
+}
+
+// PendingMessage describes a delivered but unacknowledged stream entry
+type PendingMessage struct {
+	ID         string
+	Consumer   string
+	Idle       time.Duration
+	Deliveries int64
+}
+
+// StreamClient exposes the Redis Streams commands used for consumer-group
+// messaging. It is served by the same connection as Client.
+type StreamClient interface {
+	// StreamAdd appends values to stream, trimming it to roughly maxLen entries (0 disables trimming)
+	StreamAdd(ctx context.Context, stream string, maxLen int64, values map[string]any) (string, error)
+	// StreamEnsureGroup creates the consumer group (and stream) if it does not exist
+	StreamEnsureGroup(ctx context.Context, stream, group string) error
+	// StreamReadGroup reads new entries for consumer, blocking up to block; no entries is not an error
+	StreamReadGroup(ctx context.Context, stream, group, consumer string, count int64, block time.Duration) ([]StreamMessage, error)
+	// StreamAck acknowledges processed entries
+	StreamAck(ctx context.Context, stream, group string, ids ...string) error
+	// StreamClaimIdle transfers entries idle for at least minIdle to consumer.
+	// It returns the claimed entries and the cursor to continue from ("0-0" when done).
+	StreamClaimIdle(ctx context.Context, stream, group, consumer string, minIdle time.Duration, start string, count int64) ([]StreamMessage, string, error)
+	// StreamPending returns pending entries between start and end (inclusive)
+	StreamPending(ctx context.Context, stream, group, start, end string, count int64) ([]PendingMessage, error)
+}
+
+func (c *redisClient) StreamAdd(ctx context.Context, stream string, maxLen int64, values map[string]any) (string, error) {
+	return c.rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		MaxLen: maxLen,
+		Approx: maxLen > 0,
+		Values: values,
+	}).Result()
+}
+
+func (c *redisClient) StreamEnsureGroup(ctx context.Context, stream, group string) error {
+	err := c.rdb.XGroupCreateMkStream(ctx, stream, group, "0").Err()
+	if err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil
+	}
+	return err
+}
+
+func (c *redisClient) StreamReadGroup(ctx context.Context, stream, group, consumer string, count int64, block time.Duration) ([]StreamMessage, error) {
+	streams, err := c.rdb.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    group,
+		Consumer: consumer,
+		Streams:  []string{stream, ">"},
+		Count:    count,
+		Block:    block,
+	}).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var messages []StreamMessage
+	for _, s := range streams {
+		messages = append(messages, toStreamMessages(s.Messages)...)
+	}
+	return messages, nil
+}
+
+func (c *redisClient) StreamAck(ctx context.Context, stream, group string, ids ...string) error {
+	return c.rdb.XAck(ctx, stream, group, ids...).Err()
+}
+
+func (c *redisClient) StreamClaimIdle(ctx context.Context, stream, group, consumer string, minIdle time.Duration, start string, count int64) ([]StreamMessage, string, error) {
+	messages, next, err := c.rdb.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   stream,
+		Group:    group,
+		Consumer: consumer,
+		MinIdle:  minIdle,
+		Start:    start,
+		Count:    count,
+	}).Result()
+	if err != nil {
+		return nil, "", err
+	}
+	return toStreamMessages(messages), next, nil
+}
+
+func (c *redisClient) StreamPending(ctx context.Context, stream, group, start, end string, count int64) ([]PendingMessage, error) {
+	pending, err := c.rdb.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: stream,
+		Group:  group,
+		Start:  start,
+		End:    end,
+		Count:  count,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]PendingMessage, len(pending))
+	for i, p := range pending {
+		result[i] = PendingMessage{
+			ID:         p.ID,
+			Consumer:   p.Consumer,
+			Idle:       p.Idle,
+			Deliveries: p.RetryCount,
+		}
+	}
+	return result, nil
+}
+
+func toStreamMessages(messages []redis.XMessage) []StreamMessage {
+	result := make([]StreamMessage, len(messages))
+	for i, m := range messages {
+		result[i] = StreamMessage{ID: m.ID, Values: m.Values}
+	}
+	return result
+}
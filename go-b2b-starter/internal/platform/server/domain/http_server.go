@@ -10,6 +10,7 @@ import (
 
 	config "github.com/moasq/go-b2b-starter/internal/platform/server/config"
 	"github.com/moasq/go-b2b-starter/internal/platform/server/logging"
+	"github.com/moasq/go-b2b-starter/internal/platform/server/metrics"
 	"github.com/moasq/go-b2b-starter/internal/platform/server/middleware"
 	"github.com/gin-gonic/gin"
 )
@@ -54,6 +55,7 @@ func (s *HTTPServer) Start() error {
 	srv := s.createHTTPServer()
 	s.setupHealthCheck()
 	s.setupRootEndpoint()
+	metrics.SetupPrometheus(s.router)
 
 	go s.startServer(srv)
 	return s.handleGracefulShutdown(srv)
@@ -7,12 +7,20 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// exposedHeaders are the response headers browsers let the frontend read,
+// including the plan, quota and dunning headers of paywall-protected routes
+var exposedHeaders = []string{
+	"Content-Length",
+	"X-Plan", "X-Subscription-Status", "X-Quota-Remaining", "X-Quota-Reset", "X-Quota-Warning",
+	"X-Dunning-Phase", "X-Dunning-Phase-Ends-At",
+}
+
 func CORS(allowedOrigins []string) gin.HandlerFunc {
 	return cors.New(cors.Config{
 		AllowOrigins:     allowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Organization-ID", "X-Account-ID"},
-		ExposeHeaders:    []string{"Content-Length"},
+		ExposeHeaders:    exposedHeaders,
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 		AllowWildcard:    false,
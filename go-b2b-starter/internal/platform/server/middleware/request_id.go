@@ -3,17 +3,23 @@ package middleware
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+
+	"github.com/moasq/go-b2b-starter/internal/platform/eventbus"
 )
 
 const (
 	// Headers
-	RequestIDHeader = "X-Request-ID"
+	RequestIDHeader   = "X-Request-ID"
+	TraceParentHeader = "traceparent"
+	TraceStateHeader  = "tracestate"
 
 	// Context keys
 	RequestIDKey = "request_id"
 )
 
-// RequestID middleware ensures each request has a unique ID for tracing
+// RequestID middleware ensures each request has a unique ID for tracing.
+// The ID and the W3C trace context are also put on the request context so
+// events published while handling the request carry them.
 func RequestID() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Check if request already has an ID
@@ -28,6 +34,21 @@ func RequestID() gin.HandlerFunc {
 		c.Set(RequestIDKey, requestID)
 		c.Header(RequestIDHeader, requestID)
 
+		// Continue the caller's trace, or start one
+		traceParent := c.GetHeader(TraceParentHeader)
+		traceState := c.GetHeader(TraceStateHeader)
+		if !eventbus.ValidTraceParent(traceParent) {
+			traceParent = eventbus.NewTraceParent()
+			traceState = ""
+		}
+
+		ctx := eventbus.WithCorrelation(c.Request.Context(), eventbus.Correlation{
+			RequestID:   requestID,
+			TraceParent: traceParent,
+			TraceState:  traceState,
+		})
+		c.Request = c.Request.WithContext(ctx)
+
 		c.Next()
 	}
 }
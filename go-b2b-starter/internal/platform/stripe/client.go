@@ -0,0 +1,154 @@
+package stripe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client provides a low-level HTTP client for the Stripe API
+// This is a generic HTTP wrapper - business logic should be in higher layers
+//
+// Stripe takes form-encoded request bodies; nested parameters use bracket
+// notation, e.g. "line_items[0][price]" or "metadata[external_id]".
+type Client struct {
+	secretKey  string
+	baseURL    string
+	apiVersion string
+	httpClient *http.Client
+	debug      bool
+}
+
+func NewClient(config *Config) (*Client, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return &Client{
+		secretKey:  config.SecretKey,
+		baseURL:    strings.TrimRight(config.BaseURL, "/"),
+		apiVersion: config.APIVersion,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		debug: config.Debug,
+	}, nil
+}
+
+// Get performs a GET request to the Stripe API
+func (c *Client) Get(ctx context.Context, path string, params url.Values) (*http.Response, error) {
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+	return c.doRequest(ctx, http.MethodGet, path, nil, "")
+}
+
+// Post performs a POST request to the Stripe API
+func (c *Client) Post(ctx context.Context, path string, params url.Values) (*http.Response, error) {
+	return c.doRequest(ctx, http.MethodPost, path, params, "")
+}
+
+// PostIdempotent performs a POST request with an Idempotency-Key, so a retried
+// request is applied by Stripe only once
+func (c *Client) PostIdempotent(ctx context.Context, path string, params url.Values, idempotencyKey string) (*http.Response, error) {
+	return c.doRequest(ctx, http.MethodPost, path, params, idempotencyKey)
+}
+
+// doRequest performs an HTTP request to the Stripe API
+func (c *Client) doRequest(ctx context.Context, method, path string, params url.Values, idempotencyKey string) (*http.Response, error) {
+	endpoint := c.baseURL + path
+
+	var bodyReader io.Reader
+	if params != nil {
+		bodyReader = strings.NewReader(params.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bodyReader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set required headers
+	req.Header.Set("Authorization", "Bearer "+c.secretKey)
+	req.Header.Set("Accept", "application/json")
+	if params != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if c.apiVersion != "" {
+		req.Header.Set("Stripe-Version", c.apiVersion)
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	if c.debug {
+		fmt.Printf("[Stripe Client] %s %s\n", method, endpoint)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	// Check for HTTP errors
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		return nil, newAPIError(resp)
+	}
+
+	return resp, nil
+}
+
+// ErrRateLimited matches API errors for requests rejected with HTTP 429
+var ErrRateLimited = errors.New("stripe API rate limit exceeded")
+
+// APIError is an error response of the Stripe API
+type APIError struct {
+	StatusCode int
+	Type       string `json:"type"`
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("Stripe API error (HTTP %d): %s: %s", e.StatusCode, e.Type, e.Message)
+}
+
+// Is lets errors.Is(err, ErrRateLimited) match HTTP 429 responses
+func (e *APIError) Is(target error) bool {
+	return target == ErrRateLimited && e.StatusCode == http.StatusTooManyRequests
+}
+
+// newAPIError reads the error object of a failed response
+func newAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+
+	body, _ := io.ReadAll(resp.Body)
+	var envelope struct {
+		Error *APIError `json:"error"`
+	}
+	if err := json.Unmarshal(body, &envelope); err == nil && envelope.Error != nil {
+		apiErr.Type = envelope.Error.Type
+		apiErr.Code = envelope.Error.Code
+		apiErr.Message = envelope.Error.Message
+	} else {
+		apiErr.Message = string(body)
+	}
+
+	return apiErr
+}
+
+// DecodeJSON is a helper to decode JSON response
+func DecodeJSON(resp *http.Response, v interface{}) error {
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode JSON response: %w", err)
+	}
+	return nil
+}
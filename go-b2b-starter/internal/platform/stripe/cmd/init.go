@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/moasq/go-b2b-starter/internal/platform/stripe"
+	"go.uber.org/dig"
+)
+
+// Init registers the Stripe config and client. Both are built lazily, so the
+// Stripe settings are only required when the billing provider is Stripe.
+func Init(container *dig.Container) error {
+	// Provide Stripe configuration using viper
+	if err := container.Provide(func() (*stripe.Config, error) {
+		config, err := stripe.LoadConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load Stripe configuration: %w", err)
+		}
+
+		return &config, nil
+	}); err != nil {
+		return fmt.Errorf("failed to provide Stripe config: %w", err)
+	}
+
+	// Register Stripe client
+	if err := stripe.Module(container); err != nil {
+		return fmt.Errorf("failed to register Stripe module: %w", err)
+	}
+
+	return nil
+}
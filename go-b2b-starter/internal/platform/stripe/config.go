@@ -0,0 +1,80 @@
+package stripe
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Config holds configuration for the Stripe client
+type Config struct {
+	// SecretKey is the Stripe secret (or restricted) API key
+	// Required for all API requests
+	SecretKey string `mapstructure:"STRIPE_SECRET_KEY"`
+
+	// BaseURL is the Stripe API endpoint
+	BaseURL string `mapstructure:"STRIPE_BASE_URL"`
+
+	// APIVersion pins the Stripe-Version header; empty uses the account default
+	APIVersion string `mapstructure:"STRIPE_API_VERSION"`
+
+	// WebhookSecret is the signing secret of the webhook endpoint (whsec_...)
+	// Get this from Stripe Dashboard → Developers → Webhooks
+	WebhookSecret string `mapstructure:"STRIPE_WEBHOOK_SECRET"`
+
+	// WebhookTolerance is the maximum age (and clock skew) accepted for the
+	// timestamp in the Stripe-Signature header
+	WebhookTolerance time.Duration `mapstructure:"STRIPE_WEBHOOK_TOLERANCE"`
+
+	// Debug enables debug logging
+	Debug bool `mapstructure:"STRIPE_DEBUG"`
+}
+
+// LoadConfig reads configuration from file or environment variables
+func LoadConfig() (Config, error) {
+	var cfg Config
+
+	viper.SetConfigName("app")
+	viper.SetConfigType("env")
+	viper.AddConfigPath(".")
+	viper.AutomaticEnv()
+
+	// Set default values
+	viper.SetDefault("STRIPE_BASE_URL", "https://api.stripe.com")
+	viper.SetDefault("STRIPE_WEBHOOK_TOLERANCE", "5m")
+	viper.SetDefault("STRIPE_DEBUG", false)
+
+	// Best-effort: ignore missing file, allow env-only usage
+	if err := viper.ReadInConfig(); err == nil {
+		_ = err
+	}
+
+	if err := viper.Unmarshal(&cfg); err != nil {
+		return cfg, fmt.Errorf("unable to decode stripe config: %w", err)
+	}
+
+	// Validate required fields
+	if err := cfg.Validate(); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
+// Validate checks if the configuration is valid
+func (c *Config) Validate() error {
+	if c.SecretKey == "" {
+		return fmt.Errorf("stripe secret key is required (STRIPE_SECRET_KEY)")
+	}
+
+	if c.BaseURL == "" {
+		return fmt.Errorf("stripe base URL is required (STRIPE_BASE_URL)")
+	}
+
+	if c.WebhookTolerance < 0 {
+		return fmt.Errorf("stripe webhook tolerance must not be negative (STRIPE_WEBHOOK_TOLERANCE)")
+	}
+
+	return nil
+}
@@ -0,0 +1,17 @@
+package stripe
+
+import (
+	"fmt"
+
+	"go.uber.org/dig"
+)
+
+// Module registers Stripe package dependencies in the DI container
+func Module(container *dig.Container) error {
+	// Register Stripe client
+	if err := container.Provide(NewClient); err != nil {
+		return fmt.Errorf("failed to provide Stripe client: %w", err)
+	}
+
+	return nil
+}